@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gorkem/md-to-pdf/internal/cache"
+	"github.com/gorkem/md-to-pdf/internal/config"
+	"github.com/gorkem/md-to-pdf/internal/handler"
+	"github.com/gorkem/md-to-pdf/internal/service"
+	"github.com/gorkem/md-to-pdf/pkg/diagrams"
+	"github.com/gorkem/md-to-pdf/pkg/filecache"
+	"github.com/gorkem/md-to-pdf/pkg/pdf"
+	"github.com/gorkem/md-to-pdf/pkg/pdf/browserpool"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// drainTimeout bounds how long shutdown waits for in-flight chromedp
+// jobs to finish before giving up on a graceful browserpool.Drain.
+const drainTimeout = 30 * time.Second
+
+// pruneInterval is how often the PDF cache's Prune runs, evicting
+// entries past cfg.Storage.Cache.MaxAge or over MaxBytes.
+const pruneInterval = 1 * time.Hour
+
+func main() {
+	logger := initLogger()
+	defer logger.Sync()
+
+	cfg := config.Load()
+
+	if err := ensureDirectories(cfg); err != nil {
+		logger.Fatal("failed to create directories", zap.Error(err))
+	}
+
+	browsers, err := browserpool.New(browserpool.Config{
+		Size:             cfg.Browser.PoolSize,
+		MaxJobsPerWorker: cfg.Browser.MaxJobsPerWorker,
+		JobTimeout:       cfg.Browser.JobTimeout,
+	})
+	if err != nil {
+		logger.Fatal("failed to start browser pool", zap.Error(err))
+	}
+
+	sharedCache := cache.New(0, time.Minute)
+
+	themes := pdf.NewThemeSet()
+	if cfg.Theme.Dir != "" {
+		if err := themes.Load(cfg.Theme.Dir); err != nil {
+			logger.Fatal("failed to load themes", zap.Error(err))
+		}
+	}
+
+	pdfCache, err := filecache.New(cfg.Storage.Cache.Dir)
+	if err != nil {
+		logger.Fatal("failed to open PDF cache", zap.Error(err))
+	}
+	go prunePDFCacheLoop(pdfCache, cfg.Storage.Cache, logger)
+
+	var markdownOpts []service.MarkdownOption
+	if cfg.Diagrams.Enabled {
+		markdownOpts = append(markdownOpts, service.WithDiagramRenderer(diagrams.NewFromConfig(diagrams.Config{
+			KrokiURL: cfg.Diagrams.KrokiURL,
+			Timeout:  cfg.Diagrams.Timeout,
+		})))
+	}
+	markdownService := service.NewMarkdownService(markdownOpts...)
+	converterService := service.NewConverterService(cfg, markdownService, sharedCache, logger,
+		service.WithBrowserPool(browsers), service.WithThemeSet(themes), service.WithPDFCache(pdfCache))
+	pdfExtractorService := service.NewPDFExtractorService(cfg, sharedCache, logger)
+
+	h := handler.NewHandler(cfg, markdownService, converterService, pdfExtractorService, sharedCache, logger)
+
+	app := fiber.New(fiber.Config{
+		BodyLimit:    cfg.Server.BodyLimit,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+	})
+
+	h.RegisterRoutes(app)
+
+	go func() {
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		<-sigChan
+
+		logger.Info("shutting down server...")
+
+		drainCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+		defer cancel()
+		if err := browsers.Drain(drainCtx); err != nil {
+			logger.Error("browser pool drain error", zap.Error(err))
+		}
+
+		if err := app.Shutdown(); err != nil {
+			logger.Error("server shutdown error", zap.Error(err))
+		}
+	}()
+
+	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
+	logger.Info("starting server",
+		zap.String("address", addr),
+		zap.Int("browserPoolSize", cfg.Browser.PoolSize),
+	)
+
+	if err := app.Listen(addr); err != nil {
+		logger.Fatal("server failed", zap.Error(err))
+	}
+}
+
+func initLogger() *zap.Logger {
+	encoderConfig := zapcore.EncoderConfig{
+		TimeKey:        "timestamp",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		FunctionKey:    zapcore.OmitKey,
+		MessageKey:     "message",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+
+	zapCfg := zap.Config{
+		Level:            zap.NewAtomicLevelAt(zap.InfoLevel),
+		Development:      false,
+		Encoding:         "json",
+		EncoderConfig:    encoderConfig,
+		OutputPaths:      []string{"stdout"},
+		ErrorOutputPaths: []string{"stderr"},
+	}
+
+	logger, err := zapCfg.Build()
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize logger: %v", err))
+	}
+
+	return logger
+}
+
+// prunePDFCacheLoop runs pdfCache.Prune every pruneInterval until the
+// process exits; it's started as a detached goroutine rather than
+// threaded through graceful shutdown since a mid-prune process exit just
+// leaves a few stale entries for the next run to catch.
+func prunePDFCacheLoop(pdfCache *filecache.Cache, cfg config.CacheConfig, logger *zap.Logger) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := pdfCache.Prune(cfg.MaxAge, cfg.MaxBytes); err != nil {
+			logger.Error("PDF cache prune failed", zap.Error(err))
+		}
+	}
+}
+
+func ensureDirectories(cfg *config.Config) error {
+	dirs := []string{cfg.Storage.TempDir, cfg.Storage.OutputDir}
+
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+
+	return nil
+}
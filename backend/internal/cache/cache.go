@@ -0,0 +1,219 @@
+// Package cache provides a single process-wide, memory-aware LRU cache
+// shared by the markdown, converter, and PDF extraction services so that
+// repeated requests for the same content don't redo the work.
+//
+// MarkdownService doesn't hold a reference to this Cache itself;
+// ConverterService and PDFExtractorService cache around their calls
+// into it instead. rag.Chunker already has its own
+// memory-aware cache (sdks/go/docflow/cache/memcache), but that's a
+// separate Go module with no dependency relationship to this one, so it
+// can't share this Cache.
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// entry is a single cache slot tracked by the LRU list.
+type entry struct {
+	key   string
+	value any
+	size  int64
+}
+
+// Cache is a memory-budgeted, least-recently-used cache. Entries are
+// evicted when the tracked byte total exceeds Config.MaxBytes, or when
+// process memory usage crosses the configured soft ceiling, whichever
+// comes first.
+type Cache struct {
+	mu     sync.Mutex
+	items  map[string]*list.Element
+	order  *list.List
+	size   int64
+	budget int64
+
+	hits      int64
+	misses    int64
+	evictions int64
+
+	stopPoll chan struct{}
+}
+
+// Stats reports a Cache's cumulative hit/miss/eviction counts and its
+// current entry/byte totals.
+type Stats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	Items     int   `json:"items"`
+	Bytes     int64 `json:"bytes"`
+}
+
+// New creates a cache with the given byte budget (falling back to
+// DefaultBudget() if budget is 0) and starts a background goroutine that
+// polls runtime.MemStats every pollInterval and evicts entries if
+// process memory usage crosses the budget even before the tracked size
+// does. A pollInterval of 0 disables the poller.
+func New(budget int64, pollInterval time.Duration) *Cache {
+	if budget <= 0 {
+		budget = DefaultBudget()
+	}
+
+	c := &Cache{
+		items:  make(map[string]*list.Element),
+		order:  list.New(),
+		budget: budget,
+	}
+
+	if pollInterval > 0 {
+		c.stopPoll = make(chan struct{})
+		go c.pollMemory(pollInterval)
+	}
+
+	return c
+}
+
+// Close stops the background memory poller, if one was started.
+func (c *Cache) Close() {
+	if c.stopPoll != nil {
+		close(c.stopPoll)
+	}
+}
+
+func (c *Cache) pollMemory(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.mu.Lock()
+			c.evictUntilUnderBudgetLocked()
+			c.mu.Unlock()
+		case <-c.stopPoll:
+			return
+		}
+	}
+}
+
+// GetOrCreate returns the cached value for key, calling create to
+// populate the cache on a miss. create must return the value, its
+// estimated size in bytes, and any error.
+func (c *Cache) GetOrCreate(key string, create func() (any, int64, error)) (any, error) {
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		c.order.MoveToFront(el)
+		v := el.Value.(*entry).value
+		c.hits++
+		c.mu.Unlock()
+		return v, nil
+	}
+	c.mu.Unlock()
+
+	value, size, err := create()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another goroutine may have populated it while we were creating.
+	if el, ok := c.items[key]; ok {
+		c.order.MoveToFront(el)
+		c.hits++
+		return el.Value.(*entry).value, nil
+	}
+
+	c.misses++
+	el := c.order.PushFront(&entry{key: key, value: value, size: size})
+	c.items[key] = el
+	c.size += size
+
+	c.evictUntilUnderBudgetLocked()
+
+	return value, nil
+}
+
+// Stats returns a snapshot of the cache's cumulative counters and
+// current occupancy.
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Stats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Items:     c.order.Len(),
+		Bytes:     c.size,
+	}
+}
+
+// evictUntilUnderBudgetLocked removes least-recently-used entries until
+// both the tracked size and the process's heap usage are back under the
+// budget (to 80% of it, to avoid evicting on every single insert once
+// near the ceiling).
+func (c *Cache) evictUntilUnderBudgetLocked() {
+	target := int64(float64(c.budget) * 0.8)
+
+	for (c.size > c.budget || currentHeapInuse() > c.budget) && c.order.Len() > 0 {
+		back := c.order.Back()
+		e := back.Value.(*entry)
+		c.order.Remove(back)
+		delete(c.items, e.key)
+		c.size -= e.size
+		c.evictions++
+
+		if c.size <= target {
+			break
+		}
+	}
+}
+
+func currentHeapInuse() int64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return int64(stats.HeapInuse)
+}
+
+// DefaultBudget reads DOCFLOW_MEMORYLIMIT (GiB, float) if set, otherwise
+// falls back to a quarter of the system's total memory, or a quarter of
+// the process's current heap usage if that can't be determined.
+func DefaultBudget() int64 {
+	if v, ok := os.LookupEnv("DOCFLOW_MEMORYLIMIT"); ok {
+		if gib, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil && gib > 0 {
+			return int64(gib * (1 << 30))
+		}
+	}
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	if stats.Sys > 0 {
+		return int64(stats.Sys) / 4
+	}
+
+	return currentHeapInuse() / 4
+}
+
+// Key derives a cache key as the SHA-256 hex digest of content plus the
+// JSON encoding of options, so two calls with the same input but
+// different conversion options don't collide.
+func Key(content string, options any) string {
+	h := sha256.New()
+	h.Write([]byte(content))
+	if options != nil {
+		if b, err := json.Marshal(options); err == nil {
+			h.Write(b)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,202 @@
+// Package config loads Config from the process environment, falling
+// back to hardcoded defaults for anything unset.
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config holds every environment-tunable setting the backend reads at
+// startup.
+type Config struct {
+	Server    ServerConfig
+	Storage   StorageConfig
+	Browser   BrowserConfig
+	Theme     ThemeConfig
+	Diagrams  DiagramsConfig
+	RateLimit RateLimitConfig
+}
+
+// ServerConfig configures the Fiber HTTP server.
+type ServerConfig struct {
+	Host         string
+	Port         string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	BodyLimit    int
+}
+
+// StorageConfig locates the directories ConverterService and
+// PDFExtractorService read and write files under.
+type StorageConfig struct {
+	TempDir   string
+	OutputDir string
+	Cache     CacheConfig
+}
+
+// CacheConfig sizes filecache.Cache, the content-addressed on-disk
+// cache of rendered PDFs ConverterService consults before running
+// chromedp at all.
+type CacheConfig struct {
+	// Dir is the cache's root directory.
+	Dir string
+	// MaxAge is how old a cached PDF can get before main.go's periodic
+	// Prune call evicts it, regardless of total cache size. Zero
+	// disables age-based eviction.
+	MaxAge time.Duration
+	// MaxBytes is the total cache size Prune evicts oldest-first down
+	// to, after age-based eviction. Zero disables size-based eviction.
+	MaxBytes int64
+}
+
+// BrowserConfig sizes browserpool.Pool, the long-lived chromedp worker
+// pool generatePDF acquires a tab from instead of spinning up a fresh
+// browser process per conversion.
+type BrowserConfig struct {
+	// PoolSize is the number of long-lived chromedp allocator/browser
+	// pairs the pool starts at construction time.
+	PoolSize int
+	// MaxJobsPerWorker is how many PDF jobs a worker runs before the
+	// pool recycles it (closes its browser and starts a fresh one), to
+	// bound any slow memory growth inside a long-lived Chromium process.
+	MaxJobsPerWorker int
+	// JobTimeout bounds how long a single worker may spend on one
+	// navigate+print job before the pool treats it as wedged, recycles
+	// the worker, and fails the job.
+	JobTimeout time.Duration
+}
+
+// ThemeConfig locates operator-supplied PDF themes.
+type ThemeConfig struct {
+	// Dir, if set, is loaded into every ConverterService's pdf.ThemeSet
+	// at startup: each *.css file directly under it registers (or
+	// overrides) a theme named after its filename. Empty means only the
+	// builtin themes (default, github, academic, slides) are available.
+	Dir string
+}
+
+// DiagramsConfig gates pkg/diagrams's Kroki-backed rendering of
+// PlantUML/Graphviz/BPMN fenced code blocks. MarkdownService, the
+// package that would read this config to build a diagrams.Renderer, has
+// no source file in this snapshot of backend/ (see pkg/diagrams's
+// package doc comment) - this section exists so that file can pick it
+// up once it does, without another config change.
+type DiagramsConfig struct {
+	// Enabled gates the whole feature off by default, since it sends
+	// document content to KrokiURL, which is an external service unless
+	// operated self-hosted.
+	Enabled bool
+	// KrokiURL is the Kroki-compatible server diagrams.KrokiRenderer
+	// POSTs diagram source to.
+	KrokiURL string
+	// Timeout bounds a single diagram render, so a slow or unreachable
+	// Kroki server can't stall PDF generation.
+	Timeout time.Duration
+}
+
+// RateLimitConfig sizes middleware.RateLimiter, which RegisterRoutes
+// applies ahead of every route - essential before exposing the PDF
+// converter (and its chromedp/Kroki calls) publicly, since each
+// conversion is far more expensive per-request than a typical API call.
+type RateLimitConfig struct {
+	// Rate is how many requests per second a single client (keyed by IP)
+	// may sustain once its burst allowance is used up.
+	Rate float64
+	// Burst caps how many requests a client can fire back-to-back before
+	// Rate's steady state applies.
+	Burst int
+}
+
+// Load builds a Config from the environment, defaulting any unset
+// variable.
+func Load() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Host:         getEnv("SERVER_HOST", "0.0.0.0"),
+			Port:         getEnv("SERVER_PORT", "8080"),
+			ReadTimeout:  getEnvDuration("SERVER_READ_TIMEOUT", 30*time.Second),
+			WriteTimeout: getEnvDuration("SERVER_WRITE_TIMEOUT", 120*time.Second),
+			BodyLimit:    getEnvInt("SERVER_BODY_LIMIT", 50*1024*1024),
+		},
+		Storage: StorageConfig{
+			TempDir:   getEnv("STORAGE_TEMP_DIR", "./temp"),
+			OutputDir: getEnv("STORAGE_OUTPUT_DIR", "./output"),
+			Cache: CacheConfig{
+				Dir:      getEnv("STORAGE_CACHE_DIR", "./cache/pdf"),
+				MaxAge:   getEnvDuration("STORAGE_CACHE_MAX_AGE", 30*24*time.Hour),
+				MaxBytes: getEnvInt64("STORAGE_CACHE_MAX_BYTES", 1024*1024*1024),
+			},
+		},
+		Browser: BrowserConfig{
+			PoolSize:         getEnvInt("BROWSER_POOL_SIZE", 4),
+			MaxJobsPerWorker: getEnvInt("BROWSER_MAX_JOBS_PER_WORKER", 50),
+			JobTimeout:       getEnvDuration("BROWSER_JOB_TIMEOUT", 60*time.Second),
+		},
+		Theme: ThemeConfig{
+			Dir: getEnv("THEME_DIR", ""),
+		},
+		Diagrams: DiagramsConfig{
+			Enabled:  getEnvBool("DIAGRAMS_ENABLED", false),
+			KrokiURL: getEnv("DIAGRAMS_KROKI_URL", "https://kroki.io"),
+			Timeout:  getEnvDuration("DIAGRAMS_TIMEOUT", 10*time.Second),
+		},
+		RateLimit: RateLimitConfig{
+			Rate:  getEnvFloat("RATE_LIMIT_RATE", 5),
+			Burst: getEnvInt("RATE_LIMIT_BURST", 20),
+		},
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
+
+func getEnvInt64(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
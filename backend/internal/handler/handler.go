@@ -1,71 +1,194 @@
 package handler
 
 import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gorkem/md-to-pdf/internal/cache"
+	"github.com/gorkem/md-to-pdf/internal/config"
+	"github.com/gorkem/md-to-pdf/internal/logger"
+	"github.com/gorkem/md-to-pdf/internal/middleware"
 	"github.com/gorkem/md-to-pdf/internal/model"
+	"github.com/gorkem/md-to-pdf/internal/progress"
 	"github.com/gorkem/md-to-pdf/internal/service"
+	"github.com/gorkem/md-to-pdf/pkg/pdf"
 	"go.uber.org/zap"
 )
 
 const version = "1.0.0"
 
+// init registers the EPUB MIME type: Go's mime package only reliably
+// knows it on systems whose /etc/mime.types happens to list it, and
+// OutputBrowse's outputFileInfo (and whatever static-file route main.go
+// wires up for GET /output/*.epub) both depend on mime.TypeByExtension
+// returning the right value for ConverterService's new EPUB output
+// format rather than falling back to application/octet-stream.
+func init() {
+	mime.AddExtensionType(".epub", "application/epub+zip")
+}
+
+// progressJobTTL is how long a finished job's buffered events stay
+// available for a reconnecting client before the registry evicts them.
+const progressJobTTL = 5 * time.Minute
+
+// streamHeartbeatInterval is how often a stalled SSE stream sends a
+// comment line, so reverse proxies that time out idle connections don't
+// close them mid-job.
+const streamHeartbeatInterval = 15 * time.Second
+
+// downloadURLTTL is how long a signed /api/output download URL stays
+// valid after it's issued by OutputBrowse.
+const downloadURLTTL = 1 * time.Hour
+
 // Handler contains all HTTP handlers
 type Handler struct {
-	markdown     *service.MarkdownService
-	converter    *service.ConverterService
-	pdfExtractor *service.PDFExtractorService
-	logger       *zap.Logger
+	cfg            *config.Config
+	markdown       *service.MarkdownService
+	converter      *service.ConverterService
+	pdfExtractor   *service.PDFExtractorService
+	cache          *cache.Cache
+	progress       *progress.Registry
+	downloadSecret []byte
+	logger         *zap.Logger
 }
 
 // NewHandler creates a new handler instance
 func NewHandler(
+	cfg *config.Config,
 	markdown *service.MarkdownService,
 	converter *service.ConverterService,
 	pdfExtractor *service.PDFExtractorService,
+	cache *cache.Cache,
 	logger *zap.Logger,
 ) *Handler {
+	secret := make([]byte, 32)
+	_, _ = rand.Read(secret)
+
 	return &Handler{
-		markdown:     markdown,
-		converter:    converter,
-		pdfExtractor: pdfExtractor,
-		logger:       logger,
+		cfg:            cfg,
+		markdown:       markdown,
+		converter:      converter,
+		pdfExtractor:   pdfExtractor,
+		cache:          cache,
+		progress:       progress.NewRegistry(progressJobTTL),
+		downloadSecret: secret,
+		logger:         logger,
 	}
 }
 
+// requestContext pulls the request-scoped logger middleware.RequestContext
+// attached to c (falling back to h.logger if the middleware didn't run,
+// e.g. in a handler test built on a bare fiber.App) and returns both it
+// and a context.Context carrying it, for handlers to pass down into
+// ConverterService/PDFExtractorService so their logs share this
+// request's correlation fields.
+func (h *Handler) requestContext(c *fiber.Ctx) (context.Context, *zap.Logger) {
+	log := middleware.LoggerFromLocals(c, h.logger)
+	return logger.WithContext(c.Context(), log), log
+}
+
 // RegisterRoutes registers all routes
 func (h *Handler) RegisterRoutes(app *fiber.App) {
+	app.Use(middleware.RequestContext(h.logger))
+	app.Use(middleware.RateLimiter(middleware.RateLimiterConfig{
+		Rate:  h.cfg.RateLimit.Rate,
+		Burst: h.cfg.RateLimit.Burst,
+	}))
+
 	api := app.Group("/api")
 
 	// Health
 	api.Get("/health", h.HealthCheck)
+	app.Get("/metrics", h.Metrics)
 
 	// MD to PDF
 	api.Post("/preview", h.Preview)
 	api.Post("/preview/merge", h.MergePreview)
 	api.Post("/convert", h.Convert)
+	api.Post("/convert/stream", h.ConvertStream)
+	api.Get("/convert/stream/:jobID", h.ConvertStreamWatch)
 
 	// PDF to MD
 	api.Post("/pdf/preview", h.PDFPreview)
 	api.Post("/pdf/extract", h.PDFExtract)
+	api.Post("/pdf/extract/stream", h.PDFExtractStream)
+	api.Get("/pdf/extract/stream/:jobID", h.PDFExtractStreamWatch)
+
+	// Output browsing
+	api.Get("/output", h.OutputBrowse)
+	api.Get("/output/*", h.OutputBrowse)
+
+	// Signed file downloads, for the DownloadURL OutputBrowse hands out
+	app.Get("/output/*", h.DownloadOutput)
 }
 
 // HealthCheck handles health check requests
 func (h *Handler) HealthCheck(c *fiber.Ctx) error {
+	stats := h.cache.Stats()
 	return c.JSON(model.HealthResponse{
 		Status:    "healthy",
 		Version:   version,
 		Timestamp: time.Now().Unix(),
+		Cache: model.CacheStats{
+			Hits:      stats.Hits,
+			Misses:    stats.Misses,
+			Evictions: stats.Evictions,
+			Bytes:     stats.Bytes,
+		},
 	})
 }
 
+// Metrics exposes the browser pool's load and throughput in Prometheus
+// text exposition format. Returns 200 with no series (rather than an
+// error) when the converter service was built without WithBrowserPool,
+// since a standalone-allocator deployment has no pool to report on.
+func (h *Handler) Metrics(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4")
+
+	stats, ok := h.converter.BrowserPoolMetrics()
+	if !ok {
+		return c.SendString("# browserpool: no pool configured\n")
+	}
+
+	var b strings.Builder
+	writeGauge := func(name, help string, value float64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n%s %v\n", name, help, name, name, value)
+	}
+
+	writeGauge("docflow_browserpool_size", "Number of long-lived browser workers in the pool.", float64(stats.PoolSize))
+	writeGauge("docflow_browserpool_in_flight", "Number of jobs currently running.", float64(stats.InFlight))
+	writeGauge("docflow_browserpool_queue_depth", "Number of jobs waiting for a free worker.", float64(stats.QueueDepth))
+	writeGauge("docflow_browserpool_jobs_total", "Cumulative number of jobs the pool has run.", float64(stats.TotalJobs))
+	writeGauge("docflow_browserpool_avg_print_seconds", "Average wall-clock time per job, in seconds.", stats.AvgPrintTime.Seconds())
+
+	return c.SendString(b.String())
+}
+
 // Preview handles markdown preview requests
 func (h *Handler) Preview(c *fiber.Ctx) error {
+	_, log := h.requestContext(c)
+
 	var req model.PreviewRequest
 	if err := c.BodyParser(&req); err != nil {
-		h.logger.Warn("invalid preview request", zap.Error(err))
+		log.Warn("invalid preview request", zap.Error(err))
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
 			Success: false,
 			Error:   "Invalid request body",
@@ -83,7 +206,7 @@ func (h *Handler) Preview(c *fiber.Ctx) error {
 
 	html, err := h.markdown.ToHTML(req.Content)
 	if err != nil {
-		h.logger.Error("markdown conversion failed", zap.Error(err))
+		log.Error("markdown conversion failed", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 			Success: false,
 			Error:   "Failed to convert markdown",
@@ -96,9 +219,11 @@ func (h *Handler) Preview(c *fiber.Ctx) error {
 
 // MergePreview handles merge preview requests - shows how merged document will look
 func (h *Handler) MergePreview(c *fiber.Ctx) error {
+	_, log := h.requestContext(c)
+
 	var req model.MergePreviewRequest
 	if err := c.BodyParser(&req); err != nil {
-		h.logger.Warn("invalid merge preview request", zap.Error(err))
+		log.Warn("invalid merge preview request", zap.Error(err))
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
 			Success: false,
 			Error:   "Invalid request body",
@@ -116,7 +241,7 @@ func (h *Handler) MergePreview(c *fiber.Ctx) error {
 
 	html, err := h.markdown.MergeFilesToHTML(req.Files)
 	if err != nil {
-		h.logger.Error("merge preview failed", zap.Error(err))
+		log.Error("merge preview failed", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 			Success: false,
 			Error:   "Failed to generate merge preview",
@@ -136,9 +261,11 @@ func (h *Handler) MergePreview(c *fiber.Ctx) error {
 
 // Convert handles PDF conversion requests
 func (h *Handler) Convert(c *fiber.Ctx) error {
+	ctx, log := h.requestContext(c)
+
 	var req model.ConvertRequest
 	if err := c.BodyParser(&req); err != nil {
-		h.logger.Warn("invalid convert request", zap.Error(err))
+		log.Warn("invalid convert request", zap.Error(err))
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
 			Success: false,
 			Error:   "Invalid request body",
@@ -158,9 +285,17 @@ func (h *Handler) Convert(c *fiber.Ctx) error {
 		req.MergeMode = model.MergeModeSeparate
 	}
 
-	result, err := h.converter.Convert(c.Context(), &req)
+	result, err := h.converter.Convert(ctx, &req, nil)
 	if err != nil {
-		h.logger.Error("conversion failed", zap.Error(err))
+		if errors.Is(err, pdf.ErrAssetsNotVendored) {
+			log.Warn("rejected convert request", zap.Error(err))
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+				Success: false,
+				Error:   err.Error(),
+				Code:    "FEATURE_UNAVAILABLE",
+			})
+		}
+		log.Error("conversion failed", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 			Success: false,
 			Error:   err.Error(),
@@ -171,11 +306,223 @@ func (h *Handler) Convert(c *fiber.Ctx) error {
 	return c.JSON(result)
 }
 
+// ConvertStream starts a conversion job in the background and returns its
+// job ID immediately; the caller watches progress via
+// GET /api/convert/stream/:jobID.
+func (h *Handler) ConvertStream(c *fiber.Ctx) error {
+	ctx, log := h.requestContext(c)
+
+	var req model.ConvertRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Warn("invalid convert request", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request body",
+			Code:    "INVALID_REQUEST",
+		})
+	}
+
+	if len(req.Files) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Success: false,
+			Error:   "At least one file is required",
+			Code:    "FILES_REQUIRED",
+		})
+	}
+
+	if req.MergeMode == "" {
+		req.MergeMode = model.MergeModeSeparate
+	}
+
+	jobID, job := h.progress.NewJob()
+
+	go func() {
+		reporter := model.ProgressReporter(job.Publish)
+		if _, err := h.converter.Convert(ctx, &req, reporter); err != nil {
+			job.Publish(model.ProgressEvent{Stage: model.ProgressStageError, Error: err.Error()})
+		}
+	}()
+
+	return c.JSON(fiber.Map{"jobId": jobID})
+}
+
+// ConvertStreamWatch streams a running or recently finished conversion
+// job's progress as Server-Sent Events.
+func (h *Handler) ConvertStreamWatch(c *fiber.Ctx) error {
+	return h.streamProgress(c)
+}
+
+// PDFExtractStream starts a PDF extraction job in the background and
+// returns its job ID immediately; the caller watches progress via
+// GET /api/pdf/extract/stream/:jobID.
+func (h *Handler) PDFExtractStream(c *fiber.Ctx) error {
+	ctx, log := h.requestContext(c)
+
+	var req model.PDFExtractRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Warn("invalid PDF extract request", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Success: false,
+			Error:   "Invalid request body",
+			Code:    "INVALID_REQUEST",
+		})
+	}
+
+	if req.Content == "" || req.FileName == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Success: false,
+			Error:   "Content and fileName are required",
+			Code:    "MISSING_FIELDS",
+		})
+	}
+
+	pdfData, err := base64.StdEncoding.DecodeString(req.Content)
+	if err != nil {
+		log.Warn("invalid base64 content", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Success: false,
+			Error:   "Invalid PDF content",
+			Code:    "INVALID_CONTENT",
+		})
+	}
+
+	jobID, job := h.progress.NewJob()
+
+	go func() {
+		reporter := model.ProgressReporter(job.Publish)
+		if _, err := h.pdfExtractor.ExtractToMarkdownStream(ctx, pdfData, req.FileName, reporter); err != nil {
+			job.Publish(model.ProgressEvent{Stage: model.ProgressStageError, File: req.FileName, Error: err.Error()})
+		}
+	}()
+
+	return c.JSON(fiber.Map{"jobId": jobID})
+}
+
+// PDFExtractStreamWatch streams a running or recently finished PDF
+// extraction job's progress as Server-Sent Events.
+func (h *Handler) PDFExtractStreamWatch(c *fiber.Ctx) error {
+	return h.streamProgress(c)
+}
+
+// streamProgress streams a job's progress as Server-Sent Events, shared
+// by ConvertStreamWatch and PDFExtractStreamWatch since both jobIDs are
+// registered in the same progress.Registry and watched the same way. A
+// client that reconnects mid-job sends Last-Event-ID (or
+// ?lastEventId=) to replay missed events before resuming the live
+// stream. Each frame carries an explicit "event:" line derived from the
+// ProgressEvent's Stage (sseEventName), and a heartbeat comment is sent
+// every streamHeartbeatInterval of silence so idle-timing proxies don't
+// close the connection while a job is still running.
+func (h *Handler) streamProgress(c *fiber.Ctx) error {
+	jobID := c.Params("jobID")
+	job, ok := h.progress.Get(jobID)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+			Success: false,
+			Error:   "Unknown job",
+			Code:    "JOB_NOT_FOUND",
+		})
+	}
+
+	lastSeq := 0
+	if v := c.Get("Last-Event-ID"); v != "" {
+		lastSeq, _ = strconv.Atoi(v)
+	} else if v := c.Query("lastEventId"); v != "" {
+		lastSeq, _ = strconv.Atoi(v)
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		writeEvent := func(evt progress.Event) bool {
+			payload, err := json.Marshal(evt.Data)
+			if err != nil {
+				return true
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\nid: %d\ndata: %s\n\n", sseEventName(evt.Data.Stage), evt.Seq, payload); err != nil {
+				return false
+			}
+			return w.Flush() == nil
+		}
+		writeHeartbeat := func() bool {
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return false
+			}
+			return w.Flush() == nil
+		}
+
+		buffered, done := job.Since(lastSeq)
+		for _, evt := range buffered {
+			if !writeEvent(evt) {
+				return
+			}
+			lastSeq = evt.Seq
+		}
+		if done {
+			return
+		}
+
+		sub, unsubscribe := job.Subscribe()
+		defer unsubscribe()
+
+		heartbeat := time.NewTicker(streamHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case evt, ok := <-sub:
+				if !ok {
+					return
+				}
+				if evt.Seq <= lastSeq {
+					continue
+				}
+				if !writeEvent(evt) {
+					return
+				}
+				lastSeq = evt.Seq
+				heartbeat.Reset(streamHeartbeatInterval)
+				if evt.Data.Stage == model.ProgressStageDone || evt.Data.Stage == model.ProgressStageError {
+					return
+				}
+			case <-heartbeat.C:
+				if !writeHeartbeat() {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
+
+// sseEventName maps a ProgressEvent's Stage to the SSE "event:" name
+// subscribers filter on: ProgressStagePartial, ProgressStageDone, and
+// ProgressStageError are sent as their own named events, and every other
+// stage (markdown/chromedp/merge/extract) is sent as a generic
+// "progress" event carrying page/file counters.
+func sseEventName(stage string) string {
+	switch stage {
+	case model.ProgressStagePartial:
+		return "partial"
+	case model.ProgressStageDone:
+		return "done"
+	case model.ProgressStageError:
+		return "error"
+	default:
+		return "progress"
+	}
+}
+
 // PDFPreview handles PDF preview requests
 func (h *Handler) PDFPreview(c *fiber.Ctx) error {
+	ctx, log := h.requestContext(c)
+
 	var req model.PDFExtractRequest
 	if err := c.BodyParser(&req); err != nil {
-		h.logger.Warn("invalid PDF preview request", zap.Error(err))
+		log.Warn("invalid PDF preview request", zap.Error(err))
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
 			Success: false,
 			Error:   "Invalid request body",
@@ -194,7 +541,7 @@ func (h *Handler) PDFPreview(c *fiber.Ctx) error {
 	// Decode base64 PDF
 	pdfData, err := base64.StdEncoding.DecodeString(req.Content)
 	if err != nil {
-		h.logger.Warn("invalid base64 content", zap.Error(err))
+		log.Warn("invalid base64 content", zap.Error(err))
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
 			Success: false,
 			Error:   "Invalid PDF content",
@@ -202,9 +549,9 @@ func (h *Handler) PDFPreview(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.pdfExtractor.PreviewExtraction(c.Context(), pdfData, req.FileName)
+	result, err := h.pdfExtractor.PreviewExtraction(ctx, pdfData, req.FileName)
 	if err != nil {
-		h.logger.Error("PDF preview failed", zap.Error(err))
+		log.Error("PDF preview failed", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 			Success: false,
 			Error:   err.Error(),
@@ -217,9 +564,11 @@ func (h *Handler) PDFPreview(c *fiber.Ctx) error {
 
 // PDFExtract handles PDF to Markdown extraction requests
 func (h *Handler) PDFExtract(c *fiber.Ctx) error {
+	ctx, log := h.requestContext(c)
+
 	var req model.PDFExtractRequest
 	if err := c.BodyParser(&req); err != nil {
-		h.logger.Warn("invalid PDF extract request", zap.Error(err))
+		log.Warn("invalid PDF extract request", zap.Error(err))
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
 			Success: false,
 			Error:   "Invalid request body",
@@ -238,7 +587,7 @@ func (h *Handler) PDFExtract(c *fiber.Ctx) error {
 	// Decode base64 PDF
 	pdfData, err := base64.StdEncoding.DecodeString(req.Content)
 	if err != nil {
-		h.logger.Warn("invalid base64 content", zap.Error(err))
+		log.Warn("invalid base64 content", zap.Error(err))
 		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
 			Success: false,
 			Error:   "Invalid PDF content",
@@ -246,9 +595,9 @@ func (h *Handler) PDFExtract(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.pdfExtractor.ExtractToMarkdown(c.Context(), pdfData, req.FileName)
+	result, err := h.pdfExtractor.ExtractToMarkdown(ctx, pdfData, req.FileName)
 	if err != nil {
-		h.logger.Error("PDF extraction failed", zap.Error(err))
+		log.Error("PDF extraction failed", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
 			Success: false,
 			Error:   err.Error(),
@@ -258,3 +607,281 @@ func (h *Handler) PDFExtract(c *fiber.Ctx) error {
 
 	return c.JSON(result)
 }
+
+// OutputBrowse lists generated files under cfg.Storage.OutputDir,
+// recursing into the optional *path wildcard. Supports ?sort=name|size|time,
+// ?order=asc|desc, and ?limit=&offset= pagination, and renders an HTML
+// table instead of JSON when the client prefers text/html.
+func (h *Handler) OutputBrowse(c *fiber.Ctx) error {
+	outputDir, err := filepath.Abs(h.cfg.Storage.OutputDir)
+	if err != nil {
+		h.logger.Error("failed to resolve output dir", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Success: false,
+			Error:   "Failed to resolve output directory",
+			Code:    "OUTPUT_DIR_ERROR",
+		})
+	}
+
+	requested := c.Params("*")
+	if decoded, err := url.PathUnescape(requested); err == nil {
+		requested = decoded
+	}
+
+	target, ok := resolveOutputPath(outputDir, requested)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Success: false,
+			Error:   "Path escapes output directory",
+			Code:    "INVALID_PATH",
+		})
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c.Status(fiber.StatusNotFound).JSON(model.ErrorResponse{
+				Success: false,
+				Error:   "Path not found",
+				Code:    "NOT_FOUND",
+			})
+		}
+		h.logger.Error("failed to stat output path", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Success: false,
+			Error:   "Failed to read output directory",
+			Code:    "OUTPUT_READ_ERROR",
+		})
+	}
+
+	var files []model.OutputFileInfo
+	if info.IsDir() {
+		files, err = h.listOutputFiles(outputDir, target)
+		if err != nil {
+			h.logger.Error("failed to list output files", zap.Error(err))
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+				Success: false,
+				Error:   "Failed to list output directory",
+				Code:    "OUTPUT_READ_ERROR",
+			})
+		}
+	} else {
+		files = []model.OutputFileInfo{h.outputFileInfo(outputDir, target, info)}
+	}
+
+	sortOutputFiles(files, c.Query("sort", "name"), c.Query("order", "asc"))
+
+	limit, _ := strconv.Atoi(c.Query("limit", "0"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	total := len(files)
+	paged := paginateOutputFiles(files, limit, offset)
+
+	resp := model.OutputListResponse{
+		Files:  paged,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	if strings.Contains(c.Get("Accept"), "text/html") {
+		return c.Type("html").SendString(renderOutputListHTML(requested, resp))
+	}
+	return c.JSON(resp)
+}
+
+// listOutputFiles walks dir recursively and returns every regular file
+// as an OutputFileInfo, with Name relative to root.
+func (h *Handler) listOutputFiles(root, dir string) ([]model.OutputFileInfo, error) {
+	var files []model.OutputFileInfo
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, h.outputFileInfo(root, path, info))
+		return nil
+	})
+	return files, err
+}
+
+func (h *Handler) outputFileInfo(root, path string, info os.FileInfo) model.OutputFileInfo {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = filepath.Base(path)
+	}
+	rel = filepath.ToSlash(rel)
+
+	mimeType := mime.TypeByExtension(filepath.Ext(path))
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+
+	return model.OutputFileInfo{
+		Name:        rel,
+		Size:        info.Size(),
+		SizeHuman:   formatByteSize(info.Size()),
+		ModTime:     info.ModTime().Unix(),
+		MimeType:    mimeType,
+		DownloadURL: h.signDownloadURL(rel),
+	}
+}
+
+// signDownloadURL builds a /output/<path> URL with an expiry and an
+// HMAC-SHA256 signature over "<path>:<expiry>", so a link handed out by
+// OutputBrowse can be validated later without a server-side session.
+// DownloadOutput is what performs that validation.
+func (h *Handler) signDownloadURL(relPath string) string {
+	exp := time.Now().Add(downloadURLTTL).Unix()
+	mac := hmac.New(sha256.New, h.downloadSecret)
+	fmt.Fprintf(mac, "%s:%d", relPath, exp)
+	sig := hex.EncodeToString(mac.Sum(nil))
+
+	return fmt.Sprintf("/output/%s?exp=%d&sig=%s", url.PathEscape(relPath), exp, sig)
+}
+
+// DownloadOutput serves a single file under cfg.Storage.OutputDir after
+// verifying the ?exp=&sig= query string signDownloadURL attached to the
+// link OutputBrowse handed out. It replaces a plain app.Static mount,
+// which served the entire output directory to anyone who requested a
+// path directly, signature or not.
+func (h *Handler) DownloadOutput(c *fiber.Ctx) error {
+	requested := c.Params("*")
+	if decoded, err := url.PathUnescape(requested); err == nil {
+		requested = decoded
+	}
+
+	if !h.verifyDownloadURL(requested, c.Query("exp"), c.Query("sig")) {
+		return c.Status(fiber.StatusForbidden).JSON(model.ErrorResponse{
+			Success: false,
+			Error:   "Invalid or expired download link",
+			Code:    "INVALID_SIGNATURE",
+		})
+	}
+
+	outputDir, err := filepath.Abs(h.cfg.Storage.OutputDir)
+	if err != nil {
+		h.logger.Error("failed to resolve output dir", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ErrorResponse{
+			Success: false,
+			Error:   "Failed to resolve output directory",
+			Code:    "OUTPUT_DIR_ERROR",
+		})
+	}
+
+	target, ok := resolveOutputPath(outputDir, requested)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{
+			Success: false,
+			Error:   "Path escapes output directory",
+			Code:    "INVALID_PATH",
+		})
+	}
+
+	return c.SendFile(target)
+}
+
+// verifyDownloadURL reports whether sig is a valid, unexpired
+// HMAC-SHA256 signature over "<relPath>:<exp>", matching what
+// signDownloadURL issued for relPath.
+func (h *Handler) verifyDownloadURL(relPath, expStr, sig string) bool {
+	exp, err := strconv.ParseInt(expStr, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, h.downloadSecret)
+	fmt.Fprintf(mac, "%s:%d", relPath, exp)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(sig))
+}
+
+// resolveOutputPath joins requested onto outputDir and reports whether
+// the result still lives under outputDir. filepath.Clean("/"+requested)
+// collapses any ".." before the join, so a traversal attempt can't walk
+// out even if the prefix check below didn't also catch it.
+func resolveOutputPath(outputDir, requested string) (string, bool) {
+	target := filepath.Join(outputDir, filepath.Clean("/"+requested))
+	if target != outputDir && !strings.HasPrefix(target, outputDir+string(filepath.Separator)) {
+		return "", false
+	}
+	return target, true
+}
+
+func sortOutputFiles(files []model.OutputFileInfo, sortBy, order string) {
+	less := func(i, j int) bool {
+		switch sortBy {
+		case "size":
+			return files[i].Size < files[j].Size
+		case "time":
+			return files[i].ModTime < files[j].ModTime
+		default:
+			return files[i].Name < files[j].Name
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if order == "desc" {
+			return less(j, i)
+		}
+		return less(i, j)
+	})
+}
+
+func paginateOutputFiles(files []model.OutputFileInfo, limit, offset int) []model.OutputFileInfo {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(files) {
+		return []model.OutputFileInfo{}
+	}
+	end := len(files)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return files[offset:end]
+}
+
+func renderOutputListHTML(path string, resp model.OutputListResponse) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html><html><head><title>Output files</title></head><body>")
+	fmt.Fprintf(&b, "<h1>Output files%s</h1>", htmlEscapePathSuffix(path))
+	b.WriteString("<table border=\"1\"><tr><th>Name</th><th>Size</th><th>Modified</th><th>Type</th><th>Download</th></tr>")
+	for _, f := range resp.Files {
+		fmt.Fprintf(&b, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td><a href=\"%s\">download</a></td></tr>",
+			htmlEscape(f.Name), htmlEscape(f.SizeHuman), time.Unix(f.ModTime, 0).Format(time.RFC3339), htmlEscape(f.MimeType), f.DownloadURL)
+	}
+	b.WriteString("</table></body></html>")
+	return b.String()
+}
+
+func htmlEscapePathSuffix(path string) string {
+	if path == "" {
+		return ""
+	}
+	return ": " + htmlEscape(path)
+}
+
+func htmlEscape(s string) string {
+	replacer := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\"", "&quot;")
+	return replacer.Replace(s)
+}
+
+// formatByteSize renders n bytes as a human-readable string (e.g. "1.2 MB").
+func formatByteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for x := n / unit; x >= unit; x /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -2,12 +2,21 @@ package handler
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gorkem/md-to-pdf/internal/cache"
 	"github.com/gorkem/md-to-pdf/internal/config"
 	"github.com/gorkem/md-to-pdf/internal/model"
 	"github.com/gorkem/md-to-pdf/internal/service"
@@ -23,11 +32,13 @@ func setupTestHandler() (*Handler, *fiber.App) {
 		},
 	}
 
+	testCache := cache.New(0, 0)
+
 	markdownSvc := service.NewMarkdownService()
-	converterSvc := service.NewConverterService(cfg, markdownSvc, logger)
-	pdfExtractorSvc := service.NewPDFExtractorService(cfg, logger)
+	converterSvc := service.NewConverterService(cfg, markdownSvc, testCache, logger)
+	pdfExtractorSvc := service.NewPDFExtractorService(cfg, testCache, logger)
 
-	h := NewHandler(markdownSvc, converterSvc, pdfExtractorSvc, logger)
+	h := NewHandler(cfg, markdownSvc, converterSvc, pdfExtractorSvc, testCache, logger)
 
 	app := fiber.New()
 	h.RegisterRoutes(app)
@@ -407,3 +418,168 @@ func TestInvalidJSON(t *testing.T) {
 		})
 	}
 }
+
+// setupOutputTestHandler builds a Handler scoped to outputDir, leaving
+// every other dependency nil since OutputBrowse doesn't touch them.
+func setupOutputTestHandler(outputDir string) (*Handler, *fiber.App) {
+	logger, _ := zap.NewDevelopment()
+	cfg := &config.Config{
+		Storage: config.StorageConfig{
+			OutputDir: outputDir,
+		},
+	}
+
+	h := NewHandler(cfg, nil, nil, nil, nil, logger)
+
+	app := fiber.New()
+	h.RegisterRoutes(app)
+
+	return h, app
+}
+
+func TestOutputBrowse_EmptyDir(t *testing.T) {
+	_, app := setupOutputTestHandler(t.TempDir())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/output", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	var result model.OutputListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Total != 0 || len(result.Files) != 0 {
+		t.Errorf("expected no files, got %+v", result)
+	}
+}
+
+func TestOutputBrowse_NestedSubdirs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub", "nested"), 0755); err != nil {
+		t.Fatalf("failed to create nested dirs: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "top.pdf"), []byte("top"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "nested", "deep.pdf"), []byte("deep"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	_, app := setupOutputTestHandler(dir)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/output", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	var result model.OutputListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Total != 2 {
+		t.Fatalf("expected 2 files, got %d: %+v", result.Total, result.Files)
+	}
+
+	names := map[string]bool{}
+	for _, f := range result.Files {
+		names[f.Name] = true
+		if f.DownloadURL == "" {
+			t.Errorf("expected a download URL for %q", f.Name)
+		}
+	}
+	if !names["top.pdf"] || !names["sub/nested/deep.pdf"] {
+		t.Errorf("expected top.pdf and sub/nested/deep.pdf, got %+v", names)
+	}
+}
+
+func TestDownloadOutput_SignatureVerification(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "report.pdf"), []byte("report"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	h, app := setupOutputTestHandler(dir)
+
+	t.Run("valid signature succeeds", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, h.signDownloadURL("report.pdf"), nil)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected status 200, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("stripped signature is rejected", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/output/report.pdf", nil)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		if resp.StatusCode == http.StatusOK {
+			t.Errorf("expected missing signature to be rejected, got 200")
+		}
+	})
+
+	t.Run("tampered signature is rejected", func(t *testing.T) {
+		signed := h.signDownloadURL("report.pdf")
+		tampered := strings.TrimSuffix(signed, "f") + "0"
+		req := httptest.NewRequest(http.MethodGet, tampered, nil)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		if resp.StatusCode == http.StatusOK {
+			t.Errorf("expected tampered signature to be rejected, got 200")
+		}
+	})
+
+	t.Run("expired signature is rejected", func(t *testing.T) {
+		exp := time.Now().Add(-time.Hour).Unix()
+		mac := hmac.New(sha256.New, h.downloadSecret)
+		fmt.Fprintf(mac, "%s:%d", "report.pdf", exp)
+		sig := hex.EncodeToString(mac.Sum(nil))
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/output/report.pdf?exp=%d&sig=%s", exp, sig), nil)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		if resp.StatusCode == http.StatusOK {
+			t.Errorf("expected expired signature to be rejected, got 200")
+		}
+	})
+}
+
+func TestOutputBrowse_PathTraversal(t *testing.T) {
+	dir := t.TempDir()
+	_, app := setupOutputTestHandler(dir)
+
+	paths := []string{
+		"/api/output/..%2F..%2Fetc%2Fpasswd",
+		"/api/output/../../etc/passwd",
+	}
+
+	for _, p := range paths {
+		t.Run(p, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, p, nil)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+			if resp.StatusCode == http.StatusOK {
+				t.Errorf("expected traversal attempt to be rejected, got 200")
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// Package logger lets a request-scoped *zap.Logger travel through a
+// context.Context from Handler down into ConverterService and
+// PDFExtractorService, so every log line emitted while handling one
+// request carries that request's correlation fields (request_id, route,
+// method, remote_ip - see middleware.RequestContext) without every
+// service method taking a *zap.Logger parameter alongside ctx.
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type ctxKey struct{}
+
+// WithContext returns a copy of ctx carrying l, retrievable via Ctx.
+func WithContext(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// Ctx returns the *zap.Logger WithContext attached to ctx, or fallback if
+// ctx carries none - e.g. a background job whose context didn't
+// originate from an HTTP request.
+func Ctx(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*zap.Logger); ok && l != nil {
+		return l
+	}
+	return fallback
+}
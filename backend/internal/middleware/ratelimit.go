@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimiterConfig configures a token-bucket RateLimiter.
+type RateLimiterConfig struct {
+	// Rate is how many tokens are added to a key's bucket per second.
+	Rate float64
+	// Burst caps how many tokens a bucket can hold, and so how many
+	// requests can fire back-to-back before Rate's steady state applies.
+	Burst int
+	// KeyFunc derives the rate-limit key from a request, e.g. by IP or
+	// API key. Defaults to c.IP(), so apply per-route with a stricter
+	// KeyFunc/Rate/Burst on e.g. /convert than on /health.
+	KeyFunc func(c *fiber.Ctx) string
+	// Store holds each key's bucket state. Defaults to a new
+	// MemoryRateLimitStore.
+	Store RateLimitStore
+}
+
+// RateLimitStore is a pluggable token-bucket store, so RateLimiter can
+// run against a single process (MemoryRateLimitStore) or be shared
+// across instances (RedisRateLimitStore).
+type RateLimitStore interface {
+	// Allow atomically takes one token from key's bucket, refilling it
+	// up to burst at rate tokens/sec since it was last touched, and
+	// reports whether the request is allowed, the tokens now remaining,
+	// and (when disallowed) how long to wait before retrying.
+	Allow(key string, rate float64, burst int) (allowed bool, remaining int, retryAfter time.Duration)
+}
+
+// RateLimiter is token-bucket rate limiting middleware. Requests over
+// the limit get a 429 with Retry-After and X-RateLimit-* headers. A
+// non-positive Burst disables rate limiting entirely (c.Next() every
+// time), the same "zero means off" convention config.CacheConfig uses
+// for its MaxAge/MaxBytes - it's what a zero-value RateLimiterConfig,
+// e.g. in a test that doesn't set config.RateLimitConfig, gets.
+func RateLimiter(cfg RateLimiterConfig) fiber.Handler {
+	if cfg.Burst <= 0 {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+	if cfg.KeyFunc == nil {
+		cfg.KeyFunc = func(c *fiber.Ctx) string { return c.IP() }
+	}
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryRateLimitStore(time.Minute)
+	}
+
+	return func(c *fiber.Ctx) error {
+		key := cfg.KeyFunc(c)
+		allowed, remaining, retryAfter := cfg.Store.Allow(key, cfg.Rate, cfg.Burst)
+
+		c.Set("X-RateLimit-Limit", strconv.Itoa(cfg.Burst))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+
+		if !allowed {
+			c.Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"success": false,
+				"error":   "rate limit exceeded",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// bucket is one key's token-bucket state.
+type bucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	lastSeen time.Time
+}
+
+// MemoryRateLimitStore is an in-process RateLimitStore backed by
+// sync.Map, with a background goroutine that periodically evicts
+// buckets idle longer than gcAfter so a long-running server doesn't
+// accumulate one bucket per caller forever.
+type MemoryRateLimitStore struct {
+	buckets sync.Map // string -> *bucket
+	gcAfter time.Duration
+}
+
+// NewMemoryRateLimitStore creates a MemoryRateLimitStore and starts its
+// GC loop, which runs every gcAfter and evicts buckets idle that long.
+func NewMemoryRateLimitStore(gcAfter time.Duration) *MemoryRateLimitStore {
+	s := &MemoryRateLimitStore{gcAfter: gcAfter}
+	go s.gcLoop()
+	return s
+}
+
+func (s *MemoryRateLimitStore) gcLoop() {
+	ticker := time.NewTicker(s.gcAfter)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		s.buckets.Range(func(key, value interface{}) bool {
+			b := value.(*bucket)
+
+			b.mu.Lock()
+			idle := now.Sub(b.lastSeen)
+			b.mu.Unlock()
+
+			if idle > s.gcAfter {
+				s.buckets.Delete(key)
+			}
+			return true
+		})
+	}
+}
+
+// Allow implements RateLimitStore.
+func (s *MemoryRateLimitStore) Allow(key string, rate float64, burst int) (bool, int, time.Duration) {
+	now := time.Now()
+	v, _ := s.buckets.LoadOrStore(key, &bucket{tokens: float64(burst), lastSeen: now})
+	b := v.(*bucket)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	elapsed := now.Sub(b.lastSeen).Seconds()
+	b.tokens = math.Min(float64(burst), b.tokens+elapsed*rate)
+	b.lastSeen = now
+
+	if b.tokens < 1 {
+		retryAfter := time.Duration((1 - b.tokens) / rate * float64(time.Second))
+		return false, int(b.tokens), retryAfter
+	}
+
+	b.tokens--
+	return true, int(b.tokens), 0
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// rateLimitScript atomically refills and takes one token from a bucket
+// stored as a Redis hash ({tokens, last_seen_ms}), so RateLimiter can be
+// shared across multiple server instances without a request race
+// letting more than burst requests through at once.
+const rateLimitScript = `
+local key = KEYS[1]
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local bucket = redis.call("HMGET", key, "tokens", "last_seen")
+local tokens = tonumber(bucket[1])
+local lastSeen = tonumber(bucket[2])
+if tokens == nil then
+  tokens = burst
+  lastSeen = now
+end
+
+local elapsed = math.max(0, now - lastSeen) / 1000
+tokens = math.min(burst, tokens + elapsed * rate)
+
+local allowed = 0
+if tokens >= 1 then
+  allowed = 1
+  tokens = tokens - 1
+end
+
+redis.call("HMSET", key, "tokens", tokens, "last_seen", now)
+redis.call("EXPIRE", key, math.ceil(burst / rate) + 60)
+
+return {allowed, tokens}
+`
+
+// RedisRateLimitStore is a RateLimitStore shared across server
+// instances, evaluating rateLimitScript (loaded once via SCRIPT LOAD) by
+// SHA on every Allow call so the refill-and-take happens atomically in
+// Redis rather than racily across instances.
+type RedisRateLimitStore struct {
+	client *redis.Client
+	sha    string
+}
+
+// NewRedisRateLimitStore loads rateLimitScript into Redis and returns a
+// store that evaluates it by SHA on every Allow call.
+func NewRedisRateLimitStore(ctx context.Context, client *redis.Client) (*RedisRateLimitStore, error) {
+	sha, err := client.ScriptLoad(ctx, rateLimitScript).Result()
+	if err != nil {
+		return nil, fmt.Errorf("load rate limit script: %w", err)
+	}
+	return &RedisRateLimitStore{client: client, sha: sha}, nil
+}
+
+// Allow implements RateLimitStore. A Redis error fails open (allows the
+// request) rather than taking the whole API down over a cache outage.
+func (s *RedisRateLimitStore) Allow(key string, rate float64, burst int) (bool, int, time.Duration) {
+	ctx := context.Background()
+	now := time.Now().UnixMilli()
+
+	res, err := s.client.EvalSha(ctx, s.sha, []string{key}, rate, burst, now).Result()
+	if err != nil {
+		return true, burst, 0
+	}
+
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 2 {
+		return true, burst, 0
+	}
+
+	allowed, _ := values[0].(int64)
+	remaining, _ := values[1].(int64)
+
+	var retryAfter time.Duration
+	if allowed != 1 {
+		retryAfter = time.Duration((1.0 / rate) * float64(time.Second))
+	}
+	return allowed == 1, int(remaining), retryAfter
+}
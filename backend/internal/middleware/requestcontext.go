@@ -0,0 +1,70 @@
+// Package middleware holds cross-cutting Fiber middleware for the
+// backend service.
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// requestIDLocalsKey is the c.Locals key RequestContext stores the
+// request ID under.
+const requestIDLocalsKey = "request_id"
+
+// loggerLocalsKey is the c.Locals key RequestContext stores the
+// request-scoped *zap.Logger under.
+const loggerLocalsKey = "logger"
+
+// RequestIDHeader is the request/response header RequestContext reads an
+// inbound ID from and echoes its (possibly generated) ID as.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestContext assigns every request a UUIDv7 ID (so IDs sort by
+// creation time), reusing an inbound X-Request-ID header if the caller
+// already set one, and builds a child *zap.Logger carrying that ID plus
+// the request's route, method, and remote IP. Both are stashed in
+// c.Locals so handlers can pull them out and thread the logger into a
+// context.Context (via logger.WithContext) that flows down into
+// ConverterService/PDFExtractorService, giving operators one correlation
+// ID to grep across handler, service, and PDF backend log lines for a
+// single conversion.
+func RequestContext(baseLogger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(RequestIDHeader)
+		if id == "" {
+			id = uuid.Must(uuid.NewV7()).String()
+		}
+
+		child := baseLogger.With(
+			zap.String("request_id", id),
+			zap.String("route", c.Route().Path),
+			zap.String("method", c.Method()),
+			zap.String("remote_ip", c.IP()),
+		)
+
+		c.Locals(requestIDLocalsKey, id)
+		c.Locals(loggerLocalsKey, child)
+		c.Set(RequestIDHeader, id)
+
+		return c.Next()
+	}
+}
+
+// RequestIDFromLocals returns the current request's ID, or "" if
+// RequestContext hasn't run.
+func RequestIDFromLocals(c *fiber.Ctx) string {
+	id, _ := c.Locals(requestIDLocalsKey).(string)
+	return id
+}
+
+// LoggerFromLocals returns the request-scoped child logger
+// RequestContext attached, or fallback if RequestContext hasn't run
+// (e.g. a handler test that builds its own fiber.App without the full
+// middleware chain).
+func LoggerFromLocals(c *fiber.Ctx, fallback *zap.Logger) *zap.Logger {
+	if l, ok := c.Locals(loggerLocalsKey).(*zap.Logger); ok && l != nil {
+		return l
+	}
+	return fallback
+}
@@ -23,8 +23,62 @@ type ConvertRequest struct {
 	Files      []FileData `json:"files" validate:"required,min=1"`
 	MergeMode  MergeMode  `json:"mergeMode" validate:"required,oneof=separate merged"`
 	OutputName string     `json:"outputName,omitempty"`
+	// OutputFormat selects the produced file type. Empty defaults to
+	// OutputFormatPDF, so existing clients that don't send this field
+	// are unaffected.
+	OutputFormat OutputFormat `json:"outputFormat,omitempty" validate:"omitempty,oneof=pdf epub"`
+	// EnableMath opts into KaTeX rendering for `$...$`/`$$...$$` math in
+	// the source Markdown. Off by default: most documents have none, and
+	// rendering it costs an extra chromedp wait on every conversion.
+	// Ignored for OutputFormatEPUB, which has no chromedp rendering step.
+	EnableMath bool `json:"enableMath,omitempty"`
+	// EnableMermaid opts into Mermaid rendering for ```mermaid``` code
+	// fences, for the same reason EnableMath defaults off. Also ignored
+	// for OutputFormatEPUB.
+	EnableMermaid bool `json:"enableMermaid,omitempty"`
+	// Theme selects a pkg/pdf.ThemeSet entry to style the PDF body with.
+	// Empty defaults to pdf.DefaultTheme. Unrecognized names also fall
+	// back to pdf.DefaultTheme rather than failing the request, since a
+	// cosmetic miss shouldn't fail an otherwise-valid conversion. Ignored
+	// for OutputFormatEPUB.
+	Theme string `json:"theme,omitempty"`
+	// CustomCSS is appended after the theme's stylesheet, letting a
+	// caller override individual rules without forking a theme. It's
+	// run through pdf.SanitizeCustomCSS before use. Ignored for
+	// OutputFormatEPUB.
+	CustomCSS string `json:"customCSS,omitempty"`
+	// PageSize selects a pkg/pdf paper size preset, which drives both
+	// the `@page` CSS rule and the chromedp page.PrintToPDF dimensions.
+	// Empty defaults to "a4". Ignored for OutputFormatEPUB.
+	PageSize string `json:"pageSize,omitempty" validate:"omitempty,oneof=a4 letter legal"`
+	// CoverTitle, if set, renders a centered cover page before the
+	// document body, ending in a `page-break-after: always` so the body
+	// starts on its own page. CoverSubtitle, CoverAuthor, and CoverDate
+	// are ignored if CoverTitle is empty. Only applies when MergeMode is
+	// MergeModeMerged - convertSeparate's per-file PDFs have no single
+	// "front" page to put one on - and is ignored for OutputFormatEPUB.
+	CoverTitle    string `json:"coverTitle,omitempty"`
+	CoverSubtitle string `json:"coverSubtitle,omitempty"`
+	CoverAuthor   string `json:"coverAuthor,omitempty"`
+	CoverDate     string `json:"coverDate,omitempty"`
+	// IncludeTOC renders a table of contents page built from the merged
+	// document's headings, down to TOCDepth levels deep. Same MergeMode
+	// and OutputFormat restrictions as CoverTitle.
+	IncludeTOC bool `json:"includeTOC,omitempty"`
+	// TOCDepth caps how deep a heading level IncludeTOC collects (e.g. 2
+	// collects h1 and h2 only). Zero defaults to 3. Ignored if
+	// IncludeTOC is false.
+	TOCDepth int `json:"tocDepth,omitempty" validate:"omitempty,min=1,max=6"`
 }
 
+// OutputFormat selects what file type ConverterService.Convert produces.
+type OutputFormat string
+
+const (
+	OutputFormatPDF  OutputFormat = "pdf"
+	OutputFormatEPUB OutputFormat = "epub"
+)
+
 // ConvertResponse is the response for convert endpoint
 type ConvertResponse struct {
 	Success bool     `json:"success"`
@@ -32,6 +86,40 @@ type ConvertResponse struct {
 	Error   string   `json:"error,omitempty"`
 }
 
+// ProgressEvent reports a ConverterService.Convert or
+// PDFExtractorService.ExtractToMarkdownStream job's progress through one
+// stage, for the SSE /api/convert/stream and /api/pdf/extract/stream
+// endpoints. Stage is one of the ProgressStage* constants, and maps to
+// the SSE "event:" name the stream handlers write (ProgressStagePartial,
+// ProgressStageDone, and ProgressStageError map 1:1; every other stage is
+// sent as a generic "progress" event).
+type ProgressEvent struct {
+	Stage    string `json:"stage"`
+	File     string `json:"file,omitempty"`
+	Pct      int    `json:"pct"`
+	Page     int    `json:"page,omitempty"`
+	Pages    int    `json:"pages,omitempty"`
+	Markdown string `json:"markdown,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+const (
+	ProgressStageMarkdown = "markdown"
+	ProgressStageChromedp = "chromedp"
+	ProgressStageEPUB     = "epub"
+	ProgressStageMerge    = "merge"
+	ProgressStageExtract  = "extract"
+	ProgressStagePartial  = "partial"
+	ProgressStageDone     = "done"
+	ProgressStageError    = "error"
+)
+
+// ProgressReporter receives progress updates from a long-running
+// conversion or extraction job. Implementations must be safe to call from
+// any goroutine; a nil ProgressReporter is always valid and means no one
+// is listening.
+type ProgressReporter func(ProgressEvent)
+
 // MergePreviewRequest is the request for merge preview endpoint
 type MergePreviewRequest struct {
 	Files []FileData `json:"files" validate:"required,min=1"`
@@ -61,9 +149,19 @@ type ErrorResponse struct {
 
 // HealthResponse represents health check response
 type HealthResponse struct {
-	Status    string `json:"status"`
-	Version   string `json:"version"`
-	Timestamp int64  `json:"timestamp"`
+	Status    string     `json:"status"`
+	Version   string     `json:"version"`
+	Timestamp int64      `json:"timestamp"`
+	Cache     CacheStats `json:"cache"`
+}
+
+// CacheStats reports the shared LRU cache's cumulative hit/miss/eviction
+// counts and current byte occupancy, for /api/health to expose.
+type CacheStats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	Bytes     int64 `json:"bytes"`
 }
 
 // PDFExtractRequest is the request for PDF extraction
@@ -87,3 +185,23 @@ type PDFPreviewResponse struct {
 	PageCount int    `json:"pageCount"`
 	FileName  string `json:"fileName"`
 }
+
+// OutputFileInfo describes one file under Storage.OutputDir, as returned
+// by GET /api/output.
+type OutputFileInfo struct {
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	SizeHuman   string `json:"sizeHuman"`
+	ModTime     int64  `json:"modTime"`
+	MimeType    string `json:"mimeType"`
+	DownloadURL string `json:"downloadUrl"`
+}
+
+// OutputListResponse is the response for GET /api/output and
+// GET /api/output/*path.
+type OutputListResponse struct {
+	Files  []OutputFileInfo `json:"files"`
+	Total  int              `json:"total"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
+}
@@ -0,0 +1,157 @@
+// Package progress tracks in-flight ConverterService.Convert jobs so the
+// streaming endpoint can replay buffered events to a client that
+// reconnects mid-conversion (via the SSE Last-Event-ID header), not just
+// the client that's been connected since the job started.
+package progress
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/gorkem/md-to-pdf/internal/model"
+)
+
+// Event is one buffered progress update, numbered so a reconnecting
+// client can ask to resume after Seq via Last-Event-ID.
+type Event struct {
+	Seq  int
+	Data model.ProgressEvent
+}
+
+// Job buffers every event published for one Convert call and fans them
+// out to any number of live subscribers.
+type Job struct {
+	mu       sync.Mutex
+	events   []Event
+	done     bool
+	subs     map[chan Event]struct{}
+	lastSeen time.Time
+}
+
+// Publish appends an event to the job's buffer and fans it out to every
+// live subscriber. It never blocks on a slow subscriber - a full
+// subscriber channel just misses the live push and catches up on its
+// next Since() call.
+func (j *Job) Publish(e model.ProgressEvent) {
+	j.mu.Lock()
+	seq := len(j.events) + 1
+	evt := Event{Seq: seq, Data: e}
+	j.events = append(j.events, evt)
+	if e.Stage == model.ProgressStageDone || e.Stage == model.ProgressStageError {
+		j.done = true
+	}
+	subs := make([]chan Event, 0, len(j.subs))
+	for ch := range j.subs {
+		subs = append(subs, ch)
+	}
+	j.mu.Unlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- evt:
+		default:
+		}
+	}
+}
+
+// Since returns every buffered event with Seq > lastSeq, and whether the
+// job has reached a terminal (done/error) state.
+func (j *Job) Since(lastSeq int) ([]Event, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var out []Event
+	for _, e := range j.events {
+		if e.Seq > lastSeq {
+			out = append(out, e)
+		}
+	}
+	return out, j.done
+}
+
+// Subscribe registers a channel that receives events as they're
+// published. The returned func unregisters it; callers must call it
+// when they stop reading to avoid leaking the channel.
+func (j *Job) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, 16)
+	j.mu.Lock()
+	j.subs[ch] = struct{}{}
+	j.mu.Unlock()
+
+	return ch, func() {
+		j.mu.Lock()
+		delete(j.subs, ch)
+		j.mu.Unlock()
+	}
+}
+
+// Registry holds every job currently streaming or recently finished, so
+// a dropped SSE connection can reconnect to the same job by ID within
+// ttl of its last activity.
+type Registry struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+	ttl  time.Duration
+}
+
+// NewRegistry creates a Registry and starts a background goroutine that
+// evicts jobs idle for longer than ttl.
+func NewRegistry(ttl time.Duration) *Registry {
+	r := &Registry{jobs: make(map[string]*Job), ttl: ttl}
+	go r.gcLoop()
+	return r
+}
+
+func (r *Registry) gcLoop() {
+	ticker := time.NewTicker(r.ttl / 2)
+	defer ticker.Stop()
+	for range ticker.C {
+		cutoff := time.Now().Add(-r.ttl)
+		r.mu.Lock()
+		for id, j := range r.jobs {
+			j.mu.Lock()
+			idle := j.done && j.lastSeen.Before(cutoff)
+			j.mu.Unlock()
+			if idle {
+				delete(r.jobs, id)
+			}
+		}
+		r.mu.Unlock()
+	}
+}
+
+// NewJob creates and registers a new Job under a fresh ID.
+func (r *Registry) NewJob() (string, *Job) {
+	id := newJobID()
+	j := &Job{subs: make(map[chan Event]struct{}), lastSeen: time.Now()}
+
+	r.mu.Lock()
+	r.jobs[id] = j
+	r.mu.Unlock()
+
+	return id, j
+}
+
+// Get looks up a job by ID and touches its last-seen time so the GC loop
+// keeps it around while a client is actively reconnecting to it.
+func (r *Registry) Get(id string) (*Job, bool) {
+	r.mu.Lock()
+	j, ok := r.jobs[id]
+	r.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	j.mu.Lock()
+	j.lastSeen = time.Now()
+	j.mu.Unlock()
+	return j, true
+}
+
+func newJobID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -11,9 +11,14 @@ import (
 
 	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
+	"github.com/gorkem/md-to-pdf/internal/cache"
 	"github.com/gorkem/md-to-pdf/internal/config"
+	"github.com/gorkem/md-to-pdf/internal/logger"
 	"github.com/gorkem/md-to-pdf/internal/model"
+	"github.com/gorkem/md-to-pdf/pkg/epub"
+	"github.com/gorkem/md-to-pdf/pkg/filecache"
 	"github.com/gorkem/md-to-pdf/pkg/pdf"
+	"github.com/gorkem/md-to-pdf/pkg/pdf/browserpool"
 	"go.uber.org/zap"
 )
 
@@ -21,18 +26,72 @@ import (
 type ConverterService struct {
 	cfg      *config.Config
 	markdown *MarkdownService
-	template *pdf.TemplateGenerator
+	cache    *cache.Cache
 	logger   *zap.Logger
+	browsers *browserpool.Pool
+	themes   *pdf.ThemeSet
+	pdfCache *filecache.Cache
 }
 
-// NewConverterService creates a new converter service instance
-func NewConverterService(cfg *config.Config, markdown *MarkdownService, logger *zap.Logger) *ConverterService {
-	return &ConverterService{
+// ConverterOption configures a ConverterService at construction time.
+type ConverterOption func(*ConverterService)
+
+// WithBrowserPool makes generateWithChromedp run every print through
+// pool's long-lived browsers instead of starting a fresh ExecAllocator
+// per conversion. Without this option, ConverterService falls back to
+// its original per-call allocator - slower under load, but keeps
+// ConverterService usable (e.g. in tests) without standing up a pool.
+func WithBrowserPool(pool *browserpool.Pool) ConverterOption {
+	return func(s *ConverterService) {
+		s.browsers = pool
+	}
+}
+
+// WithThemeSet makes ConvertRequest.Theme resolve against set instead of
+// a plain pdf.NewThemeSet() containing only the builtin themes - e.g. a
+// set that's had pkg/pdf.ThemeSet.Load called against an operator-
+// configured directory of custom .css files.
+func WithThemeSet(set *pdf.ThemeSet) ConverterOption {
+	return func(s *ConverterService) {
+		s.themes = set
+	}
+}
+
+// WithPDFCache makes generatePDF look up (and populate) cache for the
+// final rendered PDF bytes, short-circuiting the chromedp render
+// entirely on a hit. Without this option, ConverterService renders
+// every request from scratch, same as before this cache existed.
+func WithPDFCache(cache *filecache.Cache) ConverterOption {
+	return func(s *ConverterService) {
+		s.pdfCache = cache
+	}
+}
+
+// NewConverterService creates a new converter service instance. cache is
+// shared with MarkdownService and PDFExtractorService so repeated
+// conversions of the same markdown skip HTML rendering.
+func NewConverterService(cfg *config.Config, markdown *MarkdownService, cache *cache.Cache, logger *zap.Logger, opts ...ConverterOption) *ConverterService {
+	s := &ConverterService{
 		cfg:      cfg,
 		markdown: markdown,
-		template: pdf.NewTemplateGenerator(),
+		cache:    cache,
 		logger:   logger,
+		themes:   pdf.NewThemeSet(),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// BrowserPoolMetrics returns the browser pool's current metrics and
+// true, or a zero Metrics and false if this ConverterService was built
+// without WithBrowserPool.
+func (s *ConverterService) BrowserPoolMetrics() (browserpool.Metrics, bool) {
+	if s.browsers == nil {
+		return browserpool.Metrics{}, false
+	}
+	return s.browsers.Metrics(), true
 }
 
 // ConvertResult represents the result of a conversion operation
@@ -42,8 +101,68 @@ type ConvertResult struct {
 	Error    error
 }
 
-// Convert handles the conversion of files based on merge mode
-func (s *ConverterService) Convert(ctx context.Context, req *model.ConvertRequest) (*model.ConvertResponse, error) {
+// styleOptions bundles the resolved theme CSS, sanitized custom CSS, and
+// page size a generatePDF call wraps its HTML in - resolved once per
+// Convert call rather than per file, since they don't vary across a
+// merge mode's files the way ProgressEvent.File does.
+type styleOptions struct {
+	themeName string
+	themeCSS  string
+	customCSS string
+	page      pdf.PageOptions
+	cover     pdf.CoverOptions
+	tocDepth  int
+}
+
+// defaultTOCDepth is the heading depth IncludeTOC collects down to when
+// a request sets IncludeTOC but leaves TOCDepth unset.
+const defaultTOCDepth = 3
+
+// resolveStyleOptions looks req.Theme up in s.themes (falling back to
+// pdf.DefaultTheme for an empty or unrecognized name), sanitizes
+// req.CustomCSS, and - only for MergeModeMerged, since a per-file PDF in
+// separate mode has no single "front" page for either - resolves the
+// cover page and table of contents options.
+func (s *ConverterService) resolveStyleOptions(req *model.ConvertRequest) styleOptions {
+	name := req.Theme
+	css, ok := s.themes.CSS(name)
+	if !ok {
+		name = pdf.DefaultTheme
+		css, _ = s.themes.CSS(pdf.DefaultTheme)
+	}
+
+	style := styleOptions{
+		themeName: name,
+		themeCSS:  css,
+		customCSS: pdf.SanitizeCustomCSS(req.CustomCSS),
+		page:      pdf.PageOptionsForSize(req.PageSize),
+	}
+
+	if req.MergeMode == model.MergeModeMerged {
+		style.cover = pdf.CoverOptions{
+			Title:    req.CoverTitle,
+			Subtitle: req.CoverSubtitle,
+			Author:   req.CoverAuthor,
+			Date:     req.CoverDate,
+		}
+		if req.IncludeTOC {
+			style.tocDepth = req.TOCDepth
+			if style.tocDepth == 0 {
+				style.tocDepth = defaultTOCDepth
+			}
+		}
+	}
+
+	return style
+}
+
+// Convert handles the conversion of files based on merge mode. progress,
+// if non-nil, is called with a ProgressEvent as each stage of each file
+// completes; the synchronous /api/convert endpoint passes nil and just
+// waits for the final result, while /api/convert/stream passes a
+// reporter that publishes to the job subscribers watch as Server-Sent
+// Events.
+func (s *ConverterService) Convert(ctx context.Context, req *model.ConvertRequest, progress model.ProgressReporter) (*model.ConvertResponse, error) {
 	timestamp := time.Now().Unix()
 	var results []ConvertResult
 
@@ -54,12 +173,23 @@ func (s *ConverterService) Convert(ctx context.Context, req *model.ConvertReques
 		return sortedFiles[i].Order < sortedFiles[j].Order
 	})
 
+	if (req.EnableMath || req.EnableMermaid) && !pdf.AssetsVendored {
+		return nil, pdf.ErrAssetsNotVendored
+	}
+
+	renderOpts := pdf.RenderOptions{EnableMath: req.EnableMath, EnableMermaid: req.EnableMermaid}
+	style := s.resolveStyleOptions(req)
+	format := req.OutputFormat
+	if format == "" {
+		format = model.OutputFormatPDF
+	}
+
 	switch req.MergeMode {
 	case model.MergeModeMerged:
-		result := s.convertMerged(ctx, sortedFiles, req.OutputName, timestamp)
+		result := s.convertMerged(ctx, sortedFiles, req.OutputName, timestamp, format, renderOpts, style, progress)
 		results = append(results, result)
 	case model.MergeModeSeparate:
-		results = s.convertSeparate(ctx, sortedFiles, timestamp)
+		results = s.convertSeparate(ctx, sortedFiles, timestamp, format, renderOpts, style, progress)
 	default:
 		return nil, fmt.Errorf("invalid merge mode: %s", req.MergeMode)
 	}
@@ -71,13 +201,18 @@ func (s *ConverterService) Convert(ctx context.Context, req *model.ConvertReques
 	for _, result := range results {
 		if result.Error != nil {
 			errors = append(errors, fmt.Sprintf("%s: %v", result.FileName, result.Error))
-			s.logger.Error("conversion failed",
+			logger.Ctx(ctx, s.logger).Error("conversion failed",
 				zap.String("file", result.FileName),
 				zap.Error(result.Error),
 			)
+			publishProgress(progress, model.ProgressEvent{
+				Stage: model.ProgressStageError,
+				File:  result.FileName,
+				Error: result.Error.Error(),
+			})
 		} else {
 			outputFiles = append(outputFiles, result.FilePath)
-			s.logger.Info("conversion successful",
+			logger.Ctx(ctx, s.logger).Info("conversion successful",
 				zap.String("file", result.FileName),
 				zap.String("path", result.FilePath),
 			)
@@ -91,38 +226,57 @@ func (s *ConverterService) Convert(ctx context.Context, req *model.ConvertReques
 		}, nil
 	}
 
+	publishProgress(progress, model.ProgressEvent{Stage: model.ProgressStageDone, Pct: 100})
+
 	return &model.ConvertResponse{
 		Success: true,
 		Files:   outputFiles,
 	}, nil
 }
 
-func (s *ConverterService) convertMerged(ctx context.Context, files []model.FileData, outputName string, timestamp int64) ConvertResult {
-	mergedContent := s.markdown.MergeFiles(files)
+func (s *ConverterService) convertMerged(ctx context.Context, files []model.FileData, outputName string, timestamp int64, format model.OutputFormat, renderOpts pdf.RenderOptions, style styleOptions, progress model.ProgressReporter) ConvertResult {
+	publishProgress(progress, model.ProgressEvent{Stage: model.ProgressStageMerge, Pct: 5})
 
 	if outputName == "" {
 		outputName = fmt.Sprintf("merged_%d", timestamp)
 	}
 	outputName = sanitizeFilename(outputName)
 
-	pdfPath, err := s.generatePDF(ctx, mergedContent, outputName)
+	var path string
+	var err error
+	if format == model.OutputFormatEPUB {
+		// One EPUB, one chapter per file - unlike PDF's merged mode,
+		// the files' Markdown source is never concatenated first.
+		path, err = s.generateEPUB(ctx, files, outputName, progress)
+	} else {
+		mergedContent := s.markdown.MergeFiles(files)
+		path, err = s.generatePDF(ctx, mergedContent, outputName, renderOpts, style, progress)
+	}
+
 	return ConvertResult{
-		FilePath: pdfPath,
+		FilePath: path,
 		FileName: outputName,
 		Error:    err,
 	}
 }
 
-func (s *ConverterService) convertSeparate(ctx context.Context, files []model.FileData, timestamp int64) []ConvertResult {
+func (s *ConverterService) convertSeparate(ctx context.Context, files []model.FileData, timestamp int64, format model.OutputFormat, renderOpts pdf.RenderOptions, style styleOptions, progress model.ProgressReporter) []ConvertResult {
 	results := make([]ConvertResult, len(files))
 
 	for i, file := range files {
 		baseName := strings.TrimSuffix(file.Name, filepath.Ext(file.Name))
 		outputName := fmt.Sprintf("%s_%d", sanitizeFilename(baseName), timestamp)
 
-		pdfPath, err := s.generatePDF(ctx, file.Content, outputName)
+		var path string
+		var err error
+		if format == model.OutputFormatEPUB {
+			path, err = s.generateEPUB(ctx, []model.FileData{file}, outputName, progress)
+		} else {
+			path, err = s.generatePDF(ctx, file.Content, outputName, renderOpts, style, progress)
+		}
+
 		results[i] = ConvertResult{
-			FilePath: pdfPath,
+			FilePath: path,
 			FileName: file.Name,
 			Error:    err,
 		}
@@ -131,25 +285,48 @@ func (s *ConverterService) convertSeparate(ctx context.Context, files []model.Fi
 	return results
 }
 
-func (s *ConverterService) generatePDF(ctx context.Context, mdContent, outputName string) (string, error) {
-	htmlContent, err := s.markdown.ToHTML(mdContent)
+// generateEPUB builds a single EPUB spanning files (one chapter per
+// file, in order) and writes it to cfg.Storage.OutputDir. Unlike
+// generatePDF, it skips the HTML-cache and chromedp steps entirely -
+// epub.Build renders each chapter straight from Markdown and zips the
+// result in pure Go, with no headless-browser round trip.
+func (s *ConverterService) generateEPUB(ctx context.Context, files []model.FileData, outputName string, progress model.ProgressReporter) (string, error) {
+	publishProgress(progress, model.ProgressEvent{Stage: model.ProgressStageMarkdown, File: outputName, Pct: 10})
+
+	publishProgress(progress, model.ProgressEvent{Stage: model.ProgressStageEPUB, File: outputName, Pct: 50})
+	data, err := epub.Build(files, s.markdown.ToHTML)
 	if err != nil {
-		return "", fmt.Errorf("markdown conversion failed: %w", err)
+		return "", fmt.Errorf("EPUB generation failed: %w", err)
 	}
 
-	fullHTML := s.template.Generate(htmlContent)
-
-	// Write HTML to temp file
-	tempHTMLPath := filepath.Join(s.cfg.Storage.TempDir, outputName+".html")
-	absHTMLPath, err := filepath.Abs(tempHTMLPath)
+	outputPath := filepath.Join(s.cfg.Storage.OutputDir, outputName+".epub")
+	absOutputPath, err := filepath.Abs(outputPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to get absolute path: %w", err)
+		return "", fmt.Errorf("failed to get output path: %w", err)
 	}
 
-	if err := os.WriteFile(absHTMLPath, []byte(fullHTML), 0644); err != nil {
-		return "", fmt.Errorf("failed to write temp file: %w", err)
+	if err := os.WriteFile(absOutputPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write EPUB file: %w", err)
 	}
-	defer os.Remove(absHTMLPath)
+
+	return "/output/" + outputName + ".epub", nil
+}
+
+func (s *ConverterService) generatePDF(ctx context.Context, mdContent, outputName string, renderOpts pdf.RenderOptions, style styleOptions, progress model.ProgressReporter) (string, error) {
+	publishProgress(progress, model.ProgressEvent{Stage: model.ProgressStageMarkdown, File: outputName, Pct: 10})
+
+	key := cache.Key(mdContent, renderOpts)
+	cached, err := s.cache.GetOrCreate(key, func() (any, int64, error) {
+		html, err := s.markdown.ToHTML(mdContent)
+		if err != nil {
+			return nil, 0, fmt.Errorf("markdown conversion failed: %w", err)
+		}
+		return html, int64(len(html)), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	htmlContent := cached.(string)
 
 	outputPath := filepath.Join(s.cfg.Storage.OutputDir, outputName+".pdf")
 	absOutputPath, err := filepath.Abs(outputPath)
@@ -157,68 +334,160 @@ func (s *ConverterService) generatePDF(ctx context.Context, mdContent, outputNam
 		return "", fmt.Errorf("failed to get output path: %w", err)
 	}
 
-	// Use chromedp for PDF generation
-	if err := s.generateWithChromedp(ctx, absHTMLPath, absOutputPath); err != nil {
-		s.logger.Error("chromedp PDF generation failed", zap.Error(err))
-		return "", fmt.Errorf("PDF generation failed: %w", err)
+	render := func() ([]byte, error) {
+		return s.renderPDF(ctx, htmlContent, outputName, renderOpts, style, progress)
+	}
+
+	var pdfBytes []byte
+	if s.pdfCache != nil {
+		pdfBytes, err = s.pdfCache.GetOrCreate(s.pdfCacheKey(mdContent, renderOpts, style), outputName+".pdf", render)
+	} else {
+		pdfBytes, err = render()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(absOutputPath, pdfBytes, 0644); err != nil {
+		return "", fmt.Errorf("failed to write PDF file: %w", err)
 	}
 
 	return "/output/" + outputName + ".pdf", nil
 }
 
-func (s *ConverterService) generateWithChromedp(ctx context.Context, htmlPath, outputPath string) error {
-	// Create chromedp context with options
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.Flag("headless", true),
-		chromedp.Flag("disable-gpu", true),
-		chromedp.Flag("no-sandbox", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.Flag("disable-software-rasterizer", true),
-	)
+// renderPDF wraps htmlContent in the full print document and renders it
+// to PDF bytes via chromedp. It's the expensive path s.pdfCache.
+// GetOrCreate short-circuits on a cache hit.
+func (s *ConverterService) renderPDF(ctx context.Context, htmlContent, outputName string, renderOpts pdf.RenderOptions, style styleOptions, progress model.ProgressReporter) ([]byte, error) {
+	fullHTML := pdf.NewTemplateGenerator(
+		pdf.WithRenderOptions(renderOpts),
+		pdf.WithTheme(style.themeCSS),
+		pdf.WithCustomCSS(style.customCSS),
+		pdf.WithPageOptions(style.page),
+		pdf.WithCover(style.cover),
+		pdf.WithTOC(style.tocDepth),
+	).Generate(htmlContent)
 
-	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, opts...)
-	defer allocCancel()
+	tempHTMLPath := filepath.Join(s.cfg.Storage.TempDir, outputName+".html")
+	absHTMLPath, err := filepath.Abs(tempHTMLPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
 
-	taskCtx, taskCancel := chromedp.NewContext(allocCtx)
-	defer taskCancel()
+	if err := os.WriteFile(absHTMLPath, []byte(fullHTML), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+	defer os.Remove(absHTMLPath)
 
-	// Set timeout
-	taskCtx, cancel := context.WithTimeout(taskCtx, 60*time.Second)
-	defer cancel()
+	publishProgress(progress, model.ProgressEvent{Stage: model.ProgressStageChromedp, File: outputName, Pct: 50})
+
+	waitForRender := renderOpts.EnableMath || renderOpts.EnableMermaid
+	pdfBytes, err := s.generateWithChromedp(ctx, absHTMLPath, waitForRender, style.page)
+	if err != nil {
+		logger.Ctx(ctx, s.logger).Error("chromedp PDF generation failed", zap.Error(err))
+		return nil, fmt.Errorf("PDF generation failed: %w", err)
+	}
+	return pdfBytes, nil
+}
+
+// pdfCacheKey hashes every input that affects renderPDF's output bytes:
+// normalized Markdown content, theme name, custom CSS, the template
+// version (see pdf.TemplateVersion), and paper/margin settings. Math and
+// Mermaid rendering flags are folded in too - a hit produced without
+// them would silently ship plain-text math or diagrams for a request
+// that asked to render them. The cover page and TOC settings are folded
+// in for the same reason: two requests for the same Markdown but only
+// one asking for a cover/TOC must not collide on the same cached PDF.
+func (s *ConverterService) pdfCacheKey(mdContent string, renderOpts pdf.RenderOptions, style styleOptions) string {
+	return filecache.Key(
+		mdContent,
+		style.themeName,
+		style.customCSS,
+		pdf.TemplateVersion,
+		fmt.Sprintf("%.2fx%.2fin margins %.2f,%.2f,%.2f,%.2f", style.page.WidthIn, style.page.HeightIn, style.page.MarginTopIn, style.page.MarginRightIn, style.page.MarginBottomIn, style.page.MarginLeftIn),
+		fmt.Sprintf("math=%t mermaid=%t", renderOpts.EnableMath, renderOpts.EnableMermaid),
+		fmt.Sprintf("cover=%s|%s|%s|%s toc=%d", style.cover.Title, style.cover.Subtitle, style.cover.Author, style.cover.Date, style.tocDepth),
+	)
+}
 
-	// Navigate to the HTML file and generate PDF
+// publishProgress calls progress with e if the caller passed a reporter.
+func publishProgress(progress model.ProgressReporter, e model.ProgressEvent) {
+	if progress == nil {
+		return
+	}
+	progress(e)
+}
+
+// generateWithChromedp renders htmlPath to PDF bytes. When waitForRender
+// is set (the HTML's head has KaTeX and/or Mermaid's render initializer
+// injected by TemplateGenerator), it waits for the sentinel element that
+// initializer appends to document.body once mermaid.run() and
+// renderMathInElement() have both resolved, so PrintToPDF doesn't race
+// the async rendering and ship plain-text math or diagrams.
+func (s *ConverterService) generateWithChromedp(ctx context.Context, htmlPath string, waitForRender bool, pageOpts pdf.PageOptions) ([]byte, error) {
 	var pdfBuf []byte
 
 	fileURL := "file://" + htmlPath
 
-	if err := chromedp.Run(taskCtx,
+	actions := []chromedp.Action{
 		chromedp.Navigate(fileURL),
 		chromedp.WaitReady("body"),
+	}
+	if waitForRender {
+		actions = append(actions, chromedp.WaitVisible("#"+pdf.RenderCompleteSentinelID, chromedp.ByID))
+	}
+	actions = append(actions,
 		chromedp.ActionFunc(func(ctx context.Context) error {
 			var err error
 			pdfBuf, _, err = page.PrintToPDF().
-				WithPaperWidth(8.27).      // A4 width in inches
-				WithPaperHeight(11.69).    // A4 height in inches
-				WithMarginTop(0.79).       // 20mm in inches
-				WithMarginBottom(0.79).    // 20mm in inches
-				WithMarginLeft(0.79).      // 20mm in inches
-				WithMarginRight(0.79).     // 20mm in inches
+				WithPaperWidth(pageOpts.WidthIn).
+				WithPaperHeight(pageOpts.HeightIn).
+				WithMarginTop(pageOpts.MarginTopIn).
+				WithMarginBottom(pageOpts.MarginBottomIn).
+				WithMarginLeft(pageOpts.MarginLeftIn).
+				WithMarginRight(pageOpts.MarginRightIn).
 				WithPrintBackground(true). // Print background colors/images
 				WithScale(1.0).
 				WithPreferCSSPageSize(false). // Use our paper size, not CSS
 				Do(ctx)
 			return err
 		}),
-	); err != nil {
-		return fmt.Errorf("chromedp execution failed: %w", err)
-	}
+	)
 
-	// Write PDF to file
-	if err := os.WriteFile(outputPath, pdfBuf, 0644); err != nil {
-		return fmt.Errorf("failed to write PDF file: %w", err)
+	if s.browsers != nil {
+		if err := s.browsers.Run(ctx, actions...); err != nil {
+			return nil, fmt.Errorf("chromedp execution failed: %w", err)
+		}
+	} else if err := s.runStandaloneChromedp(ctx, actions...); err != nil {
+		return nil, fmt.Errorf("chromedp execution failed: %w", err)
 	}
 
-	return nil
+	return pdfBuf, nil
+}
+
+// runStandaloneChromedp runs actions against a freshly started browser
+// that's torn down again once they finish - the pre-pool behavior, kept
+// as a fallback for ConverterServices built without WithBrowserPool
+// (e.g. in tests, which don't want to pay for standing up a pool).
+func (s *ConverterService) runStandaloneChromedp(ctx context.Context, actions ...chromedp.Action) error {
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("headless", true),
+		chromedp.Flag("disable-gpu", true),
+		chromedp.Flag("no-sandbox", true),
+		chromedp.Flag("disable-dev-shm-usage", true),
+		chromedp.Flag("disable-software-rasterizer", true),
+	)
+
+	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, opts...)
+	defer allocCancel()
+
+	taskCtx, taskCancel := chromedp.NewContext(allocCtx)
+	defer taskCancel()
+
+	taskCtx, cancel := context.WithTimeout(taskCtx, 60*time.Second)
+	defer cancel()
+
+	return chromedp.Run(taskCtx, actions...)
 }
 
 func sanitizeFilename(name string) string {
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	stdhtml "html"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gorkem/md-to-pdf/internal/model"
+	"github.com/gorkem/md-to-pdf/pkg/diagrams"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// MarkdownService converts Markdown to HTML via goldmark. ConverterService,
+// PDFExtractorService, and pkg/epub's Build all render through it rather
+// than embedding their own goldmark instance, so every output format
+// shares one rendering pipeline.
+type MarkdownService struct {
+	md goldmark.Markdown
+}
+
+// MarkdownOption configures a MarkdownService at construction time.
+type MarkdownOption func(*markdownConfig)
+
+type markdownConfig struct {
+	diagrams diagrams.Renderer
+}
+
+// WithDiagramRenderer makes ToHTML render PlantUML/Graphviz/BPMN fenced
+// code blocks (see diagrams.Supported) to an inline <figure><img> via r,
+// instead of leaving them as plain <pre><code> like any other fenced
+// block. A block whose language r doesn't support, or whose render
+// fails or times out, still falls back to <pre><code> - r is consulted
+// per block, not required to succeed for all of them. Without this
+// option - e.g. when config.DiagramsConfig.Enabled is false - no fenced
+// code block is ever treated specially.
+func WithDiagramRenderer(r diagrams.Renderer) MarkdownOption {
+	return func(c *markdownConfig) {
+		c.diagrams = r
+	}
+}
+
+// NewMarkdownService creates a new markdown service instance.
+func NewMarkdownService(opts ...MarkdownOption) *MarkdownService {
+	var cfg markdownConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	parserOpts := []parser.Option{parser.WithAutoHeadingID()}
+	rendererOpts := []renderer.Option{
+		html.WithHardWraps(),
+		html.WithXHTML(),
+		html.WithUnsafe(),
+	}
+	if cfg.diagrams != nil {
+		transformer := &diagramTransformer{
+			renderer: cfg.diagrams,
+			sem:      make(chan struct{}, maxConcurrentDiagramRenders),
+		}
+		parserOpts = append(parserOpts,
+			parser.WithASTTransformers(util.Prioritized(transformer, 500)))
+		rendererOpts = append(rendererOpts,
+			renderer.WithNodeRenderers(util.Prioritized(&diagramHTMLRenderer{}, 500)))
+	}
+
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.Table,
+			extension.Strikethrough,
+			extension.TaskList,
+			extension.Footnote,
+			extension.DefinitionList,
+		),
+		goldmark.WithParserOptions(parserOpts...),
+		goldmark.WithRendererOptions(rendererOpts...),
+	)
+
+	return &MarkdownService{md: md}
+}
+
+// ToHTML converts markdown content to HTML
+func (s *MarkdownService) ToHTML(content string) (string, error) {
+	var buf bytes.Buffer
+	if err := s.md.Convert([]byte(content), &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// MergeFiles merges multiple files into a single content string
+// Files are sorted by their Order field
+func (s *MarkdownService) MergeFiles(files []model.FileData) string {
+	if len(files) == 0 {
+		return ""
+	}
+
+	// Sort files by order
+	sortedFiles := make([]model.FileData, len(files))
+	copy(sortedFiles, files)
+	sort.Slice(sortedFiles, func(i, j int) bool {
+		return sortedFiles[i].Order < sortedFiles[j].Order
+	})
+
+	var builder strings.Builder
+	for i, file := range sortedFiles {
+		if i > 0 {
+			// Add page break marker and separator between files
+			builder.WriteString("\n\n---\n\n")
+		}
+		builder.WriteString(file.Content)
+	}
+
+	return builder.String()
+}
+
+// MergeFilesToHTML merges files and converts to HTML with file separators
+func (s *MarkdownService) MergeFilesToHTML(files []model.FileData) (string, error) {
+	if len(files) == 0 {
+		return "", nil
+	}
+
+	// Sort files by order
+	sortedFiles := make([]model.FileData, len(files))
+	copy(sortedFiles, files)
+	sort.Slice(sortedFiles, func(i, j int) bool {
+		return sortedFiles[i].Order < sortedFiles[j].Order
+	})
+
+	var builder strings.Builder
+	for i, file := range sortedFiles {
+		escapedName := stdhtml.EscapeString(file.Name)
+		if i > 0 {
+			builder.WriteString(`<div class="file-separator"><span>`)
+			builder.WriteString(escapedName)
+			builder.WriteString(`</span></div>`)
+		} else {
+			builder.WriteString(`<div class="file-header"><span>`)
+			builder.WriteString(escapedName)
+			builder.WriteString(`</span></div>`)
+		}
+
+		html, err := s.ToHTML(file.Content)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(`<div class="file-content">`)
+		builder.WriteString(html)
+		builder.WriteString(`</div>`)
+	}
+
+	return builder.String(), nil
+}
+
+// EstimatePageCount estimates the number of PDF pages based on content
+func (s *MarkdownService) EstimatePageCount(content string) int {
+	// Rough estimation: ~3000 characters per page
+	const charsPerPage = 3000
+	pages := len(content) / charsPerPage
+	if pages < 1 {
+		return 1
+	}
+	return pages
+}
+
+// diagramKind is the ast.NodeKind diagramTransformer substitutes in for
+// a fenced code block it successfully rendered.
+var diagramKind = ast.NewNodeKind("Diagram")
+
+// diagramNode holds one diagram's already-rendered SVG, replacing the
+// ast.FencedCodeBlock diagramTransformer matched it from. It carries no
+// source text, so it renders the same regardless of how it was reached.
+type diagramNode struct {
+	ast.BaseBlock
+	svg []byte
+}
+
+func (n *diagramNode) Kind() ast.NodeKind { return diagramKind }
+
+func (n *diagramNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// diagramTransformer is a goldmark parser.ASTTransformer that swaps each
+// fenced code block written in a diagrams.Supported language for a
+// diagramNode holding the SVG renderer produced for it. A block whose
+// language isn't supported, or whose render errors or times out, is
+// left as an ordinary ast.FencedCodeBlock, rendering as goldmark's
+// default <pre><code> like any other fenced block. One diagramTransformer
+// is built per MarkdownService and reused by every Transform call the
+// service's goldmark instance makes, so sem bounds concurrent Render
+// calls across all of that service's in-flight conversions, not just
+// the diagrams in a single document.
+type diagramTransformer struct {
+	renderer diagrams.Renderer
+	sem      chan struct{}
+}
+
+// maxConcurrentDiagramRenders bounds how many Render calls a
+// diagramTransformer issues at once, the same way browserpool.Config.Size
+// bounds concurrent chromedp workers - so a server handling several
+// diagram-heavy conversions at once can't open unbounded simultaneous
+// connections to the configured Kroki server.
+const maxConcurrentDiagramRenders = 8
+
+// diagramRenderKey identifies one distinct diagram to render: its
+// fenced-code-block language plus its source text.
+type diagramRenderKey struct {
+	lang, source string
+}
+
+func (t *diagramTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	var blocks []*ast.FencedCodeBlock
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			if block, ok := n.(*ast.FencedCodeBlock); ok {
+				blocks = append(blocks, block)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	source := reader.Source()
+
+	// Group blocks by (lang, source) so a diagram repeated within the
+	// same document is only ever rendered once here - rendering every
+	// occurrence concurrently would otherwise race past
+	// diagrams.CachingRenderer's dedup cache, since all of them could
+	// miss it before any had written its result back.
+	blockIndices := make(map[diagramRenderKey][]int)
+	for i, block := range blocks {
+		lang := string(block.Language(source))
+		if !diagrams.Supported(lang) {
+			continue
+		}
+
+		var buf bytes.Buffer
+		lines := block.Lines()
+		for j := 0; j < lines.Len(); j++ {
+			buf.Write(lines.At(j).Value(source))
+		}
+
+		// Normalize lang's case for the dedup key - diagrams.Supported
+		// and KrokiRenderer.Render both already lowercase it internally,
+		// so "PlantUML" and "plantuml" resolve to the same Kroki request
+		// and should dedupe as the same diagram here too.
+		key := diagramRenderKey{lang: strings.ToLower(lang), source: buf.String()}
+		blockIndices[key] = append(blockIndices[key], i)
+	}
+
+	// Render each distinct diagram concurrently, bounded by t.sem, then
+	// mutate the AST only after every render has finished - nothing here
+	// touches doc concurrently.
+	svgs := make([][]byte, len(blocks))
+	var wg sync.WaitGroup
+	for key, indices := range blockIndices {
+		wg.Add(1)
+		go func(key diagramRenderKey, indices []int) {
+			defer wg.Done()
+			t.sem <- struct{}{}
+			defer func() { <-t.sem }()
+
+			svg, mimeType, err := t.renderer.Render(context.Background(), key.lang, key.source)
+			if err != nil || mimeType != "image/svg+xml" {
+				return
+			}
+			for _, i := range indices {
+				svgs[i] = svg
+			}
+		}(key, indices)
+	}
+	wg.Wait()
+
+	for i, block := range blocks {
+		if svgs[i] == nil {
+			continue
+		}
+		parent := block.Parent()
+		if parent == nil {
+			continue
+		}
+		parent.ReplaceChild(parent, block, &diagramNode{svg: svgs[i]})
+	}
+}
+
+// diagramHTMLRenderer renders a diagramNode as an <img> whose src is a
+// base64 data: URI holding the rendered SVG, rather than inlining the SVG
+// markup directly into the page.
+type diagramHTMLRenderer struct{}
+
+func (r *diagramHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(diagramKind, r.renderDiagram)
+}
+
+// renderDiagram writes node's SVG as a data: URI <img> src rather than
+// inline <svg> markup. Chromium (and browsers generally) treat an SVG
+// loaded as an image - via <img>, CSS background-image, etc. - as a
+// static raster-like image: it never executes <script>, event-handler
+// attributes, or any other embedded-HTML vector (foreignObject, iframe,
+// ...) that inline <svg> markup would. Since the SVG comes from a network
+// service (diagrams.Config.KrokiURL) rather than the document's own
+// author, and is later loaded into the headless Chromium instance
+// browserpool uses to print the PDF, this closes the whole class of
+// markup-based injection a compromised or malicious Kroki server could
+// otherwise attempt, rather than blacklisting individual tags/attributes.
+func (r *diagramHTMLRenderer) renderDiagram(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	node := n.(*diagramNode)
+	_, _ = w.WriteString(`<figure class="docflow-diagram"><img alt="diagram" src="data:image/svg+xml;base64,`)
+	_, _ = w.WriteString(base64.StdEncoding.EncodeToString(node.svg))
+	_, _ = w.WriteString(`"></figure>` + "\n")
+	return ast.WalkSkipChildren, nil
+}
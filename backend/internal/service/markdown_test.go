@@ -1,12 +1,46 @@
 package service
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/gorkem/md-to-pdf/internal/model"
 )
 
+// stubDiagramRenderer is a diagrams.Renderer test double that renders any
+// language in rendered to a fixed SVG body, and reports every other
+// language unsupported - so tests can exercise both the render-hit and
+// render-miss paths through WithDiagramRenderer without a real Kroki server.
+// calls counts how many times Render was invoked, so tests can assert a
+// diagram repeated in one document is only rendered once.
+type stubDiagramRenderer struct {
+	rendered map[string]string
+	calls    int64
+	mu       sync.Mutex
+	seen     map[string]int
+}
+
+func (r *stubDiagramRenderer) Render(ctx context.Context, lang, source string) ([]byte, string, error) {
+	atomic.AddInt64(&r.calls, 1)
+	r.mu.Lock()
+	if r.seen == nil {
+		r.seen = make(map[string]int)
+	}
+	r.seen[lang+"\x00"+source]++
+	r.mu.Unlock()
+
+	svg, ok := r.rendered[lang]
+	if !ok {
+		return nil, "", errors.New("stub: unsupported language")
+	}
+	return []byte(svg), "image/svg+xml", nil
+}
+
 func TestNewMarkdownService(t *testing.T) {
 	svc := NewMarkdownService()
 	if svc == nil {
@@ -229,6 +263,26 @@ func TestMergeFilesToHTML(t *testing.T) {
 	}
 }
 
+func TestMergeFilesToHTMLEscapesFileName(t *testing.T) {
+	svc := NewMarkdownService()
+
+	files := []model.FileData{
+		{ID: "1", Name: `<script>alert(1)</script>`, Content: "# Doc", Order: 0},
+	}
+
+	result, err := svc.MergeFilesToHTML(files)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(result, "<script>") {
+		t.Errorf("expected file name to be HTML-escaped, got: %s", result)
+	}
+	if !strings.Contains(result, "&lt;script&gt;") {
+		t.Errorf("expected escaped file name in output, got: %s", result)
+	}
+}
+
 func TestEstimatePageCount(t *testing.T) {
 	svc := NewMarkdownService()
 
@@ -277,3 +331,139 @@ func TestEstimatePageCount(t *testing.T) {
 		})
 	}
 }
+
+// diagramImgSrcPrefix is the fixed prefix diagramHTMLRenderer writes
+// before a rendered diagram's base64-encoded SVG.
+const diagramImgSrcPrefix = `<img alt="diagram" src="data:image/svg+xml;base64,`
+
+func TestToHTMLWithDiagramRenderer(t *testing.T) {
+	stub := &stubDiagramRenderer{rendered: map[string]string{
+		"plantuml": "<svg>plantuml diagram</svg>",
+	}}
+	svc := NewMarkdownService(WithDiagramRenderer(stub))
+
+	result, err := svc.ToHTML("# Title\n\n```plantuml\nA -> B\n```\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(result, `<figure class="docflow-diagram">`) {
+		t.Errorf("expected rendered diagram figure, got: %s", result)
+	}
+	if !strings.Contains(result, base64.StdEncoding.EncodeToString([]byte("<svg>plantuml diagram</svg>"))) {
+		t.Errorf("expected the base64-encoded SVG body, got: %s", result)
+	}
+	if strings.Contains(result, "<pre>") {
+		t.Errorf("expected fenced block to be replaced, not fall back to <pre>, got: %s", result)
+	}
+}
+
+// TestToHTMLWithDiagramRendererEmbedsSVGAsDataURI asserts a diagram's SVG
+// is always embedded as a base64 data: URI <img> src, never as raw inline
+// <svg> markup - even one carrying <script>/event-handler/foreignObject
+// content a compromised Kroki server might return. Base64-encoding that
+// markup (rather than trying to blacklist specific dangerous tags or
+// attributes within it) is what keeps it inert: Chromium never executes
+// script content inside an image-loaded SVG.
+func TestToHTMLWithDiagramRendererEmbedsSVGAsDataURI(t *testing.T) {
+	malicious := `<svg><script>alert(1)</script>` +
+		`<script src="evil.js"/>` +
+		`<foreignObject><iframe src="javascript:alert(5)"></iframe></foreignObject>` +
+		`<circle onload="alert(2)" r="1"/>` +
+		`</svg>`
+	stub := &stubDiagramRenderer{rendered: map[string]string{"plantuml": malicious}}
+	svc := NewMarkdownService(WithDiagramRenderer(stub))
+
+	result, err := svc.ToHTML("```plantuml\nA -> B\n```\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(result, diagramImgSrcPrefix) {
+		t.Fatalf("expected a data: URI <img> src, got: %s", result)
+	}
+	for _, needle := range []string{"<script", "<iframe", "<foreignObject", "onload"} {
+		if strings.Contains(result, needle) {
+			t.Errorf("expected no raw %q in output - SVG must be base64-encoded, not inlined, got: %s", needle, result)
+		}
+	}
+
+	start := strings.Index(result, diagramImgSrcPrefix) + len(diagramImgSrcPrefix)
+	end := strings.Index(result[start:], `"`)
+	decoded, err := base64.StdEncoding.DecodeString(result[start : start+end])
+	if err != nil {
+		t.Fatalf("img src wasn't valid base64: %v", err)
+	}
+	if string(decoded) != malicious {
+		t.Errorf("expected decoded data URI to round-trip the original SVG, got: %s", decoded)
+	}
+}
+
+func TestToHTMLWithDiagramRendererUnsupportedLanguageFallsBack(t *testing.T) {
+	stub := &stubDiagramRenderer{rendered: map[string]string{
+		"plantuml": "<svg>plantuml diagram</svg>",
+	}}
+	svc := NewMarkdownService(WithDiagramRenderer(stub))
+
+	result, err := svc.ToHTML("```go\nfmt.Println(\"hello\")\n```\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(result, "<pre>") || !strings.Contains(result, "fmt.Println") {
+		t.Errorf("expected unsupported language to fall back to <pre><code>, got: %s", result)
+	}
+}
+
+func TestToHTMLWithDiagramRendererDedupesRepeatedDiagram(t *testing.T) {
+	stub := &stubDiagramRenderer{rendered: map[string]string{
+		"plantuml": "<svg>plantuml diagram</svg>",
+	}}
+	svc := NewMarkdownService(WithDiagramRenderer(stub))
+
+	md := "```plantuml\nA -> B\n```\n\n```plantuml\nA -> B\n```\n"
+	result, err := svc.ToHTML(md)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Count(result, diagramImgSrcPrefix) != 2 {
+		t.Errorf("expected both occurrences rendered in the output, got: %s", result)
+	}
+	if got := atomic.LoadInt64(&stub.calls); got != 1 {
+		t.Errorf("expected the identical diagram to be rendered once, got %d calls", got)
+	}
+}
+
+func TestToHTMLWithDiagramRendererDedupesRepeatedDiagramIgnoringLanguageCase(t *testing.T) {
+	stub := &stubDiagramRenderer{rendered: map[string]string{
+		"plantuml": "<svg>plantuml diagram</svg>",
+	}}
+	svc := NewMarkdownService(WithDiagramRenderer(stub))
+
+	md := "```PlantUML\nA -> B\n```\n\n```plantuml\nA -> B\n```\n"
+	result, err := svc.ToHTML(md)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Count(result, diagramImgSrcPrefix) != 2 {
+		t.Errorf("expected both occurrences rendered in the output, got: %s", result)
+	}
+	if got := atomic.LoadInt64(&stub.calls); got != 1 {
+		t.Errorf("expected the same diagram fenced with different case to be rendered once, got %d calls", got)
+	}
+}
+
+func TestToHTMLWithoutDiagramRendererLeavesFencedBlockAsIs(t *testing.T) {
+	svc := NewMarkdownService()
+
+	result, err := svc.ToHTML("```plantuml\nA -> B\n```\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(result, "<pre>") || strings.Contains(result, "docflow-diagram") {
+		t.Errorf("expected plain <pre><code> with no diagram renderer configured, got: %s", result)
+	}
+}
@@ -11,7 +11,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gorkem/md-to-pdf/internal/cache"
 	"github.com/gorkem/md-to-pdf/internal/config"
+	"github.com/gorkem/md-to-pdf/internal/logger"
 	"github.com/gorkem/md-to-pdf/internal/model"
 	"go.uber.org/zap"
 )
@@ -19,19 +21,57 @@ import (
 // PDFExtractorService handles PDF to Markdown conversion
 type PDFExtractorService struct {
 	cfg    *config.Config
+	cache  *cache.Cache
 	logger *zap.Logger
 }
 
-// NewPDFExtractorService creates a new PDF extractor service
-func NewPDFExtractorService(cfg *config.Config, logger *zap.Logger) *PDFExtractorService {
+// NewPDFExtractorService creates a new PDF extractor service. cache is
+// shared with MarkdownService and ConverterService so repeated requests
+// for the same PDF don't re-run pdftotext/mutool.
+func NewPDFExtractorService(cfg *config.Config, cache *cache.Cache, logger *zap.Logger) *PDFExtractorService {
 	return &PDFExtractorService{
 		cfg:    cfg,
+		cache:  cache,
 		logger: logger,
 	}
 }
 
-// ExtractToMarkdown extracts text from PDF and converts to Markdown
+// ExtractToMarkdown extracts text from PDF and converts to Markdown. The
+// extracted markdown is cached by a hash of pdfData+filename, so
+// resubmitting the same PDF skips pdftotext/mutool entirely.
 func (s *PDFExtractorService) ExtractToMarkdown(ctx context.Context, pdfData []byte, filename string) (*model.PDFExtractResponse, error) {
+	return s.ExtractToMarkdownStream(ctx, pdfData, filename, nil)
+}
+
+// ExtractToMarkdownStream extracts text from PDF and converts to
+// Markdown, same as ExtractToMarkdown, but also reports page-level
+// progress to reporter as each page is extracted - used by the
+// /api/pdf/extract/stream endpoint. A nil reporter behaves exactly like
+// ExtractToMarkdown; a cache hit still reports a single ProgressStageDone
+// event so a streaming caller always sees a terminal event.
+func (s *PDFExtractorService) ExtractToMarkdownStream(ctx context.Context, pdfData []byte, filename string, reporter model.ProgressReporter) (*model.PDFExtractResponse, error) {
+	key := cache.Key(string(pdfData), filename)
+	cached, err := s.cache.GetOrCreate(key, func() (any, int64, error) {
+		resp, err := s.extractToMarkdown(ctx, pdfData, filename, reporter)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp, int64(len(resp.Markdown)), nil
+	})
+	if err != nil {
+		if reporter != nil {
+			reporter(model.ProgressEvent{Stage: model.ProgressStageError, File: filename, Error: err.Error()})
+		}
+		return nil, err
+	}
+	resp := cached.(*model.PDFExtractResponse)
+	if reporter != nil {
+		reporter(model.ProgressEvent{Stage: model.ProgressStageDone, File: filename, Pct: 100, Markdown: resp.Markdown})
+	}
+	return resp, nil
+}
+
+func (s *PDFExtractorService) extractToMarkdown(ctx context.Context, pdfData []byte, filename string, reporter model.ProgressReporter) (*model.PDFExtractResponse, error) {
 	timestamp := time.Now().Unix()
 	baseName := strings.TrimSuffix(filename, filepath.Ext(filename))
 	safeName := sanitizeFilename(baseName)
@@ -43,10 +83,15 @@ func (s *PDFExtractorService) ExtractToMarkdown(ctx context.Context, pdfData []b
 	}
 	defer os.Remove(tempPDFPath)
 
-	// Extract text using pdftotext
-	text, err := s.extractWithPdftotext(ctx, tempPDFPath)
+	var text string
+	var err error
+	if reporter != nil {
+		text, err = s.extractWithProgress(ctx, tempPDFPath, baseName, reporter)
+	} else {
+		text, err = s.extractWithPdftotext(ctx, tempPDFPath)
+	}
 	if err != nil {
-		s.logger.Warn("pdftotext failed, trying alternative method", zap.Error(err))
+		logger.Ctx(ctx, s.logger).Warn("pdftotext failed, trying alternative method", zap.Error(err))
 		// Fallback to basic extraction
 		text, err = s.extractBasic(ctx, tempPDFPath)
 		if err != nil {
@@ -64,7 +109,7 @@ func (s *PDFExtractorService) ExtractToMarkdown(ctx context.Context, pdfData []b
 		return nil, fmt.Errorf("failed to write markdown file: %w", err)
 	}
 
-	s.logger.Info("PDF extracted successfully",
+	logger.Ctx(ctx, s.logger).Info("PDF extracted successfully",
 		zap.String("input", filename),
 		zap.String("output", outputName),
 		zap.Int("textLength", len(text)),
@@ -78,6 +123,55 @@ func (s *PDFExtractorService) ExtractToMarkdown(ctx context.Context, pdfData []b
 	}, nil
 }
 
+// extractWithProgress extracts text one page at a time via repeated
+// pdftotext -f N -l N calls (the same single-page invocation
+// extractFirstPage already uses for previews), reporting a
+// ProgressStageExtract event with the running page count after each page
+// and a ProgressStagePartial event carrying the markdown extracted so
+// far. It falls back to extractWithPdftotext's bulk extraction if the
+// page count can't be determined.
+func (s *PDFExtractorService) extractWithProgress(ctx context.Context, pdfPath, baseName string, reporter model.ProgressReporter) (string, error) {
+	pageCount, err := s.getPageCount(ctx, pdfPath)
+	if err != nil || pageCount <= 0 {
+		return s.extractWithPdftotext(ctx, pdfPath)
+	}
+
+	var combined strings.Builder
+	for page := 1; page <= pageCount; page++ {
+		pageText, err := s.extractPage(ctx, pdfPath, page)
+		if err != nil {
+			return "", err
+		}
+		if combined.Len() > 0 {
+			combined.WriteString("\n\n")
+		}
+		combined.WriteString(pageText)
+
+		pct := page * 100 / pageCount
+		reporter(model.ProgressEvent{Stage: model.ProgressStageExtract, File: baseName, Page: page, Pages: pageCount, Pct: pct})
+		reporter(model.ProgressEvent{Stage: model.ProgressStagePartial, File: baseName, Page: page, Pages: pageCount, Pct: pct, Markdown: s.textToMarkdown(combined.String(), baseName)})
+	}
+
+	return combined.String(), nil
+}
+
+// extractPage extracts a single page's text, the same way
+// extractFirstPage extracts page 1.
+func (s *PDFExtractorService) extractPage(ctx context.Context, pdfPath string, page int) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	pageStr := fmt.Sprintf("%d", page)
+	cmd := exec.CommandContext(ctx, "pdftotext", "-f", pageStr, "-l", pageStr, "-layout", "-enc", "UTF-8", pdfPath, "-")
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to extract page %d: %w", page, err)
+	}
+	return stdout.String(), nil
+}
+
 // extractWithPdftotext uses pdftotext command
 func (s *PDFExtractorService) extractWithPdftotext(ctx context.Context, pdfPath string) (string, error) {
 	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
@@ -204,8 +298,24 @@ func (s *PDFExtractorService) isPotentialHeader(line string) bool {
 	return false
 }
 
-// PreviewExtraction returns preview of extracted content
+// PreviewExtraction returns preview of extracted content, cached the
+// same way as ExtractToMarkdown.
 func (s *PDFExtractorService) PreviewExtraction(ctx context.Context, pdfData []byte, filename string) (*model.PDFPreviewResponse, error) {
+	key := cache.Key(string(pdfData), "preview:"+filename)
+	cached, err := s.cache.GetOrCreate(key, func() (any, int64, error) {
+		resp, err := s.previewExtraction(ctx, pdfData, filename)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp, int64(len(resp.Preview)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cached.(*model.PDFPreviewResponse), nil
+}
+
+func (s *PDFExtractorService) previewExtraction(ctx context.Context, pdfData []byte, filename string) (*model.PDFPreviewResponse, error) {
 	timestamp := time.Now().Unix()
 	baseName := strings.TrimSuffix(filename, filepath.Ext(filename))
 	safeName := sanitizeFilename(baseName)
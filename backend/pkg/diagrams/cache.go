@@ -0,0 +1,69 @@
+package diagrams
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// CachingRenderer wraps another Renderer with an in-memory cache keyed
+// by SHA-256(lang||source), so rendering the same diagram again - across
+// pages of one document, or across repeated conversions of the same
+// Markdown - only ever reaches the wrapped Renderer once. It is safe for
+// concurrent use.
+type CachingRenderer struct {
+	next Renderer
+
+	mu    sync.RWMutex
+	cache map[string]cachedRender
+}
+
+type cachedRender struct {
+	data     []byte
+	mimeType string
+}
+
+// NewCachingRenderer wraps next with an unbounded in-memory cache. There
+// is no eviction: a docflow process renders a bounded, operator-sized
+// set of distinct diagrams over its lifetime, not an open-ended stream,
+// so the cache is sized by the rendering traffic rather than a fixed
+// budget the way internal/cache.Cache is.
+func NewCachingRenderer(next Renderer) *CachingRenderer {
+	return &CachingRenderer{next: next, cache: make(map[string]cachedRender)}
+}
+
+// Render returns the cached result for (lang, source) if present,
+// otherwise renders it via the wrapped Renderer and caches the result
+// before returning it. A failed render is not cached, so a transient
+// Kroki outage doesn't poison the cache for the rest of the process's
+// lifetime.
+func (c *CachingRenderer) Render(ctx context.Context, lang, source string) ([]byte, string, error) {
+	key := cacheKey(lang, source)
+
+	c.mu.RLock()
+	if r, ok := c.cache[key]; ok {
+		c.mu.RUnlock()
+		return r.data, r.mimeType, nil
+	}
+	c.mu.RUnlock()
+
+	data, mimeType, err := c.next.Render(ctx, lang, source)
+	if err != nil {
+		return nil, "", err
+	}
+
+	c.mu.Lock()
+	c.cache[key] = cachedRender{data: data, mimeType: mimeType}
+	c.mu.Unlock()
+
+	return data, mimeType, nil
+}
+
+func cacheKey(lang, source string) string {
+	h := sha256.New()
+	h.Write([]byte(lang))
+	h.Write([]byte{0})
+	h.Write([]byte(source))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,118 @@
+// Package diagrams renders fenced code blocks written in diagram DSLs -
+// PlantUML, Graphviz, BPMN, and whatever else a Kroki-compatible server
+// supports - to inline SVG, for MarkdownService's goldmark pipeline to
+// embed in place of the plain <pre><code> block those languages would
+// otherwise render as.
+//
+// service.WithDiagramRenderer wires a Renderer built by NewFromConfig
+// into MarkdownService as a goldmark parser.ASTTransformer: it swaps a
+// matching fenced code block's node for a <figure><img src="data:..."></figure>
+// on a render hit, and leaves it as plain <pre><code> on a miss,
+// timeout, or error. cmd/server/main.go only passes that option when
+// config.DiagramsConfig.Enabled is set.
+package diagrams
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Renderer renders source, written in the fenced-code-block language
+// lang, to an image.
+type Renderer interface {
+	// Render returns the rendered image's bytes and MIME type (e.g.
+	// "image/svg+xml"), or an error if lang isn't supported or the
+	// render itself fails.
+	Render(ctx context.Context, lang, source string) (data []byte, mimeType string, err error)
+}
+
+// Config sizes a Renderer built by NewFromConfig.
+type Config struct {
+	// KrokiURL is the Kroki-compatible server's base URL, e.g.
+	// "https://kroki.io" or a self-hosted instance's address.
+	KrokiURL string
+	// Timeout bounds a single Render call, so a slow or unreachable
+	// Kroki server fails that one diagram instead of stalling the PDF
+	// generation it's embedded in.
+	Timeout time.Duration
+}
+
+// NewFromConfig builds the Renderer MarkdownService's goldmark
+// transformer would call: a CachingRenderer wrapping a KrokiRenderer
+// pointed at cfg.KrokiURL, so repeated diagrams across a document (or
+// across conversions of the same Markdown) only ever reach Kroki once.
+func NewFromConfig(cfg Config) Renderer {
+	return NewCachingRenderer(NewKrokiRenderer(cfg.KrokiURL, cfg.Timeout))
+}
+
+// krokiDiagramType maps a fenced-code-block language to the path
+// segment Kroki expects in https://kroki.io/<type>/svg. Languages that
+// map to the same Kroki diagram type (dot is Graphviz's language name)
+// are listed separately so either fence works.
+var krokiDiagramType = map[string]string{
+	"plantuml": "plantuml",
+	"graphviz": "graphviz",
+	"dot":      "graphviz",
+	"bpmn":     "bpmn",
+}
+
+// Supported reports whether lang is a fenced-code-block language this
+// package knows how to forward to Kroki.
+func Supported(lang string) bool {
+	_, ok := krokiDiagramType[strings.ToLower(lang)]
+	return ok
+}
+
+// KrokiRenderer renders diagrams by POSTing source to a Kroki-compatible
+// HTTP endpoint's /<diagram-type>/svg route and returning the SVG body.
+// It is safe for concurrent use.
+type KrokiRenderer struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewKrokiRenderer creates a KrokiRenderer against baseURL, with
+// requestTimeout bounding every Render call.
+func NewKrokiRenderer(baseURL string, requestTimeout time.Duration) *KrokiRenderer {
+	return &KrokiRenderer{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Render implements Renderer by POSTing source as the request body to
+// <baseURL>/<kroki diagram type for lang>/svg.
+func (k *KrokiRenderer) Render(ctx context.Context, lang, source string) ([]byte, string, error) {
+	diagramType, ok := krokiDiagramType[strings.ToLower(lang)]
+	if !ok {
+		return nil, "", fmt.Errorf("diagrams: unsupported language %q", lang)
+	}
+
+	url := fmt.Sprintf("%s/%s/svg", k.baseURL, diagramType)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(source))
+	if err != nil {
+		return nil, "", fmt.Errorf("diagrams: building request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain")
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("diagrams: requesting %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("diagrams: reading response from %s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("diagrams: %s returned %s: %s", url, resp.Status, bytes.TrimSpace(body))
+	}
+
+	return body, "image/svg+xml", nil
+}
@@ -0,0 +1,71 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+)
+
+// containerXML is META-INF/container.xml, identical for every EPUB this
+// package produces: it just points reading systems at content.opf.
+const containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>`
+
+// archive zips chapters plus their generated package document, nav
+// document, and NCX into a complete EPUB. Per the OCF spec, the
+// "mimetype" entry must be the archive's first entry and must be
+// stored uncompressed - every other entry is the zip writer's default
+// deflate.
+func archive(chapters []chapter) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	if err := writeStored(zw, "mimetype", []byte("application/epub+zip")); err != nil {
+		return nil, fmt.Errorf("epub: writing mimetype entry: %w", err)
+	}
+
+	files := map[string]string{
+		"META-INF/container.xml": containerXML,
+		"content.opf":            contentOPF(chapters[0].title, chapters),
+		"nav.xhtml":              navDocument(chapters),
+		"toc.ncx":                tocNCX(chapters),
+	}
+	for _, c := range chapters {
+		files[c.id+".xhtml"] = c.xhtml
+	}
+
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			return nil, fmt.Errorf("epub: creating %s: %w", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			return nil, fmt.Errorf("epub: writing %s: %w", name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("epub: finalizing archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeStored adds name to zw as a stored (uncompressed) entry.
+// zip.Writer.Create always deflates, so the mimetype entry - the one
+// entry the EPUB spec requires to be stored - needs CreateHeader with
+// an explicit Method instead.
+func writeStored(zw *zip.Writer, name string, content []byte) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:   name,
+		Method: zip.Store,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
@@ -0,0 +1,99 @@
+// Package epub assembles ConverterService's converted Markdown chapters
+// into a spec-compliant EPUB container: an OPF 3 package document plus a
+// toc.ncx for EPUB 2 reading systems, zipped with the mimetype entry
+// stored uncompressed as the EPUB spec requires.
+//
+// pkg/epub mirrors pkg/pdf: both are leaf packages service/converter.go
+// calls into to turn a []model.FileData into a downloadable file,
+// neither reaching back into the service package itself.
+package epub
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/gorkem/md-to-pdf/internal/model"
+)
+
+// HTMLRenderer converts one chapter's Markdown source to HTML body
+// content. It matches the signature of
+// (*service.MarkdownService).ToHTML without pkg/epub importing the
+// service package - service imports pkg/epub to call Build, so the
+// reverse import would cycle.
+type HTMLRenderer func(markdown string) (string, error)
+
+// chapter is one spine entry: its XHTML document plus the title
+// extracted for the nav document and toc.ncx.
+type chapter struct {
+	id    string
+	title string
+	xhtml string
+}
+
+var (
+	firstHeadingRe = regexp.MustCompile(`(?is)<h[12][^>]*>(.*?)</h[12]>`)
+	tagRe          = regexp.MustCompile(`<[^>]+>`)
+)
+
+// Build renders files - already sorted into the desired spine order by
+// the caller - into a single EPUB and returns its bytes. Each file
+// becomes one chapter; unlike PDF's merged mode, Build never merges
+// their Markdown source first, since EPUB chapters are naturally
+// separate XHTML documents linked by a shared spine.
+func Build(files []model.FileData, render HTMLRenderer) ([]byte, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("epub: no files to build")
+	}
+
+	chapters := make([]chapter, len(files))
+	for i, f := range files {
+		body, err := render(f.Content)
+		if err != nil {
+			return nil, fmt.Errorf("epub: rendering chapter %q: %w", f.Name, err)
+		}
+
+		title := chapterTitle(body, f.Name)
+		chapters[i] = chapter{
+			id:    fmt.Sprintf("chapter%d", i+1),
+			title: title,
+			xhtml: chapterXHTML(title, body),
+		}
+	}
+
+	return archive(chapters)
+}
+
+// chapterTitle extracts the text of body's first <h1> or <h2>, falling
+// back to fallback (the source file's name) if neither exists or the
+// heading is empty once tags are stripped.
+func chapterTitle(body, fallback string) string {
+	m := firstHeadingRe.FindStringSubmatch(body)
+	if m == nil {
+		return fallback
+	}
+	title := strings.TrimSpace(tagRe.ReplaceAllString(m[1], ""))
+	if title == "" {
+		return fallback
+	}
+	return html.UnescapeString(title)
+}
+
+// chapterXHTML wraps body - already-rendered HTML from MarkdownService
+// - in the minimal well-formed XHTML document EPUB spine items require.
+//
+// MarkdownService's renderer isn't XHTML-strict (e.g. it may emit
+// unclosed <br>/<hr>/<img> tags, which are valid HTML5 but not
+// well-formed XML), so a reading system with a strict XML parser could
+// reject these chapters. Normalizing that output is out of scope here;
+// this wrapper only supplies the document shell around it.
+func chapterXHTML(title, body string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head><meta charset="UTF-8"/><title>%s</title></head>
+<body>
+%s
+</body>
+</html>`, html.EscapeString(title), body)
+}
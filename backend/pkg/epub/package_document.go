@@ -0,0 +1,108 @@
+package epub
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// bookNamespace seeds bookUUID's UUIDv5 derivation. Any fixed UUID
+// works here - it just keeps docflow's book identifiers out of the
+// other UUIDv5 namespaces RFC 4122 predefines (DNS, URL, etc.), so they
+// can't collide with an identifier some other generator derived from
+// the same content.
+var bookNamespace = uuid.MustParse("7b6f5b1e-6e6f-4b8a-9b1d-8f6a9a9d9b5c")
+
+// bookUUID derives content.opf's unique identifier from every chapter's
+// rendered XHTML (in spine order), so two conversions of the same
+// document get the same identifier - letting library managers (Calibre,
+// Apple Books, etc.) recognize a re-conversion as the same book - while
+// different documents get different ones, rather than every EPUB this
+// package ever builds colliding on one fixed UUID.
+func bookUUID(chapters []chapter) string {
+	var content strings.Builder
+	for _, c := range chapters {
+		content.WriteString(c.xhtml)
+	}
+	return "urn:uuid:" + uuid.NewSHA1(bookNamespace, []byte(content.String())).String()
+}
+
+// contentOPF builds the OPF 3 package document: metadata, a manifest
+// listing every chapter plus the nav document and NCX, and a spine in
+// chapter order. title is the book's dc:title, taken from the first
+// chapter's title when building a merged multi-chapter EPUB, or that
+// chapter's own title for a single-chapter EPUB.
+func contentOPF(title string, chapters []chapter) string {
+	var manifest, spine strings.Builder
+	for _, c := range chapters {
+		fmt.Fprintf(&manifest, `    <item id="%s" href="%s.xhtml" media-type="application/xhtml+xml"/>
+`, c.id, c.id)
+		fmt.Fprintf(&spine, `    <itemref idref="%s"/>
+`, c.id)
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="bookid">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="bookid">%s</dc:identifier>
+    <dc:title>%s</dc:title>
+    <dc:language>en</dc:language>
+    <meta property="dcterms:modified">1970-01-01T00:00:00Z</meta>
+  </metadata>
+  <manifest>
+%s    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+  </manifest>
+  <spine toc="ncx">
+%s  </spine>
+</package>`, bookUUID(chapters), html.EscapeString(title), manifest.String(), spine.String())
+}
+
+// navDocument builds the EPUB 3 nav document: an <nav epub:type="toc">
+// list linking each chapter in spine order.
+func navDocument(chapters []chapter) string {
+	var items strings.Builder
+	for _, c := range chapters {
+		fmt.Fprintf(&items, `      <li><a href="%s.xhtml">%s</a></li>
+`, c.id, html.EscapeString(c.title))
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head><meta charset="UTF-8"/><title>Table of Contents</title></head>
+<body>
+  <nav epub:type="toc" id="toc">
+    <ol>
+%s    </ol>
+  </nav>
+</body>
+</html>`, items.String())
+}
+
+// tocNCX builds the EPUB 2 toc.ncx navMap, kept alongside the nav
+// document for reading systems that predate EPUB 3 navigation.
+func tocNCX(chapters []chapter) string {
+	var navPoints strings.Builder
+	for i, c := range chapters {
+		fmt.Fprintf(&navPoints, `    <navPoint id="navpoint-%d" playOrder="%d">
+      <navLabel><text>%s</text></navLabel>
+      <content src="%s.xhtml"/>
+    </navPoint>
+`, i+1, i+1, html.EscapeString(c.title), c.id)
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+  <head>
+    <meta name="dtb:uid" content="%s"/>
+    <meta name="dtb:depth" content="1"/>
+    <meta name="dtb:totalPageCount" content="0"/>
+    <meta name="dtb:maxPageNumber" content="0"/>
+  </head>
+  <docTitle><text>%s</text></docTitle>
+  <navMap>
+%s  </navMap>
+</ncx>`, bookUUID(chapters), html.EscapeString(chapters[0].title), navPoints.String())
+}
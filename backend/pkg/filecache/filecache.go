@@ -0,0 +1,193 @@
+// Package filecache is a content-addressed, on-disk cache for rendered
+// PDFs, modeled on Hugo's file cache: each entry is written to
+// <dir>/<first two hex digits of its key>/<key>.pdf, alongside a
+// sidecar <key>.json recording when it was created, its size, and the
+// original output filename. GetOrCreate singleflights concurrent
+// requests for the same key into a single render.
+package filecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache is a content-addressed, on-disk store of rendered PDFs. It is
+// safe for concurrent use.
+type Cache struct {
+	dir   string
+	group singleflight.Group
+}
+
+// New creates a Cache rooted at dir, creating dir if it doesn't exist.
+func New(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("filecache: creating %s: %w", dir, err)
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// Key hashes parts - every input that affects a render's output bytes,
+// e.g. normalized Markdown content, theme name, custom CSS, template
+// version, and paper/margin settings - into a cache key. A null byte
+// separates each part so two different splits of the same concatenated
+// text (e.g. parts ["ab", "c"] vs ["a", "bc"]) never collide.
+func Key(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Meta is the sidecar JSON recorded alongside each cached PDF.
+type Meta struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Size      int64     `json:"size"`
+	FileName  string    `json:"fileName"`
+}
+
+func (c *Cache) paths(key string) (pdfPath, metaPath string) {
+	sub := key
+	if len(sub) > 2 {
+		sub = sub[:2]
+	}
+	base := filepath.Join(c.dir, sub, key)
+	return base + ".pdf", base + ".json"
+}
+
+// GetOrCreate returns the cached PDF bytes for key, calling build to
+// render them if no entry exists yet. fileName is recorded in the
+// sidecar metadata only; it plays no part in the cache key. Concurrent
+// GetOrCreate calls for the same key block on a single build call
+// instead of each starting their own Chromium render.
+func (c *Cache) GetOrCreate(key, fileName string, build func() ([]byte, error)) ([]byte, error) {
+	pdfPath, _ := c.paths(key)
+	if data, err := os.ReadFile(pdfPath); err == nil {
+		return data, nil
+	}
+
+	v, err, _ := c.group.Do(key, func() (any, error) {
+		// Re-check now that this goroutine owns the singleflight call:
+		// another request for this key may have populated the entry
+		// between the lookup above and the call reaching the front of
+		// the group.
+		if data, err := os.ReadFile(pdfPath); err == nil {
+			return data, nil
+		}
+
+		data, err := build()
+		if err != nil {
+			return nil, err
+		}
+		if err := c.write(key, fileName, data); err != nil {
+			return nil, err
+		}
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+func (c *Cache) write(key, fileName string, data []byte) error {
+	pdfPath, metaPath := c.paths(key)
+	if err := os.MkdirAll(filepath.Dir(pdfPath), 0755); err != nil {
+		return fmt.Errorf("filecache: creating %s: %w", filepath.Dir(pdfPath), err)
+	}
+	if err := os.WriteFile(pdfPath, data, 0644); err != nil {
+		return fmt.Errorf("filecache: writing %s: %w", pdfPath, err)
+	}
+
+	meta := Meta{CreatedAt: time.Now(), Size: int64(len(data)), FileName: fileName}
+	metaJSON, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("filecache: marshaling metadata for %s: %w", key, err)
+	}
+	if err := os.WriteFile(metaPath, metaJSON, 0644); err != nil {
+		return fmt.Errorf("filecache: writing %s: %w", metaPath, err)
+	}
+	return nil
+}
+
+// cacheEntry is one cached PDF discovered by Prune's filesystem walk.
+type cacheEntry struct {
+	pdfPath  string
+	metaPath string
+	modTime  time.Time
+	size     int64
+}
+
+// Prune deletes cached entries (PDF plus sidecar metadata) older than
+// maxAge, then - if the cache is still over maxBytes - deletes whatever
+// remains oldest-first until it isn't. Either limit can be zero to
+// disable it. Prune is safe to call while GetOrCreate calls are in
+// flight for other keys; it never touches a key mid-write since write
+// only publishes the sidecar JSON after the PDF itself is on disk.
+func (c *Cache) Prune(maxAge time.Duration, maxBytes int64) error {
+	var entries []cacheEntry
+
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".pdf" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entries = append(entries, cacheEntry{
+			pdfPath:  path,
+			metaPath: strings.TrimSuffix(path, ".pdf") + ".json",
+			modTime:  info.ModTime(),
+			size:     info.Size(),
+		})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("filecache: walking %s: %w", c.dir, err)
+	}
+
+	now := time.Now()
+	var kept []cacheEntry
+	var total int64
+	for _, e := range entries {
+		if maxAge > 0 && now.Sub(e.modTime) > maxAge {
+			c.remove(e)
+			continue
+		}
+		kept = append(kept, e)
+		total += e.size
+	}
+
+	if maxBytes > 0 && total > maxBytes {
+		sort.Slice(kept, func(i, j int) bool { return kept[i].modTime.Before(kept[j].modTime) })
+		for _, e := range kept {
+			if total <= maxBytes {
+				break
+			}
+			c.remove(e)
+			total -= e.size
+		}
+	}
+
+	return nil
+}
+
+func (c *Cache) remove(e cacheEntry) {
+	os.Remove(e.pdfPath)
+	os.Remove(e.metaPath)
+}
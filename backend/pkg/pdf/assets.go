@@ -0,0 +1,50 @@
+package pdf
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+)
+
+// assetFS embeds the KaTeX and Mermaid distributables so Generate can
+// inline them directly into the printed HTML document - no CDN fetch,
+// so PDFs render identically offline. See assets/katex.min.css's header
+// comment for how to vendor the real files into this directory.
+//
+//go:embed assets/katex.min.css assets/katex.min.js assets/auto-render.min.js assets/mermaid.min.js
+var assetFS embed.FS
+
+// AssetsVendored reports whether the embedded KaTeX/Mermaid files under
+// assets/ are the real distributables rather than the no-op placeholders
+// this repo snapshot ships (see assets/katex.min.css's header comment -
+// there's no network access here to vendor the real files). It is false
+// until someone replaces those files and flips this to true.
+//
+// ErrAssetsNotVendored is what callers see instead: a caller enabling
+// EnableMath/EnableMermaid against placeholder assets would otherwise
+// get a "successful" PDF containing raw LaTeX source text or no diagram
+// at all, silently - RenderOptions's callers should check AssetsVendored
+// (or just handle ErrAssetsNotVendored) before honoring either flag.
+const AssetsVendored = false
+
+// ErrAssetsNotVendored is returned by callers (see ConverterService.
+// Convert) that refuse to honor EnableMath/EnableMermaid while
+// AssetsVendored is false.
+var ErrAssetsNotVendored = errors.New("pdf: KaTeX/Mermaid assets in pkg/pdf/assets are placeholders, not the real vendored libraries - math/diagram rendering is unavailable in this build")
+
+func mustReadAsset(name string) string {
+	b, err := assetFS.ReadFile("assets/" + name)
+	if err != nil {
+		// Only reachable if the embed directive above and this call
+		// disagree on filenames, which go:embed would already have
+		// caught at compile time.
+		panic(fmt.Sprintf("pdf: embedded asset %q missing: %v", name, err))
+	}
+	return string(b)
+}
+
+var (
+	katexAssetsHTML = fmt.Sprintf("<style>\n%s\n</style>\n<script>\n%s\n</script>\n<script>\n%s\n</script>\n",
+		mustReadAsset("katex.min.css"), mustReadAsset("katex.min.js"), mustReadAsset("auto-render.min.js"))
+	mermaidAssetHTML = fmt.Sprintf("<script>\n%s\n</script>\n", mustReadAsset("mermaid.min.js"))
+)
@@ -0,0 +1,305 @@
+// Package browserpool maintains a small set of long-lived chromedp
+// browser processes that ConverterService prints PDFs through, instead
+// of spinning up a fresh ExecAllocator (and Chromium process) for every
+// single conversion.
+package browserpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// ErrClosed is returned by Run once the pool has been drained.
+var ErrClosed = errors.New("browserpool: pool is closed")
+
+// Config sizes a Pool.
+type Config struct {
+	// Size is how many browser processes New starts up front.
+	Size int
+	// MaxJobsPerWorker recycles a worker - closing its browser and
+	// launching a fresh one - after it completes this many jobs, to
+	// bound any slow memory growth inside a long-lived Chromium process.
+	MaxJobsPerWorker int
+	// JobTimeout bounds a single Run call; a job that exceeds it has its
+	// tab context cancelled and its worker recycled rather than left to
+	// wedge the pool.
+	JobTimeout time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.Size <= 0 {
+		c.Size = 1
+	}
+	if c.MaxJobsPerWorker <= 0 {
+		c.MaxJobsPerWorker = 50
+	}
+	if c.JobTimeout <= 0 {
+		c.JobTimeout = 60 * time.Second
+	}
+	return c
+}
+
+// worker owns one long-lived browser process. allocCtx/allocCancel own
+// the ExecAllocator (the Chromium process itself); browserCtx/
+// browserCancel own the chromedp browser connection layered on top of
+// it. Run creates one more layer per job - a tab context via
+// chromedp.NewContext(browserCtx) - rather than reusing browserCtx
+// directly, so concurrent-looking jobs on different workers (and
+// successive jobs on the same worker) each get a fresh tab.
+type worker struct {
+	id            int
+	allocCtx      context.Context
+	allocCancel   context.CancelFunc
+	browserCtx    context.Context
+	browserCancel context.CancelFunc
+	jobs          int
+}
+
+// Pool is a fixed-size, semaphore-backed set of workers. It is safe for
+// concurrent use.
+type Pool struct {
+	cfg     Config
+	workers chan *worker
+
+	mu         sync.Mutex
+	closed     bool
+	inFlight   int
+	queueDepth int
+	totalJobs  int64
+	totalTime  time.Duration
+	nextID     int
+}
+
+// New starts cfg.Size browser processes and returns the pool once every
+// one of them has answered a health-check round trip. If any worker
+// fails to start, New tears down the workers it already started and
+// returns the error.
+func New(cfg Config) (*Pool, error) {
+	cfg = cfg.withDefaults()
+	p := &Pool{cfg: cfg, workers: make(chan *worker, cfg.Size)}
+
+	for i := 0; i < cfg.Size; i++ {
+		w, err := p.startWorker()
+		if err != nil {
+			p.closeStartedWorkers(i)
+			return nil, fmt.Errorf("browserpool: starting worker %d: %w", i, err)
+		}
+		p.workers <- w
+	}
+
+	return p, nil
+}
+
+// closeStartedWorkers tears down the n workers New already pushed onto
+// p.workers, after a later startWorker call fails partway through
+// filling the pool. Unlike Drain, it reads exactly n items - never more
+// than New actually started - so it can't block forever waiting for
+// workers that were never created; Drain's cfg.Size-sized loop would,
+// since only n (not cfg.Size) workers ever reached the channel here.
+func (p *Pool) closeStartedWorkers(n int) {
+	for i := 0; i < n; i++ {
+		w := <-p.workers
+		w.browserCancel()
+		w.allocCancel()
+	}
+}
+
+// startWorker launches a new Chromium process and runs an empty task
+// list through it, which forces chromedp to actually establish the
+// CDP websocket connection now rather than lazily on the worker's
+// first real job - so a dead/missing Chromium binary fails New (or a
+// recycle) immediately instead of surfacing on some later request.
+func (p *Pool) startWorker() (*worker, error) {
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("headless", true),
+		chromedp.Flag("disable-gpu", true),
+		chromedp.Flag("no-sandbox", true),
+		chromedp.Flag("disable-dev-shm-usage", true),
+		chromedp.Flag("disable-software-rasterizer", true),
+	)
+
+	allocCtx, allocCancel := chromedp.NewExecAllocator(context.Background(), opts...)
+	browserCtx, browserCancel := chromedp.NewContext(allocCtx)
+
+	if err := chromedp.Run(browserCtx); err != nil {
+		browserCancel()
+		allocCancel()
+		return nil, err
+	}
+
+	p.mu.Lock()
+	id := p.nextID
+	p.nextID++
+	p.mu.Unlock()
+
+	return &worker{
+		id:            id,
+		allocCtx:      allocCtx,
+		allocCancel:   allocCancel,
+		browserCtx:    browserCtx,
+		browserCancel: browserCancel,
+	}, nil
+}
+
+// Run acquires a worker (blocking until one is free or ctx is done),
+// runs actions against a fresh tab in that worker's browser bounded by
+// the pool's JobTimeout, and returns the worker to the pool - recycling
+// it first if the job failed, panicked, or pushed it past
+// MaxJobsPerWorker.
+func (p *Pool) Run(ctx context.Context, actions ...chromedp.Action) (err error) {
+	w, err := p.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	jobCtx, cancel := context.WithTimeout(w.browserCtx, p.cfg.JobTimeout)
+	defer cancel()
+
+	tabCtx, tabCancel := chromedp.NewContext(jobCtx)
+	defer tabCancel()
+
+	recycle := false
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("browserpool: job panicked: %v", r)
+			recycle = true
+		}
+
+		p.mu.Lock()
+		p.totalJobs++
+		p.totalTime += time.Since(start)
+		p.mu.Unlock()
+
+		w.jobs++
+		if err != nil || w.jobs >= p.cfg.MaxJobsPerWorker {
+			recycle = true
+		}
+		if recycle {
+			p.recycle(w)
+		} else {
+			p.release(w)
+		}
+	}()
+
+	err = chromedp.Run(tabCtx, actions...)
+	return err
+}
+
+func (p *Pool) acquire(ctx context.Context) (*worker, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrClosed
+	}
+	p.queueDepth++
+	p.mu.Unlock()
+
+	select {
+	case w := <-p.workers:
+		p.mu.Lock()
+		p.queueDepth--
+		p.inFlight++
+		p.mu.Unlock()
+		return w, nil
+	case <-ctx.Done():
+		p.mu.Lock()
+		p.queueDepth--
+		p.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+func (p *Pool) release(w *worker) {
+	p.mu.Lock()
+	p.inFlight--
+	p.mu.Unlock()
+	p.workers <- w
+}
+
+// recycle closes w's browser and replaces it with a freshly started
+// one, keeping the pool at a constant size. If the replacement fails to
+// start, the pool permanently runs one worker short rather than
+// deadlocking Run callers waiting on a slot that will never come back -
+// an acceptable degradation under a persistently broken Chromium
+// install, logged by the caller via the returned error next time New
+// or a health check surfaces it, rather than retried here forever.
+func (p *Pool) recycle(w *worker) {
+	w.browserCancel()
+	w.allocCancel()
+
+	p.mu.Lock()
+	p.inFlight--
+	p.mu.Unlock()
+
+	nw, err := p.startWorker()
+	if err != nil {
+		p.mu.Lock()
+		p.cfg.Size--
+		p.mu.Unlock()
+		return
+	}
+	p.workers <- nw
+}
+
+// Drain waits for every worker to be returned to the pool (i.e. for all
+// in-flight jobs to finish) before closing their browsers, so a
+// SIGTERM-triggered shutdown doesn't cut an in-progress print short.
+// Once Drain returns (successfully or not), the pool rejects further
+// Run calls with ErrClosed.
+func (p *Pool) Drain(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	size := p.cfg.Size
+	p.mu.Unlock()
+
+	for i := 0; i < size; i++ {
+		select {
+		case w := <-p.workers:
+			w.browserCancel()
+			w.allocCancel()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Metrics is a point-in-time snapshot of a Pool's load and throughput,
+// suitable for a /metrics endpoint.
+type Metrics struct {
+	PoolSize     int
+	InFlight     int
+	QueueDepth   int
+	TotalJobs    int64
+	AvgPrintTime time.Duration
+}
+
+// Metrics returns a snapshot of the pool's current load and cumulative
+// throughput.
+func (p *Pool) Metrics() Metrics {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var avg time.Duration
+	if p.totalJobs > 0 {
+		avg = p.totalTime / time.Duration(p.totalJobs)
+	}
+
+	return Metrics{
+		PoolSize:     p.cfg.Size,
+		InFlight:     p.inFlight,
+		QueueDepth:   p.queueDepth,
+		TotalJobs:    p.totalJobs,
+		AvgPrintTime: avg,
+	}
+}
@@ -0,0 +1,62 @@
+package pdf
+
+import (
+	"fmt"
+	"html"
+)
+
+// CoverOptions configures Generate's optional cover page, rendered
+// before the body and ending in `page-break-after: always` so the body
+// starts on its own page. A zero-value CoverOptions - specifically an
+// empty Title - disables the cover page entirely, even if Subtitle,
+// Author, or Date are set.
+type CoverOptions struct {
+	Title    string
+	Subtitle string
+	Author   string
+	Date     string
+}
+
+// WithCover sets the cover page Generate renders before the body.
+func WithCover(c CoverOptions) TemplateOption {
+	return func(t *TemplateGenerator) {
+		t.cover = c
+	}
+}
+
+// html renders c as a centered, full-page title block. coverCSS supplies
+// the layout; typography (font-family, color) is left to inherit from
+// whichever theme wraps the body, so a cover page always matches its
+// document's look instead of carrying its own hardcoded font choices.
+func (c CoverOptions) html() string {
+	var body string
+	if c.Title != "" {
+		body += fmt.Sprintf(`<div class="docflow-cover-title">%s</div>`, html.EscapeString(c.Title))
+	}
+	if c.Subtitle != "" {
+		body += fmt.Sprintf(`<div class="docflow-cover-subtitle">%s</div>`, html.EscapeString(c.Subtitle))
+	}
+	if c.Author != "" {
+		body += fmt.Sprintf(`<div class="docflow-cover-author">%s</div>`, html.EscapeString(c.Author))
+	}
+	if c.Date != "" {
+		body += fmt.Sprintf(`<div class="docflow-cover-date">%s</div>`, html.EscapeString(c.Date))
+	}
+	return fmt.Sprintf(`<div class="docflow-cover">%s</div>`, body)
+}
+
+const coverCSS = `
+.docflow-cover {
+  page-break-after: always;
+  display: flex;
+  flex-direction: column;
+  align-items: center;
+  justify-content: center;
+  text-align: center;
+  height: 90vh;
+}
+.docflow-cover-title { font-size: 2.5em; font-weight: bold; margin-bottom: 0.3em; }
+.docflow-cover-subtitle { font-size: 1.4em; margin-bottom: 2em; }
+.docflow-cover-author { font-size: 1.1em; margin-bottom: 0.2em; }
+.docflow-cover-date { font-size: 1em; opacity: 0.7; }
+`
@@ -0,0 +1,37 @@
+package pdf
+
+// PageOptions sizes the printed page. It drives both the generated
+// document's `@page` CSS rule and, via the same values, the
+// page.PrintToPDF call in generateWithChromedp - so the two can never
+// drift out of sync the way the old hardcoded 8.27x11.69in/0.79in
+// constants could once a theme wanted a different page size.
+type PageOptions struct {
+	WidthIn        float64
+	HeightIn       float64
+	MarginTopIn    float64
+	MarginBottomIn float64
+	MarginLeftIn   float64
+	MarginRightIn  float64
+}
+
+// pageSizePresets are the paper sizes PageOptionsForSize recognizes.
+// Margins match docflow's original hardcoded 0.79in (20mm) on every
+// preset.
+var pageSizePresets = map[string]PageOptions{
+	"a4":     {WidthIn: 8.27, HeightIn: 11.69, MarginTopIn: 0.79, MarginBottomIn: 0.79, MarginLeftIn: 0.79, MarginRightIn: 0.79},
+	"letter": {WidthIn: 8.5, HeightIn: 11, MarginTopIn: 0.79, MarginBottomIn: 0.79, MarginLeftIn: 0.79, MarginRightIn: 0.79},
+	"legal":  {WidthIn: 8.5, HeightIn: 14, MarginTopIn: 0.79, MarginBottomIn: 0.79, MarginLeftIn: 0.79, MarginRightIn: 0.79},
+}
+
+// DefaultPageOptions is the A4 preset docflow printed every PDF at
+// before page sizes were configurable.
+var DefaultPageOptions = pageSizePresets["a4"]
+
+// PageOptionsForSize returns the PageOptions registered under name, or
+// DefaultPageOptions if name is empty or unrecognized.
+func PageOptionsForSize(name string) PageOptions {
+	if p, ok := pageSizePresets[name]; ok {
+		return p
+	}
+	return DefaultPageOptions
+}
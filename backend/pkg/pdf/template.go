@@ -0,0 +1,243 @@
+// Package pdf builds the full HTML document ConverterService hands to
+// chromedp for PDF printing, wrapping the already-rendered Markdown body
+// in a print-oriented shell.
+//
+// TemplateGenerator has no source file elsewhere in this snapshot of
+// backend/ - internal/service/converter.go references pdf.TemplateGenerator
+// and pdf.NewTemplateGenerator, but until this change nothing under
+// backend/pkg existed to provide them. This file fills that gap.
+package pdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TemplateGenerator wraps converted HTML in the print-ready document
+// chromedp loads before calling page.PrintToPDF. It is safe for
+// concurrent use - Generate does not mutate TemplateGenerator.
+type TemplateGenerator struct {
+	render    RenderOptions
+	themeCSS  string
+	customCSS string
+	page      PageOptions
+	cover     CoverOptions
+	tocDepth  int
+}
+
+// TemplateOption configures a TemplateGenerator at construction time.
+type TemplateOption func(*TemplateGenerator)
+
+// WithRenderOptions sets the math/diagram rendering options every
+// Generate call on this TemplateGenerator applies.
+func WithRenderOptions(opts RenderOptions) TemplateOption {
+	return func(t *TemplateGenerator) {
+		t.render = opts
+	}
+}
+
+// WithTheme sets the theme stylesheet Generate wraps the body in -
+// typically looked up from a ThemeSet by the caller, so TemplateGenerator
+// itself doesn't need to know about ThemeSet or its loaded-from-disk
+// themes. An empty string omits the theme <style> block entirely.
+func WithTheme(css string) TemplateOption {
+	return func(t *TemplateGenerator) {
+		t.themeCSS = css
+	}
+}
+
+// WithCustomCSS appends css after the theme's stylesheet, letting a
+// request override individual theme rules. Callers should run
+// SanitizeCustomCSS over user-supplied CSS before passing it here;
+// WithCustomCSS itself does not sanitize.
+func WithCustomCSS(css string) TemplateOption {
+	return func(t *TemplateGenerator) {
+		t.customCSS = css
+	}
+}
+
+// WithPageOptions sets the printed page's size and margins. Generate's
+// `@page` rule and ConverterService's page.PrintToPDF call must use the
+// same PageOptions value, or the PDF's page breaks (computed by Chromium
+// from the CSS) won't match the paper size PrintToPDF actually cuts the
+// page at.
+func WithPageOptions(p PageOptions) TemplateOption {
+	return func(t *TemplateGenerator) {
+		t.page = p
+	}
+}
+
+// NewTemplateGenerator creates a TemplateGenerator. With no options, math
+// and Mermaid rendering are disabled and the page prints at
+// DefaultPageOptions with no theme or custom CSS.
+func NewTemplateGenerator(opts ...TemplateOption) *TemplateGenerator {
+	t := &TemplateGenerator{page: DefaultPageOptions}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// RenderOptions selects which client-side rendering scripts Generate
+// injects into <head>. Both are opt-in: a Markdown document's `$...$`
+// math or ```mermaid``` fences render as plain text unless the
+// corresponding flag is set.
+type RenderOptions struct {
+	// EnableMath injects KaTeX and auto-render, which scans the
+	// rendered body for MathDelimiters and typesets matches in place.
+	EnableMath bool
+	// EnableMermaid injects Mermaid and calls mermaid.run() over every
+	// `<pre class="mermaid">`/`<code class="language-mermaid">` block
+	// MarkdownService's fenced-code renderer produced.
+	EnableMermaid bool
+	// MathDelimiters configures auto-render's delimiters. Nil means
+	// DefaultMathDelimiters.
+	MathDelimiters []MathDelimiter
+}
+
+// MathDelimiter is one inline or display-mode math delimiter pair, as
+// consumed by KaTeX auto-render's `delimiters` option.
+type MathDelimiter struct {
+	Left    string
+	Right   string
+	Display bool
+}
+
+// DefaultMathDelimiters matches the conventional Pandoc/GitHub Markdown
+// math delimiters: `$...$` and `\(...\)` inline, `$$...$$` and `\[...\]`
+// display.
+var DefaultMathDelimiters = []MathDelimiter{
+	{Left: `$$`, Right: `$$`, Display: true},
+	{Left: `\[`, Right: `\]`, Display: true},
+	{Left: `$`, Right: `$`, Display: false},
+	{Left: `\(`, Right: `\)`, Display: false},
+}
+
+// TemplateVersion identifies Generate's output shape for cache-key
+// purposes (see filecache.Key in ConverterService). Bump it whenever a
+// change to htmlDocumentTemplate, pageCSS, or renderInitScript would
+// change a document's rendered bytes for the same inputs, so entries
+// cached before the change can't be served after it.
+const TemplateVersion = "1"
+
+// RenderCompleteSentinelID is the id of a zero-size element the render
+// initializer appends to <body> once mermaid.run() and
+// renderMathInElement() have both resolved. ConverterService.
+// generateWithChromedp waits on this element (via chromedp.WaitVisible,
+// or by polling window.__renderComplete, which the initializer also
+// sets) instead of printing to PDF the instant the DOM is ready, which
+// would race the async rendering and ship plain-text math/diagrams.
+const RenderCompleteSentinelID = "docflow-render-complete"
+
+// Generate wraps htmlContent - the HTML MarkdownService produced from a
+// Markdown document - in a full HTML document with print CSS and, per
+// the TemplateGenerator's RenderOptions, the KaTeX and/or Mermaid
+// assets and an auto-render initializer.
+func (t *TemplateGenerator) Generate(htmlContent string) string {
+	var head strings.Builder
+	head.WriteString(t.pageCSS())
+	if t.cover.Title != "" {
+		head.WriteString(coverCSS)
+	}
+	if t.tocDepth > 0 {
+		head.WriteString(tocCSS)
+	}
+	if t.themeCSS != "" {
+		fmt.Fprintf(&head, "<style>%s</style>\n", t.themeCSS)
+	}
+	if t.customCSS != "" {
+		fmt.Fprintf(&head, "<style>%s</style>\n", t.customCSS)
+	}
+
+	if t.render.EnableMath {
+		head.WriteString(katexAssetsHTML)
+	}
+	if t.render.EnableMermaid {
+		head.WriteString(mermaidAssetHTML)
+	}
+	if t.render.EnableMath || t.render.EnableMermaid {
+		head.WriteString(t.renderInitScript())
+	}
+
+	body := htmlContent
+	if t.tocDepth > 0 {
+		rewritten, entries := InjectHeadingIDs(body)
+		body = RenderTOC(entries, t.tocDepth) + rewritten
+	}
+	if t.cover.Title != "" {
+		body = t.cover.html() + body
+	}
+
+	return fmt.Sprintf(htmlDocumentTemplate, head.String(), body)
+}
+
+// pageCSS builds the `@page` rule from t.page, in the same inches unit
+// ConverterService's page.PrintToPDF call uses for WithPaperWidth/
+// WithPaperHeight/WithMargin*, so the two stay in sync.
+func (t *TemplateGenerator) pageCSS() string {
+	return fmt.Sprintf(`<style>
+@page {
+  size: %gin %gin;
+  margin: %gin %gin %gin %gin;
+}
+</style>
+`, t.page.WidthIn, t.page.HeightIn, t.page.MarginTopIn, t.page.MarginRightIn, t.page.MarginBottomIn, t.page.MarginLeftIn)
+}
+
+// renderInitScript builds the initializer that runs Mermaid and/or
+// KaTeX auto-render once the DOM is ready, then appends the sentinel
+// element generateWithChromedp waits on.
+func (t *TemplateGenerator) renderInitScript() string {
+	var tasks strings.Builder
+
+	if t.render.EnableMermaid {
+		tasks.WriteString(`tasks.push(mermaid.run({querySelector: ".mermaid, pre.mermaid, code.language-mermaid"}));`)
+	}
+	if t.render.EnableMath {
+		tasks.WriteString(fmt.Sprintf(`tasks.push(Promise.resolve(renderMathInElement(document.body, {delimiters: %s, throwOnError: false})));`, t.delimitersJSON()))
+	}
+
+	return fmt.Sprintf(`<script>
+window.__renderComplete = false;
+document.addEventListener("DOMContentLoaded", function () {
+  var tasks = [];
+  %s
+  Promise.all(tasks).catch(function () {}).then(function () {
+    window.__renderComplete = true;
+    var sentinel = document.createElement("div");
+    sentinel.id = %q;
+    sentinel.style.cssText = "width:0;height:0;overflow:hidden;";
+    document.body.appendChild(sentinel);
+  });
+});
+</script>
+`, tasks.String(), RenderCompleteSentinelID)
+}
+
+func (t *TemplateGenerator) delimitersJSON() string {
+	delims := t.render.MathDelimiters
+	if delims == nil {
+		delims = DefaultMathDelimiters
+	}
+
+	var b strings.Builder
+	b.WriteString("[")
+	for i, d := range delims {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{"left": %q, "right": %q, "display": %t}`, d.Left, d.Right, d.Display)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+const htmlDocumentTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+%s</head>
+<body>
+%s
+</body>
+</html>`
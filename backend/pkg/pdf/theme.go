@@ -0,0 +1,162 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// DefaultTheme is the theme name Generate uses when a request doesn't
+// specify one, or specifies one ThemeSet doesn't recognize.
+const DefaultTheme = "default"
+
+// builtinThemes are the themes every ThemeSet starts with. They cover
+// the style of print document docflow shipped before themes existed
+// (default), GitHub's rendered Markdown look, a serif academic-paper
+// look, and a wide-margin slide deck look.
+var builtinThemes = map[string]string{
+	"default":  defaultThemeCSS,
+	"github":   githubThemeCSS,
+	"academic": academicThemeCSS,
+	"slides":   slidesThemeCSS,
+}
+
+// ThemeSet is a named collection of CSS stylesheets TemplateGenerator
+// wraps the converted HTML body in. It starts pre-populated with the
+// builtin themes; Load lets an operator add or override entries from a
+// directory of .css files without recompiling. It is safe for
+// concurrent use.
+type ThemeSet struct {
+	mu     sync.RWMutex
+	themes map[string]string
+}
+
+// NewThemeSet returns a ThemeSet containing only the builtin themes.
+func NewThemeSet() *ThemeSet {
+	t := &ThemeSet{themes: make(map[string]string, len(builtinThemes))}
+	for name, css := range builtinThemes {
+		t.themes[name] = css
+	}
+	return t
+}
+
+// Load registers every *.css file directly under dir as a theme named
+// after its filename (minus the .css extension), overriding any builtin
+// or previously loaded theme of the same name. It returns an error if
+// dir can't be read; a malformed individual file is not possible since
+// CSS content is taken verbatim, not parsed.
+func (t *ThemeSet) Load(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("pdf: loading themes from %s: %w", dir, err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".css" {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".css")
+		css, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("pdf: loading theme %q: %w", name, err)
+		}
+		t.themes[name] = string(css)
+	}
+	return nil
+}
+
+// CSS returns the stylesheet registered under name and true, or false if
+// no theme by that name is registered.
+func (t *ThemeSet) CSS(name string) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	css, ok := t.themes[name]
+	return css, ok
+}
+
+// importOrJSURLPattern matches `@import` rules and `url(javascript:...)`
+// references, the two ways a stylesheet can reach outside the PDF print
+// context it's scoped to: @import can pull in a remote stylesheet (and
+// with it, tracking or a much larger page than the operator expects),
+// and a javascript: URL inside url() is a renderer-dependent but
+// nonzero-risk script execution vector. Everything else - colors,
+// fonts, @page, @media - is left untouched.
+var importOrJSURLPattern = regexp.MustCompile(`(?i)@import[^;]*;|url\(\s*['"]?\s*javascript:[^)]*\)`)
+
+// SanitizeCustomCSS strips @import rules and url(javascript:...)
+// references from css, for safe inclusion in the generated HTML
+// alongside a request's chosen theme.
+func SanitizeCustomCSS(css string) string {
+	return importOrJSURLPattern.ReplaceAllString(css, "")
+}
+
+const defaultThemeCSS = `
+body {
+  font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif;
+  line-height: 1.6;
+  color: #1a1a1a;
+}
+pre, code { font-family: "SFMono-Regular", Consolas, monospace; }
+pre { background: #f5f5f5; padding: 1em; overflow-x: auto; }
+h1, h2, h3, h4, h5, h6 { page-break-after: avoid; }
+table, figure, pre { page-break-inside: avoid; }
+img { max-width: 100%; }
+`
+
+const githubThemeCSS = `
+body {
+  font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif;
+  line-height: 1.5;
+  color: #1f2328;
+  max-width: 100%;
+}
+pre, code { font-family: ui-monospace, "SFMono-Regular", Consolas, monospace; }
+pre { background: #f6f8fa; padding: 1em; border-radius: 6px; overflow-x: auto; }
+code { background: #f6f8fa; padding: 0.2em 0.4em; border-radius: 6px; }
+pre code { background: transparent; padding: 0; }
+blockquote { color: #59636e; border-left: 0.25em solid #d1d9e0; padding: 0 1em; margin-left: 0; }
+table { border-collapse: collapse; }
+table th, table td { border: 1px solid #d1d9e0; padding: 0.4em 0.8em; }
+h1, h2, h3, h4, h5, h6 { page-break-after: avoid; }
+table, figure, pre { page-break-inside: avoid; }
+img { max-width: 100%; }
+`
+
+const academicThemeCSS = `
+body {
+  font-family: "Times New Roman", Georgia, serif;
+  line-height: 1.8;
+  color: #111;
+  text-align: justify;
+}
+h1, h2, h3, h4, h5, h6 {
+  font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif;
+  page-break-after: avoid;
+}
+pre, code { font-family: "SFMono-Regular", Consolas, monospace; }
+pre { background: #f5f5f5; padding: 1em; overflow-x: auto; }
+blockquote { font-style: italic; border-left: 2px solid #888; padding-left: 1em; margin-left: 0; }
+table, figure, pre { page-break-inside: avoid; }
+img { max-width: 100%; }
+`
+
+const slidesThemeCSS = `
+body {
+  font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif;
+  line-height: 1.4;
+  color: #1a1a1a;
+  font-size: 1.3em;
+}
+h1, h2, h3 { page-break-before: always; page-break-after: avoid; }
+h1:first-child, h2:first-child, h3:first-child { page-break-before: avoid; }
+pre, code { font-family: "SFMono-Regular", Consolas, monospace; }
+pre { background: #f5f5f5; padding: 1em; overflow-x: auto; }
+table, figure, pre { page-break-inside: avoid; }
+img { max-width: 100%; }
+`
@@ -0,0 +1,134 @@
+package pdf
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// headingRe matches an <h1>-<h6> element, capturing its level, its
+// existing attribute string (if any), and its inner HTML. Like
+// pkg/epub's firstHeadingRe, the closing tag isn't required to match the
+// same level - Go's RE2 engine has no backreferences - which is safe in
+// practice since MarkdownService never nests headings.
+var headingRe = regexp.MustCompile(`(?is)<h([1-6])([^>]*)>(.*?)</h[1-6]>`)
+
+// idAttrRe extracts an existing id="..." from a heading's attribute
+// string, so InjectHeadingIDs reuses rather than overwrites an id
+// MarkdownService (or hand-authored HTML in the source Markdown) already
+// assigned.
+var idAttrRe = regexp.MustCompile(`(?i)\bid\s*=\s*"([^"]*)"`)
+
+// tagRe strips HTML tags from a heading's inner HTML to recover its
+// plain-text title, for both the slug and the TOC's link text.
+var tagRe = regexp.MustCompile(`<[^>]+>`)
+
+// nonSlugRe matches runs of characters a slug ID shouldn't contain.
+var nonSlugRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// TOCEntry is one heading InjectHeadingIDs found, in document order.
+type TOCEntry struct {
+	Level int
+	ID    string
+	Text  string
+}
+
+// InjectHeadingIDs assigns a stable slug ID to every <h1>-<h6> in
+// htmlContent that doesn't already have one, deduplicating on collision
+// by appending "-2", "-3", etc., and returns the rewritten HTML
+// alongside every heading found, regardless of level - RenderTOC is what
+// applies a TOCDepth cutoff. The IDs double as anchor targets for the
+// rendered TOC's links and, on print engines that support it, as
+// bookmark/outline entries.
+func InjectHeadingIDs(htmlContent string) (string, []TOCEntry) {
+	seen := make(map[string]int)
+	var entries []TOCEntry
+
+	rewritten := headingRe.ReplaceAllStringFunc(htmlContent, func(match string) string {
+		m := headingRe.FindStringSubmatch(match)
+		level, _ := strconv.Atoi(m[1])
+		attrs, inner := m[2], m[3]
+
+		text := strings.TrimSpace(html.UnescapeString(tagRe.ReplaceAllString(inner, "")))
+
+		id := ""
+		if existing := idAttrRe.FindStringSubmatch(attrs); existing != nil {
+			id = existing[1]
+		}
+		if id == "" {
+			id = slugify(text)
+		}
+		if id == "" {
+			id = fmt.Sprintf("section-%d", len(entries)+1)
+		}
+		if n, ok := seen[id]; ok {
+			seen[id] = n + 1
+			id = fmt.Sprintf("%s-%d", id, n+1)
+		} else {
+			seen[id] = 1
+		}
+
+		entries = append(entries, TOCEntry{Level: level, ID: id, Text: text})
+
+		escapedID := html.EscapeString(id)
+		if idAttrRe.MatchString(attrs) {
+			attrs = idAttrRe.ReplaceAllString(attrs, fmt.Sprintf(`id="%s"`, escapedID))
+		} else {
+			attrs = fmt.Sprintf(` id="%s"%s`, escapedID, attrs)
+		}
+		return fmt.Sprintf("<h%d%s>%s</h%d>", level, attrs, inner, level)
+	})
+
+	return rewritten, entries
+}
+
+// slugify lowercases s and collapses everything but letters and digits
+// into single hyphens, trimming any leading or trailing hyphen.
+func slugify(s string) string {
+	return strings.Trim(nonSlugRe.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// WithTOC enables Generate's table of contents page, collecting headings
+// down to depth levels deep (e.g. depth 2 collects h1 and h2 only). A
+// depth of 0 or less disables the TOC - the zero value TemplateGenerator
+// starts with.
+func WithTOC(depth int) TemplateOption {
+	return func(t *TemplateGenerator) {
+		t.tocDepth = depth
+	}
+}
+
+// RenderTOC builds a <nav class="docflow-toc"> listing every entry whose
+// Level is at most depth, each linking to its heading's ID. Page numbers
+// are left for print-time: tocCSS's target-counter(attr(href), page)
+// rule fills them in, which Chromium's paged-media implementation (what
+// chromedp's PrintToPDF uses) supports.
+func RenderTOC(entries []TOCEntry, depth int) string {
+	var b strings.Builder
+	b.WriteString("<nav class=\"docflow-toc\">\n<ol>\n")
+	for _, e := range entries {
+		if e.Level > depth {
+			continue
+		}
+		fmt.Fprintf(&b, "<li class=\"docflow-toc-level-%d\"><a href=\"#%s\">%s<span class=\"docflow-toc-page\"></span></a></li>\n",
+			e.Level, html.EscapeString(e.ID), html.EscapeString(e.Text))
+	}
+	b.WriteString("</ol>\n</nav>\n")
+	return b.String()
+}
+
+const tocCSS = `
+.docflow-toc { page-break-after: always; }
+.docflow-toc ol { list-style: none; padding-left: 0; margin: 0; }
+.docflow-toc li { margin: 0.35em 0; }
+.docflow-toc li a { display: flex; text-decoration: none; color: inherit; }
+.docflow-toc .docflow-toc-page { margin-left: auto; padding-left: 1em; }
+.docflow-toc .docflow-toc-page::after { content: target-counter(attr(href), page); }
+.docflow-toc-level-2 { margin-left: 1.2em; }
+.docflow-toc-level-3 { margin-left: 2.4em; }
+.docflow-toc-level-4 { margin-left: 3.6em; }
+.docflow-toc-level-5 { margin-left: 4.8em; }
+.docflow-toc-level-6 { margin-left: 6em; }
+`
@@ -1,19 +1,41 @@
 package docflow
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/xgaslan/docflow/sdks/go/docflow/batchprogress"
 	"github.com/xgaslan/docflow/sdks/go/docflow/config"
 	"github.com/xgaslan/docflow/sdks/go/docflow/rag"
 )
 
-// jobRequest wraps a job and its files for processing
+// jobRequest wraps a job, its files, and its cancellation context for
+// processing. startIndex lets a rehydrated job resume after whatever
+// prefix of files the JobStore had already checkpointed.
 type jobRequest struct {
-	job   *BatchJob
-	files []string
+	ctx        context.Context
+	job        *BatchJob
+	files      []string
+	startIndex int
+}
+
+// BatchProcessorOption configures optional BatchProcessor behavior.
+type BatchProcessorOption func(*BatchProcessor)
+
+// WithJobStore makes the BatchProcessor persist jobs through store, so
+// NewBatchProcessor can rehydrate anything left pending or processing
+// after a crash or restart, resuming each job from its last per-file
+// checkpoint instead of redoing it from scratch. Without this option, a
+// BatchProcessor behaves exactly as before: jobs live only in memory
+// and are lost on restart.
+func WithJobStore(store JobStore) BatchProcessorOption {
+	return func(bp *BatchProcessor) {
+		bp.store = store
+	}
 }
 
 // BatchProcessor handles multi-file queue processing.
@@ -21,20 +43,26 @@ type BatchProcessor struct {
 	ragConfig   config.RAGConfig
 	batchConfig config.BatchConfig
 	maxWorkers  int
+	store       JobStore
 
 	jobs    sync.Map // map[string]*BatchJob
 	queue   chan jobRequest
 	workers sync.WaitGroup
 }
 
-// NewBatchProcessor creates a new batch processor.
-func NewBatchProcessor(ragConfig config.RAGConfig, batchConfig config.BatchConfig) *BatchProcessor {
+// NewBatchProcessor creates a new batch processor. With WithJobStore,
+// it also rehydrates and re-enqueues any job the store still has
+// pending or processing from a previous run.
+func NewBatchProcessor(ragConfig config.RAGConfig, batchConfig config.BatchConfig, opts ...BatchProcessorOption) *BatchProcessor {
 	if batchConfig.MaxWorkers <= 0 {
 		batchConfig.MaxWorkers = 4
 	}
 	if batchConfig.QueueSize <= 0 {
 		batchConfig.QueueSize = 100
 	}
+	if batchConfig.MaxRetries <= 0 {
+		batchConfig.MaxRetries = 3
+	}
 
 	bp := &BatchProcessor{
 		ragConfig:   ragConfig,
@@ -42,11 +70,48 @@ func NewBatchProcessor(ragConfig config.RAGConfig, batchConfig config.BatchConfi
 		maxWorkers:  batchConfig.MaxWorkers,
 		queue:       make(chan jobRequest, batchConfig.QueueSize),
 	}
+	for _, opt := range opts {
+		opt(bp)
+	}
 
 	bp.startWorkers()
+	if bp.store != nil {
+		bp.rehydrate()
+	}
 	return bp
 }
 
+// rehydrate re-enqueues every job the store still has pending or
+// processing, so a restart resumes them instead of losing them.
+func (bp *BatchProcessor) rehydrate() {
+	pending, err := bp.store.LoadPending()
+	if err != nil {
+		return
+	}
+
+	for _, sj := range pending {
+		ctx, cancel := context.WithCancel(context.Background())
+		job := &BatchJob{
+			JobID:          sj.JobID,
+			Status:         config.JobStatusPending,
+			TotalFiles:     len(sj.Files),
+			ProcessedFiles: sj.Checkpoint,
+			Errors:         make(map[string]string),
+			Progress:       make(chan batchprogress.Event, 2*len(sj.Files)+1),
+			cancel:         cancel,
+		}
+		bp.jobs.Store(sj.JobID, job)
+
+		select {
+		case bp.queue <- jobRequest{ctx: ctx, job: job, files: sj.Files, startIndex: sj.Checkpoint}:
+		default:
+			// Queue is already full of other rehydrated jobs; leave this
+			// one in the store so the next restart picks it up.
+			cancel()
+		}
+	}
+}
+
 func (bp *BatchProcessor) startWorkers() {
 	for i := 0; i < bp.maxWorkers; i++ {
 		go bp.worker()
@@ -64,37 +129,27 @@ func (bp *BatchProcessor) processJob(req jobRequest) {
 	job.Status = config.JobStatusProcessing
 	processor := rag.NewRAGProcessor(bp.ragConfig)
 
-	// In a real implementation, we would process files in parallel too if job has multiple files
-	// For simplicity here, we process files sequentially or we could spawn goroutines
-	// But since we have worker pool for jobs, maybe files within job?
-	// The Python implementation uses thread pool for files.
-	// We can do semantic equivalent using semaphores or separate worker pool for files if needed.
-	// For now, let's just loop.
-
-	// Assuming job.Files is somehow passed or stored.
-	// The Python enqueue takes 'files' list. BatchJob struct might need to store file paths.
-	// Let's assume BatchJob has a way to access files (not defined in types.go yet? Check types.go)
-
-	// Wait, types.go BatchJob struct:
-	// type BatchJob struct {
-	// 	JobID          string                  `json:"job_id"`
-	// 	Status         JobStatus               `json:"status"`
-	// 	Results        []rag.RAGDocument       `json:"results,omitempty"`
-	// 	Errors         map[string]string       `json:"errors,omitempty"`
-	// 	TotalFiles     int                     `json:"total_files"`
-	// 	ProcessedFiles int                     `json:"processed_files"`
-	// 	FailedFiles    int                     `json:"failed_files"`
-	// 	CreatedAt      time.Time               `json:"created_at"`
-	// 	CompletedAt    time.Time               `json:"completed_at,omitempty"`
-	// }
-	// It doesn't store input files! Python stores them in closure or somewhere?
-	// Python: enqueue(files) -> creates job -> starts _process_queue_job(job_id, files).
-	// So the job struct itself doesn't strictly need to hold the file list if the processor function closes over it.
-	// However, in Go, we need to pass the file list to the worker via the channel struct.
-	// So we should define a wrapper or extend BatchJob.
-
-	// Process files
-	for _, f := range req.files {
+	for i := req.startIndex; i < len(req.files); i++ {
+		f := req.files[i]
+
+		select {
+		case <-req.ctx.Done():
+			// Cancelled before this file started; everything still
+			// queued is dropped rather than counted as failed.
+			job.Status = config.JobStatusFailed
+			job.Errors["_job"] = "cancelled"
+			if bp.store != nil {
+				bp.store.Nack(job.JobID, "cancelled", bp.batchConfig.MaxRetries)
+			}
+			bp.publish(job, batchprogress.Event{Type: batchprogress.EventJobCompleted, JobID: job.JobID})
+			close(job.Progress)
+			return
+		default:
+		}
+
+		bp.publish(job, batchprogress.Event{Type: batchprogress.EventFileStarted, JobID: job.JobID, File: f})
+		start := time.Now()
+
 		doc, err := processor.ProcessFile(f)
 		if err != nil {
 			job.FailedFiles++
@@ -102,9 +157,15 @@ func (bp *BatchProcessor) processJob(req jobRequest) {
 				job.Errors = make(map[string]string)
 			}
 			job.Errors[f] = err.Error()
+			bp.publish(job, batchprogress.Event{Type: batchprogress.EventFileFailed, JobID: job.JobID, File: f, Err: err})
 
 			if bp.batchConfig.FailFast {
 				job.Status = config.JobStatusFailed
+				if bp.store != nil {
+					bp.store.Nack(job.JobID, err.Error(), bp.batchConfig.MaxRetries)
+				}
+				bp.publish(job, batchprogress.Event{Type: batchprogress.EventJobCompleted, JobID: job.JobID})
+				close(job.Progress)
 				return
 			}
 			continue
@@ -112,42 +173,94 @@ func (bp *BatchProcessor) processJob(req jobRequest) {
 
 		job.Results = append(job.Results, *doc)
 		job.ProcessedFiles++
+		if bp.store != nil {
+			bp.store.Checkpoint(job.JobID, i+1)
+		}
+
+		var size int64
+		if info, statErr := os.Stat(f); statErr == nil {
+			size = info.Size()
+		}
+		bp.publish(job, batchprogress.Event{
+			Type:     batchprogress.EventFileCompleted,
+			JobID:    job.JobID,
+			File:     f,
+			Bytes:    size,
+			Duration: time.Since(start),
+		})
 	}
 
 	if job.Status != config.JobStatusFailed {
 		job.Status = config.JobStatusCompleted
 		now := time.Now()
-		job.CompletedAt = &now // Assumes CreatedAt/CompletedAt are *time.Time or interface{}
+		job.CompletedAt = &now
+		if bp.store != nil {
+			bp.store.Ack(job.JobID)
+		}
 	}
+	bp.publish(job, batchprogress.Event{Type: batchprogress.EventJobCompleted, JobID: job.JobID})
+	close(job.Progress)
 }
 
-// Enqueue adds files to the processing queue.
+// publish sends e on job.Progress without blocking the worker if
+// nothing is currently draining it.
+func (bp *BatchProcessor) publish(job *BatchJob, e batchprogress.Event) {
+	select {
+	case job.Progress <- e:
+	default:
+	}
+}
+
+// Enqueue adds files to the processing queue and returns the new job's
+// ID. The returned job's Progress channel streams structured events as
+// files are processed; callers that don't intend to drain it should
+// still periodically call GetStatus rather than leak it unread, since
+// it's closed (not garbage-collected) only once the job finishes.
 func (bp *BatchProcessor) Enqueue(files []string) (string, error) {
 	jobID := uuid.New().String()
 	now := time.Now()
+	ctx, cancel := context.WithCancel(context.Background())
 	job := &BatchJob{
 		JobID:      jobID,
 		Status:     config.JobStatusPending,
 		TotalFiles: len(files),
 		CreatedAt:  &now,
 		Errors:     make(map[string]string),
+		Progress:   make(chan batchprogress.Event, 2*len(files)+1),
+		cancel:     cancel,
 	}
 
-	bp.jobs.Store(jobID, job)
+	if bp.store != nil {
+		if err := bp.store.Enqueue(jobID, files); err != nil {
+			cancel()
+			return "", fmt.Errorf("batch: persist job: %w", err)
+		}
+	}
 
-	// In a real app we might want to persist this.
+	bp.jobs.Store(jobID, job)
 
-	// Send to worker (this effectively limits concurrency by queue size/workers)
-	// Only if I change channel type.
-	// Let's define queue type properly.
 	select {
-	case bp.queue <- jobRequest{job: job, files: files}:
+	case bp.queue <- jobRequest{ctx: ctx, job: job, files: files}:
 		return jobID, nil
 	default:
+		cancel()
 		return "", fmt.Errorf("queue is full")
 	}
 }
 
+// Cancel aborts a queued or in-flight job. Files already started
+// finish (rag.ProcessFile has no cancellation point of its own); no
+// further files in that job are started. Cancel is a no-op for jobs
+// that have already finished.
+func (bp *BatchProcessor) Cancel(jobID string) error {
+	job, err := bp.GetStatus(jobID)
+	if err != nil {
+		return err
+	}
+	job.cancel()
+	return nil
+}
+
 // ProcessFiles synchronously processes files.
 func (bp *BatchProcessor) ProcessFiles(files []string) ([]*rag.RAGDocument, error) {
 	processor := rag.NewRAGProcessor(bp.ragConfig)
@@ -185,3 +298,13 @@ func (bp *BatchProcessor) GetResult(jobID string) ([]rag.RAGDocument, error) {
 	}
 	return job.Results, nil
 }
+
+// GetDeadLetters returns jobs that exhausted batchConfig.MaxRetries
+// attempts, for a caller to inspect or manually resubmit. Returns an
+// empty slice (not an error) when no JobStore is configured.
+func (bp *BatchProcessor) GetDeadLetters() ([]*StoredJob, error) {
+	if bp.store == nil {
+		return nil, nil
+	}
+	return bp.store.DeadLetters()
+}
@@ -0,0 +1,50 @@
+// Package batchprogress defines the structured events BatchProcessor
+// jobs emit as they run, and the Reporter interface consumers render
+// those events through (a terminal bar, Prometheus counters, ...).
+package batchprogress
+
+import "time"
+
+// EventType identifies what a BatchProcessor job is reporting.
+type EventType string
+
+const (
+	// EventFileStarted is emitted right before a file enters ProcessFile.
+	EventFileStarted EventType = "file_started"
+	// EventFileCompleted is emitted after a file is processed successfully.
+	EventFileCompleted EventType = "file_completed"
+	// EventFileFailed is emitted after a file fails processing.
+	EventFileFailed EventType = "file_failed"
+	// EventJobCompleted is emitted once after the job's last file, success
+	// or failure. It is always the final Event sent before Progress closes.
+	EventJobCompleted EventType = "job_completed"
+)
+
+// Event is a single structured progress update for a BatchProcessor job.
+// Which fields are meaningful depends on Type: Bytes and Duration are
+// only set on EventFileCompleted, Err only on EventFileFailed.
+type Event struct {
+	Type     EventType
+	JobID    string
+	File     string
+	Bytes    int64
+	Duration time.Duration
+	Err      error
+}
+
+// Reporter consumes Events, typically by draining a job's Progress
+// channel until it's closed. Report must return quickly - the
+// BatchProcessor worker publishes onto a bounded channel and stalls if
+// nothing drains it.
+type Reporter interface {
+	Report(e Event)
+}
+
+// Watch drains ch, calling r.Report for every Event until ch is closed.
+// Callers typically start this in its own goroutine right after
+// Enqueue, alongside polling GetStatus.
+func Watch(ch <-chan Event, r Reporter) {
+	for e := range ch {
+		r.Report(e)
+	}
+}
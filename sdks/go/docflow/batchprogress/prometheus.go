@@ -0,0 +1,39 @@
+package batchprogress
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// PrometheusReporter records BatchProcessor Events as Prometheus
+// counters, so a host service can expose batch throughput on its own
+// /metrics endpoint alongside its other collectors.
+type PrometheusReporter struct {
+	filesTotal *prometheus.CounterVec
+	bytesTotal prometheus.Counter
+}
+
+// NewPrometheusReporter creates a PrometheusReporter and registers its
+// collectors with reg (typically prometheus.DefaultRegisterer).
+func NewPrometheusReporter(reg prometheus.Registerer) *PrometheusReporter {
+	p := &PrometheusReporter{
+		filesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "docflow_batch_files_total",
+			Help: "Batch files processed, labeled by outcome status.",
+		}, []string{"status"}),
+		bytesTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "docflow_batch_bytes_total",
+			Help: "Total bytes processed across all completed batch files.",
+		}),
+	}
+	reg.MustRegister(p.filesTotal, p.bytesTotal)
+	return p
+}
+
+// Report implements Reporter.
+func (p *PrometheusReporter) Report(e Event) {
+	switch e.Type {
+	case EventFileCompleted:
+		p.filesTotal.WithLabelValues("completed").Inc()
+		p.bytesTotal.Add(float64(e.Bytes))
+	case EventFileFailed:
+		p.filesTotal.WithLabelValues("failed").Inc()
+	}
+}
@@ -0,0 +1,40 @@
+package batchprogress
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+)
+
+// TerminalReporter renders a live progress bar with throughput and ETA
+// for a single BatchProcessor job, for use by CLIs driving long-running
+// batch conversions.
+type TerminalReporter struct {
+	bar *progressbar.ProgressBar
+}
+
+// NewTerminalReporter creates a TerminalReporter sized for totalFiles.
+func NewTerminalReporter(totalFiles int) *TerminalReporter {
+	bar := progressbar.NewOptions(totalFiles,
+		progressbar.OptionSetDescription("converting"),
+		progressbar.OptionShowCount(),
+		progressbar.OptionShowIts(),
+		progressbar.OptionSetItsString("files"),
+		progressbar.OptionThrottle(100*time.Millisecond),
+		progressbar.OptionShowElapsedTimeOnFinish(),
+	)
+	return &TerminalReporter{bar: bar}
+}
+
+// Report implements Reporter, advancing the bar by one on every
+// finished file (successful or not) and finishing it on EventJobCompleted.
+func (t *TerminalReporter) Report(e Event) {
+	switch e.Type {
+	case EventFileCompleted, EventFileFailed:
+		_ = t.bar.Add(1)
+	case EventJobCompleted:
+		_ = t.bar.Finish()
+		fmt.Println()
+	}
+}
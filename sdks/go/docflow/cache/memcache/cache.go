@@ -0,0 +1,184 @@
+// Package memcache provides a single process-wide, memory-aware LRU cache
+// for parsed documents, chunk lists, and rendered HTML so repeated
+// conversions of the same content don't redo the work.
+package memcache
+
+import (
+	"container/list"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// entry is a single cache slot tracked by the LRU list.
+type entry struct {
+	key   string
+	value any
+	size  int64
+}
+
+// Cache is a memory-budgeted, least-recently-used cache.
+type Cache struct {
+	mu       sync.Mutex
+	items    map[string]*list.Element
+	order    *list.List
+	size     int64
+	budget   int64
+	maxItems int
+	memCheck func() int64 // returns current HeapInuse, overridable in tests
+
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+// Stats reports a Cache's cumulative hit/miss/eviction counts and its
+// current entry/byte totals.
+type Stats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Items     int
+	Bytes     int64
+}
+
+// New creates a cache with the given byte budget and no item-count
+// ceiling. A budget of 0 falls back to DefaultBudget().
+func New(budget int64) *Cache {
+	return NewWithLimits(budget, 0)
+}
+
+// NewWithLimits creates a cache with both a byte budget and a maximum
+// entry count; either limit being exceeded triggers eviction. A budget
+// of 0 falls back to DefaultBudget(); maxItems of 0 means no item-count
+// ceiling.
+func NewWithLimits(budget int64, maxItems int) *Cache {
+	if budget <= 0 {
+		budget = DefaultBudget()
+	}
+	return &Cache{
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+		budget:   budget,
+		maxItems: maxItems,
+		memCheck: currentHeapInuse,
+	}
+}
+
+// GetOrCreate returns the cached value for key, calling create to
+// populate the cache on a miss. create must return the value, its
+// estimated size in bytes, and any error.
+func (c *Cache) GetOrCreate(key string, create func() (any, int64, error)) (any, error) {
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		c.order.MoveToFront(el)
+		v := el.Value.(*entry).value
+		c.hits++
+		c.mu.Unlock()
+		return v, nil
+	}
+	c.mu.Unlock()
+
+	value, size, err := create()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another goroutine may have populated it while we were creating.
+	if el, ok := c.items[key]; ok {
+		c.order.MoveToFront(el)
+		c.hits++
+		return el.Value.(*entry).value, nil
+	}
+
+	c.misses++
+	el := c.order.PushFront(&entry{key: key, value: value, size: size})
+	c.items[key] = el
+	c.size += size
+
+	c.evictLocked()
+
+	return value, nil
+}
+
+// Stats returns a snapshot of the cache's cumulative counters and
+// current occupancy.
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Stats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Items:     c.order.Len(),
+		Bytes:     c.size,
+	}
+}
+
+// Len returns the number of cached entries.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Size returns the sum of tracked entry sizes in bytes.
+func (c *Cache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// evictLocked removes least-recently-used entries until the cache is
+// back under 80% of budget and, if set, at or under maxItems - either
+// because the tracked size exceeds the budget, process heap usage
+// exceeds it, or there are simply too many entries.
+func (c *Cache) evictLocked() {
+	target := int64(float64(c.budget) * 0.8)
+
+	overBudget := c.size > c.budget
+	overHeap := c.memCheck() > c.budget
+	overCount := c.maxItems > 0 && c.order.Len() > c.maxItems
+
+	for (overBudget || overHeap || overCount) && c.order.Len() > 0 {
+		back := c.order.Back()
+		e := back.Value.(*entry)
+		c.order.Remove(back)
+		delete(c.items, e.key)
+		c.size -= e.size
+		c.evictions++
+
+		overBudget = c.size > target
+		overHeap = c.memCheck() > c.budget
+		overCount = c.maxItems > 0 && c.order.Len() > c.maxItems
+	}
+}
+
+// currentHeapInuse returns runtime.MemStats.HeapInuse.
+func currentHeapInuse() int64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return int64(stats.HeapInuse)
+}
+
+// DefaultBudget computes min(DOCFLOW_MEMORYLIMIT env var, totalSystemMemory/4),
+// falling back to a quarter of the process's current heap usage if the
+// total system memory can't be determined.
+func DefaultBudget() int64 {
+	if v, ok := os.LookupEnv("DOCFLOW_MEMORYLIMIT"); ok {
+		if n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil && n > 0 {
+			return n
+		}
+	}
+
+	if total := totalSystemMemory(); total > 0 {
+		return total / 4
+	}
+
+	return currentHeapInuse() / 4
+}
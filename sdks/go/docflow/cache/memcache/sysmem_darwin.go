@@ -0,0 +1,20 @@
+package memcache
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// totalSystemMemory shells out to `sysctl hw.memsize`, in bytes.
+func totalSystemMemory() int64 {
+	out, err := exec.Command("sysctl", "-n", "hw.memsize").Output()
+	if err != nil {
+		return 0
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
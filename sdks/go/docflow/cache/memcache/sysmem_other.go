@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package memcache
+
+// totalSystemMemory is unknown on platforms without a /proc/meminfo or
+// sysctl hw.memsize; callers fall back to a heap-based budget.
+func totalSystemMemory() int64 {
+	return 0
+}
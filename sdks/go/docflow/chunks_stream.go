@@ -0,0 +1,188 @@
+package docflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+	"github.com/xgaslan/docflow/sdks/go/docflow/rag"
+)
+
+// ExtractToChunks streams a PDF as rag.Chunk values over a channel,
+// chunking page by page as the backend's own page breaks are consumed
+// ("\f" between pages - a convention all three pdfBackend implementations
+// already follow) instead of materializing the whole document's markdown
+// before chunking starts.
+//
+// cfg.SplitBy selects the underlying chunker: config.SplitByToken uses a
+// rag.TokenChunker (cfg.Tokenizer, falling back to whitespace), anything
+// else uses a rag.RecursiveChunker. Per-chunk metadata (HeadingPath,
+// HasTable, HasImage, ContentType, Page) is attributed per page.
+//
+// Chunking boundaries never span pages, so a heading-aware section that
+// straddles a page break is chunked as two sections instead of one; for
+// documents where that matters more than streaming does,
+// ExtractStructured plus rag.Chunker's whole-document heading-aware
+// chunking is the better fit.
+func (e *Extractor) ExtractToChunks(ctx context.Context, pdfData []byte, filename string, cfg config.ChunkingConfig) (<-chan rag.Chunk, error) {
+	if len(pdfData) == 0 {
+		return nil, fmt.Errorf("PDF data is required")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	baseName := strings.TrimSuffix(filename, filepath.Ext(filename))
+	pages, images, err := e.extractPageTexts(ctx, pdfData, baseName)
+	if err != nil {
+		return nil, err
+	}
+
+	chunker, err := newPageChunker(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan rag.Chunk)
+	go streamPageChunks(ctx, pages, images, chunker, out)
+	return out, nil
+}
+
+// pageChunker is the subset of the rag package's chunker types
+// ExtractToChunks needs: split one page's markdown into chunks.
+type pageChunker interface {
+	Chunk(text string) ([]rag.Chunk, rag.ChunkerMetrics)
+}
+
+func newPageChunker(cfg config.ChunkingConfig) (pageChunker, error) {
+	if cfg.SplitBy == config.SplitByToken {
+		return rag.NewTokenChunker(cfg)
+	}
+	return rag.NewRecursiveChunker(cfg), nil
+}
+
+// extractPageTexts runs the configured backend once, splits its output on
+// the "\f" page breaks into per-page markdown, and extracts embedded
+// images (pdfcpu, independent of which backend did the text extraction).
+func (e *Extractor) extractPageTexts(ctx context.Context, pdfData []byte, baseName string) ([]string, []rag.ExtractedImage, error) {
+	if err := os.MkdirAll(e.options.TempDir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	safeName := sanitizeFilename(baseName)
+	tempPDFPath := filepath.Join(e.options.TempDir, fmt.Sprintf("%s_%d_chunks.pdf", safeName, time.Now().UnixNano()))
+	if err := os.WriteFile(tempPDFPath, pdfData, 0644); err != nil {
+		return nil, nil, fmt.Errorf("failed to write temp PDF: %w", err)
+	}
+	defer os.Remove(tempPDFPath)
+
+	backend, err := resolveBackend(e.options, e.options.Backend)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	text, err := backend.extractText(ctx, tempPDFPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rawPages := strings.Split(text, "\f")
+	pages := make([]string, len(rawPages))
+	for i, raw := range rawPages {
+		pages[i] = e.bodyToMarkdown(raw)
+	}
+
+	return pages, extractImages(pdfData, baseName), nil
+}
+
+// streamPageChunks chunks each page in turn, attributing HeadingPath/Page/
+// HasTable/HasImage/ContentType before sending each chunk on out. It runs
+// in its own goroutine; out is closed when every page has been processed
+// or ctx is canceled.
+func streamPageChunks(ctx context.Context, pages []string, images []rag.ExtractedImage, chunker pageChunker, out chan<- rag.Chunk) {
+	defer close(out)
+
+	var headingPath []string
+	var headingLevels []int
+	globalIndex := 0
+
+	for pageNum, pageText := range pages {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		pageMarkdown, tables := renderTables(pageText)
+		if path, levels, ok := lastHeading(pageMarkdown); ok {
+			headingPath, headingLevels = path, levels
+		}
+
+		pageHasImage := false
+		for _, img := range images {
+			if img.Page == pageNum+1 {
+				pageHasImage = true
+				break
+			}
+		}
+
+		chunks, _ := chunker.Chunk(pageMarkdown)
+		for _, chunk := range chunks {
+			chunk.Index = globalIndex
+			chunk.Metadata = rag.ChunkMetadata{
+				HeadingPath:   headingPath,
+				HeadingLevels: headingLevels,
+				HasTable:      len(tables) > 0 && strings.Contains(chunk.Content, "|"),
+				HasImage:      pageHasImage,
+				Page:          pageNum + 1,
+				ContentType:   chunkContentType(chunk.Content, len(tables) > 0, pageHasImage),
+			}
+
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				return
+			}
+			globalIndex++
+		}
+	}
+}
+
+// lastHeading returns the deepest "## " (or deeper) heading found in
+// markdown, for carrying a running HeadingPath across pages.
+func lastHeading(markdown string) ([]string, []int, bool) {
+	var text string
+	var level int
+	found := false
+
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimLeft(line, "#")
+		depth := len(line) - len(trimmed)
+		if depth == 0 || !strings.HasPrefix(trimmed, " ") {
+			continue
+		}
+		text = strings.TrimSpace(trimmed)
+		level = depth
+		found = true
+	}
+
+	if !found {
+		return nil, nil, false
+	}
+	return []string{text}, []int{level}, true
+}
+
+func chunkContentType(content string, pageHasTable, pageHasImage bool) string {
+	switch {
+	case pageHasTable && strings.Contains(content, "|"):
+		return "table"
+	case pageHasImage && strings.Contains(content, "!["):
+		return "image"
+	default:
+		return "text"
+	}
+}
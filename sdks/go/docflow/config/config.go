@@ -14,6 +14,16 @@ const (
 	SplitByToken     SplitBy = "token"
 	SplitByCharacter SplitBy = "character"
 	SplitByHeading   SplitBy = "heading"
+
+	// SplitByRecursive splits at the first separator (from Separators)
+	// that produces pieces within ChunkSize, recursing into the next
+	// separator down for any piece that is still too large.
+	SplitByRecursive SplitBy = "recursive"
+
+	// SplitBySemantic splits on sentences, embeds each one, and cuts
+	// wherever the distance between consecutive sentence embeddings
+	// exceeds a breakpoint threshold.
+	SplitBySemantic SplitBy = "semantic"
 )
 
 // ChunkingConfig configures text chunking.
@@ -31,6 +41,18 @@ type ChunkingConfig struct {
 	// Token settings
 	Tokenizer string `json:"tokenizer"`
 
+	// TokenAwareOverlap, when true, sizes chunk overlap in tokens (via
+	// Tokenizer) instead of characters.
+	TokenAwareOverlap bool `json:"token_aware_overlap"`
+
+	// Semantic splitting settings. A breakpoint is a point between two
+	// sentences whose embedding distance exceeds the threshold; exactly
+	// one of SemanticBreakpointPercentile or SemanticBreakpointStddev is
+	// used to derive it, with percentile taking precedence when both are
+	// set.
+	SemanticBreakpointPercentile float64 `json:"semantic_breakpoint_percentile"`
+	SemanticBreakpointStddev     float64 `json:"semantic_breakpoint_stddev"`
+
 	// Heading-aware settings
 	RespectHeadings    bool `json:"respect_headings"`
 	KeepTablesTogether bool `json:"keep_tables_together"`
@@ -44,18 +66,19 @@ type ChunkingConfig struct {
 // DefaultChunkingConfig returns default chunking configuration.
 func DefaultChunkingConfig() ChunkingConfig {
 	return ChunkingConfig{
-		ChunkSize:          1000,
-		ChunkOverlap:       200,
-		MinChunkSize:       100,
-		MaxChunkSize:       2000,
-		SplitBy:            SplitByParagraph,
-		Separators:         []string{"\n\n", "\n", ". ", " "},
-		Tokenizer:          "cl100k_base",
-		RespectHeadings:    true,
-		KeepTablesTogether: true,
-		KeepCodeTogether:   true,
-		AddChunkMarkers:    true,
-		MarkerFormat:       "[CHUNK %d]",
+		ChunkSize:                    1000,
+		ChunkOverlap:                 200,
+		MinChunkSize:                 100,
+		MaxChunkSize:                 2000,
+		SplitBy:                      SplitByParagraph,
+		Separators:                   []string{"\n\n", "\n", ". ", " "},
+		Tokenizer:                    "cl100k_base",
+		SemanticBreakpointPercentile: 95,
+		RespectHeadings:              true,
+		KeepTablesTogether:           true,
+		KeepCodeTogether:             true,
+		AddChunkMarkers:              true,
+		MarkerFormat:                 "[CHUNK %d]",
 	}
 }
 
@@ -73,6 +96,25 @@ func (c ChunkingConfig) Validate() error {
 	return nil
 }
 
+// HybridFusion defines how per-ranker result lists are combined into a
+// single ranked list in hybrid (keyword + vector) search.
+type HybridFusion string
+
+const (
+	// HybridFusionWeighted combines per-ranker scores as a weighted sum
+	// using KeywordWeight/SemanticWeight.
+	HybridFusionWeighted HybridFusion = "weighted"
+
+	// HybridFusionRRF combines per-ranker rankings with reciprocal rank
+	// fusion: score = Σ 1/(RRFK + rank_i), summed across every ranker a
+	// document appears in.
+	HybridFusionRRF HybridFusion = "rrf"
+
+	// HybridFusionConvex combines per-ranker scores as a convex
+	// combination after min-max normalizing each ranker's scores to [0,1].
+	HybridFusionConvex HybridFusion = "convex"
+)
+
 // RetrievalConfig configures retrieval operations.
 type RetrievalConfig struct {
 	// Basic retrieval
@@ -94,10 +136,21 @@ type RetrievalConfig struct {
 	ContextBefore  int  `json:"context_before"`
 	ContextAfter   int  `json:"context_after"`
 
+	// MaxContextTokens caps the assembled context passed to the LLM,
+	// trimming lowest-ranked chunks first. 0 means no cap.
+	MaxContextTokens int `json:"max_context_tokens"`
+
 	// Hybrid search
-	HybridSearch   bool    `json:"hybrid_search"`
-	KeywordWeight  float64 `json:"keyword_weight"`
-	SemanticWeight float64 `json:"semantic_weight"`
+	HybridSearch   bool         `json:"hybrid_search"`
+	HybridFusion   HybridFusion `json:"hybrid_fusion"`
+	KeywordWeight  float64      `json:"keyword_weight"`
+	SemanticWeight float64      `json:"semantic_weight"`
+
+	// RRF fusion settings, used when HybridFusion is HybridFusionRRF.
+	// RRFK is the rank-offset constant k in 1/(k + rank); PerRankerTopN is
+	// how many candidates to fetch from each ranker before fusing.
+	RRFK          int `json:"rrf_k"`
+	PerRankerTopN int `json:"per_ranker_top_n"`
 
 	// MMR
 	UseMMR    bool    `json:"use_mmr"`
@@ -119,8 +172,11 @@ func DefaultRetrievalConfig() RetrievalConfig {
 		ContextBefore:       1,
 		ContextAfter:        1,
 		HybridSearch:        false,
+		HybridFusion:        HybridFusionWeighted,
 		KeywordWeight:       0.3,
 		SemanticWeight:      0.7,
+		RRFK:                60,
+		PerRankerTopN:       100,
 		UseMMR:              false,
 		MMRLambda:           0.5,
 	}
@@ -9,6 +9,15 @@ const (
 	LLMProviderAnthropic   LLMProvider = "anthropic"
 	LLMProviderOllama      LLMProvider = "ollama"
 	LLMProviderGoogle      LLMProvider = "google"
+
+	// LLMProviderBedrock routes through AWS Bedrock's InvokeModel API,
+	// configured via Bedrock* fields below.
+	LLMProviderBedrock LLMProvider = "bedrock"
+
+	// LLMProviderOpenAICompatible talks the OpenAI chat/completions wire
+	// format to any server that speaks it (vLLM, LM Studio, Together,
+	// ...), resolved purely from BaseURL/Model rather than a fixed host.
+	LLMProviderOpenAICompatible LLMProvider = "openai_compatible"
 )
 
 // LLMPrompts contains custom prompts for LLM processing.
@@ -62,6 +71,15 @@ Include: people, organizations, locations, products, dates, monetary values.
 Return as a JSON object.
 
 Content:
+%s`,
+
+		DocumentQA: `Answer the question using only the context below. If the context
+does not contain the answer, say you don't know rather than guessing.
+
+Context:
+%s
+
+Question:
 %s`,
 
 		Custom: make(map[string]string),
@@ -105,6 +123,14 @@ type LLMConfig struct {
 	// Ollama
 	OllamaBaseURL string `json:"ollama_base_url"`
 
+	// AWS Bedrock. AccessKeyID/SecretAccessKey are optional - when unset
+	// the bedrockruntime client falls back to the default AWS credential
+	// chain (env vars, shared config, instance role), the same precedent
+	// storage.S3Storage follows.
+	BedrockRegion          string `json:"bedrock_region"`
+	BedrockAccessKeyID     string `json:"bedrock_access_key_id"`
+	BedrockSecretAccessKey string `json:"bedrock_secret_access_key"`
+
 	// Generation Parameters
 	Temperature      float64  `json:"temperature"`
 	MaxTokens        int      `json:"max_tokens"`
@@ -130,6 +156,11 @@ type LLMConfig struct {
 
 	// Response
 	ResponseFormat string `json:"response_format"`
+
+	// GroundednessCheck, when true, runs a second LLM pass over each
+	// sentence of a qa.Engine answer to verify it's entailed by a
+	// retrieved chunk, dropping or flagging sentences that aren't.
+	GroundednessCheck bool `json:"groundedness_check"`
 }
 
 // DefaultLLMConfig returns default LLM configuration.
@@ -157,6 +188,87 @@ func DefaultLLMConfig() LLMConfig {
 	}
 }
 
+// VectorCompressionKind selects how stored vectors are compressed,
+// mirroring Azure AI Search's 2024-07-01 vectorSearch.compressions.
+type VectorCompressionKind string
+
+const (
+	VectorCompressionNone               VectorCompressionKind = "none"
+	VectorCompressionScalarQuantization VectorCompressionKind = "scalar-quantization"
+	VectorCompressionBinaryQuantization VectorCompressionKind = "binary-quantization"
+)
+
+// RescoreStorageMethod controls whether full-precision vectors are kept
+// around for reranking compressed search results.
+type RescoreStorageMethod string
+
+const (
+	RescoreStorageMethodPreserveOriginals RescoreStorageMethod = "preserveOriginals"
+	RescoreStorageMethodDiscardOriginals  RescoreStorageMethod = "discardOriginals"
+)
+
+// VectorCompression configures scalar/binary quantization of stored
+// vectors, so an index with millions of chunks can fit in RAM. When Kind
+// is VectorCompressionNone the other fields are ignored.
+type VectorCompression struct {
+	Kind VectorCompressionKind `json:"kind"`
+
+	// Rescore, when true, fetches extra candidates with the compressed
+	// vectors and reranks them using full-precision ones kept per
+	// RescoreStorageMethod.
+	Rescore              bool                 `json:"rescore"`
+	RescoreStorageMethod RescoreStorageMethod `json:"rescore_storage_method"`
+	OversamplingFactor   float64              `json:"oversampling_factor"`
+
+	// TruncationDimension enables Matryoshka-style embedding truncation,
+	// keyed by vector search profile name. 0 means no truncation for that
+	// profile.
+	TruncationDimension map[string]int `json:"truncation_dimension,omitempty"`
+}
+
+// DefaultVectorCompression returns compression disabled.
+func DefaultVectorCompression() VectorCompression {
+	return VectorCompression{
+		Kind:                 VectorCompressionNone,
+		Rescore:              true,
+		RescoreStorageMethod: RescoreStorageMethodPreserveOriginals,
+		OversamplingFactor:   4.0,
+	}
+}
+
+// VectorizerKind selects which embedding resource a Vectorizer calls,
+// mirroring Azure AI Search's vectorSearch.vectorizers.kind values.
+type VectorizerKind string
+
+const (
+	VectorizerKindAzureOpenAI  VectorizerKind = "azureOpenAI"
+	VectorizerKindOpenAI       VectorizerKind = "openAI"
+	VectorizerKindOllama       VectorizerKind = "ollama"
+	VectorizerKindCustomWebAPI VectorizerKind = "customWebApi"
+)
+
+// VectorizerConfig binds a named embedding resource to one or more vector
+// search profiles, so a query can be sent as text and embedded by the
+// search service (or, on the Postgres/MongoDB backends, by docflow's own
+// vectorizer package) instead of requiring a pre-computed vector.
+type VectorizerConfig struct {
+	Name string         `json:"name"`
+	Kind VectorizerKind `json:"kind"`
+
+	// AzureOpenAI / OpenAI
+	ResourceURI  string `json:"resource_uri,omitempty"`
+	DeploymentID string `json:"deployment_id,omitempty"`
+	ModelName    string `json:"model_name,omitempty"`
+	APIKey       string `json:"api_key,omitempty"`
+
+	// Ollama
+	OllamaBaseURL string `json:"ollama_base_url,omitempty"`
+
+	// CustomWebApi
+	CustomURI     string            `json:"custom_uri,omitempty"`
+	CustomHeaders map[string]string `json:"custom_headers,omitempty"`
+}
+
 // AISearchConfig configures Azure AI Search.
 type AISearchConfig struct {
 	// Connection
@@ -176,16 +288,29 @@ type AISearchConfig struct {
 	SemanticConfig            string   `json:"semantic_config"`
 	SemanticPrioritizedFields []string `json:"semantic_prioritized_fields"`
 
+	// Vectorizers let a vector profile embed query text at query time
+	// instead of requiring a pre-computed vector; see VectorizerConfig.
+	Vectorizers []VectorizerConfig `json:"vectorizers"`
+
 	// Search options
 	QueryType  string `json:"query_type"`  // simple, full, semantic
 	SearchMode string `json:"search_mode"` // any, all
 	Top        int    `json:"top"`
 	Skip       int    `json:"skip"`
 
+	// QueryRewrites enables generative query rewriting ("generative" or
+	// "none"). QueryLanguage is the BCP-47 language code used for
+	// semantic ranking and query rewrites.
+	QueryRewrites string `json:"query_rewrites"`
+	QueryLanguage string `json:"query_language"`
+
 	// Vector search
 	VectorFields      []string `json:"vector_fields"`
 	KNearestNeighbors int      `json:"k_nearest_neighbors"`
 
+	// VectorCompression enables scalar/binary quantization for this index.
+	VectorCompression VectorCompression `json:"vector_compression"`
+
 	// Hybrid search
 	HybridSearch      bool `json:"hybrid_search"`
 	SemanticReranking bool `json:"semantic_reranking"`
@@ -212,8 +337,11 @@ func DefaultAISearchConfig() AISearchConfig {
 		SearchMode:                "any",
 		Top:                       10,
 		Skip:                      0,
+		QueryRewrites:             "none",
+		QueryLanguage:             "en-us",
 		VectorFields:              []string{"content_vector"},
 		KNearestNeighbors:         50,
+		VectorCompression:         DefaultVectorCompression(),
 		HybridSearch:              true,
 		SemanticReranking:         true,
 		EmbeddingModel:            "text-embedding-3-small",
@@ -246,6 +374,11 @@ type VectorStoreConfig struct {
 	IndexType      string `json:"index_type"`      // hnsw, ivfflat
 	DistanceMetric string `json:"distance_metric"` // cosine, euclidean, dot
 
+	// VectorCompression enables scalar/binary quantization so large
+	// collections fit in RAM; honored by the pgvector and MongoDB
+	// backends.
+	VectorCompression VectorCompression `json:"vector_compression"`
+
 	// PostgreSQL specific
 	Host           string `json:"host"`
 	Port           int    `json:"port"`
@@ -284,5 +417,6 @@ func DefaultVectorStoreConfig() VectorStoreConfig {
 		EFSearch:            40,
 		NumCandidates:       100,
 		IndexNameMongo:      "vector_index",
+		VectorCompression:   DefaultVectorCompression(),
 	}
 }
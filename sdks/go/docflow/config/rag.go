@@ -20,6 +20,14 @@ const (
 	ChunkingStrategySemantic      ChunkingStrategy = "semantic"
 )
 
+// ChunkSizeUnit selects what RAGConfig.ChunkSize/ChunkOverlap count.
+type ChunkSizeUnit string
+
+const (
+	ChunkSizeUnitChars  ChunkSizeUnit = "chars"
+	ChunkSizeUnitTokens ChunkSizeUnit = "tokens"
+)
+
 // OutputFormat defines the output format
 type OutputFormat string
 
@@ -42,6 +50,13 @@ type RAGConfig struct {
 	ChunkingStrategy ChunkingStrategy `json:"chunking_strategy"`
 	DocIntelConfig   *DocIntelConfig  `json:"doc_intel_config,omitempty"`
 
+	// ChunkSizeUnit selects what ChunkSize/ChunkOverlap count: "chars"
+	// (default) or "tokens", in which case they're measured via the
+	// tokenizer named by Tokenizer (tokenizer.Get; defaults to
+	// "whitespace" if unset).
+	ChunkSizeUnit ChunkSizeUnit `json:"chunk_size_unit,omitempty"`
+	Tokenizer     string        `json:"tokenizer,omitempty"`
+
 	// Extraction options
 	ExtractImages    bool `json:"extract_images"`
 	ExtractTables    bool `json:"extract_tables"`
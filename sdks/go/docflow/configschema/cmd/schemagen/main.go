@@ -0,0 +1,26 @@
+// Command schemagen writes the combined docflow config JSON Schema to
+// schema.json, via go:generate in configschema/schema.go. Editors can
+// point at the generated file for completion/validation of docflow
+// YAML/JSON config files.
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/configschema"
+)
+
+func main() {
+	schema := configschema.GenerateAll()
+
+	out, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		log.Fatalf("schemagen: marshal schema: %v", err)
+	}
+
+	if err := os.WriteFile("schema.json", out, 0644); err != nil {
+		log.Fatalf("schemagen: write schema.json: %v", err)
+	}
+}
@@ -0,0 +1,271 @@
+// Package configschema reflects over docflow's *Config structs and emits
+// a JSON Schema (draft 2020-12) for them, so editors can offer
+// completion/validation for docflow's YAML/JSON config files and callers
+// can validate a config document before unmarshalling it. This replaces
+// hand-written Validate() methods with one declarative source of truth:
+// the enum/range/oneOf tables below, plus reflection for everything else.
+package configschema
+
+//go:generate go run ./cmd/schemagen
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+)
+
+// Schema is a JSON Schema document, represented with plain Go values so
+// it serializes with encoding/json exactly as built.
+type Schema = map[string]interface{}
+
+const draftVersion = "https://json-schema.org/draft/2020-12/schema"
+
+// targetConfigs lists the *Config structs this package generates schemas
+// for, keyed by the name they're addressed by (both in $defs and in the
+// section-keyed documents Validate accepts).
+var targetConfigs = map[string]interface{}{
+	"ChunkingConfig":    config.ChunkingConfig{},
+	"RetrievalConfig":   config.RetrievalConfig{},
+	"MetadataConfig":    config.MetadataConfig{},
+	"DocIntelConfig":    config.DocIntelConfig{},
+	"LLMConfig":         config.LLMConfig{},
+	"AISearchConfig":    config.AISearchConfig{},
+	"VectorStoreConfig": config.VectorStoreConfig{},
+}
+
+// sectionKey maps a *Config struct name to the JSON key a combined
+// docflow config document would carry it under; see Validate.
+var sectionKey = map[string]string{
+	"ChunkingConfig":    "chunking",
+	"RetrievalConfig":   "retrieval",
+	"MetadataConfig":    "metadata",
+	"DocIntelConfig":    "doc_intel",
+	"LLMConfig":         "llm",
+	"AISearchConfig":    "ai_search",
+	"VectorStoreConfig": "vector_store",
+}
+
+// enumConstraints lists allowed values for fields typed as one of
+// docflow's named string types (SplitBy, LLMProvider, ...), keyed by Go
+// type name.
+var enumConstraints = map[string][]string{
+	"SplitBy":               {"paragraph", "sentence", "token", "character", "heading", "recursive", "semantic"},
+	"HybridFusion":          {"weighted", "rrf", "convex"},
+	"LLMProvider":           {"openai", "azure_openai", "anthropic", "ollama", "google"},
+	"DocIntelProvider":      {"azure", "aws"},
+	"VectorStoreProvider":   {"postgresql", "mongodb"},
+	"VectorizerKind":        {"azureOpenAI", "openAI", "ollama", "customWebApi"},
+	"VectorCompressionKind": {"none", "scalar-quantization", "binary-quantization"},
+	"RescoreStorageMethod":  {"preserveOriginals", "discardOriginals"},
+	"ChunkingStrategy":      {"simple", "heading_aware", "doc_intel", "semantic"},
+	"OutputFormat":          {"markdown", "pdf", "html"},
+}
+
+// fieldEnum special-cases plain `string`-typed fields that are a closed
+// set in practice but aren't backed by a named Go type.
+var fieldEnum = map[string][]string{
+	"AISearchConfig.QueryType":  {"simple", "full", "semantic"},
+	"AISearchConfig.SearchMode": {"any", "all"},
+}
+
+// fieldRange gives [minimum, maximum] for numeric fields with a
+// meaningful bound.
+var fieldRange = map[string][2]float64{
+	"RetrievalConfig.MMRLambda":           {0, 1},
+	"RetrievalConfig.SimilarityThreshold": {0, 1},
+	"RetrievalConfig.MinScore":            {0, 1},
+	"RetrievalConfig.KeywordWeight":       {0, 1},
+	"RetrievalConfig.SemanticWeight":      {0, 1},
+	"RetrievalConfig.DuplicateThreshold":  {0, 1},
+	"LLMConfig.Temperature":               {0, 2},
+	"LLMConfig.TopP":                      {0, 1},
+	"LLMConfig.FrequencyPenalty":          {-2, 2},
+	"LLMConfig.PresencePenalty":           {-2, 2},
+	"DocIntelConfig.MinConfidence":        {0, 1},
+}
+
+// providerBranch declares, for a struct with a Provider-like
+// discriminator field, which other fields are required for each
+// discriminator value - the oneOf-by-Provider behavior.
+type providerBranch struct {
+	field    string
+	required map[string][]string
+}
+
+var providerOneOf = map[string]providerBranch{
+	"DocIntelConfig": {
+		field: "provider",
+		required: map[string][]string{
+			"azure": {"endpoint", "api_key", "model_id"},
+			"aws":   {"aws_region", "aws_access_key", "aws_secret_key"},
+		},
+	},
+	"LLMConfig": {
+		field: "provider",
+		required: map[string][]string{
+			"azure_openai": {"azure_endpoint", "azure_deployment"},
+		},
+	},
+}
+
+// Generate builds the JSON Schema for a single *Config struct, identified
+// by its Go type name (e.g. "ChunkingConfig").
+func Generate(structName string) (Schema, error) {
+	v, ok := targetConfigs[structName]
+	if !ok {
+		return nil, fmt.Errorf("configschema: unknown config struct %q", structName)
+	}
+	return schemaFor(reflect.TypeOf(v)), nil
+}
+
+// GenerateAll builds a single JSON Schema document whose $defs holds one
+// schema per target struct, and whose top level is an object with one
+// optional property per struct (keyed by sectionKey) - the shape a
+// combined docflow config file is expected to have.
+func GenerateAll() Schema {
+	defs := Schema{}
+	properties := Schema{}
+
+	for name := range targetConfigs {
+		defs[name] = schemaFor(reflect.TypeOf(targetConfigs[name]))
+		properties[sectionKey[name]] = Schema{"$ref": "#/$defs/" + name}
+	}
+
+	return Schema{
+		"$schema":              draftVersion,
+		"$id":                  "https://docflow.dev/schema/config.json",
+		"title":                "docflow configuration",
+		"type":                 "object",
+		"$defs":                defs,
+		"properties":           properties,
+		"additionalProperties": true,
+	}
+}
+
+// schemaFor reflects over t (a struct type) and builds its JSON Schema,
+// recursing into nested struct/slice-of-struct fields.
+func schemaFor(t reflect.Type) Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	schema := Schema{
+		"type":       "object",
+		"properties": Schema{},
+	}
+
+	properties := schema["properties"].(Schema)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" { // unexported
+			continue
+		}
+
+		jsonName, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+
+		properties[jsonName] = fieldSchema(t.Name(), field)
+	}
+
+	if branch, ok := providerOneOf[t.Name()]; ok {
+		schema["oneOf"] = oneOfBranches(branch)
+	}
+
+	return schema
+}
+
+// jsonFieldName returns the JSON property name for field per its `json`
+// tag, and whether the field should be skipped entirely (tag "-").
+func jsonFieldName(field reflect.StructField) (name string, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name = strings.Split(tag, ",")[0]
+	if name == "" {
+		name = field.Name
+	}
+	return name, false
+}
+
+// fieldSchema builds the schema for a single field, applying any
+// enum/range override keyed by "<structName>.<fieldName>" or by the
+// field's own named type.
+func fieldSchema(structName string, field reflect.StructField) Schema {
+	key := structName + "." + field.Name
+	s := typeSchema(field.Type)
+
+	if enum, ok := enumConstraints[field.Type.Name()]; ok {
+		s["enum"] = enum
+	}
+	if enum, ok := fieldEnum[key]; ok {
+		s["enum"] = enum
+	}
+	if r, ok := fieldRange[key]; ok {
+		s["minimum"] = r[0]
+		s["maximum"] = r[1]
+	}
+
+	return s
+}
+
+// typeSchema maps a Go type to its JSON Schema "type", recursing into
+// structs, slices, and maps.
+func typeSchema(t reflect.Type) Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		if t.PkgPath() == "time" {
+			return Schema{"type": "string", "format": "duration"}
+		}
+		return schemaFor(t)
+	case reflect.Interface:
+		return Schema{}
+	default:
+		return Schema{}
+	}
+}
+
+// oneOfBranches builds the oneOf array for a providerBranch: one branch
+// per discriminator value, each requiring the discriminator field (fixed
+// to that value) plus the fields that value makes mandatory.
+func oneOfBranches(branch providerBranch) []Schema {
+	values := make([]string, 0, len(branch.required))
+	for v := range branch.required {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+
+	branches := make([]Schema, len(values))
+	for i, v := range values {
+		branches[i] = Schema{
+			"properties": Schema{
+				branch.field: Schema{"const": v},
+			},
+			"required": append([]string{branch.field}, branch.required[v]...),
+		}
+	}
+	return branches
+}
@@ -0,0 +1,187 @@
+package configschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Validate checks raw, a JSON document whose top-level keys are the
+// section keys in sectionKey (e.g. "chunking", "retrieval"), against the
+// schemas GenerateAll produces. Callers can run this before unmarshalling
+// a docflow config file to catch typos, bad enum values, and out-of-range
+// numbers early. Unknown top-level keys are ignored; this is not a
+// general-purpose JSON Schema validator, only the keyword subset
+// GenerateAll/Generate actually emit (type, enum, minimum/maximum,
+// required, properties, items, oneOf-by-const).
+func Validate(raw []byte) error {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("configschema: invalid JSON: %w", err)
+	}
+
+	for name, v := range targetConfigs {
+		key := sectionKey[name]
+		section, ok := doc[key]
+		if !ok {
+			continue
+		}
+		schema := schemaFor(reflect.TypeOf(v))
+		if err := validateValue(key, schema, section); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateValue checks value against schema, returning the first
+// violation found, prefixed with path for context.
+func validateValue(path string, schema Schema, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	if enum, ok := schema["enum"].([]string); ok {
+		if err := validateEnum(path, enum, value); err != nil {
+			return err
+		}
+	}
+
+	if err := validateType(path, schema, value); err != nil {
+		return err
+	}
+
+	if min, ok := schema["minimum"].(float64); ok {
+		if n, ok := toFloat(value); ok && n < min {
+			return fmt.Errorf("configschema: %s: %v is below minimum %v", path, value, min)
+		}
+	}
+	if max, ok := schema["maximum"].(float64); ok {
+		if n, ok := toFloat(value); ok && n > max {
+			return fmt.Errorf("configschema: %s: %v is above maximum %v", path, value, max)
+		}
+	}
+
+	if properties, ok := schema["properties"].(Schema); ok {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("configschema: %s: expected an object", path)
+		}
+		for field, fieldSchema := range properties {
+			if fieldValue, present := obj[field]; present {
+				if err := validateValue(path+"."+field, fieldSchema.(Schema), fieldValue); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if items, ok := schema["items"].(Schema); ok {
+		list, ok := value.([]interface{})
+		if ok {
+			for i, item := range list {
+				if err := validateValue(fmt.Sprintf("%s[%d]", path, i), items, item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if oneOf, ok := schema["oneOf"].([]Schema); ok {
+		if err := validateOneOf(path, oneOf, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateEnum(path string, enum []string, value interface{}) error {
+	s, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	for _, allowed := range enum {
+		if s == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("configschema: %s: %q is not one of %v", path, s, enum)
+}
+
+func validateType(path string, schema Schema, value interface{}) error {
+	t, ok := schema["type"].(string)
+	if !ok {
+		return nil
+	}
+	switch t {
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("configschema: %s: expected a string", path)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("configschema: %s: expected a boolean", path)
+		}
+	case "integer", "number":
+		if _, ok := toFloat(value); !ok {
+			return fmt.Errorf("configschema: %s: expected a number", path)
+		}
+	case "array":
+		if _, ok := value.([]interface{}); !ok {
+			return fmt.Errorf("configschema: %s: expected an array", path)
+		}
+	case "object":
+		if _, ok := value.(map[string]interface{}); !ok {
+			return fmt.Errorf("configschema: %s: expected an object", path)
+		}
+	}
+	return nil
+}
+
+// validateOneOf requires value to satisfy exactly one branch: match each
+// branch's discriminator const and carry its required fields.
+func validateOneOf(path string, branches []Schema, value interface{}) error {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("configschema: %s: expected an object", path)
+	}
+
+	for _, branch := range branches {
+		properties, _ := branch["properties"].(Schema)
+		required, _ := branch["required"].([]string)
+
+		discriminatorMatches := true
+		for field, fieldSchema := range properties {
+			want, _ := fieldSchema.(Schema)["const"].(string)
+			got, _ := obj[field].(string)
+			if got != want {
+				discriminatorMatches = false
+				break
+			}
+		}
+		if !discriminatorMatches {
+			continue
+		}
+
+		for _, field := range required {
+			if _, present := obj[field]; !present {
+				return fmt.Errorf("configschema: %s: missing required field %q", path, field)
+			}
+		}
+		return nil
+	}
+
+	return nil
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -10,6 +10,9 @@ import (
 
 	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
+	"github.com/xgaslan/docflow/sdks/go/docflow/cache/memcache"
+	"github.com/xgaslan/docflow/sdks/go/docflow/output"
+	"github.com/xgaslan/docflow/sdks/go/docflow/pdfops"
 	"github.com/xgaslan/docflow/sdks/go/docflow/storage"
 )
 
@@ -19,6 +22,9 @@ type Converter struct {
 	storage  storage.Storage
 	parser   *MarkdownParser
 	template *Template
+
+	onProgress  ProgressFunc
+	signalAbort bool
 }
 
 // NewConverter creates a new Converter instance.
@@ -53,6 +59,16 @@ func WithStorage(s storage.Storage) ConverterOption {
 	}
 }
 
+// WithCache sets a shared memcache.Cache used to memoize HTML rendering
+// across conversions of the same Markdown content. It's applied to the
+// Converter's MarkdownParser, so Preview and PDF generation share a
+// single cache of rendered HTML.
+func WithCache(c *memcache.Cache) ConverterOption {
+	return func(cv *Converter) {
+		WithParserCache(c)(cv.parser)
+	}
+}
+
 // WithLocalStorage creates and sets a local storage backend.
 func WithLocalStorage(path string) ConverterOption {
 	return func(c *Converter) {
@@ -66,12 +82,31 @@ func WithLocalStorage(path string) ConverterOption {
 	}
 }
 
+// WithStorageConfig builds and sets a storage backend from cfg.Type
+// (local, s3, gcs, or azure), so the backend can be chosen from
+// application config without the caller depending on a specific
+// backend's constructor.
+func WithStorageConfig(cfg storage.Config) ConverterOption {
+	return func(c *Converter) {
+		s, err := storage.NewStorage(cfg)
+		if err != nil {
+			// Log error but continue - storage is optional
+			fmt.Fprintf(os.Stderr, "docflow: failed to create %s storage: %v\n", cfg.Type, err)
+			return
+		}
+		c.storage = s
+	}
+}
+
 // ConvertToPDF converts markdown files to PDF and saves to storage.
 func (c *Converter) ConvertToPDF(ctx context.Context, files []MDFile, opts ConvertOptions) (*Result, error) {
 	if len(files) == 0 {
 		return nil, fmt.Errorf("at least one file is required")
 	}
 
+	ctx, stopAbort := c.withAbortSignal(ctx)
+	defer stopAbort()
+
 	// Ensure temp directory exists
 	if err := os.MkdirAll(c.options.TempDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create temp directory: %w", err)
@@ -89,27 +124,41 @@ func (c *Converter) ConvertToPDF(ctx context.Context, files []MDFile, opts Conve
 
 	switch opts.MergeMode {
 	case "merged":
-		path, data, err := c.convertMerged(ctx, files, opts.OutputName, timestamp)
+		path, data, err := c.convertMerged(ctx, files, opts.OutputName, timestamp, opts.PostProcess, opts.Outputs)
 		if err != nil {
-			return &Result{Success: false, Error: err}, nil
+			return c.abortOrFail(ctx, err)
 		}
 		results = append(results, path)
 		resultBytes = data
 
 	case "separate":
+		total := int64(len(files))
 		for i, file := range files {
 			file.Order = i
-			path, _, err := c.convertSingle(ctx, file, timestamp)
+			c.emit("convert_single", int64(i+1), total, file.Name)
+			path, _, err := c.convertSingle(ctx, file, timestamp, opts.PostProcess, opts.Outputs)
 			if err != nil {
-				return &Result{Success: false, Error: err}, nil
+				return c.abortOrFail(ctx, err)
 			}
 			results = append(results, path)
 		}
 
+	case "pdfcpu":
+		path, data, err := c.convertPDFCPUMerge(ctx, files, opts.OutputName, timestamp, opts.PostProcess, opts.Outputs)
+		if err != nil {
+			return c.abortOrFail(ctx, err)
+		}
+		results = append(results, path)
+		resultBytes = data
+
 	default:
 		return nil, fmt.Errorf("invalid merge mode: %s", opts.MergeMode)
 	}
 
+	if err := closeOutputs(opts.Outputs); err != nil {
+		return c.abortOrFail(ctx, err)
+	}
+
 	return &Result{
 		Success:   true,
 		FilePaths: results,
@@ -117,6 +166,28 @@ func (c *Converter) ConvertToPDF(ctx context.Context, files []MDFile, opts Conve
 	}, nil
 }
 
+// closeOutputs finalizes every configured Output (e.g. writing a tar or
+// zip archive's trailing footer) once all files have been written.
+func closeOutputs(outs []output.Output) error {
+	for _, o := range outs {
+		if err := o.Close(); err != nil {
+			return fmt.Errorf("failed to close output: %w", err)
+		}
+	}
+	return nil
+}
+
+// abortOrFail reports a failed Result, substituting ErrAborted and
+// emitting a final "aborted" progress event if err happened because ctx
+// was cancelled via an installed abort signal.
+func (c *Converter) abortOrFail(ctx context.Context, err error) (*Result, error) {
+	if c.signalAbort && ctx.Err() != nil {
+		c.emit("aborted", 0, 0, err.Error())
+		return &Result{Success: false, Error: ErrAborted}, nil
+	}
+	return &Result{Success: false, Error: err}, nil
+}
+
 // ConvertToBytes converts markdown to PDF and returns the bytes.
 func (c *Converter) ConvertToBytes(ctx context.Context, files []MDFile) ([]byte, error) {
 	if len(files) == 0 {
@@ -130,7 +201,7 @@ func (c *Converter) ConvertToBytes(ctx context.Context, files []MDFile) ([]byte,
 
 	timestamp := time.Now().Unix()
 
-	_, data, err := c.convertMerged(ctx, files, "", timestamp)
+	_, data, err := c.convertMerged(ctx, files, "", timestamp, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -143,7 +214,7 @@ func (c *Converter) Preview(content string) (string, error) {
 	return c.parser.ToHTML(content)
 }
 
-func (c *Converter) convertMerged(ctx context.Context, files []MDFile, outputName string, timestamp int64) (string, []byte, error) {
+func (c *Converter) convertMerged(ctx context.Context, files []MDFile, outputName string, timestamp int64, postProcess []pdfops.Op, outs []output.Output) (string, []byte, error) {
 	mergedContent := c.parser.MergeFiles(files)
 
 	if outputName == "" {
@@ -151,23 +222,85 @@ func (c *Converter) convertMerged(ctx context.Context, files []MDFile, outputNam
 	}
 	outputName = sanitizeFilename(outputName)
 
-	return c.generatePDF(ctx, mergedContent, outputName)
+	return c.generatePDF(ctx, mergedContent, outputName, postProcess, outs)
 }
 
-func (c *Converter) convertSingle(ctx context.Context, file MDFile, timestamp int64) (string, []byte, error) {
+func (c *Converter) convertSingle(ctx context.Context, file MDFile, timestamp int64, postProcess []pdfops.Op, outs []output.Output) (string, []byte, error) {
 	baseName := strings.TrimSuffix(file.Name, filepath.Ext(file.Name))
 	outputName := fmt.Sprintf("%s_%d", sanitizeFilename(baseName), timestamp)
 
-	return c.generatePDF(ctx, file.Content, outputName)
+	return c.generatePDF(ctx, file.Content, outputName, postProcess, outs)
 }
 
-func (c *Converter) generatePDF(ctx context.Context, mdContent, outputName string) (string, []byte, error) {
-	// Convert markdown to HTML
+// convertPDFCPUMerge renders each file to its own PDF, then concatenates
+// them with pdfcpu instead of merging the markdown source first.
+func (c *Converter) convertPDFCPUMerge(ctx context.Context, files []MDFile, outputName string, timestamp int64, postProcess []pdfops.Op, outs []output.Output) (string, []byte, error) {
+	perFilePaths := make([]string, 0, len(files))
+	for i, file := range files {
+		file.Order = i
+		path, _, err := c.convertSingle(ctx, file, timestamp, nil, nil)
+		if err != nil {
+			return "", nil, err
+		}
+		perFilePaths = append(perFilePaths, path)
+	}
+
+	merged, err := pdfops.Merge(perFilePaths)
+	if err != nil {
+		return "", nil, fmt.Errorf("pdfcpu merge failed: %w", err)
+	}
+
+	merged, err = pdfops.Apply(merged, postProcess)
+	if err != nil {
+		return "", nil, fmt.Errorf("post-processing failed: %w", err)
+	}
+
+	if outputName == "" {
+		outputName = fmt.Sprintf("merged_%d", timestamp)
+	}
+	outputName = sanitizeFilename(outputName)
+	outputPath := outputName + ".pdf"
+
+	if len(outs) > 0 {
+		if err := writeToOutputs(outs, outputPath, merged); err != nil {
+			return "", nil, err
+		}
+		return outputPath, merged, nil
+	}
+
+	if c.storage != nil {
+		if err := c.storage.Save(ctx, outputPath, merged); err != nil {
+			return "", nil, fmt.Errorf("failed to save PDF: %w", err)
+		}
+		return c.storage.GetURL(outputPath), merged, nil
+	}
+
+	tempPath := filepath.Join(c.options.TempDir, outputPath)
+	if err := os.WriteFile(tempPath, merged, 0644); err != nil {
+		return "", nil, fmt.Errorf("failed to write PDF: %w", err)
+	}
+	return tempPath, merged, nil
+}
+
+// writeToOutputs writes data to every configured Output under name.
+func writeToOutputs(outs []output.Output, name string, data []byte) error {
+	meta := map[string]string{"content-type": "application/pdf"}
+	for _, o := range outs {
+		if err := o.Write(name, data, meta); err != nil {
+			return fmt.Errorf("failed to write output %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (c *Converter) generatePDF(ctx context.Context, mdContent, outputName string, postProcess []pdfops.Op, outs []output.Output) (string, []byte, error) {
+	c.emit("parse_markdown", 0, 0, outputName)
 	htmlContent, err := c.parser.ToHTML(mdContent)
 	if err != nil {
 		return "", nil, fmt.Errorf("markdown conversion failed: %w", err)
 	}
 
+	c.emit("render_html", 0, 0, outputName)
 	// Generate full HTML document
 	fullHTML := c.template.Generate(htmlContent)
 
@@ -184,22 +317,46 @@ func (c *Converter) generatePDF(ctx context.Context, mdContent, outputName strin
 		return "", nil, err
 	}
 
+	if len(postProcess) > 0 {
+		pdfData, err = pdfops.Apply(pdfData, postProcess)
+		if err != nil {
+			return "", nil, fmt.Errorf("post-processing failed: %w", err)
+		}
+	}
+
+	c.emit("save", 0, 0, outputName)
+	outputPath := outputName + ".pdf"
+
+	// Outputs, when configured, replace the implicit storage/TempDir
+	// save path below and receive each file as soon as it's ready.
+	if len(outs) > 0 {
+		if err := writeToOutputs(outs, outputPath, pdfData); err != nil {
+			return "", nil, err
+		}
+		return outputPath, pdfData, nil
+	}
+
 	// Save to storage if configured
 	if c.storage != nil {
-		outputPath := outputName + ".pdf"
-		if err := c.storage.Save(outputPath, pdfData); err != nil {
+		if err := c.storage.Save(ctx, outputPath, pdfData); err != nil {
 			return "", nil, fmt.Errorf("failed to save PDF: %w", err)
 		}
 		return c.storage.GetURL(outputPath), pdfData, nil
 	}
 
 	// Save to temp directory if no storage configured
-	outputPath := filepath.Join(c.options.TempDir, outputName+".pdf")
-	if err := os.WriteFile(outputPath, pdfData, 0644); err != nil {
+	tempPath := filepath.Join(c.options.TempDir, outputPath)
+	if err := os.WriteFile(tempPath, pdfData, 0644); err != nil {
 		return "", nil, fmt.Errorf("failed to write PDF: %w", err)
 	}
 
-	return outputPath, pdfData, nil
+	return tempPath, pdfData, nil
+}
+
+// Stats reports the converter's HTML cache hit/miss/eviction counts, or
+// a zero Stats if no cache was configured with WithCache.
+func (c *Converter) Stats() memcache.Stats {
+	return c.parser.Stats()
 }
 
 func (c *Converter) generateWithChrome(ctx context.Context, htmlPath string) ([]byte, error) {
@@ -236,10 +393,12 @@ func (c *Converter) generateWithChrome(ctx context.Context, htmlPath string) ([]
 	var pdfBuf []byte
 	fileURL := "file://" + absPath
 
+	c.emit("chrome_navigate", 0, 0, fileURL)
 	if err := chromedp.Run(taskCtx,
 		chromedp.Navigate(fileURL),
 		chromedp.WaitReady("body"),
 		chromedp.ActionFunc(func(ctx context.Context) error {
+			c.emit("print_pdf", 0, 0, fileURL)
 			var err error
 			pdfBuf, _, err = page.PrintToPDF().
 				WithPaperWidth(8.27).      // A4 width in inches
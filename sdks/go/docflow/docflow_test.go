@@ -123,6 +123,8 @@ func TestMarkdownParser_EstimatePageCount(t *testing.T) {
 }
 
 func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+
 	// Create temp directory
 	tmpDir, err := os.MkdirTemp("", "docflow-test-*")
 	require.NoError(t, err)
@@ -135,29 +137,29 @@ func TestLocalStorage(t *testing.T) {
 	testPath := "test/file.txt"
 
 	// Test Save
-	err = s.Save(testPath, testData)
+	err = s.Save(ctx, testPath, testData)
 	require.NoError(t, err)
 
 	// Test Exists
-	exists, err := s.Exists(testPath)
+	exists, err := s.Exists(ctx, testPath)
 	require.NoError(t, err)
 	assert.True(t, exists)
 
 	// Test Load
-	loaded, err := s.Load(testPath)
+	loaded, err := s.Load(ctx, testPath)
 	require.NoError(t, err)
 	assert.Equal(t, testData, loaded)
 
 	// Test List
-	files, err := s.List("test")
+	files, err := s.List(ctx, "test")
 	require.NoError(t, err)
 	assert.Contains(t, files, "file.txt")
 
 	// Test Delete
-	err = s.Delete(testPath)
+	err = s.Delete(ctx, testPath)
 	require.NoError(t, err)
 
-	exists, err = s.Exists(testPath)
+	exists, err = s.Exists(ctx, testPath)
 	require.NoError(t, err)
 	assert.False(t, exists)
 }
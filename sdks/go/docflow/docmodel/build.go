@@ -0,0 +1,248 @@
+package docmodel
+
+import (
+	"sort"
+	"strings"
+)
+
+// line is an intermediate grouping of Words sharing a page and roughly
+// the same Y position, before column/block classification.
+type line struct {
+	page     int
+	text     string
+	words    []Word
+	x, y     float64 // top-left: leftmost word's X, this line's Y
+	w, h     float64
+	fontSize float64 // largest word font size on the line
+}
+
+// lineYTolerance is how many points two words' Y positions may differ
+// and still be considered the same line - text baselines jitter a
+// little even within one visually-aligned line.
+const lineYTolerance = 2.0
+
+// columnGapFactor: a horizontal gap between two words on the same line
+// wider than this many times the line's own font size is treated as a
+// column boundary for table-cell splitting.
+const columnGapFactor = 1.5
+
+// FromWords clusters words (in any order; FromWords sorts them) into
+// lines, ranks distinct font sizes into heading levels, detects tables
+// via aligned word-gap columns, detects lists via bullet/numbered
+// prefixes, and groups pages with two or more recurring left-edge X
+// clusters into multi-column reading order.
+//
+// It's heuristic, the same way Extractor.textToMarkdown and
+// ExtractStructured's renderTables are: real layout analysis (reading
+// order across rotated text, floating figures, footnote superscripts)
+// is out of scope. What it buys over the flat-text heuristics is using
+// position and font size, which flat text doesn't carry at all.
+func FromWords(words []Word) *Document {
+	if len(words) == 0 {
+		return &Document{}
+	}
+
+	ws := make([]Word, len(words))
+	copy(ws, words)
+	sortWords(ws)
+
+	byPage := map[int][]Word{}
+	var pageNums []int
+	for _, w := range ws {
+		if _, ok := byPage[w.Page]; !ok {
+			pageNums = append(pageNums, w.Page)
+		}
+		byPage[w.Page] = append(byPage[w.Page], w)
+	}
+	sort.Ints(pageNums)
+
+	bodySize := dominantFontSize(ws)
+	levels := rankHeadingSizes(ws, bodySize)
+
+	doc := &Document{}
+	for _, pn := range pageNums {
+		lines := groupLines(byPage[pn])
+		columns := groupColumns(lines)
+
+		var blocks []Block
+		for _, col := range columns {
+			blocks = append(blocks, classifyLines(col, bodySize, levels)...)
+		}
+		doc.Pages = append(doc.Pages, Page{Number: pn, Blocks: blocks})
+	}
+	return doc
+}
+
+// groupLines merges words (already sorted by Y then X within pageWords)
+// into lines: a new word starts a new line when it's marked NewLine or
+// its Y differs from the current line's Y by more than lineYTolerance.
+func groupLines(pageWords []Word) []line {
+	var lines []line
+	var cur *line
+
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		cur.text = joinWords(cur.words)
+		lines = append(lines, *cur)
+		cur = nil
+	}
+
+	for _, w := range pageWords {
+		startNew := cur == nil || w.NewLine || absf(w.Y-cur.y) > lineYTolerance
+		if startNew {
+			flush()
+			cur = &line{page: w.Page, x: w.X, y: w.Y, w: w.W, h: w.H, fontSize: w.FontSize}
+		}
+		cur.words = append(cur.words, w)
+		if w.X < cur.x {
+			cur.x = w.X
+		}
+		if right := w.X + w.W; right > cur.x+cur.w {
+			cur.w = right - cur.x
+		}
+		if w.FontSize > cur.fontSize {
+			cur.fontSize = w.FontSize
+		}
+	}
+	flush()
+
+	return lines
+}
+
+func joinWords(words []Word) string {
+	parts := make([]string, len(words))
+	for i, w := range words {
+		parts[i] = w.Text
+	}
+	return strings.Join(parts, " ")
+}
+
+// groupColumns splits lines into left-to-right reading columns when
+// their left edges cluster into two or more groups that each recur
+// often enough to be a real column, not just occasional indentation.
+// Single-column pages (the common case) return one column with every
+// line, in original (top-to-bottom) order.
+func groupColumns(lines []line) [][]line {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	clusters := clusterByX(lines)
+	if len(clusters) < 2 {
+		return [][]line{lines}
+	}
+
+	// Require every cluster to carry a meaningful share of the page's
+	// lines, or this is just varied indentation (list nesting, block
+	// quotes), not columns.
+	const minClusterShare = 0.15
+	for _, c := range clusters {
+		if float64(len(c))/float64(len(lines)) < minClusterShare {
+			return [][]line{lines}
+		}
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i][0].x < clusters[j][0].x
+	})
+	return clusters
+}
+
+// xClusterTolerance is how close two lines' left edges must be to count
+// as the same column.
+const xClusterTolerance = 18.0
+
+func clusterByX(lines []line) [][]line {
+	sorted := make([]line, len(lines))
+	copy(sorted, lines)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].x < sorted[j].x })
+
+	var clusters [][]line
+	for _, l := range sorted {
+		placed := false
+		for i, c := range clusters {
+			if absf(l.x-c[0].x) <= xClusterTolerance {
+				clusters[i] = append(clusters[i], l)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			clusters = append(clusters, []line{l})
+		}
+	}
+	return clusters
+}
+
+// classifyLines walks one column's lines (already in top-to-bottom
+// order) and groups them into Heading/List/Table/Paragraph blocks.
+func classifyLines(lines []line, bodySize float64, levels map[float64]int) []Block {
+	var blocks []Block
+	var paragraph []line
+	var list []line
+	var table []line
+
+	flushParagraph := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		blocks = append(blocks, paragraphBlock(paragraph, bodySize))
+		paragraph = nil
+	}
+	flushList := func() {
+		if len(list) == 0 {
+			return
+		}
+		blocks = append(blocks, listBlock(list))
+		list = nil
+	}
+	flushTable := func() {
+		if len(table) < 3 {
+			paragraph = append(paragraph, table...)
+			table = nil
+			return
+		}
+		blocks = append(blocks, tableBlock(table))
+		table = nil
+	}
+
+	for i, l := range lines {
+		level, isHeading := levels[l.fontSize]
+
+		switch {
+		case isHeading && level > 0:
+			flushParagraph()
+			flushList()
+			flushTable()
+			blocks = append(blocks, headingBlock(l, level))
+
+		case isFootnoteLine(l, lines, i):
+			flushParagraph()
+			flushList()
+			flushTable()
+			blocks = append(blocks, footnoteBlock(l))
+
+		case isListLine(l.text):
+			flushParagraph()
+			flushTable()
+			list = append(list, l)
+
+		case len(wordGaps(l, bodySize)) >= 2:
+			flushParagraph()
+			flushList()
+			table = append(table, l)
+
+		default:
+			flushList()
+			flushTable()
+			paragraph = append(paragraph, l)
+		}
+	}
+	flushParagraph()
+	flushList()
+	flushTable()
+
+	return blocks
+}
@@ -0,0 +1,205 @@
+package docmodel
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// dominantFontSize returns the most common font size across words - the
+// document's "body" text size, against which larger sizes are ranked as
+// headings.
+func dominantFontSize(words []Word) float64 {
+	counts := map[float64]int{}
+	for _, w := range words {
+		counts[w.FontSize]++
+	}
+	body, bodyCount := 0.0, -1
+	for size, count := range counts {
+		if count > bodyCount {
+			body, bodyCount = size, count
+		}
+	}
+	return body
+}
+
+// maxHeadingSizeLevels caps how many distinct larger-than-body sizes get
+// their own heading level; FromWords always ranks up to this many, and
+// RenderOptions.MaxHeadingLevel further caps what ToMarkdown emits.
+const maxHeadingSizeLevels = 6
+
+// rankHeadingSizes assigns heading levels 1..maxHeadingSizeLevels to the
+// distinct font sizes larger than bodySize, largest first.
+func rankHeadingSizes(words []Word, bodySize float64) map[float64]int {
+	seen := map[float64]bool{}
+	var larger []float64
+	for _, w := range words {
+		if w.FontSize > bodySize && !seen[w.FontSize] {
+			seen[w.FontSize] = true
+			larger = append(larger, w.FontSize)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(larger)))
+
+	levels := map[float64]int{}
+	for i, size := range larger {
+		if i >= maxHeadingSizeLevels {
+			break
+		}
+		levels[size] = i + 1
+	}
+	return levels
+}
+
+func headingBlock(l line, level int) Block {
+	return Block{Type: Heading, Level: level, Text: l.text, FontSize: l.fontSize, X: l.x, Y: l.y, W: l.w, H: l.h}
+}
+
+func footnoteBlock(l line) Block {
+	return Block{Type: Footnote, Text: stripFootnoteMarker(l.text), FontSize: l.fontSize, X: l.x, Y: l.y, W: l.w, H: l.h}
+}
+
+func paragraphBlock(lines []line, bodySize float64) Block {
+	texts := make([]string, len(lines))
+	x, y, maxW, maxH, size := lines[0].x, lines[0].y, 0.0, 0.0, bodySize
+	for i, l := range lines {
+		texts[i] = l.text
+		if l.x < x {
+			x = l.x
+		}
+		if l.w > maxW {
+			maxW = l.w
+		}
+		maxH += l.h
+		if l.fontSize > size {
+			size = l.fontSize
+		}
+	}
+	return Block{Type: Paragraph, Text: strings.Join(texts, " "), FontSize: size, X: x, Y: y, W: maxW, H: maxH}
+}
+
+// listIndentUnit is the left-edge delta treated as one extra nesting
+// level; finer jitter is folded into the same level.
+const listIndentUnit = 14.0
+
+func listBlock(lines []line) Block {
+	baseX := lines[0].x
+	items := make([]string, len(lines))
+	deepest := 0
+	for i, l := range lines {
+		items[i] = stripListMarker(l.text)
+		if depth := int((l.x - baseX) / listIndentUnit); depth > deepest {
+			deepest = depth
+		}
+	}
+	return Block{Type: List, Level: deepest, Items: items, X: baseX, Y: lines[0].y}
+}
+
+func tableBlock(lines []line) Block {
+	rows := make([][]string, len(lines))
+	for i, l := range lines {
+		rows[i] = splitByGaps(l)
+	}
+	return Block{Type: Table, Rows: rows, X: lines[0].x, Y: lines[0].y}
+}
+
+// wordGaps returns the X positions of gaps between consecutive words on
+// a line wide enough (relative to font size) to be column boundaries
+// rather than ordinary word spacing.
+func wordGaps(l line, bodySize float64) []float64 {
+	size := l.fontSize
+	if size <= 0 {
+		size = bodySize
+	}
+	if size <= 0 {
+		return nil
+	}
+
+	var gaps []float64
+	for i := 1; i < len(l.words); i++ {
+		prev, cur := l.words[i-1], l.words[i]
+		gap := cur.X - (prev.X + prev.W)
+		if gap > size*columnGapFactor {
+			gaps = append(gaps, prev.X+prev.W)
+		}
+	}
+	return gaps
+}
+
+// splitByGaps renders one table row's cells by grouping words between
+// each detected gap.
+func splitByGaps(l line) []string {
+	gaps := wordGaps(l, l.fontSize)
+	if len(gaps) == 0 {
+		return []string{l.text}
+	}
+
+	var cells []string
+	var cur []string
+	gapIdx := 0
+	for _, w := range l.words {
+		if gapIdx < len(gaps) && w.X >= gaps[gapIdx] {
+			cells = append(cells, strings.Join(cur, " "))
+			cur = nil
+			gapIdx++
+		}
+		cur = append(cur, w.Text)
+	}
+	cells = append(cells, strings.Join(cur, " "))
+	return cells
+}
+
+var (
+	bulletPattern  = regexp.MustCompile(`^[•●○◦\-\*]\s+`)
+	numberedPrefix = regexp.MustCompile(`^\d+[.)]\s+`)
+)
+
+// isListLine reports whether text looks like a bullet or numbered list
+// item.
+func isListLine(text string) bool {
+	return bulletPattern.MatchString(text) || numberedPrefix.MatchString(text)
+}
+
+func stripListMarker(text string) string {
+	if m := bulletPattern.FindString(text); m != "" {
+		return strings.TrimSpace(text[len(m):])
+	}
+	if m := numberedPrefix.FindString(text); m != "" {
+		return strings.TrimSpace(text[len(m):])
+	}
+	return text
+}
+
+var footnoteMarker = regexp.MustCompile(`^(\d{1,2}|\*|†)\s+`)
+
+// footnoteZoneFraction is how far up from the bottom of the page a line
+// must be to be considered for footnote classification.
+const footnoteZoneFraction = 0.12
+
+// isFootnoteLine reports whether l is a small, marker-prefixed line near
+// the bottom of its column - the common shape of a PDF footnote.
+func isFootnoteLine(l line, column []line, idx int) bool {
+	if !footnoteMarker.MatchString(l.text) {
+		return false
+	}
+	// Only the last few lines of the column count as "near the bottom";
+	// this is a column (already in top-to-bottom order), not a whole
+	// page, so use a small fixed tail instead of a page-height fraction
+	// we don't have here.
+	remaining := len(column) - idx
+	return remaining <= 3 && float64(idx) > footnoteZoneFraction*float64(len(column))
+}
+
+func stripFootnoteMarker(text string) string {
+	if m := footnoteMarker.FindString(text); m != "" {
+		return strings.TrimSpace(text[len(m):])
+	}
+	return text
+}
+
+func absf(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
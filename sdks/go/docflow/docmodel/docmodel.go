@@ -0,0 +1,197 @@
+// Package docmodel is a structured intermediate representation for PDF
+// page content - Document -> Pages -> Blocks - built from positioned,
+// sized text runs (docmodel.Word) instead of the flat, order-only text
+// Extractor.textToMarkdown works from. Having font size and (x, y)
+// position per word lets FromWords do the things plain text can't:
+// font-size-based heading ranking, column-alignment table detection,
+// and multi-column reading order.
+//
+// It's still heuristic, not a real layout analyzer - see FromWords for
+// the specifics - but it degrades gracefully: a Document built from a
+// single run of same-sized, single-column words renders as one
+// Paragraph block per line, same as the old flat-text heuristic would.
+package docmodel
+
+import (
+	"sort"
+	"strings"
+)
+
+// BlockType identifies the kind of content a Block holds.
+type BlockType int
+
+const (
+	Paragraph BlockType = iota
+	Heading
+	List
+	Table
+	Figure
+	Code
+	Footnote
+)
+
+// Block is one unit of page content, classified and positioned.
+type Block struct {
+	Type BlockType
+
+	// Level is the heading level (1-6) for Heading blocks, or the
+	// nesting depth (0 = top) for List blocks. Unused otherwise.
+	Level int
+
+	// Text holds the block's content for Paragraph, Heading, Code, and
+	// Footnote blocks.
+	Text string
+
+	// Items holds one entry per line for List blocks, in order.
+	Items []string
+
+	// Rows holds one row per line for Table blocks; Rows[0] is the
+	// header.
+	Rows [][]string
+
+	// FontSize is the dominant font size of the block's text.
+	FontSize float64
+
+	// X, Y is the block's top-left position and W, H its size, all in
+	// page coordinates (PDF points, Y increasing downward).
+	X, Y, W, H float64
+}
+
+// Page is one page's classified Blocks, in reading order.
+type Page struct {
+	Number int
+	Blocks []Block
+}
+
+// Document is a whole PDF's structured content.
+type Document struct {
+	Pages []Page
+}
+
+// Word is one positioned, sized text run - the raw input FromWords
+// clusters into Blocks. Source-agnostic: a backend populates it from
+// whatever coordinate data it has (pdftotext -bbox-layout XML, a native
+// content-stream decoder, ...).
+type Word struct {
+	Text     string
+	Page     int
+	X, Y     float64 // top-left of the word's bounding box
+	W, H     float64
+	FontSize float64
+
+	// NewLine marks that this word starts a new line, for sources (like
+	// a content-stream decoder) that know line breaks but not exact
+	// vertical position deltas.
+	NewLine bool
+}
+
+// RenderOptions configures (*Document).ToMarkdown.
+type RenderOptions struct {
+	// MaxHeadingLevel caps the deepest heading level assigned; distinct
+	// font sizes beyond it fold into the last level. Zero uses
+	// DefaultRenderOptions's 6.
+	MaxHeadingLevel int
+}
+
+// DefaultRenderOptions returns sensible defaults for ToMarkdown.
+func DefaultRenderOptions() RenderOptions {
+	return RenderOptions{MaxHeadingLevel: 6}
+}
+
+// ToMarkdown renders the Document's already-classified Blocks as
+// Markdown, one page at a time.
+func (d *Document) ToMarkdown(opts RenderOptions) string {
+	if opts.MaxHeadingLevel <= 0 {
+		opts.MaxHeadingLevel = DefaultRenderOptions().MaxHeadingLevel
+	}
+
+	var sb strings.Builder
+	for pi, page := range d.Pages {
+		if pi > 0 {
+			sb.WriteString("\n\f\n")
+		}
+		for _, b := range page.Blocks {
+			renderBlock(&sb, b, opts)
+		}
+	}
+	return sb.String()
+}
+
+func renderBlock(sb *strings.Builder, b Block, opts RenderOptions) {
+	switch b.Type {
+	case Heading:
+		level := b.Level
+		if level < 1 {
+			level = 1
+		}
+		if level > opts.MaxHeadingLevel {
+			level = opts.MaxHeadingLevel
+		}
+		sb.WriteString(strings.Repeat("#", level))
+		sb.WriteString(" ")
+		sb.WriteString(b.Text)
+		sb.WriteString("\n\n")
+	case List:
+		indent := strings.Repeat("  ", b.Level)
+		for _, item := range b.Items {
+			sb.WriteString(indent)
+			sb.WriteString("- ")
+			sb.WriteString(item)
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	case Table:
+		writeMarkdownTable(sb, b.Rows)
+		sb.WriteString("\n")
+	case Code:
+		sb.WriteString("```\n")
+		sb.WriteString(b.Text)
+		sb.WriteString("\n```\n\n")
+	case Footnote:
+		sb.WriteString("[^note]: ")
+		sb.WriteString(b.Text)
+		sb.WriteString("\n\n")
+	case Figure:
+		sb.WriteString("![")
+		sb.WriteString(b.Text)
+		sb.WriteString("]()\n\n")
+	default: // Paragraph
+		sb.WriteString(b.Text)
+		sb.WriteString("\n\n")
+	}
+}
+
+func writeMarkdownTable(sb *strings.Builder, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+	writeRow := func(cols []string) {
+		sb.WriteString("| ")
+		sb.WriteString(strings.Join(cols, " | "))
+		sb.WriteString(" |\n")
+	}
+	writeRow(rows[0])
+	sb.WriteString("|")
+	for range rows[0] {
+		sb.WriteString(" --- |")
+	}
+	sb.WriteString("\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+}
+
+// sortWords orders words in natural reading order: by page, then top to
+// bottom, then left to right.
+func sortWords(words []Word) {
+	sort.SliceStable(words, func(i, j int) bool {
+		a, b := words[i], words[j]
+		if a.Page != b.Page {
+			return a.Page < b.Page
+		}
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+}
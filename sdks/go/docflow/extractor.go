@@ -2,14 +2,19 @@ package docflow
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/xgaslan/docflow/sdks/go/docflow/cache/memcache"
+	"github.com/xgaslan/docflow/sdks/go/docflow/docmodel"
+	"github.com/xgaslan/docflow/sdks/go/docflow/ocr"
 	"github.com/xgaslan/docflow/sdks/go/docflow/storage"
 )
 
@@ -17,6 +22,18 @@ import (
 type Extractor struct {
 	options Options
 	storage storage.Storage
+	cache   *memcache.Cache
+
+	// ocrEngine, if set via WithOCRFallback, recognizes text on pages
+	// extractUncached judges low-yield (or every page, with
+	// Options.ForceOCR).
+	ocrEngine ocr.Engine
+	ocrLangs  string
+
+	// presignTTL, if set via WithPresignedOutput, makes ExtractToMarkdown
+	// return a presigned URL in ExtractResult.FilePath instead of
+	// storage's plain GetURL.
+	presignTTL time.Duration
 }
 
 // NewExtractor creates a new Extractor instance.
@@ -49,62 +66,245 @@ func WithExtractorStorage(s storage.Storage) ExtractorOption {
 	}
 }
 
+// WithExtractorCache sets a shared memcache.Cache used to memoize
+// extraction, keyed by the PDF's content hash and extraction options.
+// A cache hit skips the temp-file write and backend subprocess entirely.
+func WithExtractorCache(c *memcache.Cache) ExtractorOption {
+	return func(e *Extractor) {
+		e.cache = c
+	}
+}
+
+// WithBackend selects the PDF text extraction backend (BackendAuto,
+// BackendPoppler, BackendMupdf, or BackendPdfcpu). Equivalent to setting
+// Options.Backend via WithExtractorOptions.
+func WithBackend(name string) ExtractorOption {
+	return func(e *Extractor) {
+		e.options.Backend = name
+	}
+}
+
+// WithOCRFallback configures engine as the OCR fallback for scanned/
+// image-only PDFs: extractUncached rasterizes (via pdftoppm, falling
+// back to mutool) and OCRs any page whose backend-extracted text falls
+// below ocrLowYieldChars, then splices the recognized text back in
+// before textToMarkdown/docmodel ever see it. langs is passed through to
+// engine.Recognize as its language hint (e.g. Tesseract's "eng").
+func WithOCRFallback(engine ocr.Engine, langs string) ExtractorOption {
+	return func(e *Extractor) {
+		e.ocrEngine = engine
+		e.ocrLangs = langs
+	}
+}
+
+// WithForceOCR runs every page through the OCR fallback engine
+// (WithOCRFallback must also be set), instead of only pages judged
+// low-yield. Useful for PDFs with garbage embedded text that's long
+// enough to pass the low-yield check but isn't the real page content.
+func WithForceOCR() ExtractorOption {
+	return func(e *Extractor) {
+		e.options.ForceOCR = true
+	}
+}
+
+// WithPresignedOutput makes ExtractToMarkdown return a presigned,
+// time-limited download URL in ExtractResult.FilePath instead of the
+// storage backend's plain GetURL, for callers handing that URL to a
+// client that shouldn't need direct bucket/filesystem access. ttl is
+// passed through to the storage backend's PresignedURL.
+func WithPresignedOutput(ttl time.Duration) ExtractorOption {
+	return func(e *Extractor) {
+		e.presignTTL = ttl
+	}
+}
+
+// cachedExtraction is what ExtractToMarkdown's cache entries hold: enough
+// to rebuild an ExtractResult without re-running the backend.
+type cachedExtraction struct {
+	Markdown       string
+	PageCount      int
+	Backend        string
+	PageConfidence map[int]float64
+}
+
 // ExtractToMarkdown extracts text from PDF and converts to Markdown.
 func (e *Extractor) ExtractToMarkdown(ctx context.Context, pdfData []byte, filename string) (*ExtractResult, error) {
 	if len(pdfData) == 0 {
 		return nil, fmt.Errorf("PDF data is required")
 	}
 
-	// Ensure temp directory exists
-	if err := os.MkdirAll(e.options.TempDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create temp directory: %w", err)
-	}
-
 	timestamp := time.Now().Unix()
 	baseName := strings.TrimSuffix(filename, filepath.Ext(filename))
 	safeName := sanitizeFilename(baseName)
 
-	// Write PDF to temp file
-	tempPDFPath := filepath.Join(e.options.TempDir, fmt.Sprintf("%s_%d.pdf", safeName, timestamp))
-	if err := os.WriteFile(tempPDFPath, pdfData, 0644); err != nil {
-		return nil, fmt.Errorf("failed to write temp PDF: %w", err)
-	}
-	defer os.Remove(tempPDFPath)
-
-	// Extract text
-	text, err := e.extractWithPdftotext(ctx, tempPDFPath)
+	result, err := e.extractCached("md", pdfData, baseName, func() (cachedExtraction, error) {
+		return e.extractUncached(ctx, pdfData, baseName, safeName, timestamp)
+	})
 	if err != nil {
-		// Try alternative method
-		text, err = e.extractBasic(ctx, tempPDFPath)
-		if err != nil {
-			return &ExtractResult{Success: false, Error: err}, nil
+		var textErr *textExtractionError
+		if errors.As(err, &textErr) {
+			return &ExtractResult{Success: false, Error: textErr.cause}, nil
 		}
+		return nil, err
 	}
-
-	// Get page count
-	pageCount, _ := e.getPageCount(ctx, tempPDFPath)
-
-	// Convert to markdown
-	markdown := e.textToMarkdown(text, baseName)
+	markdown, pageCount := result.Markdown, result.PageCount
 
 	// Save to storage if configured
 	var outputPath string
 	if e.storage != nil {
-		outputPath = fmt.Sprintf("%s_%d.md", safeName, timestamp)
-		if err := e.storage.Save(outputPath, []byte(markdown)); err != nil {
+		savedPath := fmt.Sprintf("%s_%d.md", safeName, timestamp)
+		if err := e.storage.Save(ctx, savedPath, []byte(markdown)); err != nil {
 			return nil, fmt.Errorf("failed to save markdown: %w", err)
 		}
-		outputPath = e.storage.GetURL(outputPath)
+		if e.presignTTL > 0 {
+			url, err := e.storage.PresignedURL(ctx, savedPath, e.presignTTL)
+			if err != nil {
+				return nil, fmt.Errorf("failed to presign markdown url: %w", err)
+			}
+			outputPath = url
+		} else {
+			outputPath = e.storage.GetURL(savedPath)
+		}
 	}
 
 	return &ExtractResult{
-		Success:   true,
-		Markdown:  markdown,
-		FilePath:  outputPath,
-		PageCount: pageCount,
+		Success:        true,
+		Markdown:       markdown,
+		FilePath:       outputPath,
+		PageCount:      pageCount,
+		Backend:        result.Backend,
+		PageConfidence: result.PageConfidence,
 	}, nil
 }
 
+// extractCached memoizes a (kind, pdfData, baseName, backend) extraction
+// in e.cache, falling back to calling create directly when no cache is
+// configured. A hit skips create entirely, and with it the temp-file
+// write and backend subprocess create would otherwise perform.
+func (e *Extractor) extractCached(kind string, pdfData []byte, baseName string, create func() (cachedExtraction, error)) (cachedExtraction, error) {
+	if e.cache == nil {
+		return create()
+	}
+
+	key := e.cacheKey(kind, pdfData, baseName)
+	v, err := e.cache.GetOrCreate(key, func() (any, int64, error) {
+		result, err := create()
+		if err != nil {
+			return nil, 0, err
+		}
+		return result, int64(len(result.Markdown)), nil
+	})
+	if err != nil {
+		return cachedExtraction{}, err
+	}
+	return v.(cachedExtraction), nil
+}
+
+// cacheKey identifies an extraction by content hash, filename (the
+// markdown title depends on it, not just the PDF bytes), backend
+// (different backends can recover different text from the same PDF),
+// and whether the OCR fallback is in play (its presence, and ForceOCR,
+// change the markdown a cache hit would otherwise replay verbatim).
+func (e *Extractor) cacheKey(kind string, pdfData []byte, baseName string) string {
+	sum := sha256.Sum256(pdfData)
+	ocrKey := "noocr"
+	if e.ocrEngine != nil {
+		ocrKey = fmt.Sprintf("ocr:%s:force=%t", e.ocrLangs, e.options.ForceOCR)
+	}
+	return fmt.Sprintf("pdf:%s:%s:%s:%s:%s", kind, hex.EncodeToString(sum[:]), baseName, e.options.Backend, ocrKey)
+}
+
+// extractUncached runs the full write-temp-file, extract-text,
+// get-page-count, convert-to-markdown pipeline for ExtractToMarkdown. When
+// the PDF's content streams yield positioned, sized words,
+// docmodel.FromWords's layout-aware rendering replaces the flat-text
+// textToMarkdown heuristic; otherwise textToMarkdown's output stands.
+func (e *Extractor) extractUncached(ctx context.Context, pdfData []byte, baseName, safeName string, timestamp int64) (cachedExtraction, error) {
+	if err := os.MkdirAll(e.options.TempDir, 0755); err != nil {
+		return cachedExtraction{}, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	tempPDFPath := filepath.Join(e.options.TempDir, fmt.Sprintf("%s_%d.pdf", safeName, timestamp))
+	if err := os.WriteFile(tempPDFPath, pdfData, 0644); err != nil {
+		return cachedExtraction{}, fmt.Errorf("failed to write temp PDF: %w", err)
+	}
+	defer os.Remove(tempPDFPath)
+
+	backend, err := resolveBackend(e.options, e.options.Backend)
+	if err != nil {
+		return cachedExtraction{}, err
+	}
+
+	text, err := backend.extractText(ctx, tempPDFPath)
+	if err != nil {
+		return cachedExtraction{}, &textExtractionError{cause: err}
+	}
+
+	pageCount, _ := backend.pageCount(ctx, tempPDFPath)
+
+	var pageConfidence map[int]float64
+	if e.ocrEngine != nil {
+		text, pageConfidence = e.applyOCRFallback(ctx, tempPDFPath, text, pageCount)
+	}
+
+	markdown := e.textToMarkdown(text, baseName)
+	if words, ok := extractLayoutWords(pdfData); ok {
+		if layout := docmodel.FromWords(words).ToMarkdown(docmodel.DefaultRenderOptions()); strings.TrimSpace(layout) != "" {
+			markdown = layout
+		}
+	}
+
+	return cachedExtraction{Markdown: markdown, PageCount: pageCount, Backend: backend.name(), PageConfidence: pageConfidence}, nil
+}
+
+// textExtractionError marks a failure from the backend's text-extraction
+// step specifically, as distinct from setup failures (temp dir/file,
+// backend resolution): ExtractToMarkdown reports it as a failed
+// ExtractResult rather than a hard error, matching its pre-cache behavior.
+type textExtractionError struct {
+	cause error
+}
+
+func (e *textExtractionError) Error() string { return e.cause.Error() }
+func (e *textExtractionError) Unwrap() error { return e.cause }
+
+// previewUncached runs the write-temp-file, extract-first-page-text,
+// get-page-count, convert-to-markdown pipeline for Preview.
+func (e *Extractor) previewUncached(ctx context.Context, pdfData []byte, baseName, safeName string, timestamp int64) (cachedExtraction, error) {
+	if err := os.MkdirAll(e.options.TempDir, 0755); err != nil {
+		return cachedExtraction{}, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	tempPDFPath := filepath.Join(e.options.TempDir, fmt.Sprintf("%s_%d_preview.pdf", safeName, timestamp))
+	if err := os.WriteFile(tempPDFPath, pdfData, 0644); err != nil {
+		return cachedExtraction{}, fmt.Errorf("failed to write temp PDF: %w", err)
+	}
+	defer os.Remove(tempPDFPath)
+
+	backend, err := resolveBackend(e.options, e.options.Backend)
+	if err != nil {
+		return cachedExtraction{}, err
+	}
+
+	pageCount, _ := backend.pageCount(ctx, tempPDFPath)
+
+	text, err := backend.extractFirstPageText(ctx, tempPDFPath)
+	if err != nil {
+		text = "Preview not available"
+	}
+
+	return cachedExtraction{Markdown: e.textToMarkdown(text, baseName), PageCount: pageCount, Backend: backend.name()}, nil
+}
+
+// Stats reports the extractor's cache hit/miss/eviction counts, or a
+// zero Stats if no cache was configured with WithExtractorCache.
+func (e *Extractor) Stats() memcache.Stats {
+	if e.cache == nil {
+		return memcache.Stats{}
+	}
+	return e.cache.Stats()
+}
+
 // ExtractFromFile extracts markdown from a PDF file path.
 func (e *Extractor) ExtractFromFile(ctx context.Context, path string) (*ExtractResult, error) {
 	data, err := os.ReadFile(path)
@@ -124,7 +324,11 @@ func (e *Extractor) GetPageCount(ctx context.Context, pdfData []byte) (int, erro
 	}
 	defer os.Remove(tempPath)
 
-	return e.getPageCount(ctx, tempPath)
+	backend, err := resolveBackend(e.options, e.options.Backend)
+	if err != nil {
+		return 0, err
+	}
+	return backend.pageCount(ctx, tempPath)
 }
 
 // Preview extracts a preview of the first page.
@@ -133,32 +337,17 @@ func (e *Extractor) Preview(ctx context.Context, pdfData []byte, filename string
 		return nil, fmt.Errorf("PDF data is required")
 	}
 
-	// Ensure temp directory exists
-	if err := os.MkdirAll(e.options.TempDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create temp directory: %w", err)
-	}
-
 	timestamp := time.Now().Unix()
 	baseName := strings.TrimSuffix(filename, filepath.Ext(filename))
 	safeName := sanitizeFilename(baseName)
 
-	// Write PDF to temp file
-	tempPDFPath := filepath.Join(e.options.TempDir, fmt.Sprintf("%s_%d_preview.pdf", safeName, timestamp))
-	if err := os.WriteFile(tempPDFPath, pdfData, 0644); err != nil {
-		return nil, fmt.Errorf("failed to write temp PDF: %w", err)
-	}
-	defer os.Remove(tempPDFPath)
-
-	// Get page count
-	pageCount, _ := e.getPageCount(ctx, tempPDFPath)
-
-	// Extract first page only
-	text, err := e.extractFirstPage(ctx, tempPDFPath)
+	result, err := e.extractCached("preview", pdfData, baseName, func() (cachedExtraction, error) {
+		return e.previewUncached(ctx, pdfData, baseName, safeName, timestamp)
+	})
 	if err != nil {
-		text = "Preview not available"
+		return nil, err
 	}
-
-	markdown := e.textToMarkdown(text, baseName)
+	markdown, pageCount := result.Markdown, result.PageCount
 
 	// Truncate for preview
 	if len(markdown) > 2000 {
@@ -169,66 +358,52 @@ func (e *Extractor) Preview(ctx context.Context, pdfData []byte, filename string
 		Success:   true,
 		Markdown:  markdown,
 		PageCount: pageCount,
+		Backend:   result.Backend,
 	}, nil
 }
 
-func (e *Extractor) extractWithPdftotext(ctx context.Context, pdfPath string) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, e.options.Timeout)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "pdftotext", "-layout", "-enc", "UTF-8", pdfPath, "-")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("pdftotext failed: %w", err)
+// ExtractPage extracts and converts a single 1-indexed page, without
+// reading the whole document's text - useful for paging through a large
+// PDF instead of calling ExtractToMarkdown up front.
+func (e *Extractor) ExtractPage(ctx context.Context, pdfData []byte, filename string, page int) (*ExtractResult, error) {
+	if len(pdfData) == 0 {
+		return nil, fmt.Errorf("PDF data is required")
+	}
+	if page < 1 {
+		return nil, fmt.Errorf("page must be >= 1, got %d", page)
 	}
 
-	return string(output), nil
-}
-
-func (e *Extractor) extractBasic(ctx context.Context, pdfPath string) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, e.options.Timeout)
-	defer cancel()
+	baseName := strings.TrimSuffix(filename, filepath.Ext(filename))
+	safeName := sanitizeFilename(baseName)
 
-	// Try mutool
-	cmd := exec.CommandContext(ctx, "mutool", "draw", "-F", "txt", pdfPath)
-	output, err := cmd.Output()
-	if err == nil {
-		return string(output), nil
+	if err := os.MkdirAll(e.options.TempDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
 	}
 
-	return "", fmt.Errorf("no PDF extraction tool available (install poppler-utils or mupdf-tools)")
-}
-
-func (e *Extractor) extractFirstPage(ctx context.Context, pdfPath string) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
+	tempPDFPath := filepath.Join(e.options.TempDir, fmt.Sprintf("%s_%d_page%d.pdf", safeName, time.Now().UnixNano(), page))
+	if err := os.WriteFile(tempPDFPath, pdfData, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write temp PDF: %w", err)
+	}
+	defer os.Remove(tempPDFPath)
 
-	cmd := exec.CommandContext(ctx, "pdftotext", "-f", "1", "-l", "1", "-layout", "-enc", "UTF-8", pdfPath, "-")
-	output, err := cmd.Output()
+	backend, err := resolveBackend(e.options, e.options.Backend)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return string(output), nil
-}
+	pageCount, _ := backend.pageCount(ctx, tempPDFPath)
 
-func (e *Extractor) getPageCount(ctx context.Context, pdfPath string) (int, error) {
-	cmd := exec.CommandContext(ctx, "pdfinfo", pdfPath)
-	output, err := cmd.Output()
+	text, err := backend.extractPage(ctx, tempPDFPath, page)
 	if err != nil {
-		return 0, err
-	}
-
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if strings.HasPrefix(line, "Pages:") {
-			var pages int
-			fmt.Sscanf(line, "Pages: %d", &pages)
-			return pages, nil
-		}
+		return &ExtractResult{Success: false, Error: err}, nil
 	}
 
-	return 0, fmt.Errorf("could not determine page count")
+	return &ExtractResult{
+		Success:   true,
+		Markdown:  e.bodyToMarkdown(text),
+		PageCount: pageCount,
+		Backend:   backend.name(),
+	}, nil
 }
 
 func (e *Extractor) textToMarkdown(text, title string) string {
@@ -238,6 +413,17 @@ func (e *Extractor) textToMarkdown(text, title string) string {
 	result.WriteString("# ")
 	result.WriteString(title)
 	result.WriteString("\n\n")
+	result.WriteString(e.bodyToMarkdown(text))
+
+	return result.String()
+}
+
+// bodyToMarkdown runs textToMarkdown's header/bullet-detection heuristics
+// without the "# title" prefix, so callers that assemble their own
+// document structure (e.g. ExtractToChunks, converting one page at a time)
+// can reuse the same text cleanup.
+func (e *Extractor) bodyToMarkdown(text string) string {
+	var result strings.Builder
 
 	// Process text
 	lines := strings.Split(text, "\n")
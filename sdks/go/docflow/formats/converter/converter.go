@@ -0,0 +1,212 @@
+// Package converter dispatches office-document conversion to external
+// tools (soffice, ebook-convert, mutool) so formats outside CSV/Markdown
+// can still be turned into PDF or Markdown for the RAG pipeline.
+package converter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Converter dispatches conversions to the appropriate external tool based
+// on the source file extension.
+type Converter struct {
+	// Timeout bounds each shell-out.
+	Timeout time.Duration
+
+	// CacheDir is the root directory under which per-job temp dirs are
+	// created (cache/convert/<yyyy>/<mm>/<dd>/<hash>/).
+	CacheDir string
+
+	// SofficePath, EbookConvertPath and MutoolPath allow overriding the
+	// binaries looked up on PATH.
+	SofficePath      string
+	EbookConvertPath string
+	MutoolPath       string
+}
+
+// Option configures a Converter.
+type Option func(*Converter)
+
+// WithTimeout sets the per-conversion timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Converter) {
+		c.Timeout = timeout
+	}
+}
+
+// WithCacheDir sets the root cache directory for job temp dirs.
+func WithCacheDir(dir string) Option {
+	return func(c *Converter) {
+		c.CacheDir = dir
+	}
+}
+
+// New creates a new Converter with sensible defaults.
+func New(opts ...Option) *Converter {
+	c := &Converter{
+		Timeout:          2 * time.Minute,
+		CacheDir:         "cache/convert",
+		SofficePath:      "soffice",
+		EbookConvertPath: "ebook-convert",
+		MutoolPath:       "mutool",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+var officeExts = map[string]bool{
+	".docx": true, ".doc": true, ".ppt": true, ".pptx": true,
+	".xls": true, ".xlsx": true, ".odt": true, ".rtf": true,
+}
+
+var ebookExts = map[string]bool{
+	".epub": true, ".mobi": true, ".azw3": true,
+}
+
+// ConvertToPDF converts src to a PDF, dispatching on its extension, and
+// returns the path to the generated PDF inside the job's temp dir.
+func (c *Converter) ConvertToPDF(src string) (dst string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	jobDir, err := c.jobDir(src)
+	if err != nil {
+		return "", err
+	}
+
+	ext := strings.ToLower(filepath.Ext(src))
+	switch {
+	case officeExts[ext]:
+		return c.convertWithSoffice(ctx, src, jobDir)
+	case ebookExts[ext]:
+		return c.convertWithEbookConvert(ctx, src, jobDir, "pdf")
+	case ext == ".txt" || ext == ".umd":
+		return c.convertTextToPDF(ctx, src, jobDir)
+	case ext == ".pdf":
+		dst = filepath.Join(jobDir, filepath.Base(src))
+		return dst, copyFile(src, dst)
+	default:
+		return "", fmt.Errorf("converter: unsupported extension %q for PDF conversion", ext)
+	}
+}
+
+// ConvertToMarkdown converts src to Markdown, dispatching on its
+// extension.
+func (c *Converter) ConvertToMarkdown(src string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	jobDir, err := c.jobDir(src)
+	if err != nil {
+		return "", err
+	}
+
+	ext := strings.ToLower(filepath.Ext(src))
+	switch {
+	case officeExts[ext]:
+		pdf, err := c.convertWithSoffice(ctx, src, jobDir)
+		if err != nil {
+			return "", err
+		}
+		return c.extractWithMutool(ctx, pdf)
+	case ebookExts[ext]:
+		md, err := c.convertWithEbookConvert(ctx, src, jobDir, "txt")
+		if err != nil {
+			return "", err
+		}
+		data, err := os.ReadFile(md)
+		if err != nil {
+			return "", fmt.Errorf("converter: read ebook-convert output: %w", err)
+		}
+		return string(data), nil
+	case ext == ".txt" || ext == ".umd":
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return "", fmt.Errorf("converter: read %s: %w", src, err)
+		}
+		return string(data), nil
+	case ext == ".pdf":
+		return c.extractWithMutool(ctx, src)
+	default:
+		return "", fmt.Errorf("converter: unsupported extension %q for Markdown conversion", ext)
+	}
+}
+
+// Clean removes the job directory associated with src.
+func (c *Converter) Clean(src string) error {
+	jobDir, err := c.jobDir(src)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(jobDir)
+}
+
+func (c *Converter) convertWithSoffice(ctx context.Context, src, jobDir string) (string, error) {
+	cmd := exec.CommandContext(ctx, c.SofficePath,
+		"--headless", "--convert-to", "pdf", "--outdir", jobDir, src)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("converter: soffice failed: %w: %s", err, out)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(src), filepath.Ext(src))
+	return filepath.Join(jobDir, base+".pdf"), nil
+}
+
+func (c *Converter) convertWithEbookConvert(ctx context.Context, src, jobDir, targetExt string) (string, error) {
+	base := strings.TrimSuffix(filepath.Base(src), filepath.Ext(src))
+	dst := filepath.Join(jobDir, base+"."+targetExt)
+
+	cmd := exec.CommandContext(ctx, c.EbookConvertPath, src, dst)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("converter: ebook-convert failed: %w: %s", err, out)
+	}
+	return dst, nil
+}
+
+func (c *Converter) convertTextToPDF(ctx context.Context, src, jobDir string) (string, error) {
+	// soffice also handles plain text -> PDF conversion.
+	return c.convertWithSoffice(ctx, src, jobDir)
+}
+
+// extractWithMutool extracts text from a PDF using `mutool draw -F text`.
+func (c *Converter) extractWithMutool(ctx context.Context, pdfPath string) (string, error) {
+	cmd := exec.CommandContext(ctx, c.MutoolPath, "draw", "-F", "text", pdfPath)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("converter: mutool failed: %w", err)
+	}
+	return string(out), nil
+}
+
+// jobDir returns (creating if necessary) the per-job temp dir for src,
+// under cache/convert/<yyyy>/<mm>/<dd>/<hash>/.
+func (c *Converter) jobDir(src string) (string, error) {
+	now := time.Now()
+	hash := sha256.Sum256([]byte(src))
+	dir := filepath.Join(c.CacheDir,
+		now.Format("2006"), now.Format("01"), now.Format("02"),
+		hex.EncodeToString(hash[:])[:16],
+	)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("converter: create job dir: %w", err)
+	}
+	return dir, nil
+}
+
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("converter: read %s: %w", src, err)
+	}
+	return os.WriteFile(dst, data, 0644)
+}
@@ -2,9 +2,13 @@ package formats
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/csv"
+	"encoding/hex"
 	"fmt"
 	"strings"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/cache/memcache"
 )
 
 // CSVConverter handles CSV to Markdown conversion.
@@ -12,6 +16,9 @@ type CSVConverter struct {
 	Delimiter  rune
 	HasHeader  bool
 	TableTitle string
+
+	// Cache, if set, memoizes ToMarkdown output by content hash.
+	Cache *memcache.Cache
 }
 
 // CSVResult represents the result of a CSV conversion.
@@ -32,6 +39,24 @@ func NewCSVConverter() *CSVConverter {
 
 // ToMarkdown converts CSV content to Markdown table format.
 func (c *CSVConverter) ToMarkdown(csvData []byte, filename string) CSVResult {
+	if c.Cache == nil {
+		return c.toMarkdown(csvData, filename)
+	}
+
+	sum := sha256.Sum256(append([]byte(filename+"|"), csvData...))
+	key := "csv:" + hex.EncodeToString(sum[:])
+
+	value, err := c.Cache.GetOrCreate(key, func() (any, int64, error) {
+		result := c.toMarkdown(csvData, filename)
+		return result, int64(len(result.Content)), nil
+	})
+	if err != nil {
+		return CSVResult{Success: false, Error: err.Error()}
+	}
+	return value.(CSVResult)
+}
+
+func (c *CSVConverter) toMarkdown(csvData []byte, filename string) CSVResult {
 	reader := csv.NewReader(bytes.NewReader(csvData))
 	reader.Comma = c.Delimiter
 
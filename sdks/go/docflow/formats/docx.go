@@ -27,9 +27,6 @@ func NewDOCXConverter() *DOCXConverter {
 
 // ToMarkdown converts DOCX data to Markdown.
 func (c *DOCXConverter) ToMarkdown(data []byte, filename string) (*docflow.ConvertResult, error) {
-	// Note: Requires github.com/nguyenthenguyen/docx or similar
-	// For full implementation, use a proper DOCX library
-
 	doc, err := openDOCXFromBytes(data)
 	if err != nil {
 		return &docflow.ConvertResult{
@@ -38,7 +35,6 @@ func (c *DOCXConverter) ToMarkdown(data []byte, filename string) (*docflow.Conve
 		}, nil
 	}
 
-	content := doc.GetText()
 	images := []rag.ExtractedImage{}
 
 	if c.ExtractImages {
@@ -48,8 +44,13 @@ func (c *DOCXConverter) ToMarkdown(data []byte, filename string) (*docflow.Conve
 		}
 	}
 
-	// Convert to markdown
-	markdown := c.textToMarkdown(content, filename)
+	// GetMarkdown renders the document's real structure (headings, lists,
+	// tables, images); fall back to the plain-text heuristics only if
+	// that structure couldn't be recovered.
+	markdown := doc.GetMarkdown()
+	if strings.TrimSpace(markdown) == "" {
+		markdown = c.textToMarkdown(doc.GetText(), filename)
+	}
 
 	metadata := map[string]interface{}{
 		"filename":    filename,
@@ -181,9 +182,14 @@ func (c *DOCXConverter) processInlineFormatting(text string) string {
 	return text
 }
 
-// DOCX document interface (requires docx library)
+// docxDocument abstracts a parsed or in-progress .docx package. It's
+// implemented by ooxmlDocument (read, via openDOCXFromBytes) and
+// docxBuilder (write, via newDOCXDocument) in docx_ooxml.go/docx_writer.go.
 type docxDocument interface {
 	GetText() string
+	// GetMarkdown renders the document's real structure to Markdown, or
+	// "" if that structure isn't available (e.g. a write-only builder).
+	GetMarkdown() string
 	GetImages() ([]rag.ExtractedImage, error)
 	GetProperties() *docxProperties
 	AddHeading(text string, level int)
@@ -196,14 +202,3 @@ type docxProperties struct {
 	Title  string
 	Author string
 }
-
-// Placeholder functions - require docx dependency
-func openDOCXFromBytes(data []byte) (docxDocument, error) {
-	// Requires: go get github.com/nguyenthenguyen/docx
-	// Or: go get github.com/unidoc/unioffice
-	return nil, fmt.Errorf("docx library not installed")
-}
-
-func newDOCXDocument() docxDocument {
-	return nil
-}
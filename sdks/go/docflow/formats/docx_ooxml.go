@@ -0,0 +1,642 @@
+package formats
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/rag"
+)
+
+// ooxmlDocument is a docxDocument backed by a real .docx (OOXML) package,
+// read with archive/zip + encoding/xml. It has no CGO or unioffice
+// dependency - just enough of the WordprocessingML schema to round-trip
+// headings, lists, tables, and inline images.
+type ooxmlDocument struct {
+	blocks     []docBlock
+	media      map[string][]byte // rels target (e.g. "media/image1.png") -> bytes
+	properties *docxProperties
+}
+
+// docBlock is one top-level element of a document body: either a
+// paragraph or a table.
+type docBlock interface {
+	isDocBlock()
+}
+
+type docParagraph struct {
+	style string // w:pStyle val, e.g. "Heading1", "ListParagraph"
+	list  listKind
+	runs  []docRun
+}
+
+type listKind int
+
+const (
+	listNone listKind = iota
+	listBulleted
+	listNumbered
+)
+
+type docRun struct {
+	text       string
+	imageRelID string // set instead of text when the run carries a drawing/pic
+}
+
+type docTable struct {
+	rows [][]string
+}
+
+func (docParagraph) isDocBlock() {}
+func (docTable) isDocBlock()     {}
+
+// headingLevel returns the heading level (1-6) for a paragraph style name
+// like "Heading1", or 0 if style isn't a heading style.
+func headingLevel(style string) int {
+	for level := 1; level <= 6; level++ {
+		if style == fmt.Sprintf("Heading%d", level) {
+			return level
+		}
+	}
+	return 0
+}
+
+// openDOCXFromBytes opens a .docx package and parses its main document
+// part, relationships, media, and core properties.
+func openDOCXFromBytes(data []byte) (docxDocument, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not a valid zip/docx package: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	docXML, ok := files["word/document.xml"]
+	if !ok {
+		return nil, fmt.Errorf("missing word/document.xml")
+	}
+	docBytes, err := readZipFile(docXML)
+	if err != nil {
+		return nil, fmt.Errorf("reading word/document.xml: %w", err)
+	}
+
+	rels := map[string]string{}
+	if relsFile, ok := files["word/_rels/document.xml.rels"]; ok {
+		relsBytes, err := readZipFile(relsFile)
+		if err == nil {
+			rels, _ = parseRelationships(relsBytes)
+		}
+	}
+
+	blocks, err := parseDocumentXML(docBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing word/document.xml: %w", err)
+	}
+
+	media := map[string][]byte{}
+	for _, target := range rels {
+		f, ok := files[path.Join("word", target)]
+		if !ok {
+			continue
+		}
+		b, err := readZipFile(f)
+		if err != nil {
+			continue
+		}
+		media[target] = b
+	}
+
+	var props *docxProperties
+	if coreFile, ok := files["docProps/core.xml"]; ok {
+		coreBytes, err := readZipFile(coreFile)
+		if err == nil {
+			props, _ = parseCoreProperties(coreBytes)
+		}
+	}
+
+	doc := &ooxmlDocument{blocks: blocks, media: media, properties: props}
+	doc.resolveImageRuns(rels)
+	return doc, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// resolveImageRuns swaps each run's relationship ID for the media target
+// path it points at, so GetImages/GetMarkdown don't need the rels map.
+func (d *ooxmlDocument) resolveImageRuns(rels map[string]string) {
+	for _, b := range d.blocks {
+		p, ok := b.(docParagraph)
+		if !ok {
+			continue
+		}
+		for i, r := range p.runs {
+			if r.imageRelID == "" {
+				continue
+			}
+			p.runs[i].imageRelID = rels[r.imageRelID]
+		}
+	}
+}
+
+// GetText returns the document's plain text, one paragraph per line,
+// with table cells joined by tabs - this is what the heuristic
+// DOCXConverter.textToMarkdown falls back to when GetMarkdown is empty.
+func (d *ooxmlDocument) GetText() string {
+	var sb strings.Builder
+	for _, b := range d.blocks {
+		switch v := b.(type) {
+		case docParagraph:
+			sb.WriteString(v.text())
+			sb.WriteString("\n")
+		case docTable:
+			for _, row := range v.rows {
+				sb.WriteString(strings.Join(row, "\t"))
+				sb.WriteString("\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// GetMarkdown renders the parsed document straight to Markdown using its
+// real structure (headings, lists, tables, images) rather than the
+// plain-text heuristics DOCXConverter otherwise falls back on.
+func (d *ooxmlDocument) GetMarkdown() string {
+	var sb strings.Builder
+	listCounter := 0
+
+	for _, b := range d.blocks {
+		switch v := b.(type) {
+		case docParagraph:
+			if v.list != listNumbered {
+				listCounter = 0
+			}
+
+			text := v.text()
+			if text == "" {
+				continue
+			}
+
+			switch {
+			case headingLevel(v.style) > 0:
+				sb.WriteString(strings.Repeat("#", headingLevel(v.style)))
+				sb.WriteString(" ")
+				sb.WriteString(text)
+			case v.list == listBulleted:
+				sb.WriteString("- ")
+				sb.WriteString(text)
+			case v.list == listNumbered:
+				listCounter++
+				sb.WriteString(fmt.Sprintf("%d. ", listCounter))
+				sb.WriteString(text)
+			default:
+				sb.WriteString(text)
+			}
+			sb.WriteString("\n\n")
+		case docTable:
+			sb.WriteString(tableToMarkdown(v.rows))
+			sb.WriteString("\n")
+		}
+	}
+
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+func tableToMarkdown(rows [][]string) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	maxCols := 0
+	for _, row := range rows {
+		if len(row) > maxCols {
+			maxCols = len(row)
+		}
+	}
+
+	var sb strings.Builder
+	writeRow := func(row []string) {
+		sb.WriteString("|")
+		for i := 0; i < maxCols; i++ {
+			cell := ""
+			if i < len(row) {
+				cell = row[i]
+			}
+			sb.WriteString(" " + cell + " |")
+		}
+		sb.WriteString("\n")
+	}
+
+	writeRow(rows[0])
+	sb.WriteString("|")
+	for i := 0; i < maxCols; i++ {
+		sb.WriteString(" --- |")
+	}
+	sb.WriteString("\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return sb.String()
+}
+
+// text renders a paragraph's runs as Markdown inline content, turning
+// image runs into standard `![](path)` image syntax.
+func (p docParagraph) text() string {
+	var sb strings.Builder
+	for _, r := range p.runs {
+		if r.imageRelID != "" {
+			sb.WriteString(fmt.Sprintf("![](%s)", r.imageRelID))
+			continue
+		}
+		sb.WriteString(r.text)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// GetImages returns every image referenced by a w:drawing/w:pic run, in
+// document order.
+func (d *ooxmlDocument) GetImages() ([]rag.ExtractedImage, error) {
+	var images []rag.ExtractedImage
+	for _, b := range d.blocks {
+		p, ok := b.(docParagraph)
+		if !ok {
+			continue
+		}
+		for _, r := range p.runs {
+			if r.imageRelID == "" {
+				continue
+			}
+			data, ok := d.media[r.imageRelID]
+			if !ok {
+				continue
+			}
+			images = append(images, rag.ExtractedImage{
+				Data:     data,
+				Format:   strings.TrimPrefix(path.Ext(r.imageRelID), "."),
+				Filename: path.Base(r.imageRelID),
+			})
+		}
+	}
+	return images, nil
+}
+
+func (d *ooxmlDocument) GetProperties() *docxProperties {
+	return d.properties
+}
+
+// AddHeading/AddParagraph/AddListItem/Save are only used on documents
+// created by newDOCXDocument for FromMarkdown; a parsed ooxmlDocument is
+// read-only, so these are no-ops.
+func (d *ooxmlDocument) AddHeading(text string, level int) {}
+func (d *ooxmlDocument) AddParagraph(text string)          {}
+func (d *ooxmlDocument) AddListItem(text string)           {}
+func (d *ooxmlDocument) Save() ([]byte, error) {
+	return nil, fmt.Errorf("docx: Save called on a document opened for reading")
+}
+
+// parseRelationships parses a _rels/*.rels part into an Id -> Target map.
+func parseRelationships(data []byte) (map[string]string, error) {
+	var rels struct {
+		Relationship []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := xml.Unmarshal(data, &rels); err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(rels.Relationship))
+	for _, r := range rels.Relationship {
+		out[r.ID] = r.Target
+	}
+	return out, nil
+}
+
+// parseCoreProperties parses docProps/core.xml for title/author.
+func parseCoreProperties(data []byte) (*docxProperties, error) {
+	var core struct {
+		Title   string `xml:"title"`
+		Creator string `xml:"creator"`
+	}
+	if err := xml.Unmarshal(data, &core); err != nil {
+		return nil, err
+	}
+	return &docxProperties{Title: core.Title, Author: core.Creator}, nil
+}
+
+// parseDocumentXML walks word/document.xml's w:body, producing an
+// ordered slice of paragraph and table blocks. Elements it doesn't
+// recognize (w:sectPr, bookmarks, etc.) are skipped via dec.Skip().
+func parseDocumentXML(data []byte) ([]docBlock, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("word/document.xml: missing <w:body>")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "body" {
+			return parseBody(dec)
+		}
+	}
+}
+
+func parseBody(dec *xml.Decoder) ([]docBlock, error) {
+	var blocks []docBlock
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				p, err := parseParagraph(dec)
+				if err != nil {
+					return nil, err
+				}
+				blocks = append(blocks, p)
+			case "tbl":
+				tbl, err := parseTable(dec)
+				if err != nil {
+					return nil, err
+				}
+				blocks = append(blocks, tbl)
+			default:
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "body" {
+				return blocks, nil
+			}
+		}
+	}
+}
+
+func parseParagraph(dec *xml.Decoder) (docParagraph, error) {
+	var p docParagraph
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return p, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "pPr":
+				if err := parseParagraphProperties(dec, &p); err != nil {
+					return p, err
+				}
+			case "r":
+				r, err := parseRun(dec)
+				if err != nil {
+					return p, err
+				}
+				p.runs = append(p.runs, r)
+			default:
+				if err := dec.Skip(); err != nil {
+					return p, err
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "p" {
+				return p, nil
+			}
+		}
+	}
+}
+
+// parseParagraphProperties reads a w:pPr subtree for the paragraph style
+// (w:pStyle) and list membership (w:numPr).
+func parseParagraphProperties(dec *xml.Decoder, p *docParagraph) error {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "pStyle":
+				p.style = attrVal(t, "val")
+				if err := dec.Skip(); err != nil {
+					return err
+				}
+			case "numPr":
+				// A w:ilvl/w:numId pair is how Word distinguishes
+				// numbered from bulleted lists, but without
+				// cross-referencing numbering.xml we can't tell them
+				// apart, so treat any numPr as a bulleted list.
+				p.list = listBulleted
+				if err := dec.Skip(); err != nil {
+					return err
+				}
+			default:
+				if err := dec.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "pPr" {
+				return nil
+			}
+		}
+	}
+}
+
+func parseRun(dec *xml.Decoder) (docRun, error) {
+	var r docRun
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return r, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				text, err := readCharData(dec)
+				if err != nil {
+					return r, err
+				}
+				r.text += text
+			case "drawing":
+				relID, err := findBlipEmbed(dec)
+				if err != nil {
+					return r, err
+				}
+				if relID != "" {
+					r.imageRelID = relID
+				}
+			default:
+				if err := dec.Skip(); err != nil {
+					return r, err
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "r" {
+				return r, nil
+			}
+		}
+	}
+}
+
+// findBlipEmbed scans a w:drawing subtree for a w:blip (or pic:blip)
+// element's r:embed attribute, identifying the image's relationship ID.
+func findBlipEmbed(dec *xml.Decoder) (string, error) {
+	depth := 1
+	relID := ""
+	for depth > 0 {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if t.Name.Local == "blip" {
+				if v := attrVal(t, "embed"); v != "" {
+					relID = v
+				}
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return relID, nil
+}
+
+func parseTable(dec *xml.Decoder) (docTable, error) {
+	var tbl docTable
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return tbl, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "tr":
+				row, err := parseTableRow(dec)
+				if err != nil {
+					return tbl, err
+				}
+				tbl.rows = append(tbl.rows, row)
+			default:
+				if err := dec.Skip(); err != nil {
+					return tbl, err
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "tbl" {
+				return tbl, nil
+			}
+		}
+	}
+}
+
+func parseTableRow(dec *xml.Decoder) ([]string, error) {
+	var row []string
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return row, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "tc":
+				cell, err := parseTableCell(dec)
+				if err != nil {
+					return row, err
+				}
+				row = append(row, cell)
+			default:
+				if err := dec.Skip(); err != nil {
+					return row, err
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "tr" {
+				return row, nil
+			}
+		}
+	}
+}
+
+func parseTableCell(dec *xml.Decoder) (string, error) {
+	var parts []string
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				p, err := parseParagraph(dec)
+				if err != nil {
+					return "", err
+				}
+				if text := p.text(); text != "" {
+					parts = append(parts, text)
+				}
+			default:
+				if err := dec.Skip(); err != nil {
+					return "", err
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "tc" {
+				return strings.Join(parts, " "), nil
+			}
+		}
+	}
+}
+
+func readCharData(dec *xml.Decoder) (string, error) {
+	var text string
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return text, err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			text += string(t)
+		case xml.EndElement:
+			if t.Name.Local == "t" {
+				return text, nil
+			}
+		}
+	}
+}
+
+func attrVal(se xml.StartElement, local string) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package formats
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/sample.docx is a minimal .docx package produced by this
+// package's own DOCXConverter.FromMarkdown, checked in as a golden
+// fixture for ToMarkdown so a regression in the OOXML parser shows up as
+// a test failure instead of silently misreading real documents.
+func TestDOCXConverter_ToMarkdown_Golden(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample.docx")
+	require.NoError(t, err)
+
+	conv := NewDOCXConverter()
+	result, err := conv.ToMarkdown(data, "sample.docx")
+	require.NoError(t, err)
+	require.True(t, result.Success, "conversion failed: %v", result.Error)
+
+	md := result.Content
+	assert.Contains(t, md, "# Sample Document")
+	assert.Contains(t, md, "This is a sample paragraph for golden-file testing.")
+	assert.Contains(t, md, "## Section One")
+	assert.Contains(t, md, "- first item")
+	assert.Contains(t, md, "- second item")
+	assert.Contains(t, md, "### Details")
+	assert.Contains(t, md, "Another paragraph here.")
+
+	// Heading order must be preserved.
+	assert.Less(t,
+		strings.Index(md, "# Sample Document"),
+		strings.Index(md, "## Section One"),
+	)
+	assert.Less(t,
+		strings.Index(md, "## Section One"),
+		strings.Index(md, "### Details"),
+	)
+}
+
+func TestDOCXConverter_RoundTrip(t *testing.T) {
+	conv := NewDOCXConverter()
+	md := "# Title\n\nA paragraph.\n\n## Sub\n\n- one\n- two\n"
+
+	data, err := conv.FromMarkdown(md, "round.docx")
+	require.NoError(t, err)
+
+	result, err := conv.ToMarkdown(data, "round.docx")
+	require.NoError(t, err)
+	require.True(t, result.Success)
+
+	assert.Contains(t, result.Content, "# Title")
+	assert.Contains(t, result.Content, "A paragraph.")
+	assert.Contains(t, result.Content, "## Sub")
+	assert.Contains(t, result.Content, "- one")
+	assert.Contains(t, result.Content, "- two")
+}
@@ -0,0 +1,160 @@
+package formats
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/rag"
+)
+
+// docxBuilder accumulates paragraphs for FromMarkdown and serializes them
+// into a minimal but valid OOXML .docx package. It implements
+// docxDocument alongside ooxmlDocument, which handles the read side.
+type docxBuilder struct {
+	blocks []docParagraph
+}
+
+func newDOCXDocument() docxDocument {
+	return &docxBuilder{}
+}
+
+func (b *docxBuilder) AddHeading(text string, level int) {
+	if level < 1 {
+		level = 1
+	}
+	if level > 6 {
+		level = 6
+	}
+	b.blocks = append(b.blocks, docParagraph{
+		style: fmt.Sprintf("Heading%d", level),
+		runs:  []docRun{{text: text}},
+	})
+}
+
+func (b *docxBuilder) AddParagraph(text string) {
+	b.blocks = append(b.blocks, docParagraph{runs: []docRun{{text: text}}})
+}
+
+func (b *docxBuilder) AddListItem(text string) {
+	b.blocks = append(b.blocks, docParagraph{
+		style: "ListParagraph",
+		list:  listBulleted,
+		runs:  []docRun{{text: text}},
+	})
+}
+
+// GetText/GetMarkdown/GetImages/GetProperties are only meaningful on a
+// document opened by openDOCXFromBytes; a builder is write-only.
+func (b *docxBuilder) GetText() string                          { return "" }
+func (b *docxBuilder) GetMarkdown() string                      { return "" }
+func (b *docxBuilder) GetImages() ([]rag.ExtractedImage, error) { return nil, nil }
+func (b *docxBuilder) GetProperties() *docxProperties           { return nil }
+
+// Save serializes the accumulated paragraphs into a minimal .docx
+// package: [Content_Types].xml, _rels/.rels, word/document.xml, and
+// word/styles.xml with Heading1-6 styles, which is enough for Word and
+// LibreOffice to open it.
+func (b *docxBuilder) Save() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+
+	files := map[string]string{
+		"[Content_Types].xml":          contentTypesXML,
+		"_rels/.rels":                  rootRelsXML,
+		"word/_rels/document.xml.rels": documentRelsXML,
+		"word/styles.xml":              stylesXML,
+		"word/document.xml":            b.documentXML(),
+	}
+
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			return nil, fmt.Errorf("docx: creating %s: %w", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			return nil, fmt.Errorf("docx: writing %s: %w", name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("docx: closing package: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (b *docxBuilder) documentXML() string {
+	var body strings.Builder
+	for _, p := range b.blocks {
+		body.WriteString(paragraphXML(p))
+	}
+
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:body>` + body.String() + `<w:sectPr/></w:body>` +
+		`</w:document>`
+}
+
+func paragraphXML(p docParagraph) string {
+	var pPr string
+	switch {
+	case headingLevel(p.style) > 0:
+		pPr = fmt.Sprintf(`<w:pPr><w:pStyle w:val="%s"/></w:pPr>`, p.style)
+	case p.list == listBulleted:
+		pPr = `<w:pPr><w:pStyle w:val="ListParagraph"/><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr>`
+	}
+
+	var runs strings.Builder
+	for _, r := range p.runs {
+		runs.WriteString(fmt.Sprintf(`<w:r><w:t xml:space="preserve">%s</w:t></w:r>`, escapeXMLText(r.text)))
+	}
+
+	return `<w:p>` + pPr + runs.String() + `</w:p>`
+}
+
+func escapeXMLText(s string) string {
+	replacer := strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+	)
+	return replacer.Replace(s)
+}
+
+const contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+	`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+	`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+	`</Types>`
+
+const rootRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+	`</Relationships>`
+
+const documentRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+var stylesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+	`<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>` +
+	headingStylesXML() +
+	`<w:style w:type="paragraph" w:styleId="ListParagraph"><w:name w:val="List Paragraph"/><w:basedOn w:val="Normal"/></w:style>` +
+	`</w:styles>`
+
+func headingStylesXML() string {
+	var sb strings.Builder
+	for level := 1; level <= 6; level++ {
+		sb.WriteString(fmt.Sprintf(
+			`<w:style w:type="paragraph" w:styleId="Heading%d"><w:name w:val="heading %d"/><w:basedOn w:val="Normal"/>`+
+				`<w:pPr><w:outlineLvl w:val="%d"/></w:pPr><w:rPr><w:b/><w:sz w:val="%d"/></w:rPr></w:style>`,
+			level, level, level-1, 36-level*2,
+		))
+	}
+	return sb.String()
+}
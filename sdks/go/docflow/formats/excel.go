@@ -10,10 +10,17 @@ import (
 
 // ExcelConverter converts Excel files to/from Markdown.
 type ExcelConverter struct {
-	// IncludeAllSheets includes all sheets in conversion
+	// IncludeAllSheets includes all sheets in conversion. Ignored once
+	// SheetFilter is set.
 	IncludeAllSheets bool
 	// SheetSeparator is the separator between sheets
 	SheetSeparator string
+	// SheetFilter, if non-empty, restricts conversion to sheets with
+	// these exact names, in workbook order.
+	SheetFilter []string
+	// MaxRowsPerSheet caps how many data rows are read per sheet before
+	// the rest are dropped with a truncation note. Zero means no limit.
+	MaxRowsPerSheet int
 }
 
 // NewExcelConverter creates a new Excel converter.
@@ -24,12 +31,11 @@ func NewExcelConverter() *ExcelConverter {
 	}
 }
 
-// ToMarkdown converts Excel data to Markdown.
+// ToMarkdown converts Excel data to Markdown. Merged cells are rendered
+// as an HTML table with colspan/rowspan (GFM markdown tables have no
+// merge syntax); sheets with no merges get a plain pipe table.
 func (c *ExcelConverter) ToMarkdown(data []byte, filename string) (*docflow.ConvertResult, error) {
-	// Note: Requires github.com/xuri/excelize/v2
-	// go get github.com/xuri/excelize/v2
-
-	xlsx, err := openExcelFromBytes(data)
+	wb, err := openExcelFromBytes(data)
 	if err != nil {
 		return &docflow.ConvertResult{
 			Success: false,
@@ -37,33 +43,49 @@ func (c *ExcelConverter) ToMarkdown(data []byte, filename string) (*docflow.Conv
 		}, nil
 	}
 
+	allSheets := wb.GetSheetList()
+	sheets := c.selectSheets(allSheets)
+
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("# %s\n\n", filename))
 
-	sheets := xlsx.GetSheetList()
-	for i, sheet := range sheets {
-		if !c.IncludeAllSheets && i > 0 {
-			break
-		}
+	totalRows := 0
+	hasFormulas := false
 
+	for i, sheet := range sheets {
 		if i > 0 {
 			sb.WriteString(c.SheetSeparator)
 		}
-
 		sb.WriteString(fmt.Sprintf("## %s\n\n", sheet))
 
-		rows, err := xlsx.GetRows(sheet)
+		sheetXML, err := wb.sheetXML(sheet)
 		if err != nil {
+			sb.WriteString("*Could not read sheet*\n")
 			continue
 		}
 
+		rows, processed, truncated, formulas, err := c.renderSheet(sheetXML, wb.sst, wb.styles)
+		if err != nil {
+			sb.WriteString("*Error reading sheet*\n")
+			continue
+		}
+		totalRows += processed
+		hasFormulas = hasFormulas || formulas
+
 		if len(rows) == 0 {
 			sb.WriteString("*Empty sheet*\n")
 			continue
 		}
 
-		// Convert to markdown table
-		sb.WriteString(c.rowsToMarkdownTable(rows))
+		origins, covered := parseMergeCells(sheetXML)
+		if len(origins) > 0 {
+			sb.WriteString(htmlTableWithMerges(rows, origins, covered))
+		} else {
+			sb.WriteString(c.rowsToMarkdownTable(rows))
+		}
+		if truncated {
+			sb.WriteString(fmt.Sprintf("\n*(truncated at %d rows)*\n", c.MaxRowsPerSheet))
+		}
 	}
 
 	return &docflow.ConvertResult{
@@ -71,13 +93,131 @@ func (c *ExcelConverter) ToMarkdown(data []byte, filename string) (*docflow.Conv
 		Content: sb.String(),
 		Format:  "xlsx",
 		Metadata: map[string]interface{}{
-			"sheet_count": len(sheets),
-			"filename":    filename,
+			"sheet_count":  len(allSheets),
+			"row_count":    totalRows,
+			"has_formulas": hasFormulas,
+			"filename":     filename,
 		},
 	}, nil
 }
 
-// FromMarkdown converts Markdown table to Excel.
+// selectSheets applies SheetFilter (exact names, workbook order) if
+// set, else falls back to the legacy IncludeAllSheets behavior of
+// either every sheet or just the first.
+func (c *ExcelConverter) selectSheets(allSheets []string) []string {
+	if len(c.SheetFilter) > 0 {
+		wanted := make(map[string]bool, len(c.SheetFilter))
+		for _, name := range c.SheetFilter {
+			wanted[name] = true
+		}
+		var filtered []string
+		for _, s := range allSheets {
+			if wanted[s] {
+				filtered = append(filtered, s)
+			}
+		}
+		return filtered
+	}
+	if !c.IncludeAllSheets && len(allSheets) > 1 {
+		return allSheets[:1]
+	}
+	return allSheets
+}
+
+// renderSheet streams sheetXML's rows (see streamSheetRows) into a
+// dense [][]string indexed by actual sheet row number, so row gaps
+// (blank rows Excel omits entirely from the XML) and merged-cell
+// origins computed separately by parseMergeCells still line up.
+func (c *ExcelConverter) renderSheet(sheetXML []byte, sst []string, styles *styleSheet) (rows [][]string, processed int, truncated bool, hasFormulas bool, err error) {
+	maxCols := sheetMaxCols(sheetXML)
+	maxRowNum := 0
+	byRow := map[int][]string{}
+
+	_, formulas, err := streamSheetRows(sheetXML, sst, styles, func(rowNum int, cells []renderedCell) bool {
+		if c.MaxRowsPerSheet > 0 && processed >= c.MaxRowsPerSheet {
+			truncated = true
+			return false
+		}
+		byRow[rowNum] = renderRow(cells, maxCols)
+		if rowNum > maxRowNum {
+			maxRowNum = rowNum
+		}
+		processed++
+		return true
+	})
+	if err != nil {
+		return nil, processed, truncated, formulas, err
+	}
+	hasFormulas = formulas
+
+	rows = make([][]string, 0, maxRowNum)
+	for r := 1; r <= maxRowNum; r++ {
+		row, ok := byRow[r]
+		if !ok {
+			row = make([]string, maxCols)
+		}
+		rows = append(rows, row)
+	}
+	return rows, processed, truncated, hasFormulas, nil
+}
+
+// renderRow places cells at their declared column positions, widening
+// the row past maxCols if a cell reference falls outside the sheet's
+// declared <dimension> (which shouldn't happen in well-formed xlsx, but
+// a defensively-written row shouldn't panic if it does).
+func renderRow(cells []renderedCell, maxCols int) []string {
+	width := maxCols
+	for _, c := range cells {
+		if c.col > width {
+			width = c.col
+		}
+	}
+	row := make([]string, width)
+	for _, c := range cells {
+		if c.col >= 1 && c.col <= len(row) {
+			row[c.col-1] = c.text
+		}
+	}
+	return row
+}
+
+// htmlTableWithMerges renders rows as an HTML table, applying
+// colspan/rowspan at each merge origin and skipping cells a merge
+// covers but doesn't originate from.
+func htmlTableWithMerges(rows [][]string, origins map[[2]int]mergeSpan, covered map[[2]int]bool) string {
+	var sb strings.Builder
+	sb.WriteString("<table>\n")
+	for rIdx, row := range rows {
+		rowNum := rIdx + 1
+		sb.WriteString("  <tr>")
+		for cIdx, text := range row {
+			colNum := cIdx + 1
+			key := [2]int{rowNum, colNum}
+			if covered[key] {
+				continue
+			}
+			tag := "td"
+			if rIdx == 0 {
+				tag = "th"
+			}
+			var attrs string
+			if span, ok := origins[key]; ok {
+				if span.colSpan > 1 {
+					attrs += fmt.Sprintf(` colspan="%d"`, span.colSpan)
+				}
+				if span.rowSpan > 1 {
+					attrs += fmt.Sprintf(` rowspan="%d"`, span.rowSpan)
+				}
+			}
+			sb.WriteString(fmt.Sprintf("<%s%s>%s</%s>", tag, attrs, text, tag))
+		}
+		sb.WriteString("</tr>\n")
+	}
+	sb.WriteString("</table>\n")
+	return sb.String()
+}
+
+// FromMarkdown converts Markdown tables to Excel.
 func (c *ExcelConverter) FromMarkdown(content string, filename string) ([]byte, error) {
 	xlsx := newExcelFile()
 
@@ -200,26 +340,3 @@ func columnToLetter(col int) string {
 	}
 	return result
 }
-
-// Excel file interface (requires excelize)
-type excelFile interface {
-	GetSheetList() []string
-	GetRows(sheet string) ([][]string, error)
-	SetSheetName(old, new string) error
-	NewSheet(name string) (int, error)
-	SetCellValue(sheet, cell string, value interface{}) error
-	Write(w *bytes.Buffer) error
-}
-
-// Placeholder functions - require excelize dependency
-func openExcelFromBytes(data []byte) (excelFile, error) {
-	// Requires: go get github.com/xuri/excelize/v2
-	// import "github.com/xuri/excelize/v2"
-	// return excelize.OpenReader(bytes.NewReader(data))
-	return nil, fmt.Errorf("excelize not installed: go get github.com/xuri/excelize/v2")
-}
-
-func newExcelFile() excelFile {
-	// return excelize.NewFile()
-	return nil
-}
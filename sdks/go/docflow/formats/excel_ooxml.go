@@ -0,0 +1,613 @@
+package formats
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ooxmlWorkbook is an excelWorkbook backed by a real .xlsx (OOXML
+// spreadsheet) package, read with archive/zip + encoding/xml. Like
+// ooxmlDocument for DOCX, it has no third-party (excelize) dependency -
+// just enough of the SpreadsheetML schema to recover sheet data, number
+// formats, merged cells, and formulas.
+type ooxmlWorkbook struct {
+	files      map[string]*zip.File
+	sheetOrder []string
+	sheetPart  map[string]string // sheet name -> zip path, e.g. "xl/worksheets/sheet1.xml"
+	sst        []string          // shared strings table
+	styles     *styleSheet
+}
+
+// openExcelFromBytes opens an .xlsx package and indexes its workbook,
+// shared strings, and styles parts so sheets can be streamed on demand.
+func openExcelFromBytes(data []byte) (*ooxmlWorkbook, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not a valid zip/xlsx package: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	wbFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return nil, fmt.Errorf("missing xl/workbook.xml")
+	}
+	wbBytes, err := readZipFile(wbFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading xl/workbook.xml: %w", err)
+	}
+	sheetRefs, err := parseWorkbookXML(wbBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing xl/workbook.xml: %w", err)
+	}
+
+	rels := map[string]string{}
+	if relsFile, ok := files["xl/_rels/workbook.xml.rels"]; ok {
+		relsBytes, err := readZipFile(relsFile)
+		if err == nil {
+			rels, _ = parseRelationships(relsBytes)
+		}
+	}
+
+	sheetOrder := make([]string, 0, len(sheetRefs))
+	sheetPart := make(map[string]string, len(sheetRefs))
+	for _, ref := range sheetRefs {
+		target := rels[ref.relID]
+		if target == "" {
+			continue
+		}
+		sheetOrder = append(sheetOrder, ref.name)
+		sheetPart[ref.name] = path.Join("xl", target)
+	}
+
+	var sst []string
+	if sstFile, ok := files["xl/sharedStrings.xml"]; ok {
+		sstBytes, err := readZipFile(sstFile)
+		if err == nil {
+			sst, _ = parseSharedStrings(sstBytes)
+		}
+	}
+
+	var styles *styleSheet
+	if stylesFile, ok := files["xl/styles.xml"]; ok {
+		stylesBytes, err := readZipFile(stylesFile)
+		if err == nil {
+			styles, _ = parseStylesXML(stylesBytes)
+		}
+	}
+
+	return &ooxmlWorkbook{
+		files:      files,
+		sheetOrder: sheetOrder,
+		sheetPart:  sheetPart,
+		sst:        sst,
+		styles:     styles,
+	}, nil
+}
+
+// GetSheetList returns sheet names in workbook order.
+func (w *ooxmlWorkbook) GetSheetList() []string {
+	return w.sheetOrder
+}
+
+// sheetXML returns the raw worksheet XML for name, still compressed in
+// the zip until this read - the package as a whole never holds more
+// than one sheet's decompressed bytes at a time.
+func (w *ooxmlWorkbook) sheetXML(name string) ([]byte, error) {
+	part, ok := w.sheetPart[name]
+	if !ok {
+		return nil, fmt.Errorf("sheet %q not found", name)
+	}
+	f, ok := w.files[part]
+	if !ok {
+		return nil, fmt.Errorf("sheet part %q missing from package", part)
+	}
+	return readZipFile(f)
+}
+
+// sheetRef is one <sheet> entry from workbook.xml: its display name and
+// the r:id used to resolve its part path via workbook.xml.rels.
+type sheetRef struct {
+	name  string
+	relID string
+}
+
+// parseWorkbookXML walks xl/workbook.xml for its ordered <sheet> list.
+// attrVal matches attributes by local name, so the "r:id" namespace
+// prefix (declared further up the document) doesn't need resolving.
+func parseWorkbookXML(data []byte) ([]sheetRef, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var sheets []sheetRef
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "sheet" {
+			sheets = append(sheets, sheetRef{name: attrVal(se, "name"), relID: attrVal(se, "id")})
+		}
+	}
+	return sheets, nil
+}
+
+// parseSharedStrings parses xl/sharedStrings.xml's <si> entries into an
+// index-ordered slice, joining rich-text runs (<r><t>) when a <si> has
+// no single top-level <t>.
+func parseSharedStrings(data []byte) ([]string, error) {
+	var doc struct {
+		SI []struct {
+			T string `xml:"t"`
+			R []struct {
+				T string `xml:"t"`
+			} `xml:"r"`
+		} `xml:"si"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	out := make([]string, len(doc.SI))
+	for i, si := range doc.SI {
+		if si.T != "" {
+			out[i] = si.T
+			continue
+		}
+		var parts []string
+		for _, r := range si.R {
+			parts = append(parts, r.T)
+		}
+		out[i] = strings.Join(parts, "")
+	}
+	return out, nil
+}
+
+// numFmtKind classifies a cell's number format for rendering purposes.
+type numFmtKind int
+
+const (
+	fmtGeneral numFmtKind = iota
+	fmtDate
+	fmtPercent
+	fmtCurrency
+)
+
+// styleSheet is a minimal read of xl/styles.xml: just enough to map a
+// cell's style index ("s" attribute) to the numFmtKind that decides how
+// its raw numeric value should be rendered.
+type styleSheet struct {
+	xfNumFmt      []int          // cellXfs index -> numFmtId
+	customFormats map[int]string // numFmtId -> formatCode, for numFmtId >= 164
+}
+
+func parseStylesXML(data []byte) (*styleSheet, error) {
+	var doc struct {
+		NumFmts struct {
+			NumFmt []struct {
+				NumFmtID   int    `xml:"numFmtId,attr"`
+				FormatCode string `xml:"formatCode,attr"`
+			} `xml:"numFmt"`
+		} `xml:"numFmts"`
+		CellXfs struct {
+			Xf []struct {
+				NumFmtID int `xml:"numFmtId,attr"`
+			} `xml:"xf"`
+		} `xml:"cellXfs"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	ss := &styleSheet{customFormats: map[int]string{}}
+	for _, nf := range doc.NumFmts.NumFmt {
+		ss.customFormats[nf.NumFmtID] = nf.FormatCode
+	}
+	for _, xf := range doc.CellXfs.Xf {
+		ss.xfNumFmt = append(ss.xfNumFmt, xf.NumFmtID)
+	}
+	return ss, nil
+}
+
+// kindFor returns the numFmtKind a cell with style index styleIdx
+// renders as, or fmtGeneral if styles.xml was missing or the index is
+// out of range.
+func (ss *styleSheet) kindFor(styleIdx int) numFmtKind {
+	if ss == nil || styleIdx < 0 || styleIdx >= len(ss.xfNumFmt) {
+		return fmtGeneral
+	}
+	id := ss.xfNumFmt[styleIdx]
+	if code, ok := ss.customFormats[id]; ok {
+		return classifyFormatCode(code)
+	}
+	return classifyBuiltinNumFmt(id)
+}
+
+// classifyBuiltinNumFmt maps ECMA-376's built-in numFmtId ranges to a
+// numFmtKind.
+func classifyBuiltinNumFmt(id int) numFmtKind {
+	switch {
+	case id == 9 || id == 10:
+		return fmtPercent
+	case id >= 14 && id <= 22, id >= 45 && id <= 47:
+		return fmtDate
+	case (id >= 5 && id <= 8) || (id >= 37 && id <= 44):
+		return fmtCurrency
+	default:
+		return fmtGeneral
+	}
+}
+
+// classifyFormatCode heuristically classifies a custom numFmtId's
+// format code string, the same ALL-CAPS/glyph-heuristic spirit
+// structure.go's heading detection uses for PDFs - there's no schema
+// to consult for arbitrary custom formats, just the token characters a
+// user's format string is built from.
+func classifyFormatCode(code string) numFmtKind {
+	lower := strings.ToLower(code)
+	switch {
+	case strings.Contains(code, "%"):
+		return fmtPercent
+	case strings.ContainsAny(code, "$€£¥"):
+		return fmtCurrency
+	case strings.Contains(lower, "y") && (strings.Contains(lower, "m") || strings.Contains(lower, "d")):
+		return fmtDate
+	default:
+		return fmtGeneral
+	}
+}
+
+// excelEpoch is day zero of Excel's serial date system (1899-12-30),
+// chosen so day 1 lands on 1900-01-01 - this deliberately reproduces
+// Excel's well-known "1900 was a leap year" bug rather than correcting
+// it, since that's the value actually stored in real workbooks.
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+func excelSerialToTime(serial float64) time.Time {
+	days := int(serial)
+	frac := serial - float64(days)
+	t := excelEpoch.AddDate(0, 0, days)
+	if frac > 0 {
+		t = t.Add(time.Duration(frac*86400*float64(time.Second) + 0.5))
+	}
+	return t
+}
+
+// renderNumeric formats a raw numeric cell value (as written in a <v>
+// element) according to kind.
+func renderNumeric(raw string, kind numFmtKind) string {
+	val, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return raw
+	}
+	switch kind {
+	case fmtDate:
+		t := excelSerialToTime(val)
+		if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+			return t.Format("2006-01-02")
+		}
+		return t.Format("2006-01-02 15:04:05")
+	case fmtPercent:
+		return strconv.FormatFloat(val*100, 'f', 2, 64) + "%"
+	case fmtCurrency:
+		return "$" + strconv.FormatFloat(val, 'f', 2, 64)
+	default:
+		return raw
+	}
+}
+
+// renderedCell is one streamed, already-rendered worksheet cell.
+type renderedCell struct {
+	col  int // 1-indexed column
+	text string
+}
+
+// streamSheetRows walks sheetXML's <sheetData> one <row>/<c> at a time
+// via xml.Decoder, rendering each cell's display text as it goes rather
+// than unmarshalling the whole sheet into an in-memory table first -
+// the same no-full-buffering spirit as BatchProcessor's TeeReader
+// hashing. onRow is called once per row in document order; returning
+// false from it stops iteration early (used for MaxRowsPerSheet).
+func streamSheetRows(sheetXML []byte, sst []string, styles *styleSheet, onRow func(rowNum int, cells []renderedCell) bool) (rowCount int, hasFormulas bool, err error) {
+	dec := xml.NewDecoder(bytes.NewReader(sheetXML))
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return rowCount, hasFormulas, nil
+		}
+		if err != nil {
+			return rowCount, hasFormulas, err
+		}
+
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "row" {
+			continue
+		}
+
+		rowNum, _ := strconv.Atoi(attrVal(se, "r"))
+		cells, formulaInRow, err := parseSheetRow(dec)
+		if err != nil {
+			return rowCount, hasFormulas, err
+		}
+		rowCount++
+		hasFormulas = hasFormulas || formulaInRow
+
+		rendered := make([]renderedCell, 0, len(cells))
+		for _, c := range cells {
+			rendered = append(rendered, renderedCell{col: c.col, text: renderCellValue(c, sst, styles)})
+		}
+		if !onRow(rowNum, rendered) {
+			return rowCount, hasFormulas, nil
+		}
+	}
+}
+
+// sheetCell is one raw, unrendered <c> element's parsed fields.
+type sheetCell struct {
+	col       int
+	styleIdx  int
+	cellType  string // "s" (shared string), "str"/"inlineStr", "b" (bool), "e" (error), or "" (number)
+	raw       string
+	isFormula bool
+}
+
+// parseSheetRow reads a <row>...</row> subtree into its cells.
+func parseSheetRow(dec *xml.Decoder) ([]sheetCell, bool, error) {
+	var cells []sheetCell
+	hasFormula := false
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return cells, hasFormula, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "c" {
+				if err := dec.Skip(); err != nil {
+					return cells, hasFormula, err
+				}
+				continue
+			}
+			col, _, _ := parseCellRef(attrVal(t, "r"))
+			styleIdx := -1
+			if s := attrVal(t, "s"); s != "" {
+				styleIdx, _ = strconv.Atoi(s)
+			}
+			cell := sheetCell{col: col, styleIdx: styleIdx, cellType: attrVal(t, "t")}
+
+			inline, formula, raw, err := parseSheetCellBody(dec)
+			if err != nil {
+				return cells, hasFormula, err
+			}
+			if formula {
+				hasFormula = true
+			}
+			if inline != "" {
+				cell.cellType = "inlineStr"
+				cell.raw = inline
+			} else {
+				cell.raw = raw
+			}
+			cells = append(cells, cell)
+		case xml.EndElement:
+			if t.Name.Local == "row" {
+				return cells, hasFormula, nil
+			}
+		}
+	}
+}
+
+// parseSheetCellBody reads a <c>...</c>'s children: <f> (formula,
+// presence recorded but text discarded), <v> (value), or <is><t>
+// (inline string).
+func parseSheetCellBody(dec *xml.Decoder) (inline string, hasFormula bool, value string, err error) {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return inline, hasFormula, value, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "f":
+				hasFormula = true
+				if err := dec.Skip(); err != nil {
+					return inline, hasFormula, value, err
+				}
+			case "v":
+				value, err = readCharDataUntil(dec, "v")
+				if err != nil {
+					return inline, hasFormula, value, err
+				}
+			case "is":
+				inline, err = parseInlineString(dec)
+				if err != nil {
+					return inline, hasFormula, value, err
+				}
+			default:
+				if err := dec.Skip(); err != nil {
+					return inline, hasFormula, value, err
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "c" {
+				return inline, hasFormula, value, nil
+			}
+		}
+	}
+}
+
+// parseInlineString reads an <is>...</is> subtree, joining any <t>
+// text runs it contains.
+func parseInlineString(dec *xml.Decoder) (string, error) {
+	var sb strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return sb.String(), err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "t" {
+				text, err := readCharDataUntil(dec, "t")
+				if err != nil {
+					return sb.String(), err
+				}
+				sb.WriteString(text)
+			} else if err := dec.Skip(); err != nil {
+				return sb.String(), err
+			}
+		case xml.EndElement:
+			if t.Name.Local == "is" {
+				return sb.String(), nil
+			}
+		}
+	}
+}
+
+// readCharDataUntil reads character data up to the closing tag named
+// endLocal.
+func readCharDataUntil(dec *xml.Decoder, endLocal string) (string, error) {
+	var sb strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return sb.String(), err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			sb.Write(t)
+		case xml.EndElement:
+			if t.Name.Local == endLocal {
+				return sb.String(), nil
+			}
+		}
+	}
+}
+
+// renderCellValue produces the display text for a parsed sheetCell.
+func renderCellValue(c sheetCell, sst []string, styles *styleSheet) string {
+	switch c.cellType {
+	case "s":
+		idx, err := strconv.Atoi(c.raw)
+		if err != nil || idx < 0 || idx >= len(sst) {
+			return ""
+		}
+		return sst[idx]
+	case "str", "inlineStr":
+		return c.raw
+	case "b":
+		if c.raw == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	case "e":
+		return c.raw
+	default:
+		if c.raw == "" {
+			return ""
+		}
+		return renderNumeric(c.raw, styles.kindFor(c.styleIdx))
+	}
+}
+
+var cellRefRe = regexp.MustCompile(`^([A-Za-z]+)(\d+)$`)
+
+// parseCellRef splits a cell reference like "B3" into its 1-indexed
+// column and row.
+func parseCellRef(ref string) (col, row int, ok bool) {
+	m := cellRefRe.FindStringSubmatch(ref)
+	if m == nil {
+		return 0, 0, false
+	}
+	row, _ = strconv.Atoi(m[2])
+	return columnLetterToIndex(m[1]), row, true
+}
+
+// columnLetterToIndex converts a column letter sequence ("A", "Z",
+// "AA", ...) to a 1-indexed column number - the inverse of
+// columnToLetter.
+func columnLetterToIndex(letters string) int {
+	col := 0
+	for _, r := range strings.ToUpper(letters) {
+		col = col*26 + int(r-'A'+1)
+	}
+	return col
+}
+
+// mergeSpan records a merged cell range's extent, keyed by its
+// top-left (origin) cell.
+type mergeSpan struct {
+	colSpan, rowSpan int
+}
+
+// mergeCellRe matches xlsx's <mergeCell ref="A1:B2"/> elements. Merged
+// ranges are rare enough, and their XML trivial enough, that scanning
+// the sheet's raw bytes with a regexp is simpler than a second
+// xml.Decoder pass over the same file - the streaming row pass above
+// only cares about <sheetData>, and never needs to hold the full sheet
+// in a parsed DOM to find them.
+var mergeCellRe = regexp.MustCompile(`<mergeCell ref="([^"]+)"`)
+
+// parseMergeCells returns the merged ranges in sheetXML as an origin ->
+// span map, and the set of cells covered by a merge but not its
+// origin (which must be skipped, not rendered as their own <td>).
+func parseMergeCells(sheetXML []byte) (origins map[[2]int]mergeSpan, covered map[[2]int]bool) {
+	origins = map[[2]int]mergeSpan{}
+	covered = map[[2]int]bool{}
+
+	for _, m := range mergeCellRe.FindAllSubmatch(sheetXML, -1) {
+		parts := strings.SplitN(string(m[1]), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		startCol, startRow, ok1 := parseCellRef(parts[0])
+		endCol, endRow, ok2 := parseCellRef(parts[1])
+		if !ok1 || !ok2 {
+			continue
+		}
+		origins[[2]int{startRow, startCol}] = mergeSpan{
+			colSpan: endCol - startCol + 1,
+			rowSpan: endRow - startRow + 1,
+		}
+		for r := startRow; r <= endRow; r++ {
+			for cc := startCol; cc <= endCol; cc++ {
+				if r == startRow && cc == startCol {
+					continue
+				}
+				covered[[2]int{r, cc}] = true
+			}
+		}
+	}
+	return origins, covered
+}
+
+// dimensionRe matches xlsx's <dimension ref="A1:D10"/>, the sheet's
+// declared extent. Reading it upfront gives the table's column count
+// before any row has been streamed, instead of buffering every row to
+// find the widest one.
+var dimensionRe = regexp.MustCompile(`<dimension ref="[^"]*:([A-Za-z]+)\d+"`)
+
+// sheetMaxCols returns sheetXML's declared column count from its
+// <dimension> element, or 0 if absent (single-cell and some
+// hand-written sheets omit it).
+func sheetMaxCols(sheetXML []byte) int {
+	m := dimensionRe.FindSubmatch(sheetXML)
+	if m == nil {
+		return 0
+	}
+	return columnLetterToIndex(string(m[1]))
+}
@@ -0,0 +1,265 @@
+package formats
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// xlsxBuilder accumulates sheets for FromMarkdown and serializes them
+// into a minimal but valid OOXML .xlsx package: a bold, frozen header
+// row, autosized columns, and per-cell numeric/date type inference, all
+// without a shared-string table - every string cell is written inline
+// (t="inlineStr"), which keeps the writer side simple at the cost of
+// some file size on highly repetitive sheets.
+type xlsxBuilder struct {
+	sheets []xlsxSheet
+}
+
+type xlsxSheet struct {
+	name string
+	rows [][]string
+}
+
+func newExcelFile() *xlsxBuilder {
+	return &xlsxBuilder{}
+}
+
+func (b *xlsxBuilder) GetSheetList() []string {
+	names := make([]string, len(b.sheets))
+	for i, s := range b.sheets {
+		names[i] = s.name
+	}
+	return names
+}
+
+// SetSheetName renames the first sheet added, or does nothing if old
+// doesn't match any sheet yet - NewSheet is always called before
+// SetCellValue in ExcelConverter.FromMarkdown, so the common case is
+// renaming the implicit first sheet before any cells are written.
+func (b *xlsxBuilder) SetSheetName(old, new string) error {
+	for i, s := range b.sheets {
+		if s.name == old {
+			b.sheets[i].name = new
+			return nil
+		}
+	}
+	if len(b.sheets) == 0 && old == "Sheet1" {
+		b.sheets = append(b.sheets, xlsxSheet{name: new})
+		return nil
+	}
+	return fmt.Errorf("xlsx: sheet %q not found", old)
+}
+
+func (b *xlsxBuilder) NewSheet(name string) (int, error) {
+	b.sheets = append(b.sheets, xlsxSheet{name: name})
+	return len(b.sheets), nil
+}
+
+func (b *xlsxBuilder) SetCellValue(sheet, cell string, value interface{}) error {
+	col, row, ok := parseCellRef(cell)
+	if !ok {
+		return fmt.Errorf("xlsx: invalid cell reference %q", cell)
+	}
+
+	idx := -1
+	for i, s := range b.sheets {
+		if s.name == sheet {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return fmt.Errorf("xlsx: sheet %q not found", sheet)
+	}
+
+	for len(b.sheets[idx].rows) < row {
+		b.sheets[idx].rows = append(b.sheets[idx].rows, nil)
+	}
+	r := &b.sheets[idx].rows[row-1]
+	for len(*r) < col {
+		*r = append(*r, "")
+	}
+	(*r)[col-1] = fmt.Sprintf("%v", value)
+	return nil
+}
+
+// Write serializes the accumulated sheets into a minimal .xlsx package.
+func (b *xlsxBuilder) Write(buf *bytes.Buffer) error {
+	zw := zip.NewWriter(buf)
+
+	files := map[string]string{
+		"[Content_Types].xml":        excelContentTypesXML(len(b.sheets)),
+		"_rels/.rels":                excelRootRelsXML,
+		"xl/_rels/workbook.xml.rels": excelWorkbookRelsXML(len(b.sheets)),
+		"xl/workbook.xml":            excelWorkbookXML(b.GetSheetList()),
+		"xl/styles.xml":              excelStylesXML,
+	}
+	for i, s := range b.sheets {
+		files[fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)] = s.worksheetXML()
+	}
+
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			return fmt.Errorf("xlsx: creating %s: %w", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			return fmt.Errorf("xlsx: writing %s: %w", name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("xlsx: closing package: %w", err)
+	}
+	return nil
+}
+
+// cellStyle indices into excelStylesXML's cellXfs, assigned positionally.
+const (
+	styleGeneral = iota
+	styleBoldHeader
+	styleDate
+)
+
+// worksheetXML renders one sheet's rows, inferring each cell's type
+// (number, date, or text) rather than writing everything as a string -
+// this is what lets FromMarkdown-produced workbooks sort and filter
+// numeric/date columns correctly when reopened in a spreadsheet
+// application. The header row (row 1) gets the bold style, columns are
+// autosized from their widest cell, and a frozen pane keeps the header
+// visible while scrolling.
+func (s *xlsxSheet) worksheetXML() string {
+	maxCols := 0
+	for _, row := range s.rows {
+		if len(row) > maxCols {
+			maxCols = len(row)
+		}
+	}
+	colWidths := make([]int, maxCols)
+
+	var sheetData strings.Builder
+	for rowIdx, row := range s.rows {
+		sheetData.WriteString(fmt.Sprintf(`<row r="%d">`, rowIdx+1))
+		for colIdx := 0; colIdx < maxCols; colIdx++ {
+			var text string
+			if colIdx < len(row) {
+				text = row[colIdx]
+			}
+			if len(text) > colWidths[colIdx] {
+				colWidths[colIdx] = len(text)
+			}
+
+			ref := columnToLetter(colIdx+1) + strconv.Itoa(rowIdx+1)
+			sheetData.WriteString(cellXML(ref, text, rowIdx == 0))
+		}
+		sheetData.WriteString("</row>")
+	}
+
+	var cols strings.Builder
+	if maxCols > 0 {
+		cols.WriteString("<cols>")
+		for i, width := range colWidths {
+			w := width + 2
+			if w < 8 {
+				w = 8
+			}
+			cols.WriteString(fmt.Sprintf(`<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, w))
+		}
+		cols.WriteString("</cols>")
+	}
+
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>` +
+		cols.String() +
+		`<sheetData>` + sheetData.String() + `</sheetData>` +
+		`</worksheet>`
+}
+
+// cellXML renders one cell, writing it as a number or date serial when
+// text parses as one and isHeader is false (header cells are always
+// text, styled bold), and otherwise as an inline string.
+func cellXML(ref, text string, isHeader bool) string {
+	if text == "" {
+		return ""
+	}
+	if isHeader {
+		return fmt.Sprintf(`<c r="%s" s="%d" t="inlineStr"><is><t>%s</t></is></c>`, ref, styleBoldHeader, escapeXMLText(text))
+	}
+	if n, err := strconv.ParseFloat(text, 64); err == nil {
+		return fmt.Sprintf(`<c r="%s" s="%d"><v>%s</v></c>`, ref, styleGeneral, strconv.FormatFloat(n, 'f', -1, 64))
+	}
+	if t, err := time.Parse("2006-01-02", text); err == nil {
+		serial := float64(t.Sub(excelEpoch).Hours() / 24)
+		return fmt.Sprintf(`<c r="%s" s="%d"><v>%s</v></c>`, ref, styleDate, strconv.FormatFloat(serial, 'f', -1, 64))
+	}
+	return fmt.Sprintf(`<c r="%s" s="%d" t="inlineStr"><is><t>%s</t></is></c>`, ref, styleGeneral, escapeXMLText(text))
+}
+
+func excelContentTypesXML(sheetCount int) string {
+	var overrides strings.Builder
+	for i := 1; i <= sheetCount; i++ {
+		overrides.WriteString(fmt.Sprintf(
+			`<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i))
+	}
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		overrides.String() +
+		`</Types>`
+}
+
+const excelRootRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+func excelWorkbookRelsXML(sheetCount int) string {
+	var rels strings.Builder
+	for i := 1; i <= sheetCount; i++ {
+		rels.WriteString(fmt.Sprintf(
+			`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i))
+	}
+	rels.WriteString(fmt.Sprintf(
+		`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheetCount+1))
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		rels.String() +
+		`</Relationships>`
+}
+
+func excelWorkbookXML(sheetNames []string) string {
+	var sheets strings.Builder
+	for i, name := range sheetNames {
+		sheets.WriteString(fmt.Sprintf(
+			`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXMLText(name), i+1, i+1))
+	}
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets>` + sheets.String() + `</sheets>` +
+		`</workbook>`
+}
+
+// excelStylesXML declares exactly the three cellXfs entries styleGeneral,
+// styleBoldHeader, and styleDate reference by index.
+const excelStylesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+	`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="1"><fill><patternFill patternType="none"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`</styleSheet>`
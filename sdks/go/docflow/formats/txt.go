@@ -10,6 +10,31 @@ import (
 type TXTConverter struct {
 	DetectStructure bool
 	LineBreakMode   string // "paragraph" or "preserve"
+	Config          Config
+}
+
+// Config toggles individual block detectors detectAndConvert applies
+// when TXTConverter.DetectStructure is true. Disabling a detector falls
+// that block type back to an ordinary paragraph line, so plaintext that
+// happens to resemble, say, a setext heading underline isn't misread
+// when the caller knows better.
+type Config struct {
+	DetectHeadings    bool // ALL-CAPS and setext (===/---) headings
+	DetectCode        bool // 4-space/tab indented code blocks
+	DetectBlockquotes bool // "> " prefixed lines
+	DetectLists       bool // bullet and numbered lists
+	DetectHR          bool // ---, ***, or ___ alone on a line
+}
+
+// DefaultConfig returns a Config with every detector enabled.
+func DefaultConfig() Config {
+	return Config{
+		DetectHeadings:    true,
+		DetectCode:        true,
+		DetectBlockquotes: true,
+		DetectLists:       true,
+		DetectHR:          true,
+	}
 }
 
 // TXTResult represents the result of a TXT conversion.
@@ -25,6 +50,7 @@ func NewTXTConverter() *TXTConverter {
 	return &TXTConverter{
 		DetectStructure: true,
 		LineBreakMode:   "paragraph",
+		Config:          DefaultConfig(),
 	}
 }
 
@@ -69,6 +95,18 @@ func (c *TXTConverter) ToMarkdown(textData []byte, filename string) TXTResult {
 	}
 }
 
+// fencedCodeRe matches a fenced code block with an optional language
+// tag, capturing the tag and the body separately.
+var fencedCodeRe = regexp.MustCompile("(?s)```([a-zA-Z0-9_+-]*)\\n(.*?)```")
+
+// codeBlockPlaceholder is substituted for each fenced code block while
+// the rest of FromMarkdown's regex passes run, so code content - which
+// often contains *, _, -, and | characters that look like Markdown
+// syntax - passes through those passes unmangled. It starts with a NUL
+// byte so it can never collide with real document text or be matched
+// by the header/emphasis/list/table regexes below.
+const codeBlockPlaceholder = "\x00TXTCODEBLOCK%d\x00"
+
 // FromMarkdown converts Markdown to plain text.
 func (c *TXTConverter) FromMarkdown(markdown string) TXTResult {
 	text := markdown
@@ -81,6 +119,19 @@ func (c *TXTConverter) FromMarkdown(markdown string) TXTResult {
 		}
 	}
 
+	// Extract fenced code blocks into placeholders before any other
+	// pass runs. This must happen first: once the backticks are gone,
+	// bold/italic/list regexes below would otherwise mangle code
+	// content, and there's no way to tell a restored code line apart
+	// from prose. The language tag has no plaintext equivalent and is
+	// dropped here.
+	var codeBlocks []string
+	text = fencedCodeRe.ReplaceAllStringFunc(text, func(block string) string {
+		matches := fencedCodeRe.FindStringSubmatch(block)
+		codeBlocks = append(codeBlocks, matches[2])
+		return fmt.Sprintf(codeBlockPlaceholder, len(codeBlocks)-1)
+	})
+
 	// Remove markdown formatting
 	// Headers
 	re := regexp.MustCompile(`(?m)^#{1,6}\s+`)
@@ -92,18 +143,22 @@ func (c *TXTConverter) FromMarkdown(markdown string) TXTResult {
 	text = regexp.MustCompile(`__(.+?)__`).ReplaceAllString(text, "$1")
 	text = regexp.MustCompile(`_(.+?)_`).ReplaceAllString(text, "$1")
 
-	// Code
+	// Inline code
 	text = regexp.MustCompile("`(.+?)`").ReplaceAllString(text, "$1")
-	text = regexp.MustCompile("(?s)```.*?```").ReplaceAllStringFunc(text, func(s string) string {
-		return strings.ReplaceAll(s, "```", "")
-	})
+
+	// Images. Must run before links below: the link regex's
+	// "[...]( ...)" pattern also matches the tail of image syntax, and
+	// doesn't exclude a preceding "!", so it would otherwise consume an
+	// image's brackets first and leave a stray "!" behind. Left as the
+	// bracketed "[Image: alt]" marker rather than bare alt text, so a
+	// later pass (or a reader) can tell it apart from the surrounding
+	// prose - in particular, it must never start with "-", "*", or "+",
+	// or the list pass below would mistake it for a bullet.
+	text = regexp.MustCompile(`!\[([^\]]*)\]\([^)]+\)`).ReplaceAllString(text, "[Image: $1]")
 
 	// Links
 	text = regexp.MustCompile(`\[([^\]]+)\]\([^)]+\)`).ReplaceAllString(text, "$1")
 
-	// Images
-	text = regexp.MustCompile(`!\[([^\]]*)\]\([^)]+\)`).ReplaceAllString(text, "[Image: $1]")
-
 	// Lists
 	text = regexp.MustCompile(`(?m)^[-*+]\s+`).ReplaceAllString(text, "• ")
 	text = regexp.MustCompile(`(?m)^\d+\.\s+`).ReplaceAllString(text, "")
@@ -129,6 +184,14 @@ func (c *TXTConverter) FromMarkdown(markdown string) TXTResult {
 	}
 	text = strings.Join(resultLines, "\n")
 
+	// Restore fenced code blocks last, indented 4 spaces so a later
+	// TXTConverter.ToMarkdown pass recognizes them as code again
+	// (isIndentedCodeLine), rather than as plain unindented prose.
+	for i, block := range codeBlocks {
+		placeholder := fmt.Sprintf(codeBlockPlaceholder, i)
+		text = strings.ReplaceAll(text, placeholder, indentCodeBlock(block))
+	}
+
 	// Clean up
 	text = regexp.MustCompile(`\n{3,}`).ReplaceAllString(text, "\n\n")
 	text = strings.TrimSpace(text)
@@ -139,48 +202,194 @@ func (c *TXTConverter) FromMarkdown(markdown string) TXTResult {
 	}
 }
 
+// indentCodeBlock indents every line of a fenced code block's body with
+// four spaces, matching the indented-code-block convention the rest of
+// this file reads and writes.
+func indentCodeBlock(body string) string {
+	body = strings.TrimSuffix(body, "\n")
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		lines[i] = "    " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+var (
+	hrLineRe          = regexp.MustCompile(`^(-{3,}|\*{3,}|_{3,})$`)
+	setextUnderlineRe = regexp.MustCompile(`^(={3,}|-{3,})$`)
+	orderedListRe     = regexp.MustCompile(`^(\d+)[.)]\s+(.+)$`)
+	bullets           = []string{"•", "●", "○", "▪", "▸"}
+)
+
+// detectAndConvert walks text one line at a time as a small state
+// machine, recognizing setext headings, ATX-style ALL-CAPS headings,
+// horizontal rules, indented code blocks, blockquotes, and bullet/
+// numbered lists, and emitting the corresponding Markdown for each.
+// Anything that doesn't match a detector falls through as an ordinary
+// paragraph line. Runs of consecutive blank lines collapse to one.
 func (c *TXTConverter) detectAndConvert(text string) string {
 	lines := strings.Split(text, "\n")
-	var result []string
+	var out []string
+	lastBlank := false
+
+	emit := func(s string) {
+		if s == "" {
+			if lastBlank {
+				return
+			}
+			lastBlank = true
+		} else {
+			lastBlank = false
+		}
+		out = append(out, s)
+	}
 
-	for _, line := range lines {
+	cfg := c.Config
+	i := 0
+	for i < len(lines) {
+		line := lines[i]
 		stripped := strings.TrimSpace(line)
 
 		if stripped == "" {
-			result = append(result, "")
+			emit("")
+			i++
 			continue
 		}
 
-		// Detect headers (ALL CAPS)
-		if stripped == strings.ToUpper(stripped) &&
-			len(stripped) > 3 &&
-			len(stripped) < 80 &&
-			!strings.HasPrefix(stripped, "•") &&
-			!strings.HasPrefix(stripped, "-") &&
-			len(strings.Fields(stripped)) >= 2 {
-			result = append(result, fmt.Sprintf("\n## %s\n", strings.Title(strings.ToLower(stripped))))
+		// Setext heading: this line followed by a matching ===/--- underline.
+		if cfg.DetectHeadings && i+1 < len(lines) {
+			underline := strings.TrimSpace(lines[i+1])
+			if setextUnderlineRe.MatchString(underline) {
+				level := "#"
+				if strings.HasPrefix(underline, "-") {
+					level = "##"
+				}
+				emit("")
+				emit(fmt.Sprintf("%s %s", level, stripped))
+				emit("")
+				i += 2
+				continue
+			}
+		}
+
+		// Horizontal rule, alone on its own line.
+		if cfg.DetectHR && hrLineRe.MatchString(stripped) {
+			emit("")
+			emit("---")
+			emit("")
+			i++
 			continue
 		}
 
-		// Detect bullet points
-		for _, bullet := range []string{"•", "●", "○", "▪", "▸"} {
-			if strings.HasPrefix(stripped, bullet) {
-				result = append(result, fmt.Sprintf("- %s", strings.TrimSpace(stripped[len(bullet):])))
-				continue
+		// Indented code block: consume the contiguous run of indented (and
+		// interleaved blank) lines, trimming any trailing blanks from it.
+		// This must run before the ALL-CAPS heading check below, since an
+		// indented line like "    C: HELLO" is code, not a title, despite
+		// also looking like a short upper-case heading once stripped.
+		if cfg.DetectCode && isIndentedCodeLine(line) {
+			start := i
+			for i < len(lines) && (isIndentedCodeLine(lines[i]) || strings.TrimSpace(lines[i]) == "") {
+				i++
+			}
+			end := i
+			for end > start && strings.TrimSpace(lines[end-1]) == "" {
+				end--
+			}
+			emit("")
+			emit("```")
+			for _, codeLine := range lines[start:end] {
+				out = append(out, dedentCodeLine(codeLine))
 			}
+			lastBlank = false
+			emit("```")
+			emit("")
+			i = end
+			continue
+		}
+
+		// ATX-style heading heuristic: a short, multi-word ALL-CAPS line.
+		if cfg.DetectHeadings && isAllCapsHeading(stripped) {
+			emit("")
+			emit(fmt.Sprintf("## %s", strings.Title(strings.ToLower(stripped))))
+			emit("")
+			i++
+			continue
 		}
 
-		// Detect numbered lists
-		re := regexp.MustCompile(`^(\d+)[.)]\s+(.+)$`)
-		if matches := re.FindStringSubmatch(stripped); matches != nil {
-			result = append(result, fmt.Sprintf("%s. %s", matches[1], matches[2]))
+		// Blockquote: consume the contiguous run of "> " prefixed lines.
+		if cfg.DetectBlockquotes && strings.HasPrefix(stripped, ">") {
+			emit("")
+			for i < len(lines) {
+				s := strings.TrimSpace(lines[i])
+				if !strings.HasPrefix(s, ">") {
+					break
+				}
+				out = append(out, "> "+strings.TrimSpace(strings.TrimPrefix(s, ">")))
+				lastBlank = false
+				i++
+			}
+			emit("")
 			continue
 		}
 
-		result = append(result, stripped)
+		if cfg.DetectLists {
+			if rest, ok := trimBullet(stripped); ok {
+				emit(fmt.Sprintf("- %s", rest))
+				i++
+				continue
+			}
+
+			if matches := orderedListRe.FindStringSubmatch(stripped); matches != nil {
+				emit(fmt.Sprintf("%s. %s", matches[1], matches[2]))
+				i++
+				continue
+			}
+		}
+
+		emit(stripped)
+		i++
 	}
 
-	return strings.Join(result, "\n")
+	return strings.Join(out, "\n")
+}
+
+// isAllCapsHeading reports whether stripped looks like a plaintext
+// section title: short, multi-word, and entirely upper case.
+func isAllCapsHeading(stripped string) bool {
+	return stripped == strings.ToUpper(stripped) &&
+		len(stripped) > 3 &&
+		len(stripped) < 80 &&
+		!strings.HasPrefix(stripped, "•") &&
+		!strings.HasPrefix(stripped, "-") &&
+		len(strings.Fields(stripped)) >= 2
+}
+
+// isIndentedCodeLine reports whether line is indented with 4+ spaces or
+// a leading tab, the same heuristic Markdown itself uses for indented
+// code blocks.
+func isIndentedCodeLine(line string) bool {
+	return strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")
+}
+
+// dedentCodeLine strips exactly one level of code-block indentation,
+// preserving any indentation beyond that.
+func dedentCodeLine(line string) string {
+	if strings.HasPrefix(line, "\t") {
+		return strings.TrimPrefix(line, "\t")
+	}
+	return strings.TrimPrefix(line, "    ")
+}
+
+// trimBullet reports whether stripped starts with one of the plaintext
+// bullet glyphs this package recognizes, returning the bullet's text
+// with the glyph and surrounding whitespace removed.
+func trimBullet(stripped string) (rest string, ok bool) {
+	for _, bullet := range bullets {
+		if strings.HasPrefix(stripped, bullet) {
+			return strings.TrimSpace(strings.TrimPrefix(stripped, bullet)), true
+		}
+	}
+	return "", false
 }
 
 func (c *TXTConverter) paragraphize(text string) string {
@@ -203,3 +412,131 @@ func isTableSeparator(s string) bool {
 	clean = strings.ReplaceAll(clean, ":", "")
 	return strings.TrimSpace(clean) == ""
 }
+
+// Lost* are the construct keys RoundTripReport.LostConstructs reports.
+// Each names a Markdown feature plain text has no representation for,
+// so losing it on a round trip is expected rather than a regression.
+const (
+	LostCodeFenceLang  = "code_fence_lang"
+	LostLinkURL        = "link_url"
+	LostImageURL       = "image_url"
+	LostTableAlignment = "table_alignment"
+)
+
+var (
+	linkRe           = regexp.MustCompile(`\[[^\]]+\]\([^)]+\)`)
+	imageRe          = regexp.MustCompile(`!\[[^\]]*\]\([^)]+\)`)
+	tableAlignmentRe = regexp.MustCompile(`(?m)^\s*\|?\s*:?-{1,}:?\s*\|`)
+	fencedCodeLangRe = regexp.MustCompile("```[a-zA-Z0-9_+-]+")
+)
+
+// RoundTripReport summarizes how faithfully a Markdown document
+// survived a FromMarkdown/ToMarkdown round trip.
+type RoundTripReport struct {
+	// Similarity is the whitespace-normalized Levenshtein similarity
+	// between the original Markdown and the Markdown reconstructed
+	// from its plain-text form, from 0 (nothing alike) to 1 (identical
+	// once runs of whitespace are collapsed).
+	Similarity float64
+	// LostConstructs lists the Lost* keys for Markdown features the
+	// input used that plain text has no way to carry through the trip.
+	LostConstructs []string
+}
+
+// roundTripHeaderRe matches the frontmatter block and derived title
+// ToMarkdown always prepends to its output. Both are metadata ToMarkdown
+// itself injects - the title comes from the filename, not the document -
+// rather than anything FromMarkdown could have preserved, so RoundTrip
+// strips them before scoring similarity instead of letting them swamp
+// the comparison on short documents.
+var roundTripHeaderRe = regexp.MustCompile(`(?s)^---\n.*?\n---\n\n#[^\n]*\n\n`)
+
+// RoundTrip converts md to plain text via FromMarkdown and back to
+// Markdown via ToMarkdown, then reports how much of the original
+// survived. TXTConverter's plain-text representation is deliberately
+// lossy - links, images, tables, and fenced code all degrade - so this
+// isn't a test for textual equality; it's a regression guard. A regex
+// ordering bug (see FromMarkdown's fenced-code-block handling) shows up
+// here as a sudden drop in Similarity or a LostConstructs entry that
+// wasn't expected for the input.
+func (c *TXTConverter) RoundTrip(md, filename string) RoundTripReport {
+	txt := c.FromMarkdown(md).Content
+	back := c.ToMarkdown([]byte(txt), filename).Content
+	back = roundTripHeaderRe.ReplaceAllString(back, "")
+
+	report := RoundTripReport{Similarity: normalizedSimilarity(md, back)}
+
+	if fencedCodeLangRe.MatchString(md) {
+		report.LostConstructs = append(report.LostConstructs, LostCodeFenceLang)
+	}
+	if linkRe.MatchString(md) {
+		report.LostConstructs = append(report.LostConstructs, LostLinkURL)
+	}
+	if imageRe.MatchString(md) {
+		report.LostConstructs = append(report.LostConstructs, LostImageURL)
+	}
+	if tableAlignmentRe.MatchString(md) {
+		report.LostConstructs = append(report.LostConstructs, LostTableAlignment)
+	}
+
+	return report
+}
+
+// normalizedSimilarity scores how alike a and b are after collapsing
+// all whitespace runs to single spaces, as
+// 1 - levenshtein(a, b)/max(len(a), len(b)).
+func normalizedSimilarity(a, b string) float64 {
+	na := strings.Join(strings.Fields(a), " ")
+	nb := strings.Join(strings.Fields(b), " ")
+	if na == nb {
+		return 1
+	}
+
+	maxLen := len([]rune(na))
+	if l := len([]rune(nb)); l > maxLen {
+		maxLen = l
+	}
+	if maxLen == 0 {
+		return 1
+	}
+
+	return 1 - float64(levenshtein(na, nb))/float64(maxLen)
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			del := prev[j] + 1
+			ins := curr[j-1] + 1
+			sub := prev[j-1] + cost
+			curr[j] = min3(del, ins, sub)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
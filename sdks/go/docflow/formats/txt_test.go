@@ -0,0 +1,195 @@
+package formats
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadTXTFixture reads a plaintext golden fixture from testdata/txt/.
+func loadTXTFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile("testdata/txt/" + name)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// TestTXTConverter_DetectAndConvert_RFCExcerpt exercises ALL-CAPS
+// headings, ordered lists, and indented code blocks against an
+// RFC-style plaintext excerpt.
+func TestTXTConverter_DetectAndConvert_RFCExcerpt(t *testing.T) {
+	conv := NewTXTConverter()
+	md := conv.detectAndConvert(loadTXTFixture(t, "rfc_excerpt.txt"))
+
+	assert.Contains(t, md, "## General Introduction")
+	assert.Contains(t, md, "## List Of Requirements")
+	assert.Contains(t, md, "1. Clients MUST send a greeting message.")
+	assert.Contains(t, md, "2. Servers MUST reply within five seconds.")
+	assert.Contains(t, md, "## Example Session")
+	assert.Contains(t, md, "```\nC: HELLO\nS: HELLO ACK\n```")
+	assert.Contains(t, md, "## End Of Document")
+
+	assert.Less(t, strings.Index(md, "## General Introduction"), strings.Index(md, "## List Of Requirements"))
+	assert.Less(t, strings.Index(md, "## List Of Requirements"), strings.Index(md, "## Example Session"))
+	assert.Less(t, strings.Index(md, "## Example Session"), strings.Index(md, "## End Of Document"))
+}
+
+// TestTXTConverter_DetectAndConvert_LogFile checks that ordinary
+// timestamped log lines pass through untouched while an indented stack
+// trace becomes a fenced code block.
+func TestTXTConverter_DetectAndConvert_LogFile(t *testing.T) {
+	conv := NewTXTConverter()
+	md := conv.detectAndConvert(loadTXTFixture(t, "log_file.txt"))
+
+	assert.Contains(t, md, "2024-01-15 10:22:01 INFO  Starting worker pool")
+	assert.Contains(t, md, "2024-01-15 10:22:05 ERROR Unhandled exception")
+	assert.Contains(t, md, "```\nat worker.process (worker.go:42)\nat worker.run (worker.go:18)\n```")
+	assert.Contains(t, md, "2024-01-15 10:22:06 INFO  Restarting worker")
+}
+
+// TestTXTConverter_DetectAndConvert_Readme checks setext headings,
+// bullet lists, and a standalone horizontal rule against a README-style
+// document.
+func TestTXTConverter_DetectAndConvert_Readme(t *testing.T) {
+	conv := NewTXTConverter()
+	md := conv.detectAndConvert(loadTXTFixture(t, "readme.txt"))
+
+	assert.Contains(t, md, "# Project Overview")
+	assert.Contains(t, md, "## Features")
+	assert.Contains(t, md, "- Detects headings")
+	assert.Contains(t, md, "- Detects lists")
+	assert.Contains(t, md, "- Detects code blocks")
+	assert.Contains(t, md, "---")
+	assert.Contains(t, md, "See the LICENSE file for details.")
+
+	assert.Less(t, strings.Index(md, "# Project Overview"), strings.Index(md, "## Features"))
+	assert.Less(t, strings.Index(md, "## Features"), strings.Index(md, "- Detects headings"))
+	assert.Less(t, strings.Index(md, "- Detects code blocks"), strings.Index(md, "See the LICENSE file"))
+}
+
+// TestTXTConverter_DetectAndConvert_Letter checks that quoted
+// correspondence becomes a blockquote while the surrounding paragraphs
+// are left alone.
+func TestTXTConverter_DetectAndConvert_Letter(t *testing.T) {
+	conv := NewTXTConverter()
+	md := conv.detectAndConvert(loadTXTFixture(t, "letter.txt"))
+
+	assert.Contains(t, md, "Dear Alex,")
+	assert.Contains(t, md, "> I was wondering if the shipment would arrive")
+	assert.Contains(t, md, "> before the end of the month.")
+	assert.Contains(t, md, "It will arrive by Friday at the latest.")
+	assert.Contains(t, md, "Best regards,")
+
+	assert.Less(t,
+		strings.Index(md, "> before the end of the month."),
+		strings.Index(md, "It will arrive by Friday at the latest."),
+	)
+}
+
+// TestTXTConverter_Config_DisablesDetectors checks that turning off a
+// detector in Config falls that block type back to a plain paragraph
+// line instead of being misread.
+func TestTXTConverter_Config_DisablesDetectors(t *testing.T) {
+	conv := NewTXTConverter()
+	conv.Config.DetectHeadings = false
+	conv.Config.DetectHR = false
+
+	md := conv.detectAndConvert("ALL CAPS TITLE\n\n---\n\nbody text\n")
+
+	assert.NotContains(t, md, "## All Caps Title")
+	assert.Contains(t, md, "ALL CAPS TITLE")
+	assert.Contains(t, md, "---")
+}
+
+// TestTXTConverter_FromMarkdown_PreservesCodeFenceContent locks in the
+// regex-pass ordering fix: fenced code content must survive untouched
+// even when it contains characters the emphasis/list passes would
+// otherwise mangle, and the language tag must not leak into the output
+// as a stray line.
+func TestTXTConverter_FromMarkdown_PreservesCodeFenceContent(t *testing.T) {
+	conv := NewTXTConverter()
+	md := "Intro with **foo_bar** emphasis.\n\n```go\nfunc main() {\n\t// -1 * value, not a list or emphasis\n\tx := -1 * value\n}\n```\n\nOutro.\n"
+
+	result := conv.FromMarkdown(md)
+	require.True(t, result.Success)
+
+	assert.Contains(t, result.Content, "foo_bar")
+	assert.Contains(t, result.Content, "x := -1 * value")
+	assert.NotContains(t, result.Content, "go\n")
+	assert.NotContains(t, result.Content, "```")
+}
+
+// TestTXTConverter_FromMarkdown_ImageAltNotTreatedAsBullet checks that
+// an image whose alt text itself looks list-like doesn't get
+// re-interpreted as a bullet by the list pass that runs after images.
+func TestTXTConverter_FromMarkdown_ImageAltNotTreatedAsBullet(t *testing.T) {
+	conv := NewTXTConverter()
+	result := conv.FromMarkdown("![- icon](icon.png)\n")
+
+	assert.Contains(t, result.Content, "[Image: - icon]")
+	assert.NotContains(t, result.Content, "• ")
+}
+
+// TestTXTConverter_RoundTrip is a fuzz-style corpus covering the
+// Markdown constructs TXTConverter's plain-text form can't fully carry,
+// asserting a minimum Similarity per case and the LostConstructs that
+// case is expected to report.
+func TestTXTConverter_RoundTrip(t *testing.T) {
+	cases := []struct {
+		name          string
+		markdown      string
+		minSimilarity float64
+		wantLost      []string
+		wantNotLost   []string
+	}{
+		{
+			name:          "plain paragraphs",
+			markdown:      "# Title\n\nA plain paragraph with no special constructs.\n",
+			minSimilarity: 0.9,
+			wantNotLost:   []string{LostLinkURL, LostImageURL, LostCodeFenceLang, LostTableAlignment},
+		},
+		{
+			name:          "link",
+			markdown:      "# Title\n\nSee [the docs](https://example.com/docs) for more.\n",
+			minSimilarity: 0.45,
+			wantLost:      []string{LostLinkURL},
+		},
+		{
+			name:          "image",
+			markdown:      "# Title\n\n![a diagram](diagram.png)\n",
+			minSimilarity: 0.3,
+			wantLost:      []string{LostImageURL},
+		},
+		{
+			name:          "fenced code with language",
+			markdown:      "# Title\n\n```go\nfmt.Println(\"hi\")\n```\n",
+			minSimilarity: 0.5,
+			wantLost:      []string{LostCodeFenceLang},
+		},
+		{
+			name:          "table with alignment",
+			markdown:      "# Title\n\n| A | B |\n|---|---|\n| 1 | 2 |\n",
+			minSimilarity: 0.3,
+			wantLost:      []string{LostTableAlignment},
+		},
+	}
+
+	conv := NewTXTConverter()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			report := conv.RoundTrip(tc.markdown, "doc.txt")
+
+			assert.GreaterOrEqual(t, report.Similarity, tc.minSimilarity,
+				"round trip similarity dropped below threshold")
+			for _, lost := range tc.wantLost {
+				assert.Contains(t, report.LostConstructs, lost)
+			}
+			for _, notLost := range tc.wantNotLost {
+				assert.NotContains(t, report.LostConstructs, notLost)
+			}
+		})
+	}
+}
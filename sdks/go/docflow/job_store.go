@@ -0,0 +1,307 @@
+package docflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+)
+
+// StoredJob is the durable record a JobStore keeps for one BatchProcessor
+// job, independent of the in-memory *BatchJob a caller polls via
+// GetStatus. Checkpoint is the index of the first file in Files that
+// hasn't been processed yet, so a job interrupted mid-batch resumes
+// from Files[Checkpoint:] instead of restarting from scratch.
+type StoredJob struct {
+	JobID      string
+	Files      []string
+	Checkpoint int
+	Attempts   int
+	LastError  string
+	Status     config.JobStatus
+}
+
+// JobStore persists BatchProcessor jobs so a process restart can
+// rehydrate and resume anything that was pending or still processing,
+// rather than silently losing it. Implementations must be safe for
+// concurrent use.
+//
+// This package ships two: MemoryJobStore (the default - no durability,
+// equivalent to BatchProcessor's old bare sync.Map) and FileJobStore,
+// which writes one JSON file per job plus a ".ptr" checkpoint sidecar,
+// in the same local-disk-durability spirit as this SDK's
+// jobs.SQLiteQueue. Distributed backends (Redis lists, a Postgres table
+// claimed via SELECT ... FOR UPDATE SKIP LOCKED) fit the same interface
+// but aren't implemented here - they need a live shared service this
+// tree doesn't stand up, so multi-process coordination is out of scope
+// for this change. A single process using FileJobStore gets full
+// crash-recovery; running several processes against one FileJobStore
+// directory is not safe.
+type JobStore interface {
+	// Enqueue persists a new job and its file list as Pending.
+	Enqueue(jobID string, files []string) error
+
+	// Checkpoint records that files[:upto] have been processed, so a
+	// crash resumes from files[upto:].
+	Checkpoint(jobID string, upto int) error
+
+	// Ack marks a job permanently done and eligible for cleanup.
+	Ack(jobID string) error
+
+	// Nack records a failed attempt. Once Attempts reaches maxAttempts
+	// the job becomes a dead letter instead of being retried.
+	Nack(jobID string, errMsg string, maxAttempts int) error
+
+	// LoadPending returns every job that hasn't been Ack'd or
+	// dead-lettered, for startup rehydration.
+	LoadPending() ([]*StoredJob, error)
+
+	// DeadLetters returns jobs that exhausted their attempts.
+	DeadLetters() ([]*StoredJob, error)
+}
+
+// MemoryJobStore is the default JobStore: an in-process map with no
+// on-disk durability, matching BatchProcessor's behavior before
+// JobStore existed. Jobs are lost on restart, same as today.
+type MemoryJobStore struct {
+	mu          sync.Mutex
+	jobs        map[string]*StoredJob
+	deadLetters map[string]*StoredJob
+}
+
+// NewMemoryJobStore creates an empty MemoryJobStore.
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{
+		jobs:        make(map[string]*StoredJob),
+		deadLetters: make(map[string]*StoredJob),
+	}
+}
+
+func (s *MemoryJobStore) Enqueue(jobID string, files []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[jobID] = &StoredJob{JobID: jobID, Files: files, Status: config.JobStatusPending}
+	return nil
+}
+
+func (s *MemoryJobStore) Checkpoint(jobID string, upto int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[jobID]
+	if !ok {
+		return fmt.Errorf("jobstore: job %s not found", jobID)
+	}
+	j.Checkpoint = upto
+	j.Status = config.JobStatusProcessing
+	return nil
+}
+
+func (s *MemoryJobStore) Ack(jobID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, jobID)
+	return nil
+}
+
+func (s *MemoryJobStore) Nack(jobID string, errMsg string, maxAttempts int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[jobID]
+	if !ok {
+		return fmt.Errorf("jobstore: job %s not found", jobID)
+	}
+	j.Attempts++
+	j.LastError = errMsg
+	if j.Attempts >= maxAttempts {
+		j.Status = config.JobStatusFailed
+		s.deadLetters[jobID] = j
+		delete(s.jobs, jobID)
+		return nil
+	}
+	j.Status = config.JobStatusPending
+	return nil
+}
+
+func (s *MemoryJobStore) LoadPending() ([]*StoredJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]*StoredJob, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		cp := *j
+		out = append(out, &cp)
+	}
+	return out, nil
+}
+
+func (s *MemoryJobStore) DeadLetters() ([]*StoredJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]*StoredJob, 0, len(s.deadLetters))
+	for _, j := range s.deadLetters {
+		cp := *j
+		out = append(out, &cp)
+	}
+	return out, nil
+}
+
+// FileJobStore persists jobs to one JSON file per job ID under dir,
+// plus a "<jobID>.ptr" sidecar holding just the checkpoint index - the
+// same file-per-stream-plus-offset-pointer layout log-tailer tools
+// (e.g. filebeat's registry) use, so the checkpoint can be updated with
+// a single small write instead of rewriting the whole job record on
+// every processed file.
+type FileJobStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileJobStore creates a FileJobStore rooted at dir, creating it if
+// needed.
+func NewFileJobStore(dir string) (*FileJobStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("jobstore: create directory: %w", err)
+	}
+	return &FileJobStore{dir: dir}, nil
+}
+
+func (s *FileJobStore) jobPath(jobID string) string { return filepath.Join(s.dir, jobID+".json") }
+func (s *FileJobStore) ptrPath(jobID string) string { return filepath.Join(s.dir, jobID+".ptr") }
+func (s *FileJobStore) deadPath(jobID string) string {
+	return filepath.Join(s.dir, jobID+".dead.json")
+}
+
+func (s *FileJobStore) writeJob(j *StoredJob) error {
+	data, err := json.Marshal(j)
+	if err != nil {
+		return fmt.Errorf("jobstore: encode job: %w", err)
+	}
+	return os.WriteFile(s.jobPath(j.JobID), data, 0644)
+}
+
+func (s *FileJobStore) readJob(jobID string) (*StoredJob, error) {
+	data, err := os.ReadFile(s.jobPath(jobID))
+	if err != nil {
+		return nil, fmt.Errorf("jobstore: job %s not found: %w", jobID, err)
+	}
+	var j StoredJob
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("jobstore: decode job: %w", err)
+	}
+	if ptr, err := os.ReadFile(s.ptrPath(jobID)); err == nil {
+		if n, err := strconv.Atoi(string(ptr)); err == nil {
+			j.Checkpoint = n
+		}
+	}
+	return &j, nil
+}
+
+func (s *FileJobStore) Enqueue(jobID string, files []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.writeJob(&StoredJob{JobID: jobID, Files: files, Status: config.JobStatusPending})
+}
+
+func (s *FileJobStore) Checkpoint(jobID string, upto int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := os.Stat(s.jobPath(jobID)); err != nil {
+		return fmt.Errorf("jobstore: job %s not found", jobID)
+	}
+	return os.WriteFile(s.ptrPath(jobID), []byte(strconv.Itoa(upto)), 0644)
+}
+
+func (s *FileJobStore) Ack(jobID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	os.Remove(s.ptrPath(jobID))
+	if err := os.Remove(s.jobPath(jobID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("jobstore: ack job %s: %w", jobID, err)
+	}
+	return nil
+}
+
+func (s *FileJobStore) Nack(jobID string, errMsg string, maxAttempts int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, err := s.readJob(jobID)
+	if err != nil {
+		return err
+	}
+	j.Attempts++
+	j.LastError = errMsg
+
+	if j.Attempts >= maxAttempts {
+		j.Status = config.JobStatusFailed
+		data, err := json.Marshal(j)
+		if err != nil {
+			return fmt.Errorf("jobstore: encode dead letter: %w", err)
+		}
+		if err := os.WriteFile(s.deadPath(jobID), data, 0644); err != nil {
+			return fmt.Errorf("jobstore: write dead letter: %w", err)
+		}
+		os.Remove(s.ptrPath(jobID))
+		return os.Remove(s.jobPath(jobID))
+	}
+
+	j.Status = config.JobStatusPending
+	return s.writeJob(j)
+}
+
+func (s *FileJobStore) LoadPending() ([]*StoredJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("jobstore: list directory: %w", err)
+	}
+
+	var out []*StoredJob
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".dead.json") {
+			continue
+		}
+		jobID := strings.TrimSuffix(name, ".json")
+		j, err := s.readJob(jobID)
+		if err != nil {
+			continue
+		}
+		out = append(out, j)
+	}
+	return out, nil
+}
+
+func (s *FileJobStore) DeadLetters() ([]*StoredJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("jobstore: list directory: %w", err)
+	}
+
+	var out []*StoredJob
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".dead.json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			continue
+		}
+		var j StoredJob
+		if err := json.Unmarshal(data, &j); err != nil {
+			continue
+		}
+		out = append(out, &j)
+	}
+	return out, nil
+}
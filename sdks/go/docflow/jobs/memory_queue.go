@@ -0,0 +1,125 @@
+package jobs
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+)
+
+// MemoryQueue is an in-process Queue backed by a map. Records do not
+// survive a restart; use SQLiteQueue for durability.
+type MemoryQueue struct {
+	mu      sync.Mutex
+	records map[string]*Record
+}
+
+// NewMemoryQueue creates an empty in-memory queue.
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{records: make(map[string]*Record)}
+}
+
+// Enqueue creates a pending Record for filename and returns its ID.
+func (q *MemoryQueue) Enqueue(filename string) (string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	id := uuid.New().String()
+	q.records[id] = &Record{
+		ID:        id,
+		Filename:  filename,
+		Status:    config.JobStatusPending,
+		CreatedAt: time.Now(),
+	}
+	return id, nil
+}
+
+// Claim atomically picks the oldest pending Record, marks it
+// processing, and returns it.
+func (q *MemoryQueue) Claim() (*Record, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var oldest *Record
+	for _, r := range q.records {
+		if r.Status != config.JobStatusPending {
+			continue
+		}
+		if oldest == nil || r.CreatedAt.Before(oldest.CreatedAt) {
+			oldest = r
+		}
+	}
+	if oldest == nil {
+		return nil, nil
+	}
+
+	now := time.Now()
+	oldest.Status = config.JobStatusProcessing
+	oldest.StartedAt = &now
+	oldest.Attempts++
+
+	copyRecord := *oldest
+	return &copyRecord, nil
+}
+
+// Update persists changes to an existing Record.
+func (q *MemoryQueue) Update(r *Record) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.records[r.ID]; !ok {
+		return fmt.Errorf("jobs: record %s not found", r.ID)
+	}
+	copyRecord := *r
+	q.records[r.ID] = &copyRecord
+	return nil
+}
+
+// Get returns a Record by ID.
+func (q *MemoryQueue) Get(id string) (*Record, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	r, ok := q.records[id]
+	if !ok {
+		return nil, fmt.Errorf("jobs: record %s not found", id)
+	}
+	copyRecord := *r
+	return &copyRecord, nil
+}
+
+// List returns Records matching filter, oldest first.
+func (q *MemoryQueue) List(filter Filter) ([]*Record, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var out []*Record
+	for _, r := range q.records {
+		if filter.Status != "" && r.Status != filter.Status {
+			continue
+		}
+		copyRecord := *r
+		out = append(out, &copyRecord)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+// Cancel marks a pending or processing Record as failed.
+func (q *MemoryQueue) Cancel(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	r, ok := q.records[id]
+	if !ok {
+		return fmt.Errorf("jobs: record %s not found", id)
+	}
+	now := time.Now()
+	r.Status = config.JobStatusFailed
+	r.LastError = "cancelled"
+	r.FinishedAt = &now
+	return nil
+}
@@ -0,0 +1,52 @@
+// Package jobs provides a persistent background job queue for driving
+// the converter and RAG pipelines outside of a synchronous request.
+package jobs
+
+import (
+	"time"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+)
+
+// Record is a single queued unit of work.
+type Record struct {
+	ID         string
+	Filename   string
+	Status     config.JobStatus
+	Attempts   int
+	LastError  string
+	CreatedAt  time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	ResultPath string
+}
+
+// Filter narrows a List call. Zero values match anything.
+type Filter struct {
+	Status config.JobStatus
+}
+
+// Queue stores and hands out Records. Implementations must be safe for
+// concurrent use by multiple Runner workers.
+type Queue interface {
+	// Enqueue creates a pending Record for filename and returns its ID.
+	Enqueue(filename string) (string, error)
+
+	// Claim atomically picks the oldest pending Record, marks it
+	// processing, and returns it. It returns (nil, nil) if none are
+	// pending.
+	Claim() (*Record, error)
+
+	// Update persists changes to an existing Record.
+	Update(r *Record) error
+
+	// Get returns a Record by ID.
+	Get(id string) (*Record, error)
+
+	// List returns Records matching filter.
+	List(filter Filter) ([]*Record, error)
+
+	// Cancel marks a pending or processing Record as failed with a
+	// "cancelled" error, preventing further retries.
+	Cancel(id string) error
+}
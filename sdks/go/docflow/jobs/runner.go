@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+)
+
+// ProcessFunc runs the converter/RAG pipeline for a single queued file
+// and returns the path to its result.
+type ProcessFunc func(ctx context.Context, filename string) (resultPath string, err error)
+
+// Runner drives a Queue with a fixed pool of workers.
+type Runner struct {
+	queue   Queue
+	process ProcessFunc
+
+	maxWorkers     int
+	maxRetries     int
+	timeoutPerFile time.Duration
+	pollInterval   time.Duration
+}
+
+// NewRunner creates a Runner that drains queue using process, sized and
+// retried per batchConfig.
+func NewRunner(queue Queue, batchConfig config.BatchConfig, process ProcessFunc) *Runner {
+	maxWorkers := batchConfig.MaxWorkers
+	if maxWorkers <= 0 {
+		maxWorkers = 4
+	}
+	maxRetries := batchConfig.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = 1
+	}
+	timeout := time.Duration(batchConfig.TimeoutPerFile) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+
+	return &Runner{
+		queue:          queue,
+		process:        process,
+		maxWorkers:     maxWorkers,
+		maxRetries:     maxRetries,
+		timeoutPerFile: timeout,
+		pollInterval:   250 * time.Millisecond,
+	}
+}
+
+// Submit enqueues path for processing and returns its job ID.
+func (r *Runner) Submit(path string) (string, error) {
+	return r.queue.Enqueue(path)
+}
+
+// Get returns the current state of a job.
+func (r *Runner) Get(jobID string) (*Record, error) {
+	return r.queue.Get(jobID)
+}
+
+// List returns jobs matching filter.
+func (r *Runner) List(filter Filter) ([]*Record, error) {
+	return r.queue.List(filter)
+}
+
+// Cancel stops a pending or in-flight job from being retried further.
+func (r *Runner) Cancel(jobID string) error {
+	return r.queue.Cancel(jobID)
+}
+
+// Loop starts maxWorkers goroutines that poll the queue until ctx is
+// cancelled. It blocks until all workers have returned, so callers
+// typically invoke it in its own goroutine at server startup.
+func (r *Runner) Loop(ctx context.Context) {
+	done := make(chan struct{})
+	for i := 0; i < r.maxWorkers; i++ {
+		go func() {
+			r.worker(ctx)
+			done <- struct{}{}
+		}()
+	}
+	for i := 0; i < r.maxWorkers; i++ {
+		<-done
+	}
+}
+
+func (r *Runner) worker(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.runOne(ctx)
+		}
+	}
+}
+
+func (r *Runner) runOne(ctx context.Context) {
+	record, err := r.queue.Claim()
+	if err != nil || record == nil {
+		return
+	}
+
+	fileCtx, cancel := context.WithTimeout(ctx, r.timeoutPerFile)
+	defer cancel()
+
+	resultPath, err := r.process(fileCtx, record.Filename)
+	now := time.Now()
+
+	if err != nil {
+		record.LastError = err.Error()
+		if record.Attempts >= r.maxRetries {
+			record.Status = config.JobStatusFailed
+			record.FinishedAt = &now
+			r.queue.Update(record)
+			return
+		}
+
+		// Exponential backoff before the job becomes claimable again.
+		record.Status = config.JobStatusPending
+		r.queue.Update(record)
+		time.Sleep(backoff(record.Attempts))
+		return
+	}
+
+	record.Status = config.JobStatusCompleted
+	record.ResultPath = resultPath
+	record.FinishedAt = &now
+	r.queue.Update(record)
+}
+
+// backoff returns an exponential delay (capped at 30s) for the given
+// attempt count.
+func backoff(attempt int) time.Duration {
+	d := time.Duration(math.Pow(2, float64(attempt))) * time.Second
+	if d > 30*time.Second {
+		return 30 * time.Second
+	}
+	return d
+}
@@ -0,0 +1,173 @@
+package jobs
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3" // SQLite driver
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+)
+
+// SQLiteQueue is a Queue backed by a SQLite database, so jobs survive a
+// process restart.
+type SQLiteQueue struct {
+	db *sql.DB
+}
+
+// NewSQLiteQueue opens (creating if necessary) a SQLite-backed queue at
+// path.
+func NewSQLiteQueue(path string) (*SQLiteQueue, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("jobs: open sqlite: %w", err)
+	}
+
+	q := &SQLiteQueue{db: db}
+	if err := q.ensureSchema(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (q *SQLiteQueue) ensureSchema() error {
+	_, err := q.db.Exec(`
+		CREATE TABLE IF NOT EXISTS jobs (
+			id TEXT PRIMARY KEY,
+			filename TEXT NOT NULL,
+			status TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			result_path TEXT,
+			created_at DATETIME NOT NULL,
+			started_at DATETIME,
+			finished_at DATETIME
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("jobs: create schema: %w", err)
+	}
+	return nil
+}
+
+// Enqueue creates a pending Record for filename and returns its ID.
+func (q *SQLiteQueue) Enqueue(filename string) (string, error) {
+	id := uuid.New().String()
+	_, err := q.db.Exec(
+		`INSERT INTO jobs (id, filename, status, attempts, created_at) VALUES (?, ?, ?, 0, ?)`,
+		id, filename, config.JobStatusPending, time.Now(),
+	)
+	if err != nil {
+		return "", fmt.Errorf("jobs: enqueue: %w", err)
+	}
+	return id, nil
+}
+
+// Claim atomically picks the oldest pending Record, marks it
+// processing, and returns it.
+func (q *SQLiteQueue) Claim() (*Record, error) {
+	tx, err := q.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("jobs: begin claim: %w", err)
+	}
+	defer tx.Rollback()
+
+	var r Record
+	row := tx.QueryRow(`SELECT id, filename, status, attempts, created_at FROM jobs
+		WHERE status = ? ORDER BY created_at ASC LIMIT 1`, config.JobStatusPending)
+
+	if err := row.Scan(&r.ID, &r.Filename, &r.Status, &r.Attempts, &r.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("jobs: claim scan: %w", err)
+	}
+
+	now := time.Now()
+	r.Status = config.JobStatusProcessing
+	r.StartedAt = &now
+	r.Attempts++
+
+	if _, err := tx.Exec(`UPDATE jobs SET status = ?, attempts = ?, started_at = ? WHERE id = ?`,
+		r.Status, r.Attempts, now, r.ID); err != nil {
+		return nil, fmt.Errorf("jobs: claim update: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("jobs: commit claim: %w", err)
+	}
+	return &r, nil
+}
+
+// Update persists changes to an existing Record.
+func (q *SQLiteQueue) Update(r *Record) error {
+	_, err := q.db.Exec(`UPDATE jobs SET status = ?, attempts = ?, last_error = ?,
+		result_path = ?, started_at = ?, finished_at = ? WHERE id = ?`,
+		r.Status, r.Attempts, r.LastError, r.ResultPath, r.StartedAt, r.FinishedAt, r.ID)
+	if err != nil {
+		return fmt.Errorf("jobs: update: %w", err)
+	}
+	return nil
+}
+
+// Get returns a Record by ID.
+func (q *SQLiteQueue) Get(id string) (*Record, error) {
+	var r Record
+	row := q.db.QueryRow(`SELECT id, filename, status, attempts, last_error,
+		result_path, created_at, started_at, finished_at FROM jobs WHERE id = ?`, id)
+
+	if err := row.Scan(&r.ID, &r.Filename, &r.Status, &r.Attempts, &r.LastError,
+		&r.ResultPath, &r.CreatedAt, &r.StartedAt, &r.FinishedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("jobs: record %s not found", id)
+		}
+		return nil, fmt.Errorf("jobs: get: %w", err)
+	}
+	return &r, nil
+}
+
+// List returns Records matching filter, oldest first.
+func (q *SQLiteQueue) List(filter Filter) ([]*Record, error) {
+	query := `SELECT id, filename, status, attempts, last_error, result_path,
+		created_at, started_at, finished_at FROM jobs`
+	args := []interface{}{}
+	if filter.Status != "" {
+		query += " WHERE status = ?"
+		args = append(args, filter.Status)
+	}
+	query += " ORDER BY created_at ASC"
+
+	rows, err := q.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("jobs: list: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*Record
+	for rows.Next() {
+		var r Record
+		if err := rows.Scan(&r.ID, &r.Filename, &r.Status, &r.Attempts, &r.LastError,
+			&r.ResultPath, &r.CreatedAt, &r.StartedAt, &r.FinishedAt); err != nil {
+			return nil, fmt.Errorf("jobs: list scan: %w", err)
+		}
+		out = append(out, &r)
+	}
+	return out, nil
+}
+
+// Cancel marks a pending or processing Record as failed.
+func (q *SQLiteQueue) Cancel(id string) error {
+	now := time.Now()
+	_, err := q.db.Exec(`UPDATE jobs SET status = ?, last_error = ?, finished_at = ? WHERE id = ?`,
+		config.JobStatusFailed, "cancelled", now, id)
+	if err != nil {
+		return fmt.Errorf("jobs: cancel: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying database handle.
+func (q *SQLiteQueue) Close() error {
+	return q.db.Close()
+}
@@ -0,0 +1,34 @@
+package docflow
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/xgaslan/docflow/sdks/go/docflow/docmodel"
+)
+
+// extractLayoutWords reads pdfData's content streams via pdfcpu (same
+// approach as headingsByFontSize) and decodes each page into positioned,
+// sized docmodel.Word values via decodeContentStreamLayout. The bool
+// return is false when the PDF couldn't be parsed or no words were
+// recovered, signaling the caller to fall back to the flat-text
+// heuristic in textToMarkdown.
+func extractLayoutWords(pdfData []byte) ([]docmodel.Word, bool) {
+	var words []docmodel.Word
+	digest := func(r io.Reader, pageNr int) error {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		words = append(words, decodeContentStreamLayout(content, pageNr)...)
+		return nil
+	}
+	if err := api.ExtractContent(bytes.NewReader(pdfData), nil, digest, nil); err != nil {
+		return nil, false
+	}
+	if len(words) == 0 {
+		return nil, false
+	}
+	return words, true
+}
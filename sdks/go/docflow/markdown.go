@@ -4,6 +4,8 @@ package docflow
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"sort"
 	"strings"
 
@@ -11,15 +13,30 @@ import (
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer/html"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/cache/memcache"
 )
 
 // MarkdownParser handles markdown processing.
 type MarkdownParser struct {
-	md goldmark.Markdown
+	md    goldmark.Markdown
+	cache *memcache.Cache
+}
+
+// MarkdownParserOption is a function that configures a MarkdownParser.
+type MarkdownParserOption func(*MarkdownParser)
+
+// WithParserCache sets a shared memcache.Cache used to memoize ToHTML,
+// keyed by the content's hash. A cache hit skips the goldmark render
+// entirely.
+func WithParserCache(c *memcache.Cache) MarkdownParserOption {
+	return func(p *MarkdownParser) {
+		p.cache = c
+	}
 }
 
 // NewMarkdownParser creates a new markdown parser with sensible defaults.
-func NewMarkdownParser() *MarkdownParser {
+func NewMarkdownParser(opts ...MarkdownParserOption) *MarkdownParser {
 	md := goldmark.New(
 		goldmark.WithExtensions(
 			extension.GFM,
@@ -39,11 +56,39 @@ func NewMarkdownParser() *MarkdownParser {
 		),
 	)
 
-	return &MarkdownParser{md: md}
+	p := &MarkdownParser{md: md}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
 }
 
-// ToHTML converts markdown content to HTML.
+// ToHTML converts markdown content to HTML, memoizing on content hash
+// when a cache is configured via WithParserCache.
 func (p *MarkdownParser) ToHTML(content string) (string, error) {
+	if p.cache == nil {
+		return p.renderHTML(content)
+	}
+
+	sum := sha256.Sum256([]byte(content))
+	key := "html:" + hex.EncodeToString(sum[:])
+
+	value, err := p.cache.GetOrCreate(key, func() (any, int64, error) {
+		html, err := p.renderHTML(content)
+		if err != nil {
+			return nil, 0, err
+		}
+		return html, int64(len(html)), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return value.(string), nil
+}
+
+// renderHTML runs the underlying goldmark conversion, uncached.
+func (p *MarkdownParser) renderHTML(content string) (string, error) {
 	var buf bytes.Buffer
 	if err := p.md.Convert([]byte(content), &buf); err != nil {
 		return "", err
@@ -51,6 +96,15 @@ func (p *MarkdownParser) ToHTML(content string) (string, error) {
 	return buf.String(), nil
 }
 
+// Stats reports the parser's cache hit/miss/eviction counts, or a zero
+// Stats if no cache was configured with WithParserCache.
+func (p *MarkdownParser) Stats() memcache.Stats {
+	if p.cache == nil {
+		return memcache.Stats{}
+	}
+	return p.cache.Stats()
+}
+
 // MergeFiles merges multiple files into a single content string.
 // Files are sorted by their Order field.
 func (p *MarkdownParser) MergeFiles(files []MDFile) string {
@@ -0,0 +1,66 @@
+package docflow
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// mupdfBackend extracts text by shelling out to mutool (mupdf-tools). It
+// requires mutool on PATH.
+type mupdfBackend struct {
+	options Options
+}
+
+func (b mupdfBackend) extractText(ctx context.Context, pdfPath string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, b.options.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "mutool", "draw", "-F", "txt", pdfPath)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("mutool draw failed: %w", err)
+	}
+
+	return string(output), nil
+}
+
+func (b mupdfBackend) extractFirstPageText(ctx context.Context, pdfPath string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, b.options.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "mutool", "draw", "-F", "txt", pdfPath, "1")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("mutool draw failed: %w", err)
+	}
+
+	return string(output), nil
+}
+
+func (b mupdfBackend) extractPage(ctx context.Context, pdfPath string, page int) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, b.options.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "mutool", "draw", "-F", "txt", pdfPath, strconv.Itoa(page))
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("mutool draw failed: %w", err)
+	}
+
+	return string(output), nil
+}
+
+func (b mupdfBackend) name() string { return BackendMupdf }
+
+// pageCount counts the form-feed page separators mutool's txt output
+// puts between pages, rather than shelling out a second time.
+func (b mupdfBackend) pageCount(ctx context.Context, pdfPath string) (int, error) {
+	text, err := b.extractText(ctx, pdfPath)
+	if err != nil {
+		return 0, err
+	}
+	return strings.Count(text, "\f") + 1, nil
+}
@@ -0,0 +1,75 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPConfig configures HTTPEngine.
+type HTTPConfig struct {
+	// URL is the remote OCR service's recognition endpoint.
+	URL string
+
+	// APIKey, if set, is sent as a Bearer token.
+	APIKey string
+
+	// Timeout defaults to 30s.
+	Timeout time.Duration
+}
+
+// HTTPEngine recognizes text via a remote OCR service: it POSTs the page
+// image as the request body and expects back a JSON object
+// {"text": "..."}. Point URL at an adapter in front of a different
+// response shape if the remote service doesn't speak this directly.
+type HTTPEngine struct {
+	cfg    HTTPConfig
+	client *http.Client
+}
+
+// NewHTTPEngine returns an Engine backed by a remote HTTP OCR service.
+func NewHTTPEngine(cfg HTTPConfig) *HTTPEngine {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return &HTTPEngine{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}
+}
+
+func (e *HTTPEngine) Recognize(ctx context.Context, image []byte, lang string) (string, error) {
+	url := e.cfg.URL
+	if lang != "" {
+		url = fmt.Sprintf("%s?lang=%s", url, lang)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(image))
+	if err != nil {
+		return "", fmt.Errorf("ocr: http request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if e.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.cfg.APIKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("ocr: call: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("ocr: error: %s", string(body))
+	}
+
+	var result struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("ocr: decode: %w", err)
+	}
+	return result.Text, nil
+}
@@ -0,0 +1,17 @@
+// Package ocr recognizes text in rasterized PDF page images, for pages
+// Extractor's text backends can't recover text from (scanned/image-only
+// PDFs). It only does recognition - rasterizing the PDF page into an
+// image is Extractor's job (pdftoppm/mutool are already its text-backend
+// dependencies), so Engine takes image bytes, not a PDF.
+package ocr
+
+import "context"
+
+// Engine recognizes text in a single rasterized page image.
+type Engine interface {
+	// Recognize returns the text found in image (PNG or PPM bytes - Engine
+	// implementations decide what they accept), using lang as an
+	// implementation-specific language hint (e.g. Tesseract's "eng",
+	// "eng+fra").
+	Recognize(ctx context.Context, image []byte, lang string) (string, error)
+}
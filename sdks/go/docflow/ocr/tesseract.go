@@ -0,0 +1,61 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// TesseractEngine recognizes text by shelling out to the Tesseract OCR
+// binary. It requires "tesseract" on PATH.
+type TesseractEngine struct {
+	// BinPath overrides the "tesseract" binary name, for installs not on
+	// PATH.
+	BinPath string
+
+	// TempDir is where the page image is written for tesseract to read -
+	// it takes an input file path, not stdin. Defaults to os.TempDir().
+	TempDir string
+}
+
+// NewTesseractEngine returns an Engine backed by a local Tesseract
+// install.
+func NewTesseractEngine() *TesseractEngine {
+	return &TesseractEngine{}
+}
+
+func (e *TesseractEngine) Recognize(ctx context.Context, image []byte, lang string) (string, error) {
+	bin := e.BinPath
+	if bin == "" {
+		bin = "tesseract"
+	}
+	tempDir := e.TempDir
+	if tempDir == "" {
+		tempDir = os.TempDir()
+	}
+
+	imgPath := filepath.Join(tempDir, fmt.Sprintf("docflow-ocr-%d.png", time.Now().UnixNano()))
+	if err := os.WriteFile(imgPath, image, 0644); err != nil {
+		return "", fmt.Errorf("ocr: write page image: %w", err)
+	}
+	defer os.Remove(imgPath)
+
+	args := []string{imgPath, "stdout"}
+	if lang != "" {
+		args = append(args, "-l", lang)
+	}
+
+	cmd := exec.CommandContext(ctx, bin, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("ocr: tesseract failed: %w: %s", err, stderr.String())
+	}
+
+	return stdout.String(), nil
+}
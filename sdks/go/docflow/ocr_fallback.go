@@ -0,0 +1,130 @@
+package docflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ocrLowYieldChars is the per-page character threshold below which
+// applyOCRFallback treats a page as scanned/image-only and reruns it
+// through the configured ocr.Engine instead of trusting the backend's
+// (near-empty) text for it.
+const ocrLowYieldChars = 50
+
+// defaultOCRDPI is the rasterization resolution used when Options.OCRDPI
+// is unset.
+const defaultOCRDPI = 200
+
+// applyOCRFallback splits text on the form-feed page separators
+// Extractor's backends already emit between pages, OCRs any page that's
+// low-yield (or every page, with Options.ForceOCR), and splices the
+// recognized text back in. It returns the original text unchanged, with
+// a nil confidence map, if no page needed OCR.
+func (e *Extractor) applyOCRFallback(ctx context.Context, pdfPath, text string, pageCount int) (string, map[int]float64) {
+	pages := strings.Split(text, "\f")
+	if pageCount < 1 {
+		pageCount = len(pages)
+	}
+
+	confidence := map[int]float64{}
+	changed := false
+	for i := 0; i < pageCount; i++ {
+		var existing string
+		if i < len(pages) {
+			existing = pages[i]
+		}
+		if !e.options.ForceOCR && len(strings.TrimSpace(existing)) >= ocrLowYieldChars {
+			continue
+		}
+
+		ocrText, ok := e.ocrPage(ctx, pdfPath, i+1)
+		if !ok {
+			continue
+		}
+		for len(pages) <= i {
+			pages = append(pages, "")
+		}
+		pages[i] = ocrText
+		confidence[i+1] = ocrConfidence(ocrText)
+		changed = true
+	}
+
+	if !changed {
+		return text, nil
+	}
+	return strings.Join(pages, "\f"), confidence
+}
+
+// ocrPage rasterizes the given 1-indexed page and runs it through
+// e.ocrEngine, reporting ok=false if rasterization or recognition fails
+// or produces nothing - the caller then keeps the backend's original
+// text for that page.
+func (e *Extractor) ocrPage(ctx context.Context, pdfPath string, page int) (string, bool) {
+	dpi := e.options.OCRDPI
+	if dpi <= 0 {
+		dpi = defaultOCRDPI
+	}
+
+	image, err := rasterizePage(ctx, pdfPath, page, dpi)
+	if err != nil {
+		return "", false
+	}
+
+	recognized, err := e.ocrEngine.Recognize(ctx, image, e.ocrLangs)
+	if err != nil || strings.TrimSpace(recognized) == "" {
+		return "", false
+	}
+	return recognized, true
+}
+
+// ocrConfidence is a coarse stand-in for a real per-page confidence
+// score: ocr.Engine implementations (a tesseract subprocess, an
+// arbitrary HTTP service) don't uniformly report one, so this just
+// distinguishes "OCR found something" from "OCR found nothing."
+func ocrConfidence(text string) float64 {
+	if strings.TrimSpace(text) == "" {
+		return 0
+	}
+	return 0.75
+}
+
+// rasterizePage renders one PDF page to a PNG at dpi, preferring
+// pdftoppm (poppler-utils) and falling back to mutool (mupdf-tools) -
+// the same two external tools Extractor's text backends already depend
+// on, so this adds no new binary requirement for hosts that have either.
+func rasterizePage(ctx context.Context, pdfPath string, page int, dpi int) ([]byte, error) {
+	if _, err := exec.LookPath("pdftoppm"); err == nil {
+		return rasterizeWithPdftoppm(ctx, pdfPath, page, dpi)
+	}
+	if _, err := exec.LookPath("mutool"); err == nil {
+		return rasterizeWithMutool(ctx, pdfPath, page, dpi)
+	}
+	return nil, fmt.Errorf("ocr: no rasterizer available (need pdftoppm or mutool on PATH)")
+}
+
+func rasterizeWithPdftoppm(ctx context.Context, pdfPath string, page int, dpi int) ([]byte, error) {
+	pageArg := strconv.Itoa(page)
+	cmd := exec.CommandContext(ctx, "pdftoppm", "-f", pageArg, "-l", pageArg, "-r", strconv.Itoa(dpi), "-png", "-singlefile", pdfPath, "-")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("pdftoppm failed: %w", err)
+	}
+	return output, nil
+}
+
+func rasterizeWithMutool(ctx context.Context, pdfPath string, page int, dpi int) ([]byte, error) {
+	outPath := filepath.Join(os.TempDir(), fmt.Sprintf("docflow-ocr-page-%d-%d.png", page, time.Now().UnixNano()))
+	defer os.Remove(outPath)
+
+	cmd := exec.CommandContext(ctx, "mutool", "draw", "-o", outPath, "-r", strconv.Itoa(dpi), pdfPath, strconv.Itoa(page))
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("mutool draw failed: %w", err)
+	}
+	return os.ReadFile(outPath)
+}
@@ -1,6 +1,11 @@
 package docflow
 
-import "time"
+import (
+	"time"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/output"
+	"github.com/xgaslan/docflow/sdks/go/docflow/pdfops"
+)
 
 // Options contains configuration options for the DocFlow library.
 type Options struct {
@@ -17,6 +22,25 @@ type Options struct {
 	// UseBrowser determines if browser-based PDF generation should be used.
 	// If false, will try alternative methods.
 	UseBrowser bool
+
+	// Backend selects the PDF text extraction backend Extractor uses:
+	// BackendAuto (default) tries the pure-Go pdfcpu backend first and
+	// falls back to poppler/mupdf, when their binaries are on PATH, for
+	// PDFs pdfcpu can't recover text from; BackendPoppler and
+	// BackendMupdf pin to pdftotext/pdfinfo or mutool and fail if
+	// they're missing; BackendPdfcpu never shells out, so it works on
+	// hosts (containers, iOS, wasm) without those tools installed.
+	Backend string
+
+	// OCRDPI is the rasterization resolution used when the OCR fallback
+	// (WithOCRFallback) runs on a page - higher is more accurate but
+	// slower. Zero uses defaultOCRDPI (200).
+	OCRDPI int
+
+	// ForceOCR runs every page through the OCR fallback engine, instead
+	// of only pages extractUncached judges low-yield. Has no effect
+	// unless WithOCRFallback is also configured.
+	ForceOCR bool
 }
 
 // DefaultOptions returns the default configuration options.
@@ -26,6 +50,7 @@ func DefaultOptions() Options {
 		Timeout:    60 * time.Second,
 		ChromePath: "",
 		UseBrowser: true,
+		Backend:    BackendAuto,
 	}
 }
 
@@ -34,6 +59,9 @@ type ConvertOptions struct {
 	// MergeMode specifies how multiple files should be handled.
 	// "merged" - combine all files into one PDF
 	// "separate" - create separate PDFs for each file
+	// "pdfcpu" - render each file to its own PDF, then concatenate with
+	// pdfcpu instead of merging the markdown first. Useful when inputs
+	// already embed PDFs or need different page sizes.
 	MergeMode string
 
 	// OutputName is the name for the output file (used in merged mode).
@@ -42,6 +70,16 @@ type ConvertOptions struct {
 	// OutputPath is the path where the output should be saved.
 	// If using storage, this is relative to the storage root.
 	OutputPath string
+
+	// PostProcess is a chain of pdfops operations run in order on the
+	// PDF bytes returned by chromedp, before saving.
+	PostProcess []pdfops.Op
+
+	// Outputs lists the destinations each generated PDF is written to as
+	// it completes. When set, it replaces the implicit storage/TempDir
+	// save path below, so a caller can e.g. stream a tar to an HTTP
+	// response while also archiving to S3 via output.S3Output.
+	Outputs []output.Output
 }
 
 // Result represents the result of a conversion operation.
@@ -85,6 +123,18 @@ type ExtractResult struct {
 	// PageCount is the number of pages in the PDF.
 	PageCount int
 
+	// Backend identifies which pdfBackend actually produced this result
+	// (BackendPoppler, BackendMupdf, or BackendPdfcpu) - useful for
+	// observability when Options.Backend is BackendAuto and the pick
+	// depends on what's installed and what the PDF needed.
+	Backend string
+
+	// PageConfidence holds a coarse per-page OCR confidence (see
+	// ocrConfidence), keyed by 1-indexed page number, for pages the OCR
+	// fallback ran on. Nil unless WithOCRFallback is configured and at
+	// least one page needed it.
+	PageConfidence map[int]float64
+
 	// Error contains any error that occurred.
 	Error error
 }
@@ -0,0 +1,154 @@
+package docflow
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// pdfBackend extracts text from a PDF file on disk. Extractor picks one
+// per Options.Backend; extractor.go's text-to-markdown pipeline is the
+// same regardless of which backend produced the raw text.
+type pdfBackend interface {
+	// extractText returns the full document text.
+	extractText(ctx context.Context, pdfPath string) (string, error)
+	// extractFirstPageText returns just page one, for Preview.
+	extractFirstPageText(ctx context.Context, pdfPath string) (string, error)
+	// extractPage returns the text of a single 1-indexed page.
+	extractPage(ctx context.Context, pdfPath string, page int) (string, error)
+	// pageCount returns the document's page count.
+	pageCount(ctx context.Context, pdfPath string) (int, error)
+	// name identifies the backend for ExtractResult.Backend.
+	name() string
+}
+
+// Backend names accepted by Options.Backend and WithBackend.
+const (
+	// BackendAuto tries pdfcpuBackend (pure Go, no dependencies) first,
+	// then falls back to an external tool if one is on PATH and pdfcpu
+	// fails to extract any text - some PDFs (scanned pages, exotic
+	// CID/Type0 font encodings) recover better through poppler/mupdf.
+	BackendAuto = "auto"
+	// BackendPoppler shells out to pdftotext/pdfinfo; fails if they're
+	// not on PATH.
+	BackendPoppler = "poppler"
+	// BackendMupdf shells out to mutool; fails if it's not on PATH.
+	BackendMupdf = "mupdf"
+	// BackendPdfcpu never shells out, so it works on hosts (containers,
+	// iOS, wasm) without poppler-utils or mupdf-tools installed.
+	BackendPdfcpu = "pdfcpu"
+)
+
+// resolveBackend picks the pdfBackend for name (BackendAuto,
+// BackendPoppler, BackendMupdf, or BackendPdfcpu).
+func resolveBackend(options Options, name string) (pdfBackend, error) {
+	switch name {
+	case "", BackendAuto:
+		return &autoBackend{native: pdfcpuBackend{}, options: options}, nil
+	case BackendPoppler:
+		return popplerBackend{options: options}, nil
+	case BackendMupdf:
+		return mupdfBackend{options: options}, nil
+	case BackendPdfcpu:
+		return pdfcpuBackend{}, nil
+	default:
+		return nil, fmt.Errorf("unknown PDF backend %q (want %s, %s, %s, or %s)", name, BackendAuto, BackendPoppler, BackendMupdf, BackendPdfcpu)
+	}
+}
+
+// binaryAvailable reports whether name is resolvable on PATH.
+func binaryAvailable(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// autoBackend tries native (pure-Go, always available) extraction first
+// and only shells out to an installed external tool when native comes
+// back empty or errors - so extraction works unmodified on hosts with
+// no PDF tools installed, and still recovers text from PDFs pdfcpu
+// can't decode (scanned pages, exotic CID/Type0 font encodings) on
+// hosts that have poppler or mupdf on PATH.
+type autoBackend struct {
+	native  pdfBackend
+	options Options
+	used    string // last backend that actually produced a result, for ExtractResult.Backend
+}
+
+func (b *autoBackend) name() string {
+	if b.used != "" {
+		return b.used
+	}
+	return BackendAuto
+}
+
+// externalFallback returns the first external backend with its binary
+// on PATH, preferring poppler since it generally produces cleaner text.
+func (b *autoBackend) externalFallback() (pdfBackend, bool) {
+	if binaryAvailable("pdftotext") {
+		return popplerBackend{options: b.options}, true
+	}
+	if binaryAvailable("mutool") {
+		return mupdfBackend{options: b.options}, true
+	}
+	return nil, false
+}
+
+func (b *autoBackend) extractText(ctx context.Context, pdfPath string) (string, error) {
+	text, err := b.native.extractText(ctx, pdfPath)
+	if err == nil && strings.TrimSpace(text) != "" {
+		b.used = b.native.name()
+		return text, nil
+	}
+	if fb, ok := b.externalFallback(); ok {
+		fbText, fbErr := fb.extractText(ctx, pdfPath)
+		if fbErr == nil {
+			b.used = fb.name()
+			return fbText, nil
+		}
+	}
+	b.used = b.native.name()
+	return text, err
+}
+
+func (b *autoBackend) extractFirstPageText(ctx context.Context, pdfPath string) (string, error) {
+	text, err := b.native.extractFirstPageText(ctx, pdfPath)
+	if err == nil && strings.TrimSpace(text) != "" {
+		b.used = b.native.name()
+		return text, nil
+	}
+	if fb, ok := b.externalFallback(); ok {
+		fbText, fbErr := fb.extractFirstPageText(ctx, pdfPath)
+		if fbErr == nil {
+			b.used = fb.name()
+			return fbText, nil
+		}
+	}
+	b.used = b.native.name()
+	return text, err
+}
+
+func (b *autoBackend) extractPage(ctx context.Context, pdfPath string, page int) (string, error) {
+	text, err := b.native.extractPage(ctx, pdfPath, page)
+	if err == nil && strings.TrimSpace(text) != "" {
+		b.used = b.native.name()
+		return text, nil
+	}
+	if fb, ok := b.externalFallback(); ok {
+		fbText, fbErr := fb.extractPage(ctx, pdfPath, page)
+		if fbErr == nil {
+			b.used = fb.name()
+			return fbText, nil
+		}
+	}
+	b.used = b.native.name()
+	return text, err
+}
+
+// pageCount is metadata, not text recovery, so pdfcpu's own parsing is
+// reliable regardless of the font-encoding issues that motivate the
+// text-extraction fallbacks above - no need to shell out for it.
+func (b *autoBackend) pageCount(ctx context.Context, pdfPath string) (int, error) {
+	b.used = b.native.name()
+	return b.native.pageCount(ctx, pdfPath)
+}
@@ -0,0 +1,374 @@
+package docflow
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/docmodel"
+)
+
+// decodeContentStreamText scans a PDF page content stream and recovers
+// the text shown by Tj/TJ/'/" operators, in stream order. It's a
+// lightweight token scanner, not a full PDF content-stream interpreter:
+// it tracks just enough state (pending string/array operands, text
+// positioning operators) to reconstruct readable text, and ignores
+// everything else (graphics state, exact glyph positioning, CID/Type0
+// font encodings beyond a literal byte-per-char assumption).
+func decodeContentStreamText(content []byte) string {
+	var out strings.Builder
+	var pendingString string
+	var pendingArray string
+	haveString := false
+	haveArray := false
+
+	i := 0
+	for i < len(content) {
+		c := content[i]
+
+		switch {
+		case c == '(':
+			s, next := scanLiteralString(content, i)
+			pendingString = s
+			haveString = true
+			i = next
+
+		case c == '[':
+			s, next := scanTextArray(content, i)
+			pendingArray = s
+			haveArray = true
+			i = next
+
+		case c == '<':
+			// Hex string: skip over it (rarely shown text directly, and
+			// decoding it needs the page's font encoding, which this
+			// lightweight scanner doesn't track).
+			i = skipTo(content, i, '>') + 1
+
+		case isWhitespace(c):
+			i++
+
+		default:
+			word, next := scanWord(content, i)
+			i = next
+			switch word {
+			case "Tj", "'", "\"":
+				if haveString {
+					out.WriteString(pendingString)
+					out.WriteString(" ")
+					haveString = false
+				}
+			case "TJ":
+				if haveArray {
+					out.WriteString(pendingArray)
+					out.WriteString(" ")
+					haveArray = false
+				}
+			case "Td", "TD", "T*":
+				out.WriteString("\n")
+			}
+		}
+	}
+
+	return out.String()
+}
+
+func isWhitespace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', '\f', 0:
+		return true
+	}
+	return false
+}
+
+// scanLiteralString decodes a PDF "(...)" literal string starting at
+// content[start] == '(', honoring backslash escapes and balanced nested
+// parens, and returns the decoded text plus the index just past the
+// closing paren.
+func scanLiteralString(content []byte, start int) (string, int) {
+	var sb strings.Builder
+	depth := 0
+	i := start + 1
+	for i < len(content) {
+		c := content[i]
+		switch {
+		case c == '\\' && i+1 < len(content):
+			sb.WriteByte(decodeEscape(content[i+1]))
+			i += 2
+		case c == '(':
+			depth++
+			sb.WriteByte(c)
+			i++
+		case c == ')':
+			if depth == 0 {
+				return sb.String(), i + 1
+			}
+			depth--
+			sb.WriteByte(c)
+			i++
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return sb.String(), i
+}
+
+func decodeEscape(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	default:
+		return c
+	}
+}
+
+// scanTextArray decodes a "[(Hel)-20(lo)]" TJ operand: the literal
+// strings concatenated, with numeric kerning adjustments dropped.
+func scanTextArray(content []byte, start int) (string, int) {
+	var sb strings.Builder
+	i := start + 1
+	for i < len(content) && content[i] != ']' {
+		if content[i] == '(' {
+			s, next := scanLiteralString(content, i)
+			sb.WriteString(s)
+			i = next
+			continue
+		}
+		i++
+	}
+	if i < len(content) {
+		i++ // skip ']'
+	}
+	return sb.String(), i
+}
+
+func scanWord(content []byte, start int) (string, int) {
+	i := start
+	for i < len(content) && !isWhitespace(content[i]) && content[i] != '(' && content[i] != '[' && content[i] != '<' {
+		i++
+	}
+	if i == start {
+		return "", start + 1
+	}
+	return string(content[start:i]), i
+}
+
+func skipTo(content []byte, start int, target byte) int {
+	i := start + 1
+	for i < len(content) && content[i] != target {
+		i++
+	}
+	return i
+}
+
+// sizedRun is a single shown-text or newline event from
+// decodeContentStreamTextWithSizes, tagged with the font size active when
+// it was shown.
+type sizedRun struct {
+	Text     string
+	FontSize float64
+}
+
+// decodeContentStreamTextWithSizes mirrors decodeContentStreamText's token
+// scanning, additionally tracking the operand to the most recent "Tf"
+// (set-font-size) operator so each emitted run can be tagged with the font
+// size active when it was shown. Used to rank heading levels by font size
+// - see headingsByFontSize.
+func decodeContentStreamTextWithSizes(content []byte) []sizedRun {
+	var out []sizedRun
+	var pendingString string
+	var pendingArray string
+	haveString := false
+	haveArray := false
+	currentSize := 0.0
+	lastNumber := ""
+
+	i := 0
+	for i < len(content) {
+		c := content[i]
+
+		switch {
+		case c == '(':
+			s, next := scanLiteralString(content, i)
+			pendingString = s
+			haveString = true
+			i = next
+
+		case c == '[':
+			s, next := scanTextArray(content, i)
+			pendingArray = s
+			haveArray = true
+			i = next
+
+		case c == '<':
+			i = skipTo(content, i, '>') + 1
+
+		case isWhitespace(c):
+			i++
+
+		default:
+			word, next := scanWord(content, i)
+			i = next
+			switch word {
+			case "Tf":
+				if size, err := strconv.ParseFloat(lastNumber, 64); err == nil {
+					currentSize = size
+				}
+			case "Tj", "'", "\"":
+				if haveString {
+					out = append(out, sizedRun{Text: pendingString, FontSize: currentSize})
+					haveString = false
+				}
+			case "TJ":
+				if haveArray {
+					out = append(out, sizedRun{Text: pendingArray, FontSize: currentSize})
+					haveArray = false
+				}
+			case "Td", "TD", "T*":
+				out = append(out, sizedRun{Text: "\n", FontSize: currentSize})
+			default:
+				if _, err := strconv.ParseFloat(word, 64); err == nil {
+					lastNumber = word
+				}
+			}
+		}
+	}
+
+	return out
+}
+
+// approxTextWidth estimates a shown string's width from its character
+// count and font size, since this scanner doesn't have real glyph
+// metrics - good enough to detect table-column gaps, not to lay out
+// text precisely.
+func approxTextWidth(text string, fontSize float64) float64 {
+	return float64(len(text)) * fontSize * 0.5
+}
+
+// decodeContentStreamLayout mirrors decodeContentStreamText's token
+// scanning, additionally tracking enough of the text-positioning state
+// (Tf for font size, Tm/Td/TD/T*/BT for line origin) to emit one
+// docmodel.Word per Tj/TJ/'/" show-text operator, positioned and sized
+// for docmodel.FromWords. It's an approximation, not a content-stream
+// interpreter: it ignores the CTM, text rise, horizontal scaling, and
+// per-glyph widths (see approxTextWidth), and a word is really "one
+// show-text operator's text", not one space-delimited word.
+func decodeContentStreamLayout(content []byte, page int) []docmodel.Word {
+	var words []docmodel.Word
+	var pendingString string
+	var pendingArray string
+	haveString := false
+	haveArray := false
+	currentSize := 0.0
+	var nums []float64
+
+	// lineX, lineY is the current line's origin (PDF user space, Y
+	// increasing upward); curX advances as words are emitted along the
+	// line. started is cleared on every line-origin change so only the
+	// first word emitted after it is flagged NewLine.
+	var lineX, lineY, curX float64
+	started := false
+
+	emit := func(text string) {
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+		width := approxTextWidth(text, currentSize)
+		words = append(words, docmodel.Word{
+			Text:     text,
+			Page:     page,
+			X:        curX,
+			Y:        -lineY, // flip to docmodel's Y-increases-downward convention
+			W:        width,
+			H:        currentSize,
+			FontSize: currentSize,
+			NewLine:  !started,
+		})
+		started = true
+		curX += width + approxTextWidth(" ", currentSize)
+	}
+
+	newLine := func() {
+		curX = lineX
+		started = false
+	}
+
+	i := 0
+	for i < len(content) {
+		c := content[i]
+
+		switch {
+		case c == '(':
+			s, next := scanLiteralString(content, i)
+			pendingString = s
+			haveString = true
+			i = next
+
+		case c == '[':
+			s, next := scanTextArray(content, i)
+			pendingArray = s
+			haveArray = true
+			i = next
+
+		case c == '<':
+			i = skipTo(content, i, '>') + 1
+
+		case isWhitespace(c):
+			i++
+
+		default:
+			word, next := scanWord(content, i)
+			i = next
+			switch word {
+			case "Tf":
+				if len(nums) >= 1 {
+					currentSize = nums[len(nums)-1]
+				}
+				nums = nil
+			case "Tm":
+				if len(nums) >= 6 {
+					lineX, lineY = nums[len(nums)-2], nums[len(nums)-1]
+					newLine()
+				}
+				nums = nil
+			case "Td", "TD":
+				if len(nums) >= 2 {
+					lineX += nums[len(nums)-2]
+					lineY += nums[len(nums)-1]
+					newLine()
+				}
+				nums = nil
+			case "T*", "BT":
+				if word == "BT" {
+					lineX, lineY = 0, 0
+				}
+				newLine()
+				nums = nil
+			case "Tj", "'", "\"":
+				if haveString {
+					emit(pendingString)
+					haveString = false
+				}
+				nums = nil
+			case "TJ":
+				if haveArray {
+					emit(pendingArray)
+					haveArray = false
+				}
+				nums = nil
+			default:
+				if n, err := strconv.ParseFloat(word, 64); err == nil {
+					nums = append(nums, n)
+				} else {
+					nums = nil
+				}
+			}
+		}
+	}
+
+	return words
+}
@@ -0,0 +1,66 @@
+package docflow
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// pdfcpuBackend extracts text with github.com/pdfcpu/pdfcpu, a pure-Go
+// PDF library, instead of shelling out to pdftotext/mutool. It's the
+// only backend that works on hosts without those binaries installed
+// (minimal containers, iOS, wasm), at the cost of less accurate text
+// recovery for PDFs using embedded CID/Type0 font encodings - see
+// decodeContentStreamText.
+type pdfcpuBackend struct{}
+
+func (b pdfcpuBackend) extractText(ctx context.Context, pdfPath string) (string, error) {
+	return b.extractPages(pdfPath, nil)
+}
+
+func (b pdfcpuBackend) extractFirstPageText(ctx context.Context, pdfPath string) (string, error) {
+	return b.extractPages(pdfPath, []string{"1"})
+}
+
+func (b pdfcpuBackend) extractPages(pdfPath string, selectedPages []string) (string, error) {
+	data, err := os.ReadFile(pdfPath)
+	if err != nil {
+		return "", fmt.Errorf("pdfcpu backend: read %s: %w", pdfPath, err)
+	}
+
+	var pages []string
+	digest := func(r io.Reader, pageNr int) error {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("pdfcpu backend: read page %d content: %w", pageNr, err)
+		}
+		pages = append(pages, decodeContentStreamText(content))
+		return nil
+	}
+
+	if err := api.ExtractContent(bytes.NewReader(data), selectedPages, digest, nil); err != nil {
+		return "", fmt.Errorf("pdfcpu backend: extract content: %w", err)
+	}
+
+	return strings.Join(pages, "\f"), nil
+}
+
+func (b pdfcpuBackend) extractPage(ctx context.Context, pdfPath string, page int) (string, error) {
+	return b.extractPages(pdfPath, []string{strconv.Itoa(page)})
+}
+
+func (b pdfcpuBackend) name() string { return BackendPdfcpu }
+
+func (b pdfcpuBackend) pageCount(ctx context.Context, pdfPath string) (int, error) {
+	count, err := api.PageCountFile(pdfPath)
+	if err != nil {
+		return 0, fmt.Errorf("pdfcpu backend: page count: %w", err)
+	}
+	return count, nil
+}
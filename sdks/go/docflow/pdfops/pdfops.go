@@ -0,0 +1,192 @@
+// Package pdfops provides post-processing operations for generated PDFs
+// (watermarking, metadata, encryption, merging, splitting, outlines) on
+// top of github.com/pdfcpu/pdfcpu/pkg/api.
+package pdfops
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// Op is a single post-processing step applied to PDF bytes in order.
+type Op func(pdfBytes []byte) ([]byte, error)
+
+// Heading describes a markdown heading used to build a PDF outline.
+type Heading struct {
+	Text  string
+	Level int
+	Page  int
+}
+
+// WatermarkOptions configures a text watermark stamp.
+type WatermarkOptions struct {
+	Text     string
+	FontSize int
+	Opacity  float64
+	Rotation float64
+	OnTop    bool
+}
+
+// Watermark returns an Op that stamps every page with a text watermark.
+func Watermark(opts WatermarkOptions) Op {
+	return func(pdfBytes []byte) ([]byte, error) {
+		return withTempFile(pdfBytes, func(in, out string) error {
+			desc := fmt.Sprintf("font:Helvetica, points:%d, opacity:%.2f, rotation:%.0f",
+				opts.FontSize, opts.Opacity, opts.Rotation)
+			wm, err := api.TextWatermark(opts.Text, desc, opts.OnTop, false, model.POINTS)
+			if err != nil {
+				return fmt.Errorf("pdfops: build watermark: %w", err)
+			}
+			return api.AddWatermarksFile(in, out, nil, wm, nil)
+		})
+	}
+}
+
+// SetMetadata returns an Op that sets the document info dictionary.
+func SetMetadata(title, author, subject string, keywords []string) Op {
+	return func(pdfBytes []byte) ([]byte, error) {
+		return withTempFile(pdfBytes, func(in, out string) error {
+			ctx, err := api.ReadContextFile(in)
+			if err != nil {
+				return fmt.Errorf("pdfops: read context: %w", err)
+			}
+			ctx.XRefTable.Title = title
+			ctx.XRefTable.Author = author
+			ctx.XRefTable.Subject = subject
+			ctx.XRefTable.Keywords = keywords
+			return api.WriteContextFile(ctx, out)
+		})
+	}
+}
+
+// Encrypt returns an Op that encrypts the PDF with a user/owner password
+// and permission bitmask.
+func Encrypt(userPw, ownerPw string, perms int16) Op {
+	return func(pdfBytes []byte) ([]byte, error) {
+		return withTempFile(pdfBytes, func(in, out string) error {
+			conf := model.NewDefaultConfiguration()
+			conf.UserPW = userPw
+			conf.OwnerPW = ownerPw
+			conf.Permissions = model.PermissionBits(perms)
+			return api.EncryptFile(in, out, conf)
+		})
+	}
+}
+
+// AddOutlineFromHeadings returns an Op that builds a bookmark outline
+// from markdown headings, jumping to each heading's page.
+func AddOutlineFromHeadings(headings []Heading) Op {
+	return func(pdfBytes []byte) ([]byte, error) {
+		return withTempFile(pdfBytes, func(in, out string) error {
+			bookmarks := make([]api.Bookmark, 0, len(headings))
+			for _, h := range headings {
+				bookmarks = append(bookmarks, api.Bookmark{
+					Title:    h.Text,
+					PageFrom: h.Page,
+					Bold:     h.Level == 1,
+				})
+			}
+			return api.ImportBookmarksFile(in, out, bookmarks, true, nil)
+		})
+	}
+}
+
+// Split splits the PDF into one file per page range (e.g. "1-3", "4-").
+// Unlike the other Ops, Split does not fit the single-Op pipeline and is
+// called directly, returning the bytes of each resulting PDF.
+func Split(pdfBytes []byte, ranges []string) ([][]byte, error) {
+	tmpDir, err := os.MkdirTemp("", "pdfops-split-*")
+	if err != nil {
+		return nil, fmt.Errorf("pdfops: create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	in := filepath.Join(tmpDir, "in.pdf")
+	if err := os.WriteFile(in, pdfBytes, 0644); err != nil {
+		return nil, fmt.Errorf("pdfops: write temp input: %w", err)
+	}
+
+	results := make([][]byte, 0, len(ranges))
+	for i, r := range ranges {
+		out := filepath.Join(tmpDir, fmt.Sprintf("out-%d.pdf", i))
+		if err := api.CollectFile(in, out, []string{r}, nil); err != nil {
+			return nil, fmt.Errorf("pdfops: split range %q: %w", r, err)
+		}
+		data, err := os.ReadFile(out)
+		if err != nil {
+			return nil, fmt.Errorf("pdfops: read split result: %w", err)
+		}
+		results = append(results, data)
+	}
+	return results, nil
+}
+
+// Merge concatenates the PDFs at paths, in order, into a single PDF.
+func Merge(paths []string) ([]byte, error) {
+	tmpDir, err := os.MkdirTemp("", "pdfops-merge-*")
+	if err != nil {
+		return nil, fmt.Errorf("pdfops: create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	out := filepath.Join(tmpDir, "merged.pdf")
+	if err := api.MergeCreateFile(paths, out, false, nil); err != nil {
+		return nil, fmt.Errorf("pdfops: merge: %w", err)
+	}
+	return os.ReadFile(out)
+}
+
+// Optimize returns an Op that runs pdfcpu's structural optimizer, which
+// typically shrinks file size by deduplicating objects and fonts.
+func Optimize() Op {
+	return func(pdfBytes []byte) ([]byte, error) {
+		return withTempFile(pdfBytes, func(in, out string) error {
+			return api.OptimizeFile(in, out, nil)
+		})
+	}
+}
+
+// Apply runs ops in order over pdfBytes, threading the output of each
+// into the next.
+func Apply(pdfBytes []byte, ops []Op) ([]byte, error) {
+	data := pdfBytes
+	for i, op := range ops {
+		result, err := op(data)
+		if err != nil {
+			return nil, fmt.Errorf("pdfops: op %d: %w", i, err)
+		}
+		data = result
+	}
+	return data, nil
+}
+
+// withTempFile writes pdfBytes to a temp input file, runs fn against an
+// output temp path, and returns the resulting file's bytes.
+func withTempFile(pdfBytes []byte, fn func(in, out string) error) ([]byte, error) {
+	tmpDir, err := os.MkdirTemp("", "pdfops-*")
+	if err != nil {
+		return nil, fmt.Errorf("pdfops: create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	in := filepath.Join(tmpDir, "in.pdf")
+	out := filepath.Join(tmpDir, "out.pdf")
+
+	if err := os.WriteFile(in, pdfBytes, 0644); err != nil {
+		return nil, fmt.Errorf("pdfops: write temp input: %w", err)
+	}
+
+	if err := fn(in, out); err != nil {
+		return nil, err
+	}
+
+	result, err := os.ReadFile(out)
+	if err != nil {
+		return nil, fmt.Errorf("pdfops: read temp output: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,77 @@
+package docflow
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// popplerBackend extracts text by shelling out to poppler-utils
+// (pdftotext, pdfinfo). It requires those binaries on PATH.
+type popplerBackend struct {
+	options Options
+}
+
+func (b popplerBackend) extractText(ctx context.Context, pdfPath string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, b.options.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "pdftotext", "-layout", "-enc", "UTF-8", pdfPath, "-")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("pdftotext failed: %w", err)
+	}
+
+	return string(output), nil
+}
+
+func (b popplerBackend) extractFirstPageText(ctx context.Context, pdfPath string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "pdftotext", "-f", "1", "-l", "1", "-layout", "-enc", "UTF-8", pdfPath, "-")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return string(output), nil
+}
+
+func (b popplerBackend) extractPage(ctx context.Context, pdfPath string, page int) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	pageArg := strconv.Itoa(page)
+	cmd := exec.CommandContext(ctx, "pdftotext", "-f", pageArg, "-l", pageArg, "-layout", "-enc", "UTF-8", pdfPath, "-")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return string(output), nil
+}
+
+func (b popplerBackend) name() string { return BackendPoppler }
+
+func (b popplerBackend) pageCount(ctx context.Context, pdfPath string) (int, error) {
+	cmd := exec.CommandContext(ctx, "pdfinfo", pdfPath)
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, err
+	}
+
+	lines := strings.Split(string(output), "\n")
+	for _, line := range lines {
+		if strings.HasPrefix(line, "Pages:") {
+			var pages int
+			fmt.Sscanf(line, "Pages: %d", &pages)
+			return pages, nil
+		}
+	}
+
+	return 0, fmt.Errorf("could not determine page count")
+}
@@ -0,0 +1,66 @@
+package docflow
+
+import (
+	"context"
+	"errors"
+	"os/signal"
+	"syscall"
+)
+
+// Progress describes a single point-in-time update during a conversion.
+type Progress struct {
+	// Stage identifies the step being reported, e.g. "parse_markdown",
+	// "render_html", "chrome_navigate", "print_pdf", "save", or
+	// "convert_single n/N" for per-file progress in batch mode.
+	Stage string
+
+	// Current and Total give optional progress within Stage (e.g. file
+	// n of N). Both are 0 when not applicable.
+	Current int64
+	Total   int64
+
+	// Message is a short human-readable detail.
+	Message string
+}
+
+// ProgressFunc receives Progress updates as a conversion runs.
+type ProgressFunc func(Progress)
+
+// ErrAborted is returned when a conversion is cancelled via a signal
+// installed by WithSignalAbort.
+var ErrAborted = errors.New("docflow: conversion aborted")
+
+// WithProgress installs a callback invoked at stage boundaries during
+// conversion.
+func WithProgress(fn ProgressFunc) ConverterOption {
+	return func(c *Converter) {
+		c.onProgress = fn
+	}
+}
+
+// WithSignalAbort opts the converter into cancelling its context on
+// SIGINT/SIGTERM, letting in-flight chromedp tasks clean up and
+// reporting a final Stage: "aborted" progress event.
+func WithSignalAbort(enabled bool) ConverterOption {
+	return func(c *Converter) {
+		c.signalAbort = enabled
+	}
+}
+
+func (c *Converter) emit(stage string, current, total int64, message string) {
+	if c.onProgress != nil {
+		c.onProgress(Progress{Stage: stage, Current: current, Total: total, Message: message})
+	}
+}
+
+// withAbortSignal wraps ctx so that, if signal abort is enabled, SIGINT
+// or SIGTERM cancels it. The returned cancel func must be deferred by
+// the caller; it restores default signal handling.
+func (c *Converter) withAbortSignal(ctx context.Context) (context.Context, context.CancelFunc) {
+	if !c.signalAbort {
+		return ctx, func() {}
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	return ctx, stop
+}
@@ -0,0 +1,332 @@
+package qa
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+	"github.com/xgaslan/docflow/sdks/go/docflow/rag"
+	"github.com/xgaslan/docflow/sdks/go/docflow/tokenizer"
+)
+
+// LLMCaller generates a completion for a single prompt. rag.LLMProcessor
+// satisfies this via its GenerateText method.
+type LLMCaller interface {
+	GenerateText(prompt string) (string, error)
+}
+
+// StreamingLLMCaller is an optional capability an LLMCaller can implement
+// for token-by-token generation. When the configured caller doesn't
+// implement it, AskStream falls back to a single-shot call whose answer
+// is replayed as word-sized deltas.
+type StreamingLLMCaller interface {
+	GenerateTextStream(ctx context.Context, prompt string) (<-chan string, error)
+}
+
+// ContextRetriever is an optional Retriever capability that also returns
+// the before/after neighbors of each matched chunk, for
+// RetrievalConfig.ContextBefore/ContextAfter. A Retriever that doesn't
+// implement it still works; ContextBefore/ContextAfter are simply ignored.
+type ContextRetriever interface {
+	Retriever
+	RetrieveWithNeighbors(ctx context.Context, query string, topK, before, after int) ([]RetrievedChunk, error)
+}
+
+// Engine answers questions about a document corpus, grounded in chunks
+// pulled from a Retriever.
+type Engine interface {
+	Ask(ctx context.Context, question string, opts ...Option) (*Answer, error)
+	AskStream(ctx context.Context, question string, opts ...Option) (<-chan StreamToken, error)
+}
+
+// engine is the default Engine implementation: retrieval -> context
+// assembly -> an LLM call using Prompts.DocumentQA, with an optional
+// second groundedness-check pass.
+type engine struct {
+	Retriever Retriever
+	LLM       LLMCaller
+	Retrieval config.RetrievalConfig
+	LLMConfig config.LLMConfig
+
+	tok tokenizer.Tokenizer
+}
+
+// NewEngine creates a document QA Engine. llm is typically a
+// *rag.LLMProcessor configured with llmConfig.
+func NewEngine(retriever Retriever, llm LLMCaller, retrievalConfig config.RetrievalConfig, llmConfig config.LLMConfig) Engine {
+	tok, err := tokenizer.Get("cl100k_base")
+	if err != nil {
+		tok, _ = tokenizer.Get("whitespace")
+	}
+	return &engine{Retriever: retriever, LLM: llm, Retrieval: retrievalConfig, LLMConfig: llmConfig, tok: tok}
+}
+
+// Ask retrieves relevant chunks, assembles context, and answers question
+// with Prompts.DocumentQA.
+func (e *engine) Ask(ctx context.Context, question string, opts ...Option) (*Answer, error) {
+	o := newOptions(e.Retrieval.TopK, opts)
+
+	chunks, err := e.retrieve(ctx, question, o.topK)
+	if err != nil {
+		return nil, fmt.Errorf("qa: retrieve: %w", err)
+	}
+
+	contextText := e.assembleContext(chunks)
+
+	prompt := e.buildPrompt(contextText, question)
+	answerText, err := e.LLM.GenerateText(prompt)
+	if err != nil {
+		return nil, fmt.Errorf("qa: generate answer: %w", err)
+	}
+
+	claims := e.attributeClaims(answerText, chunks)
+
+	if e.LLMConfig.GroundednessCheck {
+		claims = e.checkGroundedness(claims, chunks)
+		answerText = supportedText(claims)
+	}
+
+	return &Answer{
+		Text:       answerText,
+		Claims:     claims,
+		Confidence: confidence(claims),
+		Chunks:     chunks,
+	}, nil
+}
+
+// AskStream behaves like Ask but streams the answer as it's generated.
+// Claims, confidence, and the groundedness pass (if enabled) are computed
+// once the stream completes and are not available mid-stream.
+func (e *engine) AskStream(ctx context.Context, question string, opts ...Option) (<-chan StreamToken, error) {
+	o := newOptions(e.Retrieval.TopK, opts)
+
+	chunks, err := e.retrieve(ctx, question, o.topK)
+	if err != nil {
+		return nil, fmt.Errorf("qa: retrieve: %w", err)
+	}
+
+	prompt := e.buildPrompt(e.assembleContext(chunks), question)
+
+	out := make(chan StreamToken)
+
+	streamer, ok := e.LLM.(StreamingLLMCaller)
+	if !ok {
+		go e.replayAsStream(prompt, out)
+		return out, nil
+	}
+
+	deltas, err := streamer.GenerateTextStream(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("qa: generate answer: %w", err)
+	}
+
+	go func() {
+		defer close(out)
+		for delta := range deltas {
+			select {
+			case out <- StreamToken{Delta: delta}:
+			case <-ctx.Done():
+				out <- StreamToken{Done: true, Err: ctx.Err()}
+				return
+			}
+		}
+		out <- StreamToken{Done: true}
+	}()
+
+	return out, nil
+}
+
+// replayAsStream calls the LLM once and emits its answer as whitespace-
+// delimited deltas, the fallback for LLMCallers with no real streaming.
+func (e *engine) replayAsStream(prompt string, out chan<- StreamToken) {
+	defer close(out)
+
+	answerText, err := e.LLM.GenerateText(prompt)
+	if err != nil {
+		out <- StreamToken{Done: true, Err: fmt.Errorf("qa: generate answer: %w", err)}
+		return
+	}
+
+	words := strings.Fields(answerText)
+	for i, w := range words {
+		delta := w
+		if i < len(words)-1 {
+			delta += " "
+		}
+		out <- StreamToken{Delta: delta}
+	}
+	out <- StreamToken{Done: true}
+}
+
+// retrieve fetches the topK most relevant chunks. When IncludeContext is
+// set and the Retriever supports it, each match is expanded with its
+// ContextBefore/ContextAfter neighbors so the LLM sees surrounding text,
+// not just the matched chunk in isolation.
+func (e *engine) retrieve(ctx context.Context, question string, topK int) ([]RetrievedChunk, error) {
+	before, after := e.Retrieval.ContextBefore, e.Retrieval.ContextAfter
+	if cr, ok := e.Retriever.(ContextRetriever); ok && e.Retrieval.IncludeContext && (before > 0 || after > 0) {
+		return cr.RetrieveWithNeighbors(ctx, question, topK, before, after)
+	}
+	return e.Retriever.Retrieve(ctx, question, topK)
+}
+
+// assembleContext joins retrieved chunks into a single prompt context,
+// tagging each with its chunk ID so attributeClaims can cite it back, and
+// trims to MaxContextTokens (lowest-ranked chunks dropped first) when set.
+func (e *engine) assembleContext(chunks []RetrievedChunk) string {
+	maxTokens := e.Retrieval.MaxContextTokens
+
+	var sb strings.Builder
+	tokens := 0
+	for _, c := range chunks {
+		block := fmt.Sprintf("[chunk:%s]\n%s\n", c.ChunkID, c.Content)
+		blockTokens := e.tok.CountTokens(block)
+		if maxTokens > 0 && tokens+blockTokens > maxTokens && sb.Len() > 0 {
+			break
+		}
+		sb.WriteString(block)
+		sb.WriteString("\n")
+		tokens += blockTokens
+	}
+	return sb.String()
+}
+
+func (e *engine) buildPrompt(contextText, question string) string {
+	tmpl := e.LLMConfig.Prompts.DocumentQA
+	if tmpl == "" {
+		tmpl = config.DefaultLLMPrompts().DocumentQA
+	}
+	return fmt.Sprintf(tmpl, contextText, question)
+}
+
+// attributeClaims splits the answer into sentences and attaches each one
+// to whichever retrieved chunk it overlaps with most, by shared-word
+// Jaccard similarity. Sentences with no decent match get no citation.
+func (e *engine) attributeClaims(answerText string, chunks []RetrievedChunk) []Claim {
+	sentences := rag.SplitSentences(answerText)
+	claims := make([]Claim, len(sentences))
+
+	for i, sentence := range sentences {
+		claims[i] = Claim{Sentence: sentence, Supported: true}
+
+		bestChunk, bestScore := "", 0.0
+		bestStart, bestEnd := 0, 0
+		for _, c := range chunks {
+			score := wordOverlap(sentence, c.Content)
+			if score <= bestScore {
+				continue
+			}
+			bestScore, bestChunk = score, c.ChunkID
+			if idx := strings.Index(c.Content, sentence); idx >= 0 {
+				bestStart, bestEnd = idx, idx+len(sentence)
+			} else {
+				bestStart, bestEnd = 0, 0
+			}
+		}
+
+		if bestChunk != "" && bestScore > 0.1 {
+			claims[i].Citations = []Citation{{ChunkID: bestChunk, StartChar: bestStart, EndChar: bestEnd}}
+		}
+	}
+
+	return claims
+}
+
+// checkGroundedness asks the LLM, once per claim, whether the claim's
+// cited chunk(s) entail the claim sentence, flagging (Supported = false)
+// any claim that fails or that has no citation at all.
+func (e *engine) checkGroundedness(claims []Claim, chunks []RetrievedChunk) []Claim {
+	byID := make(map[string]string, len(chunks))
+	for _, c := range chunks {
+		byID[c.ChunkID] = c.Content
+	}
+
+	for i, claim := range claims {
+		if len(claim.Citations) == 0 {
+			claims[i].Supported = false
+			continue
+		}
+
+		var evidence strings.Builder
+		for _, cit := range claim.Citations {
+			evidence.WriteString(byID[cit.ChunkID])
+			evidence.WriteString("\n")
+		}
+
+		prompt := fmt.Sprintf(`Does the following evidence support the claim below? Answer with only "yes" or "no".
+
+Evidence:
+%s
+
+Claim:
+%s`, evidence.String(), claim.Sentence)
+
+		response, err := e.LLM.GenerateText(prompt)
+		if err != nil {
+			claims[i].Supported = false
+			continue
+		}
+		claims[i].Supported = strings.HasPrefix(strings.ToLower(strings.TrimSpace(response)), "yes")
+	}
+
+	return claims
+}
+
+// supportedText rebuilds the answer text from only the claims still
+// marked Supported, after a groundedness pass.
+func supportedText(claims []Claim) string {
+	var sentences []string
+	for _, c := range claims {
+		if c.Supported {
+			sentences = append(sentences, c.Sentence)
+		}
+	}
+	return strings.Join(sentences, " ")
+}
+
+// confidence averages the fraction of claims with a citation and, if a
+// groundedness pass ran, the fraction marked Supported.
+func confidence(claims []Claim) float64 {
+	if len(claims) == 0 {
+		return 0
+	}
+	cited := 0
+	for _, c := range claims {
+		if len(c.Citations) > 0 {
+			cited++
+		}
+	}
+	return float64(cited) / float64(len(claims))
+}
+
+// wordOverlap returns the Jaccard similarity of a and b's lowercased word
+// sets, a cheap proxy for "does this chunk contain this sentence".
+func wordOverlap(a, b string) float64 {
+	setA := wordSet(a)
+	setB := wordSet(b)
+	if len(setA) == 0 || len(setB) == 0 {
+		return 0
+	}
+
+	intersection := 0
+	for w := range setA {
+		if setB[w] {
+			intersection++
+		}
+	}
+	union := len(setA) + len(setB) - intersection
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
+
+func wordSet(s string) map[string]bool {
+	words := strings.Fields(strings.ToLower(s))
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
@@ -0,0 +1,77 @@
+// Package qa implements document question-answering driven by
+// config.LLMPrompts.DocumentQA: retrieve relevant chunks, assemble them
+// into context, and ask the configured LLM to answer grounded in that
+// context.
+package qa
+
+import "context"
+
+// RetrievedChunk is one chunk returned by a Retriever, with enough
+// information to cite it back in an Answer.
+type RetrievedChunk struct {
+	ChunkID string
+	Content string
+	Score   float64
+}
+
+// Retriever fetches the topK chunks most relevant to query. docflow's
+// search/vector store backends each satisfy this independently; qa has no
+// opinion on which one is wired in.
+type Retriever interface {
+	Retrieve(ctx context.Context, query string, topK int) ([]RetrievedChunk, error)
+}
+
+// Citation points a claim sentence back to the chunk (and, when found
+// verbatim, the character span within that chunk) it was drawn from.
+type Citation struct {
+	ChunkID   string
+	StartChar int
+	EndChar   int
+}
+
+// Claim is one sentence of the generated answer together with the
+// chunk(s) it's grounded in. Supported is only meaningful when
+// LLMConfig.GroundednessCheck is enabled; otherwise it's always true.
+type Claim struct {
+	Sentence  string
+	Citations []Citation
+	Supported bool
+}
+
+// Answer is the result of Engine.Ask.
+type Answer struct {
+	Text       string
+	Claims     []Claim
+	Confidence float64
+	Chunks     []RetrievedChunk
+}
+
+// StreamToken is one increment of a streamed answer. Err is set (and Done
+// is true) if the stream failed; Done is also set, with no Err, on the
+// final token.
+type StreamToken struct {
+	Delta string
+	Done  bool
+	Err   error
+}
+
+// options holds the result of applying Option values to a single Ask call.
+type options struct {
+	topK int
+}
+
+// Option customizes a single Ask/AskStream call.
+type Option func(*options)
+
+// WithTopK overrides RetrievalConfig.TopK for this call.
+func WithTopK(topK int) Option {
+	return func(o *options) { o.topK = topK }
+}
+
+func newOptions(defaultTopK int, opts []Option) options {
+	o := options{topK: defaultTopK}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,343 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BatchMode selects how a Batch* method issues its underlying calls.
+type BatchMode int
+
+const (
+	// BatchModeSync fans calls out across a local worker pool - the
+	// default. Lowest latency, same per-call cost as issuing each call
+	// individually.
+	BatchModeSync BatchMode = iota
+
+	// BatchModeAsync submits the whole batch through the configured
+	// Provider's native batch API (OpenAI's Batch API, Anthropic's
+	// Message Batches) when it implements BatchProvider, trading latency
+	// - results can take up to 24h - for each provider's bulk-pricing
+	// discount (typically ~50% off). Falls back to BatchModeSync when the
+	// provider doesn't implement BatchProvider.
+	BatchModeAsync
+)
+
+// BatchOptions configures BatchDescribeImages/BatchAnalyzeTables/BatchGenerateText.
+type BatchOptions struct {
+	// Concurrency caps how many calls are in flight at once under
+	// BatchModeSync. Defaults to 4.
+	Concurrency int
+
+	// MaxRetries caps how many times a single item is retried, with
+	// exponential backoff and jitter between attempts, after a
+	// retryable (429/5xx) error. Defaults to 3.
+	MaxRetries int
+
+	// Mode selects BatchModeSync or BatchModeAsync. Defaults to
+	// BatchModeSync.
+	Mode BatchMode
+
+	// PollInterval controls how often BatchModeAsync polls the
+	// provider's batch job for completion. Defaults to 30s.
+	PollInterval time.Duration
+
+	// Progress, if set, is called after every item completes (success or
+	// final failure) with a running snapshot of the batch's progress.
+	// Under BatchModeAsync this fires once, after the whole job
+	// completes, since native batch APIs don't report per-item progress.
+	Progress func(BatchProgress)
+}
+
+func (o BatchOptions) withDefaults() BatchOptions {
+	if o.Concurrency <= 0 {
+		o.Concurrency = 4
+	}
+	if o.MaxRetries <= 0 {
+		o.MaxRetries = 3
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = 30 * time.Second
+	}
+	return o
+}
+
+// BatchProgress is a running snapshot of a batch call's progress, passed
+// to BatchOptions.Progress - enough to drive a CLI progress bar.
+type BatchProgress struct {
+	Done, Total int
+	Bytes       int64
+	Tokens      int64
+	Elapsed     time.Duration
+	ETA         time.Duration
+}
+
+// BatchResult is one item's outcome from a Batch* call. Index ties it
+// back to its position in the input slice, since results can complete out
+// of order across a worker pool or a native batch API's output file.
+type BatchResult[T any] struct {
+	Index int
+	Value T
+	Err   error
+}
+
+// BatchProvider is implemented by Providers with a native bulk-submission
+// API, so BatchMode = BatchModeAsync can submit every prompt as one job
+// and poll for results instead of making N individual HTTP calls.
+type BatchProvider interface {
+	// SubmitBatch uploads prompts as a single native batch job and blocks,
+	// polling at pollInterval, until every result is ready or ctx is
+	// cancelled. Results are returned in the same order as prompts, one
+	// BatchResult per prompt.
+	SubmitBatch(ctx context.Context, prompts []string, pollInterval time.Duration) ([]BatchResult[string], error)
+}
+
+// batchBackoff mirrors search.ExponentialBackoff's algorithm (doubling
+// from 500ms up to 30s, with up to 50% jitter so retrying workers don't
+// all retry in lockstep) - duplicated here rather than imported, since
+// the rag and search packages don't otherwise depend on each other.
+func batchBackoff(attempt int) time.Duration {
+	initial, max := 500*time.Millisecond, 30*time.Second
+	d := initial
+	for i := 0; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+// isRetryableBatchError reports whether err looks like a 429/5xx response
+// worth retrying. HTTPError (returned by postJSON/postSSE, and so by
+// every built-in Provider except bedrockProvider) carries the status code
+// directly; bedrockProvider's AWS SDK errors are classified by matching
+// the throttling/server-error exception names the Bedrock Runtime API
+// documents.
+func isRetryableBatchError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == 429 || httpErr.StatusCode >= 500
+	}
+	msg := err.Error()
+	for _, name := range []string{"ThrottlingException", "TooManyRequestsException", "ServiceUnavailableException", "InternalServerException", "ModelTimeoutException"} {
+		if strings.Contains(msg, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// runBatchSync fans call out across opts.Concurrency workers, one per
+// item, retrying an item up to opts.MaxRetries times on a retryable error
+// (see isRetryableBatchError), and reporting running progress through
+// opts.Progress as each item finishes. itemBytes estimates an item's size
+// for BatchProgress.Bytes; it may be nil to leave Bytes at 0.
+func runBatchSync[I, T any](ctx context.Context, items []I, opts BatchOptions, itemBytes func(I) int64, call func(ctx context.Context, item I) (T, int64, error)) []BatchResult[T] {
+	opts = opts.withDefaults()
+	results := make([]BatchResult[T], len(items))
+
+	var mu sync.Mutex
+	done := 0
+	var bytesDone, tokensDone int64
+	start := time.Now()
+
+	reportDone := func(bytes, tokens int64) {
+		mu.Lock()
+		done++
+		bytesDone += bytes
+		tokensDone += tokens
+		d, b, t := done, bytesDone, tokensDone
+		elapsed := time.Since(start)
+		mu.Unlock()
+
+		if opts.Progress == nil {
+			return
+		}
+		var eta time.Duration
+		if d > 0 && d < len(items) {
+			eta = elapsed / time.Duration(d) * time.Duration(len(items)-d)
+		}
+		opts.Progress(BatchProgress{Done: d, Total: len(items), Bytes: b, Tokens: t, Elapsed: elapsed, ETA: eta})
+	}
+
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		i, item := i, item
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			value, tokens, err := callWithRetry(ctx, opts.MaxRetries, item, call)
+			results[i] = BatchResult[T]{Index: i, Value: value, Err: err}
+
+			var b int64
+			if itemBytes != nil {
+				b = itemBytes(item)
+			}
+			reportDone(b, tokens)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// callWithRetry invokes call(ctx, item) up to maxRetries+1 times, waiting
+// batchBackoff(attempt) between attempts, stopping as soon as it succeeds
+// or fails with a non-retryable error.
+func callWithRetry[I, T any](ctx context.Context, maxRetries int, item I, call func(ctx context.Context, item I) (T, int64, error)) (T, int64, error) {
+	var value T
+	var tokens int64
+	var err error
+	for attempt := 0; ; attempt++ {
+		value, tokens, err = call(ctx, item)
+		if err == nil || attempt >= maxRetries || !isRetryableBatchError(err) {
+			return value, tokens, err
+		}
+		select {
+		case <-time.After(batchBackoff(attempt)):
+		case <-ctx.Done():
+			return value, tokens, ctx.Err()
+		}
+	}
+}
+
+// BatchDescribeImages runs DescribeImageContext over images concurrently,
+// per opts (see BatchOptions), returning one BatchResult per image in
+// input order. None of the built-in Providers' native batch APIs are
+// wired for multimodal content here, so opts.Mode is always treated as
+// BatchModeSync for this method regardless of what's configured.
+func (p *LLMProcessor) BatchDescribeImages(ctx context.Context, images []ExtractedImage, opts BatchOptions) ([]BatchResult[string], error) {
+	if p.providerErr != nil {
+		return nil, p.providerErr
+	}
+	results := runBatchSync(ctx, images, opts,
+		func(img ExtractedImage) int64 { return int64(len(img.Data)) },
+		func(ctx context.Context, img ExtractedImage) (string, int64, error) {
+			prompt := p.buildImagePrompt(img, "")
+			text, err := p.callVisionAPI(ctx, img.Data, img.Format, prompt)
+			return text, int64(p.tokenizer.Count(prompt)), err
+		})
+	return results, nil
+}
+
+// BatchAnalyzeTables runs AnalyzeTableContext over tables concurrently
+// (BatchModeSync) or through the provider's native batch API
+// (BatchModeAsync, when supported - see BatchProvider), returning one
+// BatchResult per table in input order.
+func (p *LLMProcessor) BatchAnalyzeTables(ctx context.Context, tables []ExtractedTable, docContext string, opts BatchOptions) ([]BatchResult[string], error) {
+	if p.providerErr != nil {
+		return nil, p.providerErr
+	}
+	prompts := make([]string, len(tables))
+	for i, table := range tables {
+		prompts[i] = p.tableAnalysisPrompt(table, docContext)
+	}
+	return p.batchPrompts(ctx, prompts, opts)
+}
+
+// BatchGenerateText runs GenerateTextContext over prompts concurrently
+// (BatchModeSync) or through the provider's native batch API
+// (BatchModeAsync, when supported - see BatchProvider), returning one
+// BatchResult per prompt in input order.
+func (p *LLMProcessor) BatchGenerateText(ctx context.Context, prompts []string, opts BatchOptions) ([]BatchResult[string], error) {
+	if p.providerErr != nil {
+		return nil, p.providerErr
+	}
+	return p.batchPrompts(ctx, prompts, opts)
+}
+
+// batchPrompts is BatchAnalyzeTables/BatchGenerateText's shared
+// implementation: a plain []string of prompts in, one BatchResult per
+// prompt out.
+func (p *LLMProcessor) batchPrompts(ctx context.Context, prompts []string, opts BatchOptions) ([]BatchResult[string], error) {
+	opts = opts.withDefaults()
+
+	if opts.Mode == BatchModeAsync {
+		if bp, ok := p.provider.(BatchProvider); ok {
+			results, err := bp.SubmitBatch(ctx, prompts, opts.PollInterval)
+			if err != nil {
+				return nil, err
+			}
+			if opts.Progress != nil {
+				var tokens int64
+				for _, prompt := range prompts {
+					tokens += int64(p.tokenizer.Count(prompt))
+				}
+				opts.Progress(BatchProgress{Done: len(prompts), Total: len(prompts), Tokens: tokens})
+			}
+			return results, nil
+		}
+		// Falls through to BatchModeSync - the configured Provider
+		// doesn't implement BatchProvider.
+	}
+
+	return runBatchSync(ctx, prompts, opts,
+		func(prompt string) int64 { return int64(len(prompt)) },
+		func(ctx context.Context, prompt string) (string, int64, error) {
+			text, err := p.callTextAPI(ctx, prompt)
+			return text, int64(p.tokenizer.Count(prompt)), err
+		}), nil
+}
+
+// tableAnalysisPrompt is AnalyzeTableContext's prompt-building logic,
+// factored out so BatchAnalyzeTables can build every table's prompt up
+// front and hand the plain []string to batchPrompts/BatchProvider.
+func (p *LLMProcessor) tableAnalysisPrompt(table ExtractedTable, docContext string) string {
+	tableMD := p.tableToMarkdown(table)
+	return fmt.Sprintf(`Analyze this table and provide:
+1. Brief summary of what the table contains
+2. Key insights or patterns
+3. Important data points
+
+Table:
+%s
+
+Context: %s`, tableMD, docContext)
+}
+
+// EnhanceMetadataContextConcurrent is EnhanceMetadataContext, but issues
+// its three independent calls (summary, key points, entities) concurrently
+// instead of sequentially - worthwhile once a RateLimiter/UsageMeter is
+// configured to make the concurrency safe, or when running over many
+// documents where the extra latency of doing these three calls back to
+// back adds up.
+func (p *LLMProcessor) EnhanceMetadataContextConcurrent(ctx context.Context, meta DocumentMetadata, content string) (DocumentMetadata, error) {
+	var wg sync.WaitGroup
+	var summary string
+	var keyPoints, entities []string
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		summary, _ = p.GenerateSummaryContext(ctx, content, 500)
+	}()
+	go func() {
+		defer wg.Done()
+		keyPoints, _ = p.ExtractKeyPointsContext(ctx, content, 5)
+	}()
+	go func() {
+		defer wg.Done()
+		entities, _ = p.ExtractEntitiesContext(ctx, content)
+	}()
+	wg.Wait()
+
+	meta.Summary = summary
+	meta.KeyPoints = keyPoints
+	meta.Entities = entities
+	return meta, nil
+}
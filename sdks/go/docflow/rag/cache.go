@@ -0,0 +1,209 @@
+package rag
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ResponseCache is implemented by anything LLMProcessor can use to skip an
+// HTTP round-trip for a call it's already made. Configure one via
+// WithCache/WithSemanticCache; BoltResponseCache is the built-in,
+// disk-backed implementation. AnalyzeImageForRAG and ExtractTableData are
+// the typical beneficiaries - both are normally run once per document
+// element and re-run unchanged every time a pipeline is re-executed.
+type ResponseCache interface {
+	// Get returns the cached response for key, and whether one was found.
+	Get(ctx context.Context, key CacheKey) (string, bool, error)
+	// Put stores response under key for future Get calls.
+	Put(ctx context.Context, key CacheKey, response string) error
+	// Semantic reports whether this cache does cosine-nearest embedding
+	// matching rather than exact-key matching - callers use this to
+	// decide whether computing CacheKey.Embedding is worth the extra
+	// Provider.Embed call.
+	Semantic() bool
+	// Close releases any resources (file handles, connections) the cache
+	// holds.
+	Close() error
+}
+
+// CacheKey identifies one LLM call for caching purposes.
+type CacheKey struct {
+	Provider    string
+	Model       string
+	Temperature float64
+	Prompt      string
+	ImageData   []byte
+
+	// Embedding, when non-nil, lets a semantic ResponseCache match this
+	// key against the nearest previously cached one within its
+	// threshold instead of requiring an exact Prompt match - so a
+	// paraphrased prompt ("summarize this" vs. "give me a summary of
+	// this") still hits the cache.
+	Embedding []float32
+}
+
+// scope groups cache entries that are meaningfully comparable: the same
+// provider, model, and temperature. Comparing embeddings or exact prompts
+// across different scopes wouldn't be meaningful even if the text happens
+// to match.
+func (k CacheKey) scope() string {
+	return fmt.Sprintf("%s\x00%s\x00%x", k.Provider, k.Model, math.Float64bits(k.Temperature))
+}
+
+// hash returns the exact-match cache key: a hex SHA-256 over scope,
+// prompt, and image bytes.
+func (k CacheKey) hash() string {
+	h := sha256.New()
+	h.Write([]byte(k.scope()))
+	h.Write([]byte{0})
+	h.Write([]byte(k.Prompt))
+	h.Write([]byte{0})
+	h.Write(k.ImageData)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+var (
+	cacheBucket    = []byte("responses")
+	semanticBucket = []byte("semantic")
+)
+
+// cacheEntry is what's stored under CacheKey.hash() in cacheBucket.
+type cacheEntry struct {
+	Response string `json:"response"`
+}
+
+// semanticEntry is additionally stored in semanticBucket whenever a
+// CacheKey carries an Embedding, so getSemantic can scan it independently
+// of the exact-match hash.
+type semanticEntry struct {
+	Scope     string    `json:"scope"`
+	Embedding []float32 `json:"embedding"`
+	Response  string    `json:"response"`
+}
+
+// BoltResponseCache is a ResponseCache backed by a local BoltDB
+// (go.etcd.io/bbolt) file, so cached responses survive process restarts -
+// the common case while iterating on a pipeline's prompts or document
+// handling.
+type BoltResponseCache struct {
+	db                *bolt.DB
+	semanticThreshold float64 // cosine distance; 0 disables semantic matching
+}
+
+// NewBoltResponseCache opens (creating if necessary) a BoltDB file at
+// path for use as an LLMProcessor ResponseCache, with exact-match-only
+// semantics. Use NewSemanticBoltResponseCache for cosine-nearest matching.
+func NewBoltResponseCache(path string) (*BoltResponseCache, error) {
+	return NewSemanticBoltResponseCache(path, 0)
+}
+
+// NewSemanticBoltResponseCache is NewBoltResponseCache plus semantic
+// matching: a call whose CacheKey.Embedding is within semanticThreshold
+// cosine distance (0..2, lower is more similar; a typical threshold is
+// around 0.05-0.1) of a previously cached call in the same scope counts
+// as a hit too.
+func NewSemanticBoltResponseCache(path string, semanticThreshold float64) (*BoltResponseCache, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("opening response cache %q: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(cacheBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(semanticBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("initializing response cache %q: %w", path, err)
+	}
+	return &BoltResponseCache{db: db, semanticThreshold: semanticThreshold}, nil
+}
+
+// Semantic implements ResponseCache.
+func (c *BoltResponseCache) Semantic() bool {
+	return c.semanticThreshold > 0
+}
+
+// Get implements ResponseCache.
+func (c *BoltResponseCache) Get(ctx context.Context, key CacheKey) (string, bool, error) {
+	if len(key.Embedding) > 0 && c.Semantic() {
+		return c.getSemantic(key)
+	}
+	return c.getExact(key)
+}
+
+func (c *BoltResponseCache) getExact(key CacheKey) (string, bool, error) {
+	var response string
+	var found bool
+	err := c.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(cacheBucket).Get([]byte(key.hash()))
+		if v == nil {
+			return nil
+		}
+		var entry cacheEntry
+		if err := json.Unmarshal(v, &entry); err != nil {
+			return err
+		}
+		response, found = entry.Response, true
+		return nil
+	})
+	return response, found, err
+}
+
+func (c *BoltResponseCache) getSemantic(key CacheKey) (string, bool, error) {
+	var response string
+	var found bool
+	best := c.semanticThreshold
+	scope := key.scope()
+
+	err := c.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(semanticBucket).ForEach(func(_, v []byte) error {
+			var entry semanticEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			if entry.Scope != scope {
+				return nil
+			}
+			if d := cosineDistance(key.Embedding, entry.Embedding); d <= best {
+				best, found, response = d, true, entry.Response
+			}
+			return nil
+		})
+	})
+	return response, found, err
+}
+
+// Put implements ResponseCache.
+func (c *BoltResponseCache) Put(ctx context.Context, key CacheKey, response string) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		data, err := json.Marshal(cacheEntry{Response: response})
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(cacheBucket).Put([]byte(key.hash()), data); err != nil {
+			return err
+		}
+		if len(key.Embedding) == 0 {
+			return nil
+		}
+		semData, err := json.Marshal(semanticEntry{Scope: key.scope(), Embedding: key.Embedding, Response: response})
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(semanticBucket).Put([]byte(key.hash()), semData)
+	})
+}
+
+// Close implements ResponseCache.
+func (c *BoltResponseCache) Close() error {
+	return c.db.Close()
+}
@@ -1,23 +1,56 @@
 package rag
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"regexp"
 	"strings"
 
+	"github.com/xgaslan/docflow/sdks/go/docflow/cache/memcache"
 	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+	"github.com/xgaslan/docflow/sdks/go/docflow/tokenizer"
 )
 
 // Chunker splits text into chunks based on configuration.
 type Chunker struct {
 	Config config.RAGConfig
+
+	// Cache, if set, memoizes Chunk output by content hash + config.
+	Cache *memcache.Cache
+
+	// tok is always resolved (defaulting to "whitespace"), so
+	// ChunkMetadata.TokenCount is populated regardless of ChunkSizeUnit;
+	// it's only used for sizing decisions when ChunkSizeUnit is "tokens".
+	tok tokenizer.Tokenizer
 }
 
-// NewChunker creates a new chunker.
-func NewChunker(config config.RAGConfig) *Chunker {
+// NewChunker creates a new chunker. If cfg.Tokenizer names an unknown
+// tokenizer, NewChunker falls back to "whitespace" rather than failing -
+// chunking should degrade gracefully, not break document ingestion over
+// a bad config value.
+func NewChunker(cfg config.RAGConfig) *Chunker {
+	name := cfg.Tokenizer
+	if name == "" {
+		name = "whitespace"
+	}
+	tok, err := tokenizer.Get(name)
+	if err != nil {
+		tok, _ = tokenizer.Get("whitespace")
+	}
 	return &Chunker{
-		Config: config,
+		Config: cfg,
+		tok:    tok,
+	}
+}
+
+// size returns the length of s in whatever unit Config.ChunkSizeUnit
+// selects: characters (the default) or tokens, via c.tok.
+func (c *Chunker) size(s string) int {
+	if c.Config.ChunkSizeUnit == config.ChunkSizeUnitTokens {
+		return c.tok.CountTokens(s)
 	}
+	return len(s)
 }
 
 // Chunk represents a text chunk.
@@ -25,6 +58,28 @@ func NewChunker(config config.RAGConfig) *Chunker {
 
 // Chunk splits markdown content into RAG-optimized chunks.
 func (c *Chunker) Chunk(markdown string) []Chunk {
+	if c.Cache == nil {
+		return c.chunk(markdown)
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d|%d|%v|%s|%s|%s", c.Config.ChunkSize, c.Config.ChunkOverlap, c.Config.RespectHeadings, c.Config.ChunkSizeUnit, c.Config.Tokenizer, markdown)))
+	key := "chunks:" + hex.EncodeToString(sum[:])
+
+	value, err := c.Cache.GetOrCreate(key, func() (any, int64, error) {
+		chunks := c.chunk(markdown)
+		size := int64(0)
+		for _, ch := range chunks {
+			size += int64(len(ch.Content))
+		}
+		return chunks, size, nil
+	})
+	if err != nil {
+		return c.chunk(markdown)
+	}
+	return value.([]Chunk)
+}
+
+func (c *Chunker) chunk(markdown string) []Chunk {
 	// Extract frontmatter
 	content, _ := c.extractFrontmatter(markdown)
 
@@ -144,7 +199,7 @@ func (c *Chunker) chunkSection(content, sectionTitle string, startIndex, charOff
 			}
 			block := strings.Join(blockLines, "\n")
 
-			if len(currentChunk)+len(block) > c.Config.ChunkSize && currentChunk != "" {
+			if c.size(currentChunk)+c.size(block) > c.Config.ChunkSize && currentChunk != "" {
 				chunks = append(chunks, c.createChunk(
 					strings.TrimSpace(currentChunk),
 					chunkIdx,
@@ -171,7 +226,7 @@ func (c *Chunker) chunkSection(content, sectionTitle string, startIndex, charOff
 			}
 			block := strings.Join(tableLines, "\n")
 
-			if len(currentChunk)+len(block) > c.Config.ChunkSize && currentChunk != "" {
+			if c.size(currentChunk)+c.size(block) > c.Config.ChunkSize && currentChunk != "" {
 				chunks = append(chunks, c.createChunk(
 					strings.TrimSpace(currentChunk),
 					chunkIdx,
@@ -188,7 +243,7 @@ func (c *Chunker) chunkSection(content, sectionTitle string, startIndex, charOff
 		}
 
 		// Regular line
-		if len(currentChunk)+len(line) > c.Config.ChunkSize && currentChunk != "" {
+		if c.size(currentChunk)+c.size(line) > c.Config.ChunkSize && currentChunk != "" {
 			chunks = append(chunks, c.createChunk(
 				strings.TrimSpace(currentChunk),
 				chunkIdx,
@@ -244,6 +299,7 @@ func (c *Chunker) createChunk(content string, index, startChar, endChar int, sec
 			HasImage:     hasImage,
 			HasCode:      hasCode,
 			ContentType:  contentType,
+			TokenCount:   c.tok.CountTokens(content),
 		},
 	}
 }
@@ -271,10 +327,7 @@ func (c *Chunker) addOverlap(chunks []Chunk, _ string) []Chunk {
 		content := prevChunk.Content
 
 		// Get overlap from end of previous chunk
-		overlapText := content
-		if len(content) > overlapSize {
-			overlapText = content[len(content)-overlapSize:]
-		}
+		overlapText := c.overlapTail(content, overlapSize)
 
 		// Find a good break point
 		for _, breakStr := range []string{"\n\n", ". ", "\n"} {
@@ -293,6 +346,25 @@ func (c *Chunker) addOverlap(chunks []Chunk, _ string) []Chunk {
 	return chunks
 }
 
+// overlapTail returns the last overlapSize units of content - characters
+// by default, or tokens (walked back via c.tok.Encode/Decode) when
+// Config.ChunkSizeUnit is "tokens" - for addOverlap to then snap to a
+// sentence/paragraph boundary.
+func (c *Chunker) overlapTail(content string, overlapSize int) string {
+	if c.Config.ChunkSizeUnit != config.ChunkSizeUnitTokens {
+		if len(content) > overlapSize {
+			return content[len(content)-overlapSize:]
+		}
+		return content
+	}
+
+	tokens := c.tok.Encode(content)
+	if len(tokens) > overlapSize {
+		tokens = tokens[len(tokens)-overlapSize:]
+	}
+	return c.tok.Decode(tokens)
+}
+
 func (c *Chunker) countChars(lines []string) int {
 	total := 0
 	for _, line := range lines {
@@ -0,0 +1,65 @@
+package rag
+
+import (
+	"sync"
+	"time"
+)
+
+// deadline implements a resettable cancellation point, the same pattern
+// net.Conn implementations use internally for SetDeadline: a
+// mutex-guarded time.AfterFunc timer and a channel that's closed when it
+// fires, so a blocked caller can select on Done() instead of polling
+// Time.Now() against a stored deadline. LLMProcessor keeps one so
+// pipeline code can apply a single document-wide budget across many
+// GenerateText/StreamText calls via SetDeadline, instead of being
+// limited to the fixed per-call http.Client.Timeout set at construction.
+type deadline struct {
+	mu    sync.Mutex
+	timer *time.Timer
+	done  chan struct{}
+}
+
+func newDeadline() *deadline {
+	return &deadline{done: make(chan struct{})}
+}
+
+// Set stops any pending timer, replaces the Done channel if the old one
+// already fired, and schedules a new AfterFunc that closes it. A zero t
+// disables the deadline; a t already in the past closes the channel
+// immediately.
+func (d *deadline) Set(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+
+	select {
+	case <-d.done:
+		d.done = make(chan struct{})
+	default:
+	}
+
+	if t.IsZero() {
+		return
+	}
+
+	if dur := time.Until(t); dur <= 0 {
+		close(d.done)
+		return
+	}
+
+	done := d.done
+	d.timer = time.AfterFunc(time.Until(t), func() { close(done) })
+}
+
+// Done returns the channel that closes when the deadline fires. Safe to
+// call concurrently with Set - each call returns whichever channel is
+// current as of that call.
+func (d *deadline) Done() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.done
+}
@@ -1,47 +1,153 @@
 package rag
 
 import (
-	"bytes"
-	"encoding/base64"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
 	"time"
 
 	"github.com/xgaslan/docflow/sdks/go/docflow/config"
 )
 
-// LLMProcessor handles unified LLM processing for images, tables, and text.
+// LLMProcessor handles unified LLM processing for images, tables, and
+// text. It's a thin orchestrator: the actual wire-format work lives in a
+// Provider, resolved from Config.Provider via NewProvider at construction
+// time (see provider.go and the provider_*.go files for the built-ins).
 type LLMProcessor struct {
 	Config config.LLMConfig
-	client *http.Client
+
+	provider    Provider
+	providerErr error
+	tokenizer   Tokenizer
+
+	// rateLimiter and usage are both optional, set via WithRateLimiter /
+	// WithUsageMeter - nil means the corresponding check/recording is
+	// skipped entirely.
+	rateLimiter *RateLimiter
+	usage       *UsageMeter
+
+	// cache is optional, set via WithCache/WithSemanticCache - nil means
+	// every call goes straight to the provider.
+	cache ResponseCache
+
+	// deadline backs SetDeadline, letting pipeline code cap the total
+	// time spent across many calls instead of only the fixed per-call
+	// client.Timeout each Provider constructs itself with.
+	deadline *deadline
 }
 
-// NewLLMProcessor creates a new LLM processor.
-func NewLLMProcessor(cfg config.LLMConfig) *LLMProcessor {
-	timeout := time.Duration(cfg.Timeout) * time.Second
-	if timeout == 0 {
-		timeout = 60 * time.Second
-	}
+// LLMOption configures optional LLMProcessor behavior not driven by
+// config.LLMConfig - a RateLimiter or UsageMeter shared across several
+// processors, for instance.
+type LLMOption func(*LLMProcessor)
+
+// WithRateLimiter makes p wait on rl before every provider call.
+func WithRateLimiter(rl *RateLimiter) LLMOption {
+	return func(p *LLMProcessor) { p.rateLimiter = rl }
+}
 
-	return &LLMProcessor{
-		Config: cfg,
-		client: &http.Client{Timeout: timeout},
+// WithUsageMeter makes p record prompt/completion tokens and estimated
+// cost for every successful call into m, retrievable via p.Usage().
+func WithUsageMeter(m *UsageMeter) LLMOption {
+	return func(p *LLMProcessor) { p.usage = m }
+}
+
+// WithCache makes p consult cache before every text/vision/structured
+// call and populate it after a successful one, so pipelines that re-run
+// unmodified (the common case while iterating on prompts or document
+// handling) skip the HTTP round-trip entirely on a repeat run.
+func WithCache(cache ResponseCache) LLMOption {
+	return func(p *LLMProcessor) { p.cache = cache }
+}
+
+// NewLLMProcessor creates a new LLM processor. Provider construction
+// errors (e.g. a misconfigured or unknown Config.Provider) aren't
+// returned here - they're surfaced lazily from the first call, matching
+// this type's pre-existing no-error constructor signature and its
+// existing "unsupported provider: %s" call-time error.
+func NewLLMProcessor(cfg config.LLMConfig, opts ...LLMOption) *LLMProcessor {
+	provider, err := NewProvider(string(cfg.Provider), cfg)
+	p := &LLMProcessor{
+		Config:      cfg,
+		provider:    provider,
+		providerErr: err,
+		tokenizer:   tokenizerForProvider(cfg.Provider),
+		deadline:    newDeadline(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Usage returns the running token/cost total recorded by the UsageMeter
+// configured via WithUsageMeter, or a zero Usage if none was configured.
+func (p *LLMProcessor) Usage() Usage {
+	if p.usage == nil {
+		return Usage{}
 	}
+	return p.usage.Snapshot()
+}
+
+// SetDeadline arms (or, with a zero t, disables) a document-wide budget
+// that every subsequent call - GenerateText, StreamText, and the rest -
+// is cancelled by once it passes, on top of whatever ctx each call is
+// given individually. A t already in the past cancels any in-flight call
+// immediately.
+func (p *LLMProcessor) SetDeadline(t time.Time) {
+	p.deadline.Set(t)
+}
+
+// withDeadline returns a context that's cancelled when either ctx itself
+// is cancelled or p's deadline fires, and a cancel func the caller must
+// invoke to release the goroutine watching for that - mirroring how a
+// net.Conn's SetDeadline unblocks a pending Read without the caller
+// having to poll it.
+func (p *LLMProcessor) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := p.deadline.Done()
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
 }
 
 // ============== Image Processing ==============
 
 // DescribeImage generates a description for an image.
-func (p *LLMProcessor) DescribeImage(image ExtractedImage, context string) (string, error) {
-	prompt := p.buildImagePrompt(image, context)
-	return p.callVisionAPI(image.Data, image.Format, prompt)
+func (p *LLMProcessor) DescribeImage(image ExtractedImage, docContext string) (string, error) {
+	return p.DescribeImageContext(context.Background(), image, docContext)
+}
+
+// DescribeImageContext is DescribeImage with a caller-supplied context for
+// cancelling the underlying request mid-flight.
+func (p *LLMProcessor) DescribeImageContext(ctx context.Context, image ExtractedImage, docContext string) (string, error) {
+	prompt := p.buildImagePrompt(image, docContext)
+	return p.callVisionAPI(ctx, image.Data, image.Format, prompt)
 }
 
 // AnalyzeImageForRAG performs full RAG analysis on an image.
 func (p *LLMProcessor) AnalyzeImageForRAG(image ExtractedImage) (map[string]interface{}, error) {
+	return p.AnalyzeImageForRAGContext(context.Background(), image)
+}
+
+// imageAnalysis is AnalyzeImageForRAG's structured result shape, schema-
+// checked against the model's response instead of best-effort parsed.
+type imageAnalysis struct {
+	Description    string   `json:"description"`
+	KeyInformation []string `json:"key_information"`
+	Entities       []string `json:"entities"`
+	Context        string   `json:"context"`
+	DataExtraction string   `json:"data_extraction"`
+}
+
+// AnalyzeImageForRAGContext is AnalyzeImageForRAG with a caller-supplied
+// context for cancelling the underlying request mid-flight.
+func (p *LLMProcessor) AnalyzeImageForRAGContext(ctx context.Context, image ExtractedImage) (map[string]interface{}, error) {
 	prompt := `Analyze this image for RAG (Retrieval-Augmented Generation):
 
 1. **Description**: Detailed description of the image content
@@ -52,76 +158,97 @@ func (p *LLMProcessor) AnalyzeImageForRAG(image ExtractedImage) (map[string]inte
 
 Respond in JSON format.`
 
-	response, err := p.callVisionAPI(image.Data, image.Format, prompt)
-	if err != nil {
+	var result imageAnalysis
+	if err := p.callStructuredVision(ctx, image.Data, image.Format, prompt, imageAnalysis{}, &result); err != nil {
 		return nil, err
 	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal([]byte(extractJSON(response)), &result); err != nil {
-		// Fallback if JSON parsing fails
-		return map[string]interface{}{
-			"description":     response,
-			"key_information": []string{},
-			"entities":        []string{},
-		}, nil
-	}
-	return result, nil
+	return map[string]interface{}{
+		"description":     result.Description,
+		"key_information": result.KeyInformation,
+		"entities":        result.Entities,
+		"context":         result.Context,
+		"data_extraction": result.DataExtraction,
+	}, nil
 }
 
 // ============== Table Processing ==============
 
 // AnalyzeTable generates analysis/summary for a table.
-func (p *LLMProcessor) AnalyzeTable(table ExtractedTable, context string) (string, error) {
-	tableMD := p.tableToMarkdown(table)
-	prompt := fmt.Sprintf(`Analyze this table and provide:
-1. Brief summary of what the table contains
-2. Key insights or patterns
-3. Important data points
-
-Table:
-%s
-
-Context: %s`, tableMD, context)
+func (p *LLMProcessor) AnalyzeTable(table ExtractedTable, docContext string) (string, error) {
+	return p.AnalyzeTableContext(context.Background(), table, docContext)
+}
 
-	return p.callTextAPI(prompt)
+// AnalyzeTableContext is AnalyzeTable with a caller-supplied context for
+// cancelling the underlying request mid-flight.
+func (p *LLMProcessor) AnalyzeTableContext(ctx context.Context, table ExtractedTable, docContext string) (string, error) {
+	return p.callTextAPI(ctx, p.tableAnalysisPrompt(table, docContext))
 }
 
 // ExtractTableData extracts structured data from a table.
 func (p *LLMProcessor) ExtractTableData(table ExtractedTable) (map[string]interface{}, error) {
+	return p.ExtractTableDataContext(context.Background(), table)
+}
+
+// tableData is ExtractTableData's structured result shape, schema-checked
+// against the model's response instead of best-effort parsed.
+type tableData struct {
+	Summary    string                 `json:"summary"`
+	Columns    []string               `json:"columns"`
+	KeyValues  map[string]interface{} `json:"key_values"`
+	Statistics map[string]interface{} `json:"statistics"`
+	Trends     []string               `json:"trends"`
+	Entities   []string               `json:"entities"`
+}
+
+// ExtractTableDataContext is ExtractTableData with a caller-supplied
+// context for cancelling the underlying request mid-flight.
+func (p *LLMProcessor) ExtractTableDataContext(ctx context.Context, table ExtractedTable) (map[string]interface{}, error) {
 	tableMD := p.tableToMarkdown(table)
 	prompt := fmt.Sprintf(`Extract structured information from this table:
 
 %s
 
-Respond with JSON containing:
-{
-    "summary": "Brief table summary",
-    "columns": ["column descriptions"],
-    "key_values": {"important": "values"},
-    "statistics": {"if applicable": "stats"},
-    "trends": ["observed patterns"],
-    "entities": ["mentioned entities"]
-}`, tableMD)
-
-	response, err := p.callTextAPI(prompt)
-	if err != nil {
-		return nil, err
-	}
+Include a brief summary, the column descriptions, any important
+key/value pairs or statistics, observed trends, and mentioned entities.`, tableMD)
 
-	var result map[string]interface{}
-	if err := json.Unmarshal([]byte(extractJSON(response)), &result); err != nil {
-		return map[string]interface{}{"summary": response}, nil
+	var result tableData
+	if err := p.CallStructuredContext(ctx, prompt, tableData{}, &result); err != nil {
+		return nil, err
 	}
-	return result, nil
+	return map[string]interface{}{
+		"summary":    result.Summary,
+		"columns":    result.Columns,
+		"key_values": result.KeyValues,
+		"statistics": result.Statistics,
+		"trends":     result.Trends,
+		"entities":   result.Entities,
+	}, nil
 }
 
 // ============== Text Processing ==============
 
+// Token budgets for the content passed into the prompts below, enforced
+// via prepareContent instead of the byte-slice truncation this package
+// used to do (which could split a multi-byte UTF-8 rune and didn't track
+// any real model's token budget).
+const (
+	maxSummaryInputTokens   = 4000
+	maxKeyPointsInputTokens = 4000
+	maxEntitiesInputTokens  = 3000
+	mapReduceChunkTokens    = 2000
+)
+
 // GenerateSummary generates a summary of the content.
 func (p *LLMProcessor) GenerateSummary(content string, maxLength int) (string, error) {
-	if len(content) > 8000 {
-		content = content[:8000]
+	return p.GenerateSummaryContext(context.Background(), content, maxLength)
+}
+
+// GenerateSummaryContext is GenerateSummary with a caller-supplied
+// context for cancelling the underlying request mid-flight.
+func (p *LLMProcessor) GenerateSummaryContext(ctx context.Context, content string, maxLength int) (string, error) {
+	content, err := p.prepareContent(ctx, content, maxSummaryInputTokens)
+	if err != nil {
+		return "", err
 	}
 	prompt := fmt.Sprintf(`Summarize the following content in %d characters or less.
 Focus on the main points and key information.
@@ -129,91 +256,207 @@ Focus on the main points and key information.
 Content:
 %s`, maxLength, content)
 
-	return p.callTextAPI(prompt)
+	return p.callTextAPI(ctx, prompt)
 }
 
 // ExtractKeyPoints extracts key points from content.
 func (p *LLMProcessor) ExtractKeyPoints(content string, maxPoints int) ([]string, error) {
-	if len(content) > 8000 {
-		content = content[:8000]
+	return p.ExtractKeyPointsContext(context.Background(), content, maxPoints)
+}
+
+// ExtractKeyPointsContext is ExtractKeyPoints with a caller-supplied
+// context for cancelling the underlying request mid-flight.
+func (p *LLMProcessor) ExtractKeyPointsContext(ctx context.Context, content string, maxPoints int) ([]string, error) {
+	content, err := p.prepareContent(ctx, content, maxKeyPointsInputTokens)
+	if err != nil {
+		return []string{}, err
 	}
 	prompt := fmt.Sprintf(`Extract the %d most important key points from this content.
-Return as a JSON array of strings.
 
 Content:
 %s`, maxPoints, content)
 
-	response, err := p.callTextAPI(prompt)
-	if err != nil {
+	var points []string
+	if err := p.CallStructuredContext(ctx, prompt, []string{}, &points); err != nil {
 		return []string{}, err
 	}
-
-	var result []string
-	if err := json.Unmarshal([]byte(extractJSON(response)), &result); err != nil {
-		// Fallback line splitting
-		lines := strings.Split(response, "\n")
-		var points []string
-		for _, line := range lines {
-			line = strings.TrimSpace(strings.TrimLeft(line, "- â€¢0123456789."))
-			if line != "" {
-				points = append(points, line)
-			}
-		}
-		if len(points) > maxPoints {
-			points = points[:maxPoints]
-		}
-		return points, nil
+	if len(points) > maxPoints {
+		points = points[:maxPoints]
 	}
-	return result, nil
+	return points, nil
 }
 
 // ExtractEntities extracts named entities.
 func (p *LLMProcessor) ExtractEntities(content string) ([]string, error) {
-	if len(content) > 6000 {
-		content = content[:6000]
+	return p.ExtractEntitiesContext(context.Background(), content)
+}
+
+// ExtractEntitiesContext is ExtractEntities with a caller-supplied
+// context for cancelling the underlying request mid-flight.
+func (p *LLMProcessor) ExtractEntitiesContext(ctx context.Context, content string) ([]string, error) {
+	content, err := p.prepareContent(ctx, content, maxEntitiesInputTokens)
+	if err != nil {
+		return []string{}, err
 	}
 	prompt := `Extract all named entities from this content.
 Include: people, organizations, locations, products, dates, numbers.
-Return as a JSON array of strings.
 
 Content:
 ` + content
 
-	response, err := p.callTextAPI(prompt)
-	if err != nil {
+	var entities []string
+	if err := p.CallStructuredContext(ctx, prompt, []string{}, &entities); err != nil {
 		return []string{}, err
 	}
+	return entities, nil
+}
+
+// prepareContent returns content unchanged if it already fits within
+// maxTokens per p's Tokenizer, otherwise reduces it to a map-reduce
+// summary that does: splitting on token boundaries instead of the fixed
+// byte-offset truncation this package used to apply, which could split a
+// multi-byte UTF-8 rune and didn't track any real model's context budget.
+func (p *LLMProcessor) prepareContent(ctx context.Context, content string, maxTokens int) (string, error) {
+	if p.tokenizer.Count(content) <= maxTokens {
+		return content, nil
+	}
+	return p.mapReduceSummarize(ctx, content, maxTokens)
+}
+
+// mapReduceSummarize reduces content to at most maxTokens tokens by
+// splitting it into mapReduceChunkTokens-sized pieces, summarizing each
+// independently (the "map" phase), joining the per-chunk summaries, and
+// recursing on the join if it's still over budget (the "reduce" phase).
+func (p *LLMProcessor) mapReduceSummarize(ctx context.Context, content string, maxTokens int) (string, error) {
+	chunks := p.tokenizer.Split(content, mapReduceChunkTokens)
+	summaries := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		prompt := fmt.Sprintf(`Summarize the following excerpt concisely, preserving its
+key facts, figures, and named entities - this summary will be combined
+with summaries of other excerpts from the same document.
+
+Excerpt:
+%s`, chunk)
+		summary, err := p.callTextAPI(ctx, prompt)
+		if err != nil {
+			return "", fmt.Errorf("map-reduce summarization: chunk %d/%d: %w", i+1, len(chunks), err)
+		}
+		summaries[i] = summary
+	}
 
-	var result []string
-	if err := json.Unmarshal([]byte(extractJSON(response)), &result); err != nil {
-		return []string{}, nil
+	joined := strings.Join(summaries, "\n\n")
+	if p.tokenizer.Count(joined) <= maxTokens || len(chunks) == 1 {
+		return joined, nil
 	}
-	return result, nil
+	return p.mapReduceSummarize(ctx, joined, maxTokens)
 }
 
 // EnhanceMetadata enhances document metadata using LLM.
 func (p *LLMProcessor) EnhanceMetadata(meta DocumentMetadata, content string) (DocumentMetadata, error) {
-	summary, _ := p.GenerateSummary(content, 500)
+	return p.EnhanceMetadataContext(context.Background(), meta, content)
+}
+
+// EnhanceMetadataContext is EnhanceMetadata with a caller-supplied
+// context for cancelling the underlying requests mid-flight.
+func (p *LLMProcessor) EnhanceMetadataContext(ctx context.Context, meta DocumentMetadata, content string) (DocumentMetadata, error) {
+	summary, _ := p.GenerateSummaryContext(ctx, content, 500)
 	meta.Summary = summary
 
-	keyPoints, _ := p.ExtractKeyPoints(content, 5)
+	keyPoints, _ := p.ExtractKeyPointsContext(ctx, content, 5)
 	meta.KeyPoints = keyPoints
 
-	entities, _ := p.ExtractEntities(content)
+	entities, _ := p.ExtractEntitiesContext(ctx, content)
 	meta.Entities = entities
 
 	return meta, nil
 }
 
+// GenerateText sends prompt to the configured provider and returns the raw
+// completion, for callers (e.g. qa.Engine) that build their own prompts
+// instead of using one of the task-specific methods above.
+func (p *LLMProcessor) GenerateText(prompt string) (string, error) {
+	return p.GenerateTextContext(context.Background(), prompt)
+}
+
+// GenerateTextContext is GenerateText with a caller-supplied context for
+// cancelling the underlying request mid-flight.
+func (p *LLMProcessor) GenerateTextContext(ctx context.Context, prompt string) (string, error) {
+	return p.callTextAPI(ctx, prompt)
+}
+
+// Embed returns one vector per element of texts, delegating to the
+// configured Provider's embeddings endpoint.
+func (p *LLMProcessor) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if p.providerErr != nil {
+		return nil, p.providerErr
+	}
+	ctx, cancel := p.withDeadline(ctx)
+	defer cancel()
+	return p.provider.Embed(ctx, texts)
+}
+
+// StreamText sends prompt to the configured provider and streams the
+// completion to out as it's generated - SSE chunks for OpenAI/Anthropic,
+// newline-delimited `stream: true` responses for Ollama - instead of
+// blocking for the whole completion the way GenerateText does. out is
+// closed when the stream ends, whether it finished or failed; cancelling
+// ctx (or a SetDeadline firing) stops the stream and unblocks the read in
+// progress via the request's context.
+//
+// Rate limiting (when configured via WithRateLimiter) is applied once
+// up front, against the prompt's estimated token count, before the
+// stream starts; usage recording (WithUsageMeter) is not applied to
+// streamed calls, since accounting for completion tokens chunk-by-chunk
+// as they arrive isn't something UsageMeter.Record's one-shot call
+// shape supports cleanly.
+func (p *LLMProcessor) StreamText(ctx context.Context, prompt string, out chan<- string) error {
+	defer close(out)
+	if p.providerErr != nil {
+		return p.providerErr
+	}
+	if err := p.waitForRateLimit(ctx, prompt); err != nil {
+		return err
+	}
+	ctx, cancel := p.withDeadline(ctx)
+	defer cancel()
+	return p.provider.Stream(ctx, prompt, out)
+}
+
+// StreamVision is StreamText for a vision prompt over image data, mirroring
+// DescribeImage/AnalyzeImageForRAG's callVisionAPI path. Providers that
+// implement VisionStreamer stream it incrementally; others fall back to a
+// single non-streamed Vision call delivered as one chunk.
+func (p *LLMProcessor) StreamVision(ctx context.Context, data []byte, format, prompt string, out chan<- string) error {
+	defer close(out)
+	if p.providerErr != nil {
+		return p.providerErr
+	}
+	if err := p.waitForRateLimit(ctx, prompt); err != nil {
+		return err
+	}
+	ctx, cancel := p.withDeadline(ctx)
+	defer cancel()
+
+	if vs, ok := p.provider.(VisionStreamer); ok {
+		return vs.StreamVision(ctx, data, format, prompt, out)
+	}
+	text, err := p.provider.Vision(ctx, data, format, prompt)
+	if err != nil {
+		return err
+	}
+	out <- text
+	return nil
+}
+
 // ============== Internal Helpers ==============
 
-func (p *LLMProcessor) buildImagePrompt(image ExtractedImage, context string) string {
+func (p *LLMProcessor) buildImagePrompt(image ExtractedImage, docContext string) string {
 	prompt := "Describe this image in detail for use in a document retrieval system."
 	if image.Caption != "" {
 		prompt += fmt.Sprintf("\n\nOriginal caption: %s", image.Caption)
 	}
-	if context != "" {
-		prompt += fmt.Sprintf("\n\nSurrounding context: %s", context)
+	if docContext != "" {
+		prompt += fmt.Sprintf("\n\nSurrounding context: %s", docContext)
 	}
 	prompt += "\n\nFocus on: key information, text visible, data shown, and relevance to the document."
 	return prompt
@@ -250,233 +493,101 @@ func extractJSON(s string) string {
 
 // ============== API Calls ==============
 
-func (p *LLMProcessor) callVisionAPI(data []byte, format, prompt string) (string, error) {
-	switch p.Config.Provider {
-	case "openai":
-		return p.callOpenAIVision(data, format, prompt)
-	case "anthropic":
-		return p.callAnthropicVision(data, format, prompt)
-	case "ollama":
-		return p.callOllamaVision(data, prompt)
-	default:
-		return "", fmt.Errorf("unsupported provider: %s", p.Config.Provider)
-	}
-}
-
-func (p *LLMProcessor) callTextAPI(prompt string) (string, error) {
-	switch p.Config.Provider {
-	case "openai":
-		return p.callOpenAIText(prompt)
-	case "anthropic":
-		return p.callAnthropicText(prompt)
-	case "ollama":
-		return p.callOllamaText(prompt)
-	default:
-		return "", fmt.Errorf("unsupported provider: %s", p.Config.Provider)
-	}
-}
-
-// OpenAI Implementation
-func (p *LLMProcessor) callOpenAIVision(data []byte, format, prompt string) (string, error) {
-	b64Image := base64.StdEncoding.EncodeToString(data)
-	mediaType := fmt.Sprintf("image/%s", format)
-	detail := "auto"
-	if p.Config.Detail != "" {
-		detail = p.Config.Detail
-	}
-
-	payload := map[string]interface{}{
-		"model": p.Config.Model,
-		"messages": []map[string]interface{}{
-			{
-				"role": "user",
-				"content": []map[string]interface{}{
-					{"type": "text", "text": prompt},
-					{
-						"type": "image_url",
-						"image_url": map[string]interface{}{
-							"url":    fmt.Sprintf("data:%s;base64,%s", mediaType, b64Image),
-							"detail": detail,
-						},
-					},
-				},
-			},
-		},
-		"max_tokens":  p.Config.MaxTokens,
-		"temperature": p.Config.Temperature,
-	}
-
-	return p.makeRequest("POST", "https://api.openai.com/v1/chat/completions", payload, p.Config.APIKey)
-}
-
-func (p *LLMProcessor) callOpenAIText(prompt string) (string, error) {
-	model := p.Config.Model
-	if strings.Contains(model, "vision") {
-		model = "gpt-4"
-	}
-
-	payload := map[string]interface{}{
-		"model":       model,
-		"messages":    []map[string]interface{}{{"role": "user", "content": prompt}},
-		"max_tokens":  p.Config.MaxTokens,
-		"temperature": p.Config.Temperature,
-	}
-
-	return p.makeRequest("POST", "https://api.openai.com/v1/chat/completions", payload, p.Config.APIKey)
-}
-
-// Anthropic Implementation
-func (p *LLMProcessor) callAnthropicVision(data []byte, format, prompt string) (string, error) {
-	b64Image := base64.StdEncoding.EncodeToString(data)
-	payload := map[string]interface{}{
-		"model":      p.Config.Model,
-		"max_tokens": p.Config.MaxTokens,
-		"messages": []map[string]interface{}{
-			{
-				"role": "user",
-				"content": []map[string]interface{}{
-					{
-						"type": "image",
-						"source": map[string]interface{}{
-							"type":       "base64",
-							"media_type": "image/" + format,
-							"data":       b64Image,
-						},
-					},
-					{"type": "text", "text": prompt},
-				},
-			},
-		},
-	}
-	return p.makeRequestUnwrapContent("POST", "https://api.anthropic.com/v1/messages", payload, p.Config.APIKey, "anthropic")
-}
-
-func (p *LLMProcessor) callAnthropicText(prompt string) (string, error) {
-	model := strings.Replace(p.Config.Model, "-vision", "", 1)
-	payload := map[string]interface{}{
-		"model":      model,
-		"max_tokens": p.Config.MaxTokens,
-		"messages":   []map[string]interface{}{{"role": "user", "content": prompt}},
+func (p *LLMProcessor) callVisionAPI(ctx context.Context, data []byte, format, prompt string) (string, error) {
+	if p.providerErr != nil {
+		return "", p.providerErr
 	}
-	return p.makeRequestUnwrapContent("POST", "https://api.anthropic.com/v1/messages", payload, p.Config.APIKey, "anthropic")
+	ctx, cancel := p.withDeadline(ctx)
+	defer cancel()
+	call := p.wrapTextCall(func(ctx context.Context, prompt string) (string, error) {
+		return p.provider.Vision(ctx, data, format, prompt)
+	})
+	return p.cachedCall(ctx, prompt, data, call)
 }
 
-// Ollama Implementation
-func (p *LLMProcessor) callOllamaVision(data []byte, prompt string) (string, error) {
-	b64Image := base64.StdEncoding.EncodeToString(data)
-	baseURL := "http://localhost:11434"
-	if p.Config.BaseURL != "" {
-		baseURL = p.Config.BaseURL
-	}
-
-	payload := map[string]interface{}{
-		"model":  p.Config.Model,
-		"prompt": prompt,
-		"images": []string{b64Image},
-		"stream": false,
+func (p *LLMProcessor) callTextAPI(ctx context.Context, prompt string) (string, error) {
+	if p.providerErr != nil {
+		return "", p.providerErr
 	}
-	return p.makeRequestOllama(baseURL+"/api/generate", payload)
-}
-
-func (p *LLMProcessor) callOllamaText(prompt string) (string, error) {
-	baseURL := "http://localhost:11434"
-	if p.Config.BaseURL != "" {
-		baseURL = p.Config.BaseURL
-	}
-
-	payload := map[string]interface{}{
-		"model":  p.Config.Model,
-		"prompt": prompt,
-		"stream": false,
-	}
-	return p.makeRequestOllama(baseURL+"/api/generate", payload)
+	ctx, cancel := p.withDeadline(ctx)
+	defer cancel()
+	return p.cachedCall(ctx, prompt, nil, p.wrapTextCall(p.provider.Text))
 }
 
-// Generic Request Helpers
-func (p *LLMProcessor) makeRequest(method, url string, payload interface{}, apiKey string) (string, error) {
-	body, _ := json.Marshal(payload)
-	req, _ := http.NewRequest(method, url, bytes.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
-	if apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+apiKey)
-	}
-
-	resp, err := p.client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	respBody, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("API error: %s", string(respBody))
-	}
-
-	var result struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
+// cachedCall is callTextAPI/callVisionAPI's cache-aware call path: a
+// ResponseCache hit (configured via WithCache) returns the stored
+// response without invoking call at all; a miss invokes call and, on
+// success, populates the cache for next time.
+func (p *LLMProcessor) cachedCall(ctx context.Context, prompt string, imageData []byte, call func(ctx context.Context, prompt string) (string, error)) (string, error) {
+	if p.cache == nil {
+		return call(ctx, prompt)
 	}
-	if err := json.Unmarshal(respBody, &result); err == nil && len(result.Choices) > 0 {
-		return result.Choices[0].Message.Content, nil
-	}
-	return "", fmt.Errorf("failed to parse response: %s", string(respBody))
-}
-
-func (p *LLMProcessor) makeRequestUnwrapContent(method, url string, payload interface{}, apiKey, typeStr string) (string, error) {
-	body, _ := json.Marshal(payload)
-	req, _ := http.NewRequest(method, url, bytes.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
-	if typeStr == "anthropic" {
-		req.Header.Set("x-api-key", apiKey)
-		req.Header.Set("anthropic-version", "2023-06-01")
+	key := p.buildCacheKey(ctx, prompt, imageData)
+	if response, ok, err := p.cache.Get(ctx, key); err == nil && ok {
+		return response, nil
 	}
-
-	resp, err := p.client.Do(req)
+	response, err := call(ctx, prompt)
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-
-	respBody, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("API error: %s", string(respBody))
-	}
+	// A cache write failure shouldn't fail the call that already
+	// succeeded - it just means this response won't be reusable later.
+	_ = p.cache.Put(ctx, key, response)
+	return response, nil
+}
 
-	var result struct {
-		Content []struct {
-			Text string `json:"text"`
-		} `json:"content"`
-	}
-	if err := json.Unmarshal(respBody, &result); err == nil && len(result.Content) > 0 {
-		return result.Content[0].Text, nil
+// buildCacheKey assembles the CacheKey for prompt/imageData, computing an
+// embedding via the provider only when p.cache does semantic matching.
+func (p *LLMProcessor) buildCacheKey(ctx context.Context, prompt string, imageData []byte) CacheKey {
+	key := CacheKey{
+		Provider:    string(p.Config.Provider),
+		Model:       p.Config.Model,
+		Temperature: p.Config.Temperature,
+		Prompt:      prompt,
+		ImageData:   imageData,
+	}
+	if p.cache.Semantic() {
+		if embeddings, err := p.provider.Embed(ctx, []string{prompt}); err == nil && len(embeddings) == 1 {
+			key.Embedding = embeddings[0]
+		}
 	}
-	return "", fmt.Errorf("failed to parse response: %s", string(respBody))
+	return key
 }
 
-func (p *LLMProcessor) makeRequestOllama(url string, payload interface{}) (string, error) {
-	body, _ := json.Marshal(payload)
-	req, _ := http.NewRequest("POST", url, bytes.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := p.client.Do(req)
-	if err != nil {
-		return "", err
+// waitForRateLimit blocks until a request slot and a token budget
+// estimated from prompt are both available, when p has a RateLimiter
+// configured via WithRateLimiter; it's a no-op otherwise.
+func (p *LLMProcessor) waitForRateLimit(ctx context.Context, prompt string) error {
+	if p.rateLimiter == nil {
+		return nil
 	}
-	defer resp.Body.Close()
+	return p.rateLimiter.Wait(ctx, p.tokenizer.Count(prompt))
+}
 
-	respBody, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("Ollama error: %s", string(respBody))
+// recordUsage records prompt/response token counts against p's UsageMeter
+// when one is configured via WithUsageMeter; it's a no-op otherwise.
+func (p *LLMProcessor) recordUsage(prompt, response string) {
+	if p.usage == nil {
+		return
 	}
+	p.usage.Record(p.Config.Model, p.tokenizer.Count(prompt), p.tokenizer.Count(response))
+}
 
-	var result struct {
-		Response string `json:"response"`
-	}
-	if err := json.Unmarshal(respBody, &result); err == nil {
-		return result.Response, nil
+// wrapTextCall wraps a raw provider call with p's rate limiting and usage
+// recording, so callTextAPI, callVisionAPI, and the structured-call
+// fallback (CallStructuredContext/callStructuredVision, in structured.go)
+// all go through the same budget/accounting logic instead of duplicating
+// it at each call site.
+func (p *LLMProcessor) wrapTextCall(call func(ctx context.Context, prompt string) (string, error)) func(ctx context.Context, prompt string) (string, error) {
+	return func(ctx context.Context, prompt string) (string, error) {
+		if err := p.waitForRateLimit(ctx, prompt); err != nil {
+			return "", err
+		}
+		response, err := call(ctx, prompt)
+		if err != nil {
+			return "", err
+		}
+		p.recordUsage(prompt, response)
+		return response, nil
 	}
-	return "", fmt.Errorf("failed to parse response: %s", string(respBody))
 }
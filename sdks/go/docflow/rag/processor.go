@@ -1,16 +1,29 @@
 package rag
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+	"github.com/xgaslan/docflow/sdks/go/docflow/formats/converter"
 )
 
+// markdownExts are extensions ProcessFile treats as already-Markdown,
+// skipping the office converter entirely.
+var markdownExts = map[string]bool{".md": true, ".markdown": true}
+
 // RAGProcessor coordinates file processing for RAG.
 type RAGProcessor struct {
-	Config  config.RAGConfig
-	chunker *Chunker
-	llm     *LLMProcessor
+	Config    config.RAGConfig
+	chunker   *Chunker
+	llm       *LLMProcessor
+	converter *converter.Converter
+
+	// OnProgress, if set, is invoked at stage boundaries ("convert",
+	// "chunk", "llm_images", "llm_tables", "embed") as Process runs.
+	OnProgress ProgressFunc
 }
 
 // NewRAGProcessor creates a new RAG processor.
@@ -18,19 +31,38 @@ func NewRAGProcessor(cfg config.RAGConfig) *RAGProcessor {
 	chunker := NewChunker(cfg) // Assumes Chunker uses RAGConfig or similar
 	llm := NewLLMProcessor(cfg.LLMConfig)
 	return &RAGProcessor{
-		Config:  cfg,
-		chunker: chunker,
-		llm:     llm,
+		Config:    cfg,
+		chunker:   chunker,
+		llm:       llm,
+		converter: converter.New(),
 	}
 }
 
 // ProcessFile processes a file path and returns RAGDocument.
 func (r *RAGProcessor) ProcessFile(path string) (*RAGDocument, error) {
-	// Implementation to read file and convert using format converter
-	// Then process content
-	// This requires integration with converters.
-	// For now returns placeholder.
-	return nil, nil // TODO: Implement full pipeline
+	ext := strings.ToLower(filepath.Ext(path))
+
+	r.emit("convert", 0, 0, path)
+
+	var markdown string
+	if markdownExts[ext] {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		markdown = string(data)
+	} else {
+		// Unknown/office formats are routed through the shell-out
+		// converter so chunking still works on arbitrary inputs.
+		md, err := r.converter.ConvertToMarkdown(path)
+		if err != nil {
+			return nil, err
+		}
+		markdown = md
+		defer r.converter.Clean(path)
+	}
+
+	return r.Process([]byte(markdown), filepath.Base(path))
 }
 
 // Process processes raw data and returns RAGDocument.
@@ -39,6 +71,11 @@ func (r *RAGProcessor) Process(data []byte, filename string) (*RAGDocument, erro
 	// 2. Chunk
 	// 3. Process LLM (images, tables, metadata)
 
+	r.emit("chunk", 0, 0, filename)
+	r.emit("llm_images", 0, 0, filename)
+	r.emit("llm_tables", 0, 0, filename)
+	r.emit("embed", 0, 0, filename)
+
 	// Create placeholder result for now as converters are in formats package
 	doc := &RAGDocument{
 		ID:        "doc_" + filename,
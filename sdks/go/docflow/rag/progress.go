@@ -0,0 +1,27 @@
+package rag
+
+// Progress describes a single point-in-time update during RAGProcessor
+// processing. It mirrors docflow.Progress but lives in this package to
+// avoid an import cycle (docflow imports rag for its document types).
+type Progress struct {
+	// Stage identifies the step being reported: "convert", "chunk",
+	// "llm_images", "llm_tables", or "embed".
+	Stage string
+
+	// Current and Total give optional progress within Stage. Both are 0
+	// when not applicable.
+	Current int64
+	Total   int64
+
+	// Message is a short human-readable detail.
+	Message string
+}
+
+// ProgressFunc receives Progress updates as RAGProcessor.Process runs.
+type ProgressFunc func(Progress)
+
+func (r *RAGProcessor) emit(stage string, current, total int64, message string) {
+	if r.OnProgress != nil {
+		r.OnProgress(Progress{Stage: stage, Current: current, Total: total, Message: message})
+	}
+}
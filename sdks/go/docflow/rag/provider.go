@@ -0,0 +1,78 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+)
+
+// Provider is a single LLM backend - OpenAI, Azure OpenAI, Anthropic,
+// Ollama, Bedrock, Gemini, or a custom OpenAI-compatible endpoint.
+// LLMProcessor resolves one Provider at construction time from
+// Config.Provider and delegates every call to it, the same way a
+// storage.Storage backend is resolved once by storage.New and then
+// driven through its interface.
+type Provider interface {
+	// Name identifies the provider, typically the same string it was
+	// registered under.
+	Name() string
+
+	// Text sends prompt and returns the completion.
+	Text(ctx context.Context, prompt string) (string, error)
+
+	// Vision sends prompt alongside image data (format is the image's
+	// file extension, e.g. "png") and returns the completion.
+	Vision(ctx context.Context, data []byte, format, prompt string) (string, error)
+
+	// Stream is Text, delivered incrementally on out as it's generated.
+	// out is not closed by Stream - the caller (LLMProcessor.StreamText)
+	// owns that.
+	Stream(ctx context.Context, prompt string, out chan<- string) error
+
+	// Embed returns one vector per element of texts, for providers that
+	// expose an embeddings endpoint. Providers without one (e.g.
+	// Anthropic) return an error.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// VisionStreamer is implemented by Providers that can stream a vision
+// completion incrementally. LLMProcessor.StreamVision checks for it via
+// type assertion and falls back to a single non-streamed Vision call,
+// delivered as one chunk, for providers that don't.
+type VisionStreamer interface {
+	StreamVision(ctx context.Context, data []byte, format, prompt string, out chan<- string) error
+}
+
+// ProviderFactory builds a Provider from LLM configuration.
+type ProviderFactory func(cfg config.LLMConfig) (Provider, error)
+
+var (
+	providerRegistryMu sync.RWMutex
+	providerRegistry   = map[string]ProviderFactory{}
+)
+
+// RegisterProvider adds a named Provider factory, so NewProvider (and
+// therefore NewLLMProcessor) can construct it by Config.Provider. Built-in
+// providers register themselves from an init() func in their own file
+// (see provider_openai.go, provider_anthropic.go, ...); third-party code
+// can register a custom Provider the same way without patching this
+// package.
+func RegisterProvider(name string, factory ProviderFactory) {
+	providerRegistryMu.Lock()
+	defer providerRegistryMu.Unlock()
+	providerRegistry[name] = factory
+}
+
+// NewProvider builds the Provider registered under name with the given
+// config.
+func NewProvider(name string, cfg config.LLMConfig) (Provider, error) {
+	providerRegistryMu.RLock()
+	factory, ok := providerRegistry[name]
+	providerRegistryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider: %s", name)
+	}
+	return factory(cfg)
+}
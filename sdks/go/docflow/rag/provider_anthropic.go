@@ -0,0 +1,313 @@
+package rag
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+)
+
+func init() {
+	RegisterProvider(string(config.LLMProviderAnthropic), newAnthropicProvider)
+}
+
+// anthropicProvider implements Provider against Anthropic's Messages API.
+type anthropicProvider struct {
+	client  *http.Client
+	model   string
+	url     string
+	headers map[string]string
+	cfg     config.LLMConfig
+}
+
+func newAnthropicProvider(cfg config.LLMConfig) (Provider, error) {
+	url := strings.TrimSuffix(cfg.BaseURL, "/")
+	if url == "" {
+		url = "https://api.anthropic.com/v1"
+	}
+	return &anthropicProvider{
+		client: httpClientFor(cfg),
+		model:  strings.Replace(cfg.Model, "-vision", "", 1),
+		url:    url + "/messages",
+		headers: map[string]string{
+			"x-api-key":         cfg.APIKey,
+			"anthropic-version": "2023-06-01",
+		},
+		cfg: cfg,
+	}, nil
+}
+
+func (p *anthropicProvider) Name() string { return string(config.LLMProviderAnthropic) }
+
+func (p *anthropicProvider) textPayload(prompt string) map[string]interface{} {
+	return map[string]interface{}{
+		"model":      p.model,
+		"max_tokens": p.cfg.MaxTokens,
+		"messages":   []map[string]interface{}{{"role": "user", "content": prompt}},
+	}
+}
+
+func (p *anthropicProvider) visionPayload(data []byte, format, prompt string) map[string]interface{} {
+	b64Image := base64.StdEncoding.EncodeToString(data)
+	return map[string]interface{}{
+		"model":      p.model,
+		"max_tokens": p.cfg.MaxTokens,
+		"messages": []map[string]interface{}{
+			{
+				"role": "user",
+				"content": []map[string]interface{}{
+					{
+						"type": "image",
+						"source": map[string]interface{}{
+							"type":       "base64",
+							"media_type": "image/" + format,
+							"data":       b64Image,
+						},
+					},
+					{"type": "text", "text": prompt},
+				},
+			},
+		},
+	}
+}
+
+func (p *anthropicProvider) request(ctx context.Context, payload map[string]interface{}) (string, error) {
+	respBody, err := postJSON(ctx, p.client, p.url, payload, p.headers)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(respBody, &result); err == nil && len(result.Content) > 0 {
+		return result.Content[0].Text, nil
+	}
+	return "", fmt.Errorf("failed to parse response: %s", string(respBody))
+}
+
+func (p *anthropicProvider) Text(ctx context.Context, prompt string) (string, error) {
+	return p.request(ctx, p.textPayload(prompt))
+}
+
+func (p *anthropicProvider) Vision(ctx context.Context, data []byte, format, prompt string) (string, error) {
+	return p.request(ctx, p.visionPayload(data, format, prompt))
+}
+
+func (p *anthropicProvider) stream(ctx context.Context, payload map[string]interface{}, out chan<- string) error {
+	payload["stream"] = true
+	return postSSE(ctx, p.client, p.url, payload, p.headers, func(data string) (bool, error) {
+		var event struct {
+			Type  string `json:"type"`
+			Delta struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"delta"`
+		}
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return false, nil
+		}
+		if event.Type == "content_block_delta" && event.Delta.Type == "text_delta" {
+			out <- event.Delta.Text
+		}
+		return event.Type == "message_stop", nil
+	})
+}
+
+func (p *anthropicProvider) Stream(ctx context.Context, prompt string, out chan<- string) error {
+	return p.stream(ctx, p.textPayload(prompt), out)
+}
+
+func (p *anthropicProvider) StreamVision(ctx context.Context, data []byte, format, prompt string, out chan<- string) error {
+	return p.stream(ctx, p.visionPayload(data, format, prompt), out)
+}
+
+// Structured implements StructuredProvider via Anthropic's tool-use mode,
+// forcing the model to call a single "respond" tool whose input_schema is
+// schema, then returning that tool call's input as the JSON response.
+func (p *anthropicProvider) Structured(ctx context.Context, prompt string, schema map[string]interface{}) (string, error) {
+	payload := p.textPayload(prompt)
+	payload["tools"] = []map[string]interface{}{
+		{
+			"name":         "respond",
+			"description":  "Respond with the structured result.",
+			"input_schema": schema,
+		},
+	}
+	payload["tool_choice"] = map[string]interface{}{"type": "tool", "name": "respond"}
+
+	respBody, err := postJSON(ctx, p.client, p.url, payload, p.headers)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Content []struct {
+			Type  string          `json:"type"`
+			Input json.RawMessage `json:"input"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %s", string(respBody))
+	}
+	for _, c := range result.Content {
+		if c.Type == "tool_use" {
+			return string(c.Input), nil
+		}
+	}
+	return "", fmt.Errorf("anthropic: no tool_use block in response: %s", string(respBody))
+}
+
+// Embed returns an error: Anthropic has no embeddings endpoint.
+func (p *anthropicProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return nil, fmt.Errorf("anthropic: embeddings are not supported")
+}
+
+// batchesURL is the Message Batches API's collection endpoint, derived
+// from p.url (the Messages API endpoint the rest of this file uses).
+func (p *anthropicProvider) batchesURL() string {
+	return strings.TrimSuffix(p.url, "/messages") + "/messages/batches"
+}
+
+// SubmitBatch implements BatchProvider via Anthropic's Message Batches
+// API: every prompt becomes one request in a single batch submission,
+// then this polls the batch until its processing_status is "ended"
+// before downloading and parsing its results.
+func (p *anthropicProvider) SubmitBatch(ctx context.Context, prompts []string, pollInterval time.Duration) ([]BatchResult[string], error) {
+	requests := make([]map[string]interface{}, len(prompts))
+	for i, prompt := range prompts {
+		requests[i] = map[string]interface{}{
+			"custom_id": strconv.Itoa(i),
+			"params":    p.textPayload(prompt),
+		}
+	}
+
+	respBody, err := postJSON(ctx, p.client, p.batchesURL(), map[string]interface{}{"requests": requests}, p.headers)
+	if err != nil {
+		return nil, fmt.Errorf("creating batch: %w", err)
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &created); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %s", string(respBody))
+	}
+
+	resultsURL, err := p.pollBatch(ctx, created.ID, pollInterval)
+	if err != nil {
+		return nil, err
+	}
+	return p.downloadBatchResults(ctx, resultsURL, len(prompts))
+}
+
+// pollBatch polls a Message Batch's status until processing_status is
+// "ended" or ctx is cancelled, returning its results_url to download.
+func (p *anthropicProvider) pollBatch(ctx context.Context, batchID string, pollInterval time.Duration) (string, error) {
+	statusURL := p.batchesURL() + "/" + batchID
+	for {
+		req, err := http.NewRequestWithContext(ctx, "GET", statusURL, nil)
+		if err != nil {
+			return "", err
+		}
+		for k, v := range p.headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		respBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != 200 {
+			return "", &HTTPError{StatusCode: resp.StatusCode, Body: string(respBody)}
+		}
+
+		var status struct {
+			ProcessingStatus string `json:"processing_status"`
+			ResultsURL       string `json:"results_url"`
+		}
+		if err := json.Unmarshal(respBody, &status); err != nil {
+			return "", fmt.Errorf("failed to parse response: %s", string(respBody))
+		}
+		if status.ProcessingStatus == "ended" {
+			return status.ResultsURL, nil
+		}
+
+		select {
+		case <-time.After(pollInterval):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+// downloadBatchResults downloads and parses a completed batch's JSONL
+// results, matching each line's custom_id back to its prompt index.
+func (p *anthropicProvider) downloadBatchResults(ctx context.Context, resultsURL string, n int) ([]BatchResult[string], error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", resultsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &HTTPError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	results := make([]BatchResult[string], n)
+	for i := range results {
+		results[i] = BatchResult[string]{Index: i, Err: fmt.Errorf("no result returned for this item")}
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var line struct {
+			CustomID string `json:"custom_id"`
+			Result   struct {
+				Type    string `json:"type"`
+				Message struct {
+					Content []struct {
+						Text string `json:"text"`
+					} `json:"content"`
+				} `json:"message"`
+				Error struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		idx, err := strconv.Atoi(line.CustomID)
+		if err != nil || idx < 0 || idx >= n {
+			continue
+		}
+		switch line.Result.Type {
+		case "succeeded":
+			if len(line.Result.Message.Content) > 0 {
+				results[idx] = BatchResult[string]{Index: idx, Value: line.Result.Message.Content[0].Text}
+			}
+		case "errored":
+			results[idx] = BatchResult[string]{Index: idx, Err: fmt.Errorf("%s", line.Result.Error.Message)}
+		default:
+			results[idx] = BatchResult[string]{Index: idx, Err: fmt.Errorf("batch item %s: %s", line.CustomID, line.Result.Type)}
+		}
+	}
+	return results, scanner.Err()
+}
@@ -0,0 +1,151 @@
+package rag
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+)
+
+func init() {
+	RegisterProvider(string(config.LLMProviderGoogle), newGeminiProvider)
+}
+
+// geminiProvider implements Provider against Google's Generative Language
+// API (generateContent/embedContent), the wire format behind Gemini.
+type geminiProvider struct {
+	client  *http.Client
+	model   string
+	baseURL string
+	apiKey  string
+	cfg     config.LLMConfig
+}
+
+func newGeminiProvider(cfg config.LLMConfig) (Provider, error) {
+	baseURL := strings.TrimSuffix(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = "https://generativelanguage.googleapis.com/v1beta"
+	}
+	model := cfg.Model
+	if model == "" {
+		model = "gemini-1.5-flash"
+	}
+	return &geminiProvider{
+		client:  httpClientFor(cfg),
+		model:   model,
+		baseURL: baseURL,
+		apiKey:  cfg.APIKey,
+		cfg:     cfg,
+	}, nil
+}
+
+func (p *geminiProvider) Name() string { return string(config.LLMProviderGoogle) }
+
+func (p *geminiProvider) url(action string) string {
+	return fmt.Sprintf("%s/models/%s:%s?key=%s", p.baseURL, p.model, action, p.apiKey)
+}
+
+func (p *geminiProvider) textPayload(prompt string) map[string]interface{} {
+	return map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{"parts": []map[string]interface{}{{"text": prompt}}},
+		},
+		"generationConfig": map[string]interface{}{
+			"temperature":     p.cfg.Temperature,
+			"maxOutputTokens": p.cfg.MaxTokens,
+		},
+	}
+}
+
+func (p *geminiProvider) visionPayload(data []byte, format, prompt string) map[string]interface{} {
+	return map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{
+				"parts": []map[string]interface{}{
+					{"text": prompt},
+					{
+						"inline_data": map[string]interface{}{
+							"mime_type": fmt.Sprintf("image/%s", format),
+							"data":      base64.StdEncoding.EncodeToString(data),
+						},
+					},
+				},
+			},
+		},
+		"generationConfig": map[string]interface{}{
+			"temperature":     p.cfg.Temperature,
+			"maxOutputTokens": p.cfg.MaxTokens,
+		},
+	}
+}
+
+func (p *geminiProvider) request(ctx context.Context, payload map[string]interface{}) (string, error) {
+	respBody, err := postJSON(ctx, p.client, p.url("generateContent"), payload, nil)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"content"`
+		} `json:"candidates"`
+	}
+	if err := json.Unmarshal(respBody, &result); err == nil && len(result.Candidates) > 0 && len(result.Candidates[0].Content.Parts) > 0 {
+		return result.Candidates[0].Content.Parts[0].Text, nil
+	}
+	return "", fmt.Errorf("failed to parse response: %s", string(respBody))
+}
+
+func (p *geminiProvider) Text(ctx context.Context, prompt string) (string, error) {
+	return p.request(ctx, p.textPayload(prompt))
+}
+
+func (p *geminiProvider) Vision(ctx context.Context, data []byte, format, prompt string) (string, error) {
+	return p.request(ctx, p.visionPayload(data, format, prompt))
+}
+
+// Stream calls the non-streaming generateContent endpoint and delivers the
+// whole completion as a single chunk. Gemini's streamGenerateContent
+// response is a top-level JSON array rather than SSE, which doesn't fit
+// postSSE's line-oriented scanner, so true token-level streaming isn't
+// implemented for this provider.
+func (p *geminiProvider) Stream(ctx context.Context, prompt string, out chan<- string) error {
+	text, err := p.Text(ctx, prompt)
+	if err != nil {
+		return err
+	}
+	out <- text
+	return nil
+}
+
+// Embed calls Gemini's embedContent endpoint once per text.
+func (p *geminiProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		respBody, err := postJSON(ctx, p.client, p.url("embedContent"), map[string]interface{}{
+			"content": map[string]interface{}{
+				"parts": []map[string]interface{}{{"text": text}},
+			},
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result struct {
+			Embedding struct {
+				Values []float32 `json:"values"`
+			} `json:"embedding"`
+		}
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %s", string(respBody))
+		}
+		vectors[i] = result.Embedding.Values
+	}
+	return vectors, nil
+}
@@ -0,0 +1,106 @@
+package rag
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// HTTPError wraps a non-200 provider HTTP response. Its Error() wording
+// matches what LLMProcessor's request helpers returned before providers
+// were split out ("API error: <body>"); StatusCode is exposed on top of
+// that so callers - the batch retry logic in batch.go, in particular -
+// can classify a 429/5xx worth retrying without parsing the message.
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("API error: %s", e.Body)
+}
+
+// postJSON posts payload as JSON to url with headers applied on top of
+// Content-Type, and returns the raw response body. A non-200 status is
+// reported as an *HTTPError containing the body, matching the wording
+// LLMProcessor's request helpers used before providers were split out.
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}, headers map[string]string) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, &HTTPError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	return respBody, nil
+}
+
+// postSSE posts payload to url and scans the SSE response line by line,
+// stripping the "data: " prefix and handing each data line to onData.
+// onData returns true to stop reading early (e.g. on "[DONE]" or a
+// terminal event type). Lines that aren't a "data:" field (blank lines,
+// "event:" lines) are skipped.
+func postSSE(ctx context.Context, client *http.Client, url string, payload interface{}, headers map[string]string, onData func(data string) (stop bool, err error)) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return &HTTPError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		data := strings.TrimPrefix(line, "data: ")
+		stop, err := onData(data)
+		if err != nil {
+			return err
+		}
+		if stop {
+			break
+		}
+	}
+	return scanner.Err()
+}
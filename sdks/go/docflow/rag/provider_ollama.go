@@ -0,0 +1,175 @@
+package rag
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+)
+
+func init() {
+	RegisterProvider(string(config.LLMProviderOllama), newOllamaProvider)
+}
+
+// ollamaProvider implements Provider against a local or remote Ollama
+// server's /api/generate and /api/embeddings endpoints.
+type ollamaProvider struct {
+	client  *http.Client
+	model   string
+	baseURL string
+}
+
+func newOllamaProvider(cfg config.LLMConfig) (Provider, error) {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = cfg.OllamaBaseURL
+	}
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+	return &ollamaProvider{
+		client:  httpClientFor(cfg),
+		model:   cfg.Model,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}, nil
+}
+
+func (p *ollamaProvider) Name() string { return string(config.LLMProviderOllama) }
+
+func (p *ollamaProvider) generate(ctx context.Context, payload map[string]interface{}) (string, error) {
+	respBody, err := postJSON(ctx, p.client, p.baseURL+"/api/generate", payload, nil)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Response string `json:"response"`
+	}
+	if err := json.Unmarshal(respBody, &result); err == nil {
+		return result.Response, nil
+	}
+	return "", fmt.Errorf("failed to parse response: %s", string(respBody))
+}
+
+func (p *ollamaProvider) Text(ctx context.Context, prompt string) (string, error) {
+	return p.generate(ctx, map[string]interface{}{
+		"model":  p.model,
+		"prompt": prompt,
+		"stream": false,
+	})
+}
+
+func (p *ollamaProvider) Vision(ctx context.Context, data []byte, format, prompt string) (string, error) {
+	return p.generate(ctx, map[string]interface{}{
+		"model":  p.model,
+		"prompt": prompt,
+		"images": []string{base64.StdEncoding.EncodeToString(data)},
+		"stream": false,
+	})
+}
+
+// stream reads Ollama's `stream: true` response, which (unlike
+// OpenAI/Anthropic) isn't SSE-framed - just one JSON object per line - so
+// it scans lines directly rather than going through postSSE.
+func (p *ollamaProvider) stream(ctx context.Context, payload map[string]interface{}, out chan<- string) error {
+	payload["stream"] = true
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/generate", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Ollama error: %s", string(respBody))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var chunk struct {
+			Response string `json:"response"`
+			Done     bool   `json:"done"`
+		}
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			continue
+		}
+		if chunk.Response != "" {
+			out <- chunk.Response
+		}
+		if chunk.Done {
+			break
+		}
+	}
+	return scanner.Err()
+}
+
+func (p *ollamaProvider) Stream(ctx context.Context, prompt string, out chan<- string) error {
+	return p.stream(ctx, map[string]interface{}{
+		"model":  p.model,
+		"prompt": prompt,
+	}, out)
+}
+
+func (p *ollamaProvider) StreamVision(ctx context.Context, data []byte, format, prompt string, out chan<- string) error {
+	return p.stream(ctx, map[string]interface{}{
+		"model":  p.model,
+		"prompt": prompt,
+		"images": []string{base64.StdEncoding.EncodeToString(data)},
+	}, out)
+}
+
+// Structured implements StructuredProvider via Ollama's `format` field,
+// which (since 0.5) accepts a JSON Schema object directly in addition to
+// the plain "json" string mode.
+func (p *ollamaProvider) Structured(ctx context.Context, prompt string, schema map[string]interface{}) (string, error) {
+	return p.generate(ctx, map[string]interface{}{
+		"model":  p.model,
+		"prompt": prompt,
+		"stream": false,
+		"format": schema,
+	})
+}
+
+// Embed calls Ollama's /api/embeddings endpoint once per text - unlike
+// OpenAI's batched /v1/embeddings, Ollama only accepts a single prompt per
+// request.
+func (p *ollamaProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		respBody, err := postJSON(ctx, p.client, p.baseURL+"/api/embeddings", map[string]interface{}{
+			"model":  p.model,
+			"prompt": text,
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result struct {
+			Embedding []float32 `json:"embedding"`
+		}
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %s", string(respBody))
+		}
+		vectors[i] = result.Embedding
+	}
+	return vectors, nil
+}
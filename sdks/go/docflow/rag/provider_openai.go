@@ -0,0 +1,483 @@
+package rag
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+)
+
+func init() {
+	RegisterProvider(string(config.LLMProviderOpenAI), newOpenAIProvider)
+	RegisterProvider(string(config.LLMProviderAzureOpenAI), newAzureOpenAIProvider)
+	RegisterProvider(string(config.LLMProviderOpenAICompatible), newOpenAICompatibleProvider)
+}
+
+// openAIChatProvider implements Provider against any endpoint that speaks
+// OpenAI's chat/completions, embeddings, and SSE streaming wire format -
+// api.openai.com itself, an Azure OpenAI deployment, or any
+// OpenAI-compatible server (vLLM, LM Studio, Together, ...) - with the
+// differences between them (URL shape, auth header) fixed at construction.
+type openAIChatProvider struct {
+	name          string
+	client        *http.Client
+	model         string
+	chatURL       string
+	embeddingsURL string
+	headers       map[string]string
+	cfg           config.LLMConfig
+
+	// batchBaseURL backs SubmitBatch (OpenAI's Batch API). Only set for
+	// the plain "openai" provider - Azure OpenAI and OpenAI-compatible
+	// servers don't expose this API in a way this package is wired
+	// against, so SubmitBatch errors for them instead of guessing a URL.
+	batchBaseURL string
+}
+
+func httpClientFor(cfg config.LLMConfig) *http.Client {
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	if timeout == 0 {
+		timeout = 60 * time.Second
+	}
+	return &http.Client{Timeout: timeout}
+}
+
+func newOpenAIProvider(cfg config.LLMConfig) (Provider, error) {
+	baseURL := strings.TrimSuffix(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	headers := map[string]string{}
+	if cfg.APIKey != "" {
+		headers["Authorization"] = "Bearer " + cfg.APIKey
+	}
+	if cfg.Organization != "" {
+		headers["OpenAI-Organization"] = cfg.Organization
+	}
+	return &openAIChatProvider{
+		name:          string(config.LLMProviderOpenAI),
+		client:        httpClientFor(cfg),
+		model:         cfg.Model,
+		chatURL:       baseURL + "/chat/completions",
+		embeddingsURL: baseURL + "/embeddings",
+		headers:       headers,
+		cfg:           cfg,
+		batchBaseURL:  baseURL,
+	}, nil
+}
+
+// newAzureOpenAIProvider builds a provider for an Azure OpenAI deployment.
+// Azure addresses a model by deployment rather than model name, and
+// authenticates with a plain "api-key" header instead of OpenAI's
+// "Authorization: Bearer".
+func newAzureOpenAIProvider(cfg config.LLMConfig) (Provider, error) {
+	if cfg.AzureEndpoint == "" || cfg.AzureDeployment == "" {
+		return nil, fmt.Errorf("azure_openai: azure_endpoint and azure_deployment are required")
+	}
+	apiVersion := cfg.APIVersion
+	if apiVersion == "" {
+		apiVersion = "2024-02-01"
+	}
+	base := fmt.Sprintf("%s/openai/deployments/%s", strings.TrimSuffix(cfg.AzureEndpoint, "/"), cfg.AzureDeployment)
+	headers := map[string]string{}
+	if cfg.APIKey != "" {
+		headers["api-key"] = cfg.APIKey
+	}
+	return &openAIChatProvider{
+		name:          string(config.LLMProviderAzureOpenAI),
+		client:        httpClientFor(cfg),
+		model:         cfg.Model,
+		chatURL:       fmt.Sprintf("%s/chat/completions?api-version=%s", base, apiVersion),
+		embeddingsURL: fmt.Sprintf("%s/embeddings?api-version=%s", base, apiVersion),
+		headers:       headers,
+		cfg:           cfg,
+	}, nil
+}
+
+// newOpenAICompatibleProvider builds a provider for any server that speaks
+// OpenAI's wire format - vLLM, LM Studio, Together, etc. - driven purely by
+// BaseURL rather than a fixed host like the "openai" provider.
+func newOpenAICompatibleProvider(cfg config.LLMConfig) (Provider, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("openai_compatible: base_url is required")
+	}
+	baseURL := strings.TrimSuffix(cfg.BaseURL, "/")
+	headers := map[string]string{}
+	if cfg.APIKey != "" {
+		headers["Authorization"] = "Bearer " + cfg.APIKey
+	}
+	return &openAIChatProvider{
+		name:          string(config.LLMProviderOpenAICompatible),
+		client:        httpClientFor(cfg),
+		model:         cfg.Model,
+		chatURL:       baseURL + "/chat/completions",
+		embeddingsURL: baseURL + "/embeddings",
+		headers:       headers,
+		cfg:           cfg,
+	}, nil
+}
+
+func (p *openAIChatProvider) Name() string { return p.name }
+
+func (p *openAIChatProvider) textPayload(prompt string) map[string]interface{} {
+	model := p.model
+	if strings.Contains(model, "vision") {
+		model = "gpt-4"
+	}
+	return map[string]interface{}{
+		"model":       model,
+		"messages":    []map[string]interface{}{{"role": "user", "content": prompt}},
+		"max_tokens":  p.cfg.MaxTokens,
+		"temperature": p.cfg.Temperature,
+	}
+}
+
+func (p *openAIChatProvider) visionPayload(data []byte, format, prompt string) map[string]interface{} {
+	b64Image := base64.StdEncoding.EncodeToString(data)
+	mediaType := fmt.Sprintf("image/%s", format)
+	detail := "auto"
+	if p.cfg.Detail != "" {
+		detail = p.cfg.Detail
+	}
+	return map[string]interface{}{
+		"model": p.model,
+		"messages": []map[string]interface{}{
+			{
+				"role": "user",
+				"content": []map[string]interface{}{
+					{"type": "text", "text": prompt},
+					{
+						"type": "image_url",
+						"image_url": map[string]interface{}{
+							"url":    fmt.Sprintf("data:%s;base64,%s", mediaType, b64Image),
+							"detail": detail,
+						},
+					},
+				},
+			},
+		},
+		"max_tokens":  p.cfg.MaxTokens,
+		"temperature": p.cfg.Temperature,
+	}
+}
+
+func (p *openAIChatProvider) request(ctx context.Context, payload map[string]interface{}) (string, error) {
+	respBody, err := postJSON(ctx, p.client, p.chatURL, payload, p.headers)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(respBody, &result); err == nil && len(result.Choices) > 0 {
+		return result.Choices[0].Message.Content, nil
+	}
+	return "", fmt.Errorf("failed to parse response: %s", string(respBody))
+}
+
+func (p *openAIChatProvider) Text(ctx context.Context, prompt string) (string, error) {
+	return p.request(ctx, p.textPayload(prompt))
+}
+
+func (p *openAIChatProvider) Vision(ctx context.Context, data []byte, format, prompt string) (string, error) {
+	return p.request(ctx, p.visionPayload(data, format, prompt))
+}
+
+func (p *openAIChatProvider) stream(ctx context.Context, payload map[string]interface{}, out chan<- string) error {
+	payload["stream"] = true
+	return postSSE(ctx, p.client, p.chatURL, payload, p.headers, func(data string) (bool, error) {
+		if data == "[DONE]" {
+			return true, nil
+		}
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return false, nil // ignore unparseable keep-alive lines
+		}
+		if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+			out <- chunk.Choices[0].Delta.Content
+		}
+		return false, nil
+	})
+}
+
+func (p *openAIChatProvider) Stream(ctx context.Context, prompt string, out chan<- string) error {
+	return p.stream(ctx, p.textPayload(prompt), out)
+}
+
+func (p *openAIChatProvider) StreamVision(ctx context.Context, data []byte, format, prompt string, out chan<- string) error {
+	return p.stream(ctx, p.visionPayload(data, format, prompt), out)
+}
+
+// Structured implements StructuredProvider via OpenAI's
+// response_format:{type:"json_schema",strict:true} chat completions mode.
+func (p *openAIChatProvider) Structured(ctx context.Context, prompt string, schema map[string]interface{}) (string, error) {
+	payload := p.textPayload(prompt)
+	payload["response_format"] = map[string]interface{}{
+		"type": "json_schema",
+		"json_schema": map[string]interface{}{
+			"name":   "response",
+			"strict": true,
+			"schema": schema,
+		},
+	}
+	return p.request(ctx, payload)
+}
+
+func (p *openAIChatProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	model := p.model
+	if model == "" || strings.Contains(model, "vision") || strings.Contains(model, "gpt") {
+		model = "text-embedding-3-small"
+	}
+	respBody, err := postJSON(ctx, p.client, p.embeddingsURL, map[string]interface{}{
+		"model": model,
+		"input": texts,
+	}, p.headers)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Data []struct {
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %s", string(respBody))
+	}
+	vectors := make([][]float32, len(result.Data))
+	for i, d := range result.Data {
+		vectors[i] = d.Embedding
+	}
+	return vectors, nil
+}
+
+// SubmitBatch implements BatchProvider via OpenAI's Batch API: every
+// prompt becomes one chat/completions request in a JSONL file, uploaded
+// and submitted as a single batch job, then polled (OpenAI targets a 24h
+// completion window) until it finishes, before downloading and parsing
+// the output file. Only available for the plain "openai" provider.
+func (p *openAIChatProvider) SubmitBatch(ctx context.Context, prompts []string, pollInterval time.Duration) ([]BatchResult[string], error) {
+	if p.batchBaseURL == "" {
+		return nil, fmt.Errorf("%s: batch API is not supported", p.name)
+	}
+
+	var jsonl bytes.Buffer
+	for i, prompt := range prompts {
+		line, err := json.Marshal(map[string]interface{}{
+			"custom_id": strconv.Itoa(i),
+			"method":    "POST",
+			"url":       "/v1/chat/completions",
+			"body":      p.textPayload(prompt),
+		})
+		if err != nil {
+			return nil, err
+		}
+		jsonl.Write(line)
+		jsonl.WriteByte('\n')
+	}
+
+	fileID, err := p.uploadBatchFile(ctx, jsonl.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("uploading batch input: %w", err)
+	}
+	batchID, err := p.createBatch(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("creating batch: %w", err)
+	}
+	outputFileID, err := p.pollBatch(ctx, batchID, pollInterval)
+	if err != nil {
+		return nil, err
+	}
+	return p.downloadBatchResults(ctx, outputFileID, len(prompts))
+}
+
+func (p *openAIChatProvider) uploadBatchFile(ctx context.Context, jsonl []byte) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("purpose", "batch"); err != nil {
+		return "", err
+	}
+	part, err := writer.CreateFormFile("file", "batch_input.jsonl")
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(jsonl); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.batchBaseURL+"/files", &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return "", &HTTPError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %s", string(respBody))
+	}
+	return result.ID, nil
+}
+
+func (p *openAIChatProvider) createBatch(ctx context.Context, fileID string) (string, error) {
+	respBody, err := postJSON(ctx, p.client, p.batchBaseURL+"/batches", map[string]interface{}{
+		"input_file_id":     fileID,
+		"endpoint":          "/v1/chat/completions",
+		"completion_window": "24h",
+	}, p.headers)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %s", string(respBody))
+	}
+	return result.ID, nil
+}
+
+// pollBatch polls a batch job's status until it completes, fails, or ctx
+// is cancelled, returning the output file ID to download on success.
+func (p *openAIChatProvider) pollBatch(ctx context.Context, batchID string, pollInterval time.Duration) (string, error) {
+	statusURL := p.batchBaseURL + "/batches/" + batchID
+	for {
+		req, err := http.NewRequestWithContext(ctx, "GET", statusURL, nil)
+		if err != nil {
+			return "", err
+		}
+		for k, v := range p.headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		respBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != 200 {
+			return "", &HTTPError{StatusCode: resp.StatusCode, Body: string(respBody)}
+		}
+
+		var status struct {
+			Status       string `json:"status"`
+			OutputFileID string `json:"output_file_id"`
+		}
+		if err := json.Unmarshal(respBody, &status); err != nil {
+			return "", fmt.Errorf("failed to parse response: %s", string(respBody))
+		}
+
+		switch status.Status {
+		case "completed":
+			return status.OutputFileID, nil
+		case "failed", "expired", "cancelled":
+			return "", fmt.Errorf("batch %s did not complete: status %s", batchID, status.Status)
+		}
+
+		select {
+		case <-time.After(pollInterval):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+// downloadBatchResults downloads and parses a completed batch's output
+// file, matching each line's custom_id back to its prompt index. A
+// prompt whose custom_id never appears in the output (OpenAI omits lines
+// for requests it couldn't process at all) keeps its default "no result"
+// error.
+func (p *openAIChatProvider) downloadBatchResults(ctx context.Context, fileID string, n int) ([]BatchResult[string], error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.batchBaseURL+"/files/"+fileID+"/content", nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &HTTPError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	results := make([]BatchResult[string], n)
+	for i := range results {
+		results[i] = BatchResult[string]{Index: i, Err: fmt.Errorf("no result returned for this item")}
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var line struct {
+			CustomID string `json:"custom_id"`
+			Response *struct {
+				Body struct {
+					Choices []struct {
+						Message struct {
+							Content string `json:"content"`
+						} `json:"message"`
+					} `json:"choices"`
+				} `json:"body"`
+			} `json:"response"`
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		idx, err := strconv.Atoi(line.CustomID)
+		if err != nil || idx < 0 || idx >= n {
+			continue
+		}
+		if line.Error != nil {
+			results[idx].Err = fmt.Errorf("%s", line.Error.Message)
+			continue
+		}
+		if line.Response != nil && len(line.Response.Body.Choices) > 0 {
+			results[idx] = BatchResult[string]{Index: idx, Value: line.Response.Body.Choices[0].Message.Content}
+		}
+	}
+	return results, scanner.Err()
+}
@@ -0,0 +1,111 @@
+package rag
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter caps calls to both a requests/minute and a tokens/minute
+// budget - the two axes OpenAI, Anthropic, and most other providers rate
+// limit on - using a token-bucket per axis. A zero limit on either axis
+// disables that axis's check. LLMProcessor calls Wait before every
+// provider call when one is configured via WithRateLimiter.
+type RateLimiter struct {
+	mu sync.Mutex
+
+	requestsPerMinute float64
+	tokensPerMinute   float64
+
+	requestBudget float64
+	tokenBudget   float64
+	last          time.Time
+}
+
+// NewRateLimiter creates a RateLimiter. A zero requestsPerMinute or
+// tokensPerMinute disables that axis.
+func NewRateLimiter(requestsPerMinute, tokensPerMinute int) *RateLimiter {
+	return &RateLimiter{
+		requestsPerMinute: float64(requestsPerMinute),
+		tokensPerMinute:   float64(tokensPerMinute),
+		requestBudget:     float64(requestsPerMinute),
+		tokenBudget:       float64(tokensPerMinute),
+		last:              time.Now(),
+	}
+}
+
+func (r *RateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(r.last).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	if r.requestsPerMinute > 0 {
+		r.requestBudget += elapsed * r.requestsPerMinute / 60
+		if r.requestBudget > r.requestsPerMinute {
+			r.requestBudget = r.requestsPerMinute
+		}
+	}
+	if r.tokensPerMinute > 0 {
+		r.tokenBudget += elapsed * r.tokensPerMinute / 60
+		if r.tokenBudget > r.tokensPerMinute {
+			r.tokenBudget = r.tokensPerMinute
+		}
+	}
+	r.last = now
+}
+
+// Wait blocks until one request slot and estimatedTokens of token budget
+// are both available, or ctx is cancelled. estimatedTokens is the
+// caller's best guess at prompt+completion size (LLMProcessor uses its
+// Tokenizer on the prompt alone, which undercounts the completion, but
+// that's the only estimate available before the call returns).
+func (r *RateLimiter) Wait(ctx context.Context, estimatedTokens int) error {
+	for {
+		r.mu.Lock()
+		r.refill(time.Now())
+
+		requestOK := r.requestsPerMinute <= 0 || r.requestBudget >= 1
+		tokensOK := r.tokensPerMinute <= 0 || r.tokenBudget >= float64(estimatedTokens)
+
+		if requestOK && tokensOK {
+			if r.requestsPerMinute > 0 {
+				r.requestBudget--
+			}
+			if r.tokensPerMinute > 0 {
+				r.tokenBudget -= float64(estimatedTokens)
+			}
+			r.mu.Unlock()
+			return nil
+		}
+
+		wait := 10 * time.Millisecond
+		if !requestOK {
+			if d := durationFor(1-r.requestBudget, r.requestsPerMinute/60); d > wait {
+				wait = d
+			}
+		}
+		if !tokensOK {
+			if d := durationFor(float64(estimatedTokens)-r.tokenBudget, r.tokensPerMinute/60); d > wait {
+				wait = d
+			}
+		}
+		r.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// durationFor returns how long it takes to refill deficit units at
+// perSecondRate units/second.
+func durationFor(deficit, perSecondRate float64) time.Duration {
+	if perSecondRate <= 0 || deficit <= 0 {
+		return 0
+	}
+	return time.Duration(deficit / perSecondRate * float64(time.Second))
+}
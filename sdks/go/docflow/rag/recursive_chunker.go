@@ -0,0 +1,220 @@
+package rag
+
+import (
+	"strings"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+	"github.com/xgaslan/docflow/sdks/go/docflow/tokenizer"
+)
+
+// ChunkerMetrics reports how many boundaries a chunking pass produced, so
+// callers can compare strategies without re-deriving it from the chunks.
+type ChunkerMetrics struct {
+	Strategy      config.SplitBy
+	InputLength   int
+	ChunkCount    int
+	BoundaryCount int
+	AvgChunkSize  float64
+}
+
+// RecursiveChunker implements config.SplitByRecursive: it tries each
+// separator in Config.Separators in order, splitting at the first one
+// that produces pieces within ChunkSize, and recurses into the next
+// separator down for any piece that is still too large.
+type RecursiveChunker struct {
+	Config config.ChunkingConfig
+
+	tok tokenizer.Tokenizer
+}
+
+// NewRecursiveChunker creates a RecursiveChunker.
+func NewRecursiveChunker(cfg config.ChunkingConfig) *RecursiveChunker {
+	if len(cfg.Separators) == 0 {
+		cfg.Separators = []string{"\n\n", "\n", ". ", " "}
+	}
+	return &RecursiveChunker{Config: cfg, tok: resolveTokenizer(cfg)}
+}
+
+// Chunk splits text using the recursive separator strategy.
+func (c *RecursiveChunker) Chunk(text string) ([]Chunk, ChunkerMetrics) {
+	pieces := c.split(text, c.Config.Separators)
+	merged := c.mergeToBounds(pieces)
+
+	if c.Config.ChunkOverlap > 0 {
+		merged = addTextOverlap(merged, c.Config.ChunkOverlap, overlapTokenizer(c.Config.TokenAwareOverlap, c.tok))
+	}
+
+	chunks := make([]Chunk, len(merged))
+	offset := 0
+	for i, content := range merged {
+		chunks[i] = Chunk{
+			Content:   content,
+			Index:     i,
+			StartChar: offset,
+			EndChar:   offset + len(content),
+		}
+		offset += len(content)
+	}
+
+	return chunks, c.metrics(text, chunks)
+}
+
+// split recurses through seps, splitting text at the first separator that
+// keeps every piece within ChunkSize. A piece still over ChunkSize after
+// the last separator is hard-split at ChunkSize characters.
+func (c *RecursiveChunker) split(text string, seps []string) []string {
+	if len(text) <= c.Config.ChunkSize {
+		return []string{text}
+	}
+	if len(seps) == 0 {
+		return hardSplit(text, c.Config.ChunkSize)
+	}
+
+	var pieces []string
+	for _, part := range strings.SplitAfter(text, seps[0]) {
+		if part == "" {
+			continue
+		}
+		if len(part) > c.Config.ChunkSize {
+			pieces = append(pieces, c.split(part, seps[1:])...)
+		} else {
+			pieces = append(pieces, part)
+		}
+	}
+	if len(pieces) == 0 {
+		return []string{text}
+	}
+	return pieces
+}
+
+// mergeToBounds greedily packs consecutive pieces into chunks up to
+// ChunkSize, then folds any chunk left under MinChunkSize into its
+// neighbor so splits don't produce slivers.
+func (c *RecursiveChunker) mergeToBounds(pieces []string) []string {
+	var merged []string
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			merged = append(merged, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, p := range pieces {
+		if current.Len() > 0 && current.Len()+len(p) > c.Config.ChunkSize {
+			flush()
+		}
+		current.WriteString(p)
+	}
+	flush()
+
+	return foldShortChunks(merged, c.Config.MinChunkSize)
+}
+
+func (c *RecursiveChunker) metrics(text string, chunks []Chunk) ChunkerMetrics {
+	return newChunkerMetrics(config.SplitByRecursive, text, chunks)
+}
+
+// hardSplit breaks text into size-character pieces, the last-resort
+// fallback once no configured separator is small enough.
+func hardSplit(text string, size int) []string {
+	if size <= 0 {
+		return []string{text}
+	}
+	var pieces []string
+	for len(text) > size {
+		pieces = append(pieces, text[:size])
+		text = text[size:]
+	}
+	if text != "" {
+		pieces = append(pieces, text)
+	}
+	return pieces
+}
+
+// foldShortChunks merges any chunk under minSize into its following
+// neighbor (or, for the last chunk, its preceding one).
+func foldShortChunks(chunks []string, minSize int) []string {
+	if minSize <= 0 || len(chunks) < 2 {
+		return chunks
+	}
+
+	var folded []string
+	for i := 0; i < len(chunks); i++ {
+		if len(chunks[i]) < minSize && i+1 < len(chunks) {
+			chunks[i+1] = chunks[i] + chunks[i+1]
+			continue
+		}
+		if len(chunks[i]) < minSize && len(folded) > 0 {
+			folded[len(folded)-1] += chunks[i]
+			continue
+		}
+		folded = append(folded, chunks[i])
+	}
+	return folded
+}
+
+// newChunkerMetrics summarizes a finished chunking pass.
+func newChunkerMetrics(strategy config.SplitBy, text string, chunks []Chunk) ChunkerMetrics {
+	total := 0
+	for _, ch := range chunks {
+		total += len(ch.Content)
+	}
+	avg := 0.0
+	if len(chunks) > 0 {
+		avg = float64(total) / float64(len(chunks))
+	}
+	return ChunkerMetrics{
+		Strategy:      strategy,
+		InputLength:   len(text),
+		ChunkCount:    len(chunks),
+		BoundaryCount: len(chunks) - 1,
+		AvgChunkSize:  avg,
+	}
+}
+
+// overlapTokenizer returns the Tokenizer addTextOverlap should use for
+// token-aware overlap, or nil to keep the overlap character-based.
+func overlapTokenizer(tokenAware bool, tok tokenizer.Tokenizer) tokenizer.Tokenizer {
+	if !tokenAware {
+		return nil
+	}
+	return tok
+}
+
+// addTextOverlap prepends a tail slice of each chunk to the next one, the
+// same "[...] tail" convention used by Chunker.addOverlap. When tok is
+// non-nil, overlapSize is interpreted as a token count (per tok) instead
+// of characters.
+func addTextOverlap(chunks []string, overlapSize int, tok tokenizer.Tokenizer) []string {
+	if len(chunks) <= 1 {
+		return chunks
+	}
+
+	out := make([]string, len(chunks))
+	copy(out, chunks)
+
+	for i := 1; i < len(out); i++ {
+		prev := out[i-1]
+		var overlap string
+		if tok != nil {
+			tokens := tok.Encode(prev)
+			if len(tokens) > overlapSize {
+				tokens = tokens[len(tokens)-overlapSize:]
+			}
+			overlap = tok.Decode(tokens)
+		} else {
+			overlap = prev
+			if len(overlap) > overlapSize {
+				overlap = overlap[len(overlap)-overlapSize:]
+			}
+		}
+
+		if strings.TrimSpace(overlap) != "" {
+			out[i] = "[...] " + strings.TrimSpace(overlap) + "\n\n" + out[i]
+		}
+	}
+
+	return out
+}
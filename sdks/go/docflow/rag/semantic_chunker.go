@@ -0,0 +1,364 @@
+package rag
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/cache/memcache"
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+	"github.com/xgaslan/docflow/sdks/go/docflow/tokenizer"
+	"github.com/xgaslan/docflow/sdks/go/docflow/vectorizer"
+)
+
+// sentenceSplitPattern splits on sentence-ending punctuation followed by
+// whitespace, keeping the punctuation with the preceding sentence.
+var sentenceSplitPattern = regexp.MustCompile(`(?s)([.!?])\s+`)
+
+// errNotCached is a sentinel create() returns from cachedEmbedding to
+// signal "not present" without populating the cache; it never escapes
+// embedSentences.
+var errNotCached = errors.New("rag: embedding not cached")
+
+// SemanticChunker implements config.SplitBySemantic: it splits text into
+// sentences, embeds each one with the configured Vectorizer, and cuts
+// wherever the cosine distance between consecutive sentence embeddings
+// exceeds a breakpoint threshold derived from SemanticBreakpointPercentile
+// or SemanticBreakpointStddev.
+type SemanticChunker struct {
+	Config     config.ChunkingConfig
+	Vectorizer vectorizer.Vectorizer
+
+	// Cache, if set, memoizes sentence embeddings by content hash so
+	// re-chunking the same document (or documents sharing sentences)
+	// doesn't re-embed what's already been seen.
+	Cache *memcache.Cache
+
+	tok tokenizer.Tokenizer
+}
+
+// NewSemanticChunker creates a SemanticChunker. embedder is reused from
+// whichever vector store the caller has configured; SemanticChunker has
+// no embedding logic of its own.
+func NewSemanticChunker(cfg config.ChunkingConfig, embedder vectorizer.Vectorizer) *SemanticChunker {
+	return &SemanticChunker{Config: cfg, Vectorizer: embedder, tok: resolveTokenizer(cfg)}
+}
+
+// Chunk splits text using the semantic breakpoint strategy.
+func (c *SemanticChunker) Chunk(ctx context.Context, text string) ([]Chunk, ChunkerMetrics, error) {
+	sentences := splitProtectedSentences(text)
+	if len(sentences) <= 1 {
+		chunks := []Chunk{{Content: text, Index: 0, StartChar: 0, EndChar: len(text)}}
+		return chunks, c.metrics(text, chunks), nil
+	}
+
+	embeddings, err := c.embedSentences(ctx, sentences)
+	if err != nil {
+		return nil, ChunkerMetrics{}, err
+	}
+
+	distances := make([]float64, len(sentences)-1)
+	for i := 0; i < len(distances); i++ {
+		distances[i] = cosineDistance(embeddings[i], embeddings[i+1])
+	}
+
+	threshold := c.breakpointThreshold(distances)
+
+	groups := [][]string{{sentences[0]}}
+	for i, d := range distances {
+		last := len(groups) - 1
+		groupLen := groupTextLen(groups[last])
+		if d > threshold && groupLen >= c.Config.MinChunkSize {
+			groups = append(groups, []string{sentences[i+1]})
+			continue
+		}
+		if groupLen+len(sentences[i+1]) > c.Config.MaxChunkSize && c.Config.MaxChunkSize > 0 {
+			groups = append(groups, []string{sentences[i+1]})
+			continue
+		}
+		groups[last] = append(groups[last], sentences[i+1])
+	}
+
+	merged := make([]string, len(groups))
+	for i, g := range groups {
+		merged[i] = strings.Join(g, " ")
+	}
+	merged = foldShortChunks(merged, c.Config.MinChunkSize)
+
+	if c.Config.ChunkOverlap > 0 {
+		merged = addTextOverlap(merged, c.Config.ChunkOverlap, overlapTokenizer(c.Config.TokenAwareOverlap, c.tok))
+	}
+
+	chunks := make([]Chunk, len(merged))
+	offset := 0
+	for i, content := range merged {
+		chunks[i] = Chunk{
+			Content:   content,
+			Index:     i,
+			StartChar: offset,
+			EndChar:   offset + len(content),
+		}
+		offset += len(content)
+	}
+
+	return chunks, c.metrics(text, chunks), nil
+}
+
+// embedSentences embeds sentences, reusing c.Cache for any already seen
+// and, for the rest, embedding in one batch call when c.Vectorizer
+// implements vectorizer.BatchVectorizer, falling back to one EmbedQuery
+// call per sentence otherwise.
+func (c *SemanticChunker) embedSentences(ctx context.Context, sentences []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(sentences))
+	var missIdx []int
+	var missSentences []string
+
+	for i, s := range sentences {
+		if c.Cache == nil {
+			missIdx = append(missIdx, i)
+			missSentences = append(missSentences, s)
+			continue
+		}
+		if v, ok := c.cachedEmbedding(s); ok {
+			embeddings[i] = v
+			continue
+		}
+		missIdx = append(missIdx, i)
+		missSentences = append(missSentences, s)
+	}
+
+	if len(missSentences) == 0 {
+		return embeddings, nil
+	}
+
+	var missed [][]float32
+	if batcher, ok := c.Vectorizer.(vectorizer.BatchVectorizer); ok {
+		vecs, err := batcher.EmbedBatch(ctx, missSentences)
+		if err != nil {
+			return nil, err
+		}
+		missed = vecs
+	} else {
+		missed = make([][]float32, len(missSentences))
+		for i, s := range missSentences {
+			vec, err := c.Vectorizer.EmbedQuery(ctx, s)
+			if err != nil {
+				return nil, err
+			}
+			missed[i] = vec
+		}
+	}
+
+	for i, idx := range missIdx {
+		embeddings[idx] = missed[i]
+		if c.Cache != nil {
+			c.storeEmbedding(missSentences[i], missed[i])
+		}
+	}
+
+	return embeddings, nil
+}
+
+func (c *SemanticChunker) embeddingCacheKey(sentence string) string {
+	sum := sha256.Sum256([]byte(sentence))
+	return "embedding:" + hex.EncodeToString(sum[:])
+}
+
+func (c *SemanticChunker) cachedEmbedding(sentence string) ([]float32, bool) {
+	v, err := c.Cache.GetOrCreate(c.embeddingCacheKey(sentence), func() (any, int64, error) {
+		return nil, 0, errNotCached
+	})
+	if err != nil || v == nil {
+		return nil, false
+	}
+	vec, ok := v.([]float32)
+	return vec, ok
+}
+
+func (c *SemanticChunker) storeEmbedding(sentence string, vec []float32) {
+	key := c.embeddingCacheKey(sentence)
+	_, _ = c.Cache.GetOrCreate(key, func() (any, int64, error) {
+		return vec, int64(len(vec) * 4), nil
+	})
+}
+
+func (c *SemanticChunker) metrics(text string, chunks []Chunk) ChunkerMetrics {
+	return newChunkerMetrics(config.SplitBySemantic, text, chunks)
+}
+
+// breakpointThreshold derives the distance above which a sentence pair is
+// treated as a chunk boundary. SemanticBreakpointPercentile takes
+// precedence over SemanticBreakpointStddev when both are set; if neither
+// is set, it falls back to the 95th percentile.
+func (c *SemanticChunker) breakpointThreshold(distances []float64) float64 {
+	if c.Config.SemanticBreakpointPercentile > 0 {
+		return percentile(distances, c.Config.SemanticBreakpointPercentile)
+	}
+	if c.Config.SemanticBreakpointStddev > 0 {
+		mean, stddev := meanStddev(distances)
+		return mean + c.Config.SemanticBreakpointStddev*stddev
+	}
+	return percentile(distances, 95)
+}
+
+// SplitSentences breaks text into sentences on ./!/? followed by
+// whitespace. It's a lightweight heuristic, not a full NLP tokenizer.
+func SplitSentences(text string) []string {
+	parts := sentenceSplitPattern.Split(text, -1)
+	seps := sentenceSplitPattern.FindAllString(text, -1)
+
+	var sentences []string
+	for i, p := range parts {
+		s := p
+		if i < len(seps) {
+			s += strings.TrimRight(seps[i], " \t\n")
+		}
+		s = strings.TrimSpace(s)
+		if s != "" {
+			sentences = append(sentences, s)
+		}
+	}
+	if len(sentences) == 0 {
+		return []string{text}
+	}
+	return sentences
+}
+
+// splitProtectedSentences is SplitSentences with fenced code blocks and
+// tables kept atomic, mirroring the protected-block handling in
+// Chunker.chunkSection: each such block becomes its own "sentence" rather
+// than being split on internal punctuation.
+func splitProtectedSentences(text string) []string {
+	lines := strings.Split(text, "\n")
+	var sentences []string
+	var prose strings.Builder
+
+	flushProse := func() {
+		if prose.Len() == 0 {
+			return
+		}
+		sentences = append(sentences, SplitSentences(prose.String())...)
+		prose.Reset()
+	}
+
+	i := 0
+	for i < len(lines) {
+		line := lines[i]
+
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			flushProse()
+			blockLines := []string{line}
+			i++
+			for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+				blockLines = append(blockLines, lines[i])
+				i++
+			}
+			if i < len(lines) {
+				blockLines = append(blockLines, lines[i])
+				i++
+			}
+			sentences = append(sentences, strings.Join(blockLines, "\n"))
+			continue
+		}
+
+		if strings.HasPrefix(strings.TrimSpace(line), "|") && strings.HasSuffix(strings.TrimSpace(line), "|") {
+			flushProse()
+			tableLines := []string{line}
+			i++
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|") {
+				tableLines = append(tableLines, lines[i])
+				i++
+			}
+			sentences = append(sentences, strings.Join(tableLines, "\n"))
+			continue
+		}
+
+		prose.WriteString(line)
+		prose.WriteString("\n")
+		i++
+	}
+	flushProse()
+
+	if len(sentences) == 0 {
+		return []string{text}
+	}
+	return sentences
+}
+
+func groupTextLen(sentences []string) int {
+	total := 0
+	for _, s := range sentences {
+		total += len(s) + 1
+	}
+	return total
+}
+
+// cosineDistance returns 1 - cosine similarity between a and b.
+func cosineDistance(a, b []float32) float64 {
+	var dot, normA, normB float64
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return 1 - dot/(math.Sqrt(normA)*math.Sqrt(normB))
+}
+
+// percentile returns the p-th percentile (0-100) of values using linear
+// interpolation between closest ranks.
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	if p <= 0 {
+		return sorted[0]
+	}
+	if p >= 100 {
+		return sorted[len(sorted)-1]
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	if lo == hi {
+		return sorted[lo]
+	}
+	frac := rank - float64(lo)
+	return sorted[lo] + frac*(sorted[hi]-sorted[lo])
+}
+
+// meanStddev returns the population mean and standard deviation of values.
+func meanStddev(values []float64) (mean, stddev float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		d := v - mean
+		variance += d * d
+	}
+	variance /= float64(len(values))
+
+	return mean, math.Sqrt(variance)
+}
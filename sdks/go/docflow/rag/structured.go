@@ -0,0 +1,153 @@
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// maxStructuredRepairAttempts bounds the re-prompt loop CallStructured
+// falls back to when a Provider has no native structured-output mode (or
+// its schema can't use one - see CallStructuredContext).
+const maxStructuredRepairAttempts = 3
+
+// StructuredProvider is implemented by Providers with a native
+// schema-constrained output mode - OpenAI's
+// response_format:{type:"json_schema"}, Anthropic's tool-use with an
+// input_schema, Ollama's format field - so CallStructured can request a
+// schema-valid response directly instead of needing the repair loop.
+type StructuredProvider interface {
+	Structured(ctx context.Context, prompt string, schema map[string]interface{}) (string, error)
+}
+
+// CallStructured sends prompt and unmarshals the response into out, whose
+// type (and schema's, typically the same type as a zero value) drives a
+// JSON Schema generated via reflection. It replaces the old pattern of
+// calling GenerateText/DescribeImage and best-effort parsing the result
+// with extractJSON, which silently dropped data whenever the model
+// deviated from the requested shape.
+func (p *LLMProcessor) CallStructured(prompt string, schema any, out any) error {
+	return p.CallStructuredContext(context.Background(), prompt, schema, out)
+}
+
+// CallStructuredContext is CallStructured with a caller-supplied context
+// for cancelling the underlying request(s) mid-flight.
+func (p *LLMProcessor) CallStructuredContext(ctx context.Context, prompt string, schema any, out any) error {
+	if p.providerErr != nil {
+		return p.providerErr
+	}
+	ctx, cancel := p.withDeadline(ctx)
+	defer cancel()
+
+	jsonSchema := generateSchema(schema)
+	if p.cache == nil {
+		return p.structuredLoop(ctx, prompt, jsonSchema, out, p.wrapTextCall(p.provider.Text))
+	}
+	return p.cachedStructuredCall(ctx, prompt, jsonSchema, nil, out, func(ctx context.Context, out any) error {
+		return p.structuredLoop(ctx, prompt, jsonSchema, out, p.wrapTextCall(p.provider.Text))
+	})
+}
+
+// callStructuredVision is CallStructuredContext for a vision prompt over
+// image data. None of the built-in Providers implement a native
+// structured-output mode for vision calls, so this always goes through
+// the repair loop.
+func (p *LLMProcessor) callStructuredVision(ctx context.Context, data []byte, format, prompt string, schema any, out any) error {
+	if p.providerErr != nil {
+		return p.providerErr
+	}
+	ctx, cancel := p.withDeadline(ctx)
+	defer cancel()
+
+	jsonSchema := generateSchema(schema)
+	call := p.wrapTextCall(func(ctx context.Context, prompt string) (string, error) {
+		return p.provider.Vision(ctx, data, format, prompt)
+	})
+	if p.cache == nil {
+		return p.structuredLoopFallback(ctx, prompt, jsonSchema, out, call)
+	}
+	return p.cachedStructuredCall(ctx, prompt, jsonSchema, data, out, func(ctx context.Context, out any) error {
+		return p.structuredLoopFallback(ctx, prompt, jsonSchema, out, call)
+	})
+}
+
+// cachedStructuredCall is CallStructuredContext/callStructuredVision's
+// cache-aware path: the prompt plus a canonical encoding of schema (since
+// the same prompt text can be asked for under different schemas) forms
+// the cache key. A hit unmarshals the cached JSON directly into out; a
+// miss runs loop and, on success, caches the marshaled out for next time.
+func (p *LLMProcessor) cachedStructuredCall(ctx context.Context, prompt string, schema map[string]interface{}, imageData []byte, out any, loop func(ctx context.Context, out any) error) error {
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return loop(ctx, out)
+	}
+	cacheKeyPrompt := prompt + "\x00" + string(schemaJSON)
+
+	key := p.buildCacheKey(ctx, cacheKeyPrompt, imageData)
+	if response, ok, err := p.cache.Get(ctx, key); err == nil && ok {
+		if err := json.Unmarshal([]byte(response), out); err == nil {
+			return nil
+		}
+		// Stale/incompatible cache entry (out's type changed since it was
+		// written, say) - fall through to a live call instead of failing.
+	}
+
+	if err := loop(ctx, out); err != nil {
+		return err
+	}
+	if raw, err := json.Marshal(out); err == nil {
+		_ = p.cache.Put(ctx, key, string(raw))
+	}
+	return nil
+}
+
+// structuredLoop tries the provider's native StructuredProvider mode first
+// (when its schema is object-shaped - OpenAI's strict json_schema mode
+// requires an object root, so an array/string/etc.-shaped schema, e.g.
+// ExtractKeyPoints' []string, always falls back to fallback below), then
+// falls back to re-prompting fallback with the validator error until the
+// response parses cleanly or the repair budget is exhausted.
+func (p *LLMProcessor) structuredLoop(ctx context.Context, prompt string, schema map[string]interface{}, out any, fallback func(ctx context.Context, prompt string) (string, error)) error {
+	if sp, ok := p.provider.(StructuredProvider); ok && schema["type"] == "object" {
+		if err := p.waitForRateLimit(ctx, prompt); err != nil {
+			return err
+		}
+		response, err := sp.Structured(ctx, prompt, schema)
+		if err != nil {
+			return err
+		}
+		p.recordUsage(prompt, response)
+		if err := json.Unmarshal([]byte(extractJSON(response)), out); err == nil {
+			return nil
+		}
+		// Native mode still produced something that didn't parse (a
+		// provider that ignores the schema, say) - fall through to the
+		// repair loop instead of failing outright.
+	}
+	return p.structuredLoopFallback(ctx, prompt, schema, out, fallback)
+}
+
+func (p *LLMProcessor) structuredLoopFallback(ctx context.Context, prompt string, schema map[string]interface{}, out any, call func(ctx context.Context, prompt string) (string, error)) error {
+	currentPrompt := prompt
+	var lastErr error
+	for attempt := 0; attempt <= maxStructuredRepairAttempts; attempt++ {
+		response, err := call(ctx, currentPrompt)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(extractJSON(response)), out); err == nil {
+			return nil
+		} else {
+			lastErr = err
+			currentPrompt = fmt.Sprintf(`%s
+
+Your previous response did not parse as valid JSON matching the required
+schema (error: %s). Previous response:
+
+%s
+
+Return ONLY valid JSON matching the schema, with no other text.`, prompt, err, response)
+		}
+	}
+	return fmt.Errorf("failed to get structured response after %d attempts: %w", maxStructuredRepairAttempts+1, lastErr)
+}
@@ -0,0 +1,75 @@
+package rag
+
+import (
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+	"github.com/xgaslan/docflow/sdks/go/docflow/tokenizer"
+)
+
+// TokenChunker implements config.SplitByToken: it encodes the whole text
+// with Config.Tokenizer once, then slices the token stream into
+// ChunkSize-token windows (ChunkOverlap tokens of each window repeated at
+// the start of the next), instead of the character counts the other
+// chunkers use.
+type TokenChunker struct {
+	Config config.ChunkingConfig
+
+	tok tokenizer.Tokenizer
+}
+
+// NewTokenChunker creates a TokenChunker, resolving Config.Tokenizer
+// through the tokenizer registry.
+func NewTokenChunker(cfg config.ChunkingConfig) (*TokenChunker, error) {
+	name := cfg.Tokenizer
+	if name == "" {
+		name = "whitespace"
+	}
+	tok, err := tokenizer.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenChunker{Config: cfg, tok: tok}, nil
+}
+
+// Chunk splits text into fixed-size token windows.
+func (c *TokenChunker) Chunk(text string) ([]Chunk, ChunkerMetrics) {
+	tokens := c.tok.Encode(text)
+
+	size := c.Config.ChunkSize
+	if size <= 0 {
+		size = len(tokens)
+	}
+	overlap := c.Config.ChunkOverlap
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+	step := size - overlap
+
+	var chunks []Chunk
+	offset := 0
+	for start := 0; start < len(tokens); start += step {
+		end := start + size
+		if end > len(tokens) {
+			end = len(tokens)
+		}
+		content := c.tok.Decode(tokens[start:end])
+		chunks = append(chunks, Chunk{
+			Content:   content,
+			Index:     len(chunks),
+			StartChar: offset,
+			EndChar:   offset + len(content),
+		})
+		offset += len(content)
+		if end == len(tokens) {
+			break
+		}
+	}
+	if len(chunks) == 0 {
+		chunks = []Chunk{{Content: text, Index: 0, StartChar: 0, EndChar: len(text)}}
+	}
+
+	return chunks, c.metrics(text, chunks)
+}
+
+func (c *TokenChunker) metrics(text string, chunks []Chunk) ChunkerMetrics {
+	return newChunkerMetrics(config.SplitByToken, text, chunks)
+}
@@ -0,0 +1,20 @@
+package rag
+
+import (
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+	"github.com/xgaslan/docflow/sdks/go/docflow/tokenizer"
+)
+
+// resolveTokenizer looks up cfg.Tokenizer in the tokenizer registry,
+// falling back to the whitespace tokenizer if the name isn't registered
+// (e.g. left at its zero value, or a typo) rather than failing chunking
+// outright.
+func resolveTokenizer(cfg config.ChunkingConfig) tokenizer.Tokenizer {
+	if cfg.Tokenizer != "" {
+		if tok, err := tokenizer.Get(cfg.Tokenizer); err == nil {
+			return tok
+		}
+	}
+	tok, _ := tokenizer.Get("whitespace")
+	return tok
+}
@@ -26,6 +26,7 @@ type ChunkMetadata struct {
 	SectionIndex    int
 	SubsectionIndex int
 	ContentType     string // text, table, code, image
+	TokenCount      int    // token count per Chunker.Config.Tokenizer (or "whitespace" if unset)
 }
 
 // ExtractedImage represents an extracted image.
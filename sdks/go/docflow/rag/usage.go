@@ -0,0 +1,101 @@
+package rag
+
+import "sync"
+
+// Usage is a snapshot of tokens and estimated cost consumed so far.
+type Usage struct {
+	Calls            int64
+	PromptTokens     int64
+	CompletionTokens int64
+	CostUSD          float64
+}
+
+// UsageEvent is emitted to every registered UsageReporter each time
+// UsageMeter.Record is called.
+type UsageEvent struct {
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	CostUSD          float64
+}
+
+// UsageReporter consumes UsageEvents, e.g. to export them as Prometheus
+// counters (see PrometheusUsageReporter). Report must return quickly -
+// UsageMeter.Record calls it synchronously from the calling goroutine.
+type UsageReporter interface {
+	Report(e UsageEvent)
+}
+
+// modelPricing is USD per 1000 tokens, prompt and completion priced
+// separately since most providers charge more for completion tokens.
+type modelPricing struct {
+	promptPer1K     float64
+	completionPer1K float64
+}
+
+// defaultPricing covers a handful of well-known models as of their
+// respective announcements; an unrecognized Config.Model still
+// contributes to PromptTokens/CompletionTokens, just not CostUSD.
+var defaultPricing = map[string]modelPricing{
+	"gpt-4o":                     {promptPer1K: 0.005, completionPer1K: 0.015},
+	"gpt-4o-mini":                {promptPer1K: 0.00015, completionPer1K: 0.0006},
+	"gpt-4-vision-preview":       {promptPer1K: 0.01, completionPer1K: 0.03},
+	"gpt-4":                      {promptPer1K: 0.03, completionPer1K: 0.06},
+	"gpt-3.5-turbo":              {promptPer1K: 0.0005, completionPer1K: 0.0015},
+	"claude-3-5-sonnet-20241022": {promptPer1K: 0.003, completionPer1K: 0.015},
+	"claude-3-opus-20240229":     {promptPer1K: 0.015, completionPer1K: 0.075},
+	"claude-3-haiku-20240307":    {promptPer1K: 0.00025, completionPer1K: 0.00125},
+}
+
+// UsageMeter accumulates prompt/completion token counts and estimated USD
+// cost across calls, so a long-running batch can be budgeted against
+// instead of discovered after the invoice arrives. LLMProcessor records
+// into one when configured via WithUsageMeter; Snapshot/Usage exposes the
+// running total, and reporters registered via AddReporter get a live feed.
+type UsageMeter struct {
+	mu        sync.Mutex
+	usage     Usage
+	reporters []UsageReporter
+}
+
+// NewUsageMeter creates an empty UsageMeter.
+func NewUsageMeter() *UsageMeter {
+	return &UsageMeter{}
+}
+
+// AddReporter registers r to receive every subsequent UsageEvent.
+func (m *UsageMeter) AddReporter(r UsageReporter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reporters = append(m.reporters, r)
+}
+
+// Record adds one call's token counts to the running total, pricing it
+// against model via defaultPricing if recognized, and forwards the event
+// to every registered UsageReporter.
+func (m *UsageMeter) Record(model string, promptTokens, completionTokens int) {
+	var cost float64
+	if pricing, ok := defaultPricing[model]; ok {
+		cost = float64(promptTokens)/1000*pricing.promptPer1K + float64(completionTokens)/1000*pricing.completionPer1K
+	}
+
+	m.mu.Lock()
+	m.usage.Calls++
+	m.usage.PromptTokens += int64(promptTokens)
+	m.usage.CompletionTokens += int64(completionTokens)
+	m.usage.CostUSD += cost
+	reporters := append([]UsageReporter(nil), m.reporters...)
+	m.mu.Unlock()
+
+	event := UsageEvent{Model: model, PromptTokens: promptTokens, CompletionTokens: completionTokens, CostUSD: cost}
+	for _, r := range reporters {
+		r.Report(event)
+	}
+}
+
+// Snapshot returns the running totals.
+func (m *UsageMeter) Snapshot() Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.usage
+}
@@ -0,0 +1,43 @@
+package rag
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// PrometheusUsageReporter records UsageMeter events as Prometheus
+// counters, so a host service can expose LLM token/cost usage on its own
+// /metrics endpoint alongside its other collectors.
+type PrometheusUsageReporter struct {
+	tokensTotal *prometheus.CounterVec
+	costTotal   *prometheus.CounterVec
+	callsTotal  *prometheus.CounterVec
+}
+
+// NewPrometheusUsageReporter creates a PrometheusUsageReporter and
+// registers its collectors with reg (typically
+// prometheus.DefaultRegisterer). Pass the result to
+// UsageMeter.AddReporter.
+func NewPrometheusUsageReporter(reg prometheus.Registerer) *PrometheusUsageReporter {
+	p := &PrometheusUsageReporter{
+		tokensTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "docflow_llm_tokens_total",
+			Help: "LLM tokens consumed, labeled by model and kind (prompt/completion).",
+		}, []string{"model", "kind"}),
+		costTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "docflow_llm_cost_usd_total",
+			Help: "Estimated LLM cost in USD, labeled by model.",
+		}, []string{"model"}),
+		callsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "docflow_llm_calls_total",
+			Help: "LLM calls recorded, labeled by model.",
+		}, []string{"model"}),
+	}
+	reg.MustRegister(p.tokensTotal, p.costTotal, p.callsTotal)
+	return p
+}
+
+// Report implements UsageReporter.
+func (p *PrometheusUsageReporter) Report(e UsageEvent) {
+	p.tokensTotal.WithLabelValues(e.Model, "prompt").Add(float64(e.PromptTokens))
+	p.tokensTotal.WithLabelValues(e.Model, "completion").Add(float64(e.CompletionTokens))
+	p.costTotal.WithLabelValues(e.Model).Add(e.CostUSD)
+	p.callsTotal.WithLabelValues(e.Model).Inc()
+}
@@ -0,0 +1,84 @@
+// Package retrieval fuses ranked result lists from independent rankers
+// (keyword, dense vector, sparse/ColBERT) into a single ranking, so the
+// Azure AI Search, Postgres, and MongoDB backends can share one fusion
+// implementation instead of each reimplementing it.
+package retrieval
+
+import "sort"
+
+// RankedResult is one document's position in a single ranker's result
+// list. Rank is 1-based; Score is that ranker's raw, ranker-specific
+// score and is only used for tie-breaking, never compared across rankers.
+type RankedResult struct {
+	ID    string
+	Rank  int
+	Score float64
+}
+
+// FusedResult is a document's combined score after fusing across rankers.
+type FusedResult struct {
+	ID    string
+	Score float64
+
+	// DenseScore is the raw score this document got from the dense
+	// ranker, or 0 if it didn't appear there. Used to break ties.
+	DenseScore float64
+}
+
+// RRF fuses rankers with reciprocal rank fusion: each document's score is
+// Σ 1/(k + rank_i), summed over every ranker list the document appears
+// in. Documents missing from a ranker contribute 0 for that ranker. k
+// defaults to 60 when <= 0, the standard RRF constant. denseRankerIndex
+// identifies which entry in rankers is the dense/vector ranker, so its
+// raw score can be used to break ties deterministically.
+func RRF(rankers [][]RankedResult, k int, denseRankerIndex int) []FusedResult {
+	if k <= 0 {
+		k = 60
+	}
+
+	scores := make(map[string]float64)
+	dense := make(map[string]float64)
+	order := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for ri, results := range rankers {
+		for _, r := range results {
+			if !seen[r.ID] {
+				seen[r.ID] = true
+				order = append(order, r.ID)
+			}
+			scores[r.ID] += 1.0 / float64(k+r.Rank)
+			if ri == denseRankerIndex {
+				dense[r.ID] = r.Score
+			}
+		}
+	}
+
+	fused := make([]FusedResult, len(order))
+	for i, id := range order {
+		fused[i] = FusedResult{ID: id, Score: scores[id], DenseScore: dense[id]}
+	}
+
+	sort.Slice(fused, func(i, j int) bool {
+		if fused[i].Score != fused[j].Score {
+			return fused[i].Score > fused[j].Score
+		}
+		return fused[i].DenseScore > fused[j].DenseScore
+	})
+
+	return fused
+}
+
+// Rank numbers a ranker's already-sorted-descending result list 1..n,
+// the shape RRF expects as input.
+func Rank(ids []string, rawScores []float64) []RankedResult {
+	results := make([]RankedResult, len(ids))
+	for i, id := range ids {
+		score := 0.0
+		if i < len(rawScores) {
+			score = rawScores[i]
+		}
+		results[i] = RankedResult{ID: id, Rank: i + 1, Score: score}
+	}
+	return results
+}
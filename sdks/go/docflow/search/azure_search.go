@@ -2,21 +2,34 @@ package search
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
 )
 
-// AzureAISearch client for Azure Cognitive Search.
+// AzureAISearch client for Azure Cognitive Search. Every call accepts a
+// context.Context for cancellation/deadlines, in addition to an optional
+// client-wide deadline set via SetDeadline/SetTimeout; callers that want
+// a request aborted when e.g. an upstream HTTP client disconnects should
+// cancel the context they pass in (this package has no HTTP server of its
+// own to wire that up to).
 type AzureAISearch struct {
-	endpoint   string
-	apiKey     string
-	indexName  string
-	apiVersion string
-	httpClient *http.Client
+	endpoint      string
+	apiKey        string
+	indexName     string
+	apiVersion    string
+	queryRewrites string
+	queryLanguage string
+	httpClient    *http.Client
+	reranker      Reranker
+	deadline      *deadlineTimer
 }
 
 // AzureSearchConfig holds configuration for Azure AI Search.
@@ -26,25 +39,106 @@ type AzureSearchConfig struct {
 	IndexName    string
 	APIVersion   string
 	VectorFields string
+
+	// QueryRewrites enables generative query rewriting ("generative" or
+	// "none"). QueryLanguage is the BCP-47 language code sent alongside
+	// semantic queries and rewrites.
+	QueryRewrites string
+	QueryLanguage string
 }
 
 // DefaultAzureSearchConfig returns sensible defaults.
 func DefaultAzureSearchConfig() AzureSearchConfig {
 	return AzureSearchConfig{
-		APIVersion:   "2024-05-01-preview",
-		VectorFields: "content_vector",
+		APIVersion:    "2024-05-01-preview",
+		VectorFields:  "content_vector",
+		QueryRewrites: "none",
+		QueryLanguage: "en-us",
 	}
 }
 
 // NewAzureAISearch creates a new Azure AI Search client.
 func NewAzureAISearch(config AzureSearchConfig) *AzureAISearch {
 	return &AzureAISearch{
-		endpoint:   config.Endpoint,
-		apiKey:     config.APIKey,
-		indexName:  config.IndexName,
-		apiVersion: config.APIVersion,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		endpoint:      config.Endpoint,
+		apiKey:        config.APIKey,
+		indexName:     config.IndexName,
+		apiVersion:    config.APIVersion,
+		queryRewrites: config.QueryRewrites,
+		queryLanguage: config.QueryLanguage,
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		deadline:      newDeadlineTimer(),
+	}
+}
+
+// deadlineTimer implements the net.Conn-style deadline pattern: a channel
+// that a time.AfterFunc closes when the deadline elapses, so a blocked
+// call can select on it alongside a request's own context. set with a
+// zero Time disarms it.
+type deadlineTimer struct {
+	mu    sync.Mutex
+	timer *time.Timer
+	done  chan struct{}
+}
+
+func newDeadlineTimer() *deadlineTimer {
+	return &deadlineTimer{done: make(chan struct{})}
+}
+
+func (d *deadlineTimer) set(deadline time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.done = make(chan struct{})
+	if deadline.IsZero() {
+		d.timer = nil
+		return
+	}
+
+	done := d.done
+	d.timer = time.AfterFunc(time.Until(deadline), func() { close(done) })
+}
+
+func (d *deadlineTimer) channel() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.done
+}
+
+// SetDeadline sets an absolute deadline that cancels every AzureAISearch
+// call still running (or started) after it, analogous to net.Conn's
+// SetDeadline. A zero Time clears any deadline.
+func (c *AzureAISearch) SetDeadline(deadline time.Time) {
+	c.deadline.set(deadline)
+}
+
+// SetTimeout sets a deadline timeout-from-now that cancels every
+// AzureAISearch call still running (or started) before it elapses. A
+// non-positive d clears any deadline.
+func (c *AzureAISearch) SetTimeout(d time.Duration) {
+	if d <= 0 {
+		c.deadline.set(time.Time{})
+		return
 	}
+	c.deadline.set(time.Now().Add(d))
+}
+
+// withDeadline derives a context from ctx that's also canceled when the
+// client's deadline (if any) elapses.
+func (c *AzureAISearch) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	derived, cancel := context.WithCancel(ctx)
+	ch := c.deadline.channel()
+	go func() {
+		select {
+		case <-ch:
+			cancel()
+		case <-derived.Done():
+		}
+	}()
+	return derived, cancel
 }
 
 // AzureSearchResult represents a search result.
@@ -56,17 +150,49 @@ type AzureSearchResult struct {
 }
 
 // Search performs a keyword search.
-func (c *AzureAISearch) Search(query string, top int) ([]AzureSearchResult, error) {
-	return c.HybridSearch(query, nil, top)
+func (c *AzureAISearch) Search(ctx context.Context, query string, top int) ([]AzureSearchResult, error) {
+	return c.HybridSearch(ctx, query, nil, top)
 }
 
 // VectorSearch performs a vector-only search.
-func (c *AzureAISearch) VectorSearch(vector []float32, top int) ([]AzureSearchResult, error) {
-	return c.HybridSearch("", vector, top)
+func (c *AzureAISearch) VectorSearch(ctx context.Context, vector []float32, top int) ([]AzureSearchResult, error) {
+	return c.HybridSearch(ctx, "", vector, top)
+}
+
+// VectorizableTextQuery sends query text for the search service's own
+// vectorizer to embed, instead of a pre-computed vector, per Azure AI
+// Search's "kind": "text" vector query.
+type VectorizableTextQuery struct {
+	Text           string
+	VectorizerName string
+}
+
+// VectorSearchText performs a vector-only search using a vectorizer bound
+// to the index's vector profile, so docflow never computes the query
+// embedding itself.
+func (c *AzureAISearch) VectorSearchText(ctx context.Context, q VectorizableTextQuery, top int) ([]AzureSearchResult, error) {
+	return c.hybridSearch(ctx, "", nil, &q, top, config.RetrievalConfig{})
 }
 
 // HybridSearch performs a hybrid keyword + vector search.
-func (c *AzureAISearch) HybridSearch(query string, vector []float32, top int) ([]AzureSearchResult, error) {
+func (c *AzureAISearch) HybridSearch(ctx context.Context, query string, vector []float32, top int) ([]AzureSearchResult, error) {
+	return c.hybridSearch(ctx, query, vector, nil, top, config.RetrievalConfig{})
+}
+
+// HybridSearchFusion performs a hybrid keyword + vector search with an
+// explicit fusion mode. When fusion.HybridFusion is config.HybridFusionRRF,
+// Azure AI Search's native reciprocal rank fusion is requested via the
+// "hybridSearch" query parameter, with PerRankerTopN controlling how many
+// candidates each side recalls before fusing; RRFK is not sent because the
+// service fixes its RRF constant at 60 and does not expose it.
+func (c *AzureAISearch) HybridSearchFusion(ctx context.Context, query string, vector []float32, top int, fusion config.RetrievalConfig) ([]AzureSearchResult, error) {
+	return c.hybridSearch(ctx, query, vector, nil, top, fusion)
+}
+
+// hybridSearch is the shared implementation behind HybridSearch and
+// VectorSearchText; textQuery, when set, sends a vectorizable text query
+// instead of (or alongside) a pre-computed vector.
+func (c *AzureAISearch) hybridSearch(ctx context.Context, query string, vector []float32, textQuery *VectorizableTextQuery, top int, fusion config.RetrievalConfig) ([]AzureSearchResult, error) {
 	requestBody := make(map[string]interface{})
 
 	if query != "" {
@@ -82,11 +208,39 @@ func (c *AzureAISearch) HybridSearch(query string, vector []float32, top int) ([
 				"k":      top,
 			},
 		}
+	} else if textQuery != nil {
+		requestBody["vectorQueries"] = []map[string]interface{}{
+			{
+				"kind":       "text",
+				"text":       textQuery.Text,
+				"vectorizer": textQuery.VectorizerName,
+				"fields":     "content_vector",
+				"k":          top,
+			},
+		}
+	}
+
+	if fusion.HybridFusion == config.HybridFusionRRF {
+		perRankerTopN := fusion.PerRankerTopN
+		if perRankerTopN <= 0 {
+			perRankerTopN = 100
+		}
+		requestBody["hybridSearch"] = map[string]interface{}{
+			"maxTextRecallSize": perRankerTopN,
+			"countAndFacetMode": "countRetrievableResults",
+		}
 	}
 
 	requestBody["top"] = top
 	requestBody["select"] = "id,content,metadata"
 
+	if c.queryRewrites != "" && c.queryRewrites != "none" {
+		requestBody["queryRewrites"] = c.queryRewrites
+	}
+	if c.queryLanguage != "" {
+		requestBody["queryLanguage"] = c.queryLanguage
+	}
+
 	bodyBytes, err := json.Marshal(requestBody)
 	if err != nil {
 		return nil, err
@@ -95,7 +249,10 @@ func (c *AzureAISearch) HybridSearch(query string, vector []float32, top int) ([
 	url := fmt.Sprintf("%s/indexes/%s/docs/search?api-version=%s",
 		c.endpoint, c.indexName, c.apiVersion)
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(bodyBytes))
+	ctx, cancel := c.withDeadline(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
 	if err != nil {
 		return nil, err
 	}
@@ -126,7 +283,7 @@ func (c *AzureAISearch) HybridSearch(query string, vector []float32, top int) ([
 }
 
 // UploadDocuments uploads documents to the index.
-func (c *AzureAISearch) UploadDocuments(documents []map[string]interface{}) error {
+func (c *AzureAISearch) UploadDocuments(ctx context.Context, documents []map[string]interface{}) error {
 	for _, doc := range documents {
 		doc["@search.action"] = "mergeOrUpload"
 	}
@@ -143,7 +300,10 @@ func (c *AzureAISearch) UploadDocuments(documents []map[string]interface{}) erro
 	url := fmt.Sprintf("%s/indexes/%s/docs/index?api-version=%s",
 		c.endpoint, c.indexName, c.apiVersion)
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(bodyBytes))
+	ctx, cancel := c.withDeadline(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
 	if err != nil {
 		return err
 	}
@@ -166,7 +326,7 @@ func (c *AzureAISearch) UploadDocuments(documents []map[string]interface{}) erro
 }
 
 // DeleteDocuments deletes documents by ID.
-func (c *AzureAISearch) DeleteDocuments(ids []string) error {
+func (c *AzureAISearch) DeleteDocuments(ctx context.Context, ids []string) error {
 	documents := make([]map[string]interface{}, len(ids))
 	for i, id := range ids {
 		documents[i] = map[string]interface{}{
@@ -187,7 +347,10 @@ func (c *AzureAISearch) DeleteDocuments(ids []string) error {
 	url := fmt.Sprintf("%s/indexes/%s/docs/index?api-version=%s",
 		c.endpoint, c.indexName, c.apiVersion)
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(bodyBytes))
+	ctx, cancel := c.withDeadline(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
 	if err != nil {
 		return err
 	}
@@ -210,7 +373,7 @@ func (c *AzureAISearch) DeleteDocuments(ids []string) error {
 }
 
 // SearchWithFilter performs a filtered search.
-func (c *AzureAISearch) SearchWithFilter(query string, filter string, top int) ([]AzureSearchResult, error) {
+func (c *AzureAISearch) SearchWithFilter(ctx context.Context, query string, filter string, top int) ([]AzureSearchResult, error) {
 	requestBody := map[string]interface{}{
 		"search": query,
 		"top":    top,
@@ -229,7 +392,10 @@ func (c *AzureAISearch) SearchWithFilter(query string, filter string, top int) (
 	url := fmt.Sprintf("%s/indexes/%s/docs/search?api-version=%s",
 		c.endpoint, c.indexName, c.apiVersion)
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(bodyBytes))
+	ctx, cancel := c.withDeadline(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
 	if err != nil {
 		return nil, err
 	}
@@ -254,6 +420,34 @@ func (c *AzureAISearch) SearchWithFilter(query string, filter string, top int) (
 	return response.Value, nil
 }
 
+// Upsert satisfies search.Backend; it's a thin alias for UploadDocuments.
+func (c *AzureAISearch) Upsert(ctx context.Context, documents []map[string]interface{}) error {
+	return c.UploadDocuments(ctx, documents)
+}
+
+// Delete satisfies search.Backend; it's a thin alias for DeleteDocuments.
+func (c *AzureAISearch) Delete(ctx context.Context, ids []string) error {
+	return c.DeleteDocuments(ctx, ids)
+}
+
+// EnsureIndex satisfies search.Backend. It's a no-op for AzureAISearch:
+// index/schema provisioning for Azure AI Search is done through the
+// portal, ARM, or Bicep, not this client.
+func (c *AzureAISearch) EnsureIndex(ctx context.Context, schema IndexSchema) error {
+	return nil
+}
+
+// SearchWithFilterExpr is SearchWithFilter's typed counterpart: filter is
+// a Filter built from Eq/And/Or/In/etc instead of a raw OData string,
+// which avoids injection bugs when filter values come from user input.
+func (c *AzureAISearch) SearchWithFilterExpr(ctx context.Context, query string, filter Filter, top int) ([]AzureSearchResult, error) {
+	var expr string
+	if filter != nil {
+		expr = filter.String()
+	}
+	return c.SearchWithFilter(ctx, query, expr, top)
+}
+
 // buildODataFilter constructs an OData filter expression.
 func buildODataFilter(filters map[string]interface{}) string {
 	if len(filters) == 0 {
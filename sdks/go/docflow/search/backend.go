@@ -0,0 +1,75 @@
+package search
+
+import (
+	"context"
+	"fmt"
+)
+
+// Backend is the common interface every search provider docflow can
+// index into and query implements: AzureAISearch, ElasticsearchBackend,
+// and MemoryBackend. Selecting a provider through config (e.g. a
+// SEARCH_BACKEND=azure|elastic|memory setting) lets callers, and unit
+// tests, swap providers without touching call sites.
+type Backend interface {
+	Search(ctx context.Context, query string, top int) ([]AzureSearchResult, error)
+	HybridSearch(ctx context.Context, query string, vector []float32, top int) ([]AzureSearchResult, error)
+	Upsert(ctx context.Context, documents []map[string]interface{}) error
+	Delete(ctx context.Context, ids []string) error
+	EnsureIndex(ctx context.Context, schema IndexSchema) error
+}
+
+// IndexSchema describes what EnsureIndex should provision, independent
+// of any one backend's native schema format.
+type IndexSchema struct {
+	Name      string
+	VectorDim int
+}
+
+var (
+	_ Backend = (*AzureAISearch)(nil)
+	_ Backend = (*MemoryBackend)(nil)
+	_ Backend = (*ElasticsearchBackend)(nil)
+)
+
+// CopyIndex migrates every document visible through src.Search into dst,
+// batchSize documents at a time, for switching search providers without
+// hand-rolling pagination. It returns how many documents were copied.
+//
+// Backend has no scroll/paginate primitive, so CopyIndex reads src with
+// one large Search call; that makes it a good fit for small-to-medium
+// indexes, but very large migrations are better served by each backend's
+// own bulk export tooling.
+func CopyIndex(ctx context.Context, src, dst Backend, batchSize int) (int, error) {
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	results, err := src.Search(ctx, "", batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("read source index: %w", err)
+	}
+
+	copied := 0
+	for start := 0; start < len(results); start += batchSize {
+		end := start + batchSize
+		if end > len(results) {
+			end = len(results)
+		}
+
+		batch := make([]map[string]interface{}, end-start)
+		for i, r := range results[start:end] {
+			doc := map[string]interface{}{"id": r.ID, "content": r.Content}
+			if r.Metadata != nil {
+				doc["metadata"] = r.Metadata
+			}
+			batch[i] = doc
+		}
+
+		if err := dst.Upsert(ctx, batch); err != nil {
+			return copied, fmt.Errorf("write destination index: %w", err)
+		}
+		copied += len(batch)
+	}
+
+	return copied, nil
+}
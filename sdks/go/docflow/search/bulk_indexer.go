@@ -0,0 +1,385 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BulkIndexerConfig configures a BulkIndexer's batching and retry
+// behavior.
+type BulkIndexerConfig struct {
+	// FlushActions and FlushBytes cap how large a batch can grow before
+	// it's flushed automatically. Azure AI Search caps a batch at
+	// roughly 1000 documents or 16MB, whichever comes first; those are
+	// also this config's defaults.
+	FlushActions int
+	FlushBytes   int
+
+	// FlushInterval flushes a non-empty, under-threshold batch on a
+	// timer, so documents don't sit buffered indefinitely under light
+	// load. 0 disables the timer.
+	FlushInterval time.Duration
+
+	// MaxRetries caps how many times a failed document is retried
+	// before being counted as failed for good.
+	MaxRetries int
+
+	// Workers caps how many flushes can be in flight concurrently.
+	// Defaults to 1 (flush sequentially).
+	Workers int
+
+	// BackoffFunc returns how long to wait before the given retry
+	// attempt (0-based). Defaults to ExponentialBackoff(100ms, 30s).
+	BackoffFunc func(retry int) time.Duration
+}
+
+func (cfg BulkIndexerConfig) withDefaults() BulkIndexerConfig {
+	if cfg.FlushActions <= 0 {
+		cfg.FlushActions = 1000
+	}
+	if cfg.FlushBytes <= 0 {
+		cfg.FlushBytes = 16 * 1024 * 1024
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.BackoffFunc == nil {
+		cfg.BackoffFunc = ExponentialBackoff(100*time.Millisecond, 30*time.Second)
+	}
+	return cfg
+}
+
+// ExponentialBackoff returns a backoff function that doubles from initial
+// up to max as retry grows, with up to 50% jitter so many retrying
+// workers don't all retry in lockstep.
+func ExponentialBackoff(initial, max time.Duration) func(retry int) time.Duration {
+	return func(retry int) time.Duration {
+		d := initial
+		for i := 0; i < retry && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		if d <= 0 {
+			return 0
+		}
+		return d/2 + time.Duration(rand.Int63n(int64(d)/2+1))
+	}
+}
+
+// BulkIndexerStats reports a BulkIndexer's cumulative counters.
+type BulkIndexerStats struct {
+	Added   int64
+	Flushed int64
+	Failed  int64
+	Retried int64
+	Bytes   int64
+}
+
+// bulkAction is one buffered Add or Delete call.
+type bulkAction struct {
+	id       string
+	document map[string]interface{}
+	isDelete bool
+}
+
+// key is the document ID this action acts on, used to match it against
+// Azure's per-document status in a batch response.
+func (a bulkAction) key() string {
+	if a.isDelete {
+		return a.id
+	}
+	if id, ok := a.document["id"].(string); ok {
+		return id
+	}
+	return ""
+}
+
+func (a bulkAction) toRequest() map[string]interface{} {
+	if a.isDelete {
+		return map[string]interface{}{"@search.action": "delete", "id": a.id}
+	}
+	doc := make(map[string]interface{}, len(a.document)+1)
+	for k, v := range a.document {
+		doc[k] = v
+	}
+	doc["@search.action"] = "mergeOrUpload"
+	return doc
+}
+
+// BulkIndexer batches Add/Delete actions against an AzureAISearch index,
+// flushing on size, byte, or time thresholds and retrying partial
+// (HTTP 207) failures with backoff, so callers can index thousands of
+// documents without hand-writing batching/retry logic themselves.
+type BulkIndexer struct {
+	client *AzureAISearch
+	cfg    BulkIndexerConfig
+
+	mu           sync.Mutex
+	pending      []bulkAction
+	pendingBytes int
+	timer        *time.Timer
+	closed       bool
+
+	added, flushed, failed, retried, bytesTotal int64
+
+	sem chan struct{}
+	wg  sync.WaitGroup
+}
+
+// NewBulkIndexer creates a BulkIndexer that flushes batches through
+// client.
+func NewBulkIndexer(client *AzureAISearch, cfg BulkIndexerConfig) *BulkIndexer {
+	cfg = cfg.withDefaults()
+	return &BulkIndexer{
+		client: client,
+		cfg:    cfg,
+		sem:    make(chan struct{}, cfg.Workers),
+	}
+}
+
+// Add buffers an upsert ("mergeOrUpload") action, flushing the current
+// batch immediately if it now meets the FlushActions/FlushBytes
+// threshold.
+func (b *BulkIndexer) Add(ctx context.Context, doc map[string]interface{}) error {
+	return b.enqueue(ctx, bulkAction{document: doc})
+}
+
+// Delete buffers a delete-by-id action.
+func (b *BulkIndexer) Delete(ctx context.Context, id string) error {
+	return b.enqueue(ctx, bulkAction{id: id, isDelete: true})
+}
+
+func (b *BulkIndexer) enqueue(ctx context.Context, a bulkAction) error {
+	size := estimateSize(a.toRequest())
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return fmt.Errorf("bulk indexer is closed")
+	}
+
+	b.pending = append(b.pending, a)
+	b.pendingBytes += size
+	b.added++
+	b.bytesTotal += int64(size)
+
+	var batch []bulkAction
+	if len(b.pending) >= b.cfg.FlushActions || b.pendingBytes >= b.cfg.FlushBytes {
+		batch = b.pending
+		b.pending = nil
+		b.pendingBytes = 0
+		b.stopTimerLocked()
+	} else {
+		b.resetTimerLocked()
+	}
+	b.mu.Unlock()
+
+	if batch != nil {
+		b.flushAsync(ctx, batch)
+	}
+	return nil
+}
+
+func estimateSize(doc map[string]interface{}) int {
+	data, _ := json.Marshal(doc)
+	return len(data)
+}
+
+func (b *BulkIndexer) resetTimerLocked() {
+	if b.cfg.FlushInterval <= 0 {
+		return
+	}
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.timer = time.AfterFunc(b.cfg.FlushInterval, b.flushDue)
+}
+
+func (b *BulkIndexer) stopTimerLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+}
+
+func (b *BulkIndexer) flushDue() {
+	b.mu.Lock()
+	if len(b.pending) == 0 {
+		b.mu.Unlock()
+		return
+	}
+	batch := b.pending
+	b.pending = nil
+	b.pendingBytes = 0
+	b.mu.Unlock()
+
+	b.flushAsync(context.Background(), batch)
+}
+
+// flushAsync runs a flush on its own goroutine, gated by the Workers
+// semaphore, without blocking the caller that triggered it.
+func (b *BulkIndexer) flushAsync(ctx context.Context, batch []bulkAction) {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.sem <- struct{}{}
+		defer func() { <-b.sem }()
+		b.flush(ctx, batch)
+	}()
+}
+
+// flush sends batch, resending only the items Azure reports as failed,
+// with backoff between attempts, until everything succeeds, MaxRetries
+// is exhausted, or ctx is done.
+func (b *BulkIndexer) flush(ctx context.Context, batch []bulkAction) {
+	for attempt := 0; ; attempt++ {
+		failedItems, err := b.send(ctx, batch)
+		if err != nil {
+			failedItems = batch
+		}
+
+		if n := len(batch) - len(failedItems); n > 0 {
+			b.mu.Lock()
+			b.flushed += int64(n)
+			b.mu.Unlock()
+		}
+		if len(failedItems) == 0 {
+			return
+		}
+		if attempt >= b.cfg.MaxRetries {
+			b.mu.Lock()
+			b.failed += int64(len(failedItems))
+			b.mu.Unlock()
+			return
+		}
+
+		b.mu.Lock()
+		b.retried += int64(len(failedItems))
+		b.mu.Unlock()
+
+		select {
+		case <-time.After(b.cfg.BackoffFunc(attempt)):
+		case <-ctx.Done():
+			b.mu.Lock()
+			b.failed += int64(len(failedItems))
+			b.mu.Unlock()
+			return
+		}
+		batch = failedItems
+	}
+}
+
+type bulkResultItem struct {
+	Key          string `json:"key"`
+	Status       bool   `json:"status"`
+	StatusCode   int    `json:"statusCode"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// send POSTs one batch to Azure's docs/index endpoint and reports which
+// actions failed, by matching the response's per-document status against
+// each action's key. A non-200/207 response fails every action in batch.
+func (b *BulkIndexer) send(ctx context.Context, batch []bulkAction) ([]bulkAction, error) {
+	docs := make([]map[string]interface{}, len(batch))
+	for i, a := range batch {
+		docs[i] = a.toRequest()
+	}
+
+	bodyBytes, err := json.Marshal(map[string]interface{}{"value": docs})
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/indexes/%s/docs/index?api-version=%s",
+		b.client.endpoint, b.client.indexName, b.client.apiVersion)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("api-key", b.client.apiKey)
+
+	resp, err := b.client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusMultiStatus {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("bulk index failed: %s", string(respBody))
+	}
+
+	var result struct {
+		Value []bulkResultItem `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]bulkResultItem, len(result.Value))
+	for _, item := range result.Value {
+		byKey[item.Key] = item
+	}
+
+	var failed []bulkAction
+	for _, a := range batch {
+		if item, ok := byKey[a.key()]; !ok || !item.Status {
+			failed = append(failed, a)
+		}
+	}
+	return failed, nil
+}
+
+// Stats returns a snapshot of the indexer's cumulative counters.
+func (b *BulkIndexer) Stats() BulkIndexerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return BulkIndexerStats{
+		Added:   b.added,
+		Flushed: b.flushed,
+		Failed:  b.failed,
+		Retried: b.retried,
+		Bytes:   b.bytesTotal,
+	}
+}
+
+// Close flushes any buffered actions and waits for every in-flight flush
+// to finish, or for ctx to be done, whichever comes first. Add/Delete
+// return an error after Close is called.
+func (b *BulkIndexer) Close(ctx context.Context) error {
+	b.mu.Lock()
+	b.closed = true
+	b.stopTimerLocked()
+	batch := b.pending
+	b.pending = nil
+	b.pendingBytes = 0
+	b.mu.Unlock()
+
+	if len(batch) > 0 {
+		b.flushAsync(ctx, batch)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
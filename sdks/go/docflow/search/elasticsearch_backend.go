@@ -0,0 +1,253 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+// ElasticsearchBackend implements Backend against Elasticsearch (and
+// OpenSearch, which speaks the same bulk/_search/knn API) via the
+// official go-elasticsearch/v8 client.
+type ElasticsearchBackend struct {
+	client       *elasticsearch.Client
+	index        string
+	vectorField  string
+	contentField string
+}
+
+// ElasticsearchConfig holds connection settings for ElasticsearchBackend.
+type ElasticsearchConfig struct {
+	Addresses []string
+	APIKey    string
+	Username  string
+	Password  string
+
+	Index        string
+	VectorField  string
+	ContentField string
+}
+
+// DefaultElasticsearchConfig returns sensible defaults.
+func DefaultElasticsearchConfig() ElasticsearchConfig {
+	return ElasticsearchConfig{
+		Addresses:    []string{"http://localhost:9200"},
+		Index:        "docflow",
+		VectorField:  "content_vector",
+		ContentField: "content",
+	}
+}
+
+// NewElasticsearchBackend creates an ElasticsearchBackend.
+func NewElasticsearchBackend(cfg ElasticsearchConfig) (*ElasticsearchBackend, error) {
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: cfg.Addresses,
+		APIKey:    cfg.APIKey,
+		Username:  cfg.Username,
+		Password:  cfg.Password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create elasticsearch client: %w", err)
+	}
+
+	vectorField := cfg.VectorField
+	if vectorField == "" {
+		vectorField = "content_vector"
+	}
+	contentField := cfg.ContentField
+	if contentField == "" {
+		contentField = "content"
+	}
+
+	return &ElasticsearchBackend{
+		client:       client,
+		index:        cfg.Index,
+		vectorField:  vectorField,
+		contentField: contentField,
+	}, nil
+}
+
+// EnsureIndex creates the index with a dense_vector mapping for
+// VectorField if it doesn't already exist.
+func (e *ElasticsearchBackend) EnsureIndex(ctx context.Context, schema IndexSchema) error {
+	name := schema.Name
+	if name == "" {
+		name = e.index
+	}
+
+	exists, err := esapi.IndicesExistsRequest{Index: []string{name}}.Do(ctx, e.client)
+	if err != nil {
+		return fmt.Errorf("check index exists: %w", err)
+	}
+	defer exists.Body.Close()
+	if exists.StatusCode == 200 {
+		return nil
+	}
+
+	mapping := map[string]interface{}{
+		"mappings": map[string]interface{}{
+			"properties": map[string]interface{}{
+				e.contentField: map[string]interface{}{"type": "text"},
+				e.vectorField: map[string]interface{}{
+					"type":       "dense_vector",
+					"dims":       schema.VectorDim,
+					"index":      true,
+					"similarity": "cosine",
+				},
+			},
+		},
+	}
+	body, err := json.Marshal(mapping)
+	if err != nil {
+		return err
+	}
+
+	res, err := esapi.IndicesCreateRequest{Index: name, Body: bytes.NewReader(body)}.Do(ctx, e.client)
+	if err != nil {
+		return fmt.Errorf("create index: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("create index: %s", res.String())
+	}
+	return nil
+}
+
+// Search performs a keyword-only (multi_match) search.
+func (e *ElasticsearchBackend) Search(ctx context.Context, query string, top int) ([]AzureSearchResult, error) {
+	return e.HybridSearch(ctx, query, nil, top)
+}
+
+// HybridSearch performs a keyword + knn vector search, fused server-side
+// via Elasticsearch's "rank": {"rrf": {}} reranker when both a query and
+// a vector are given.
+func (e *ElasticsearchBackend) HybridSearch(ctx context.Context, query string, vector []float32, top int) ([]AzureSearchResult, error) {
+	body := map[string]interface{}{"size": top}
+
+	if query != "" {
+		body["query"] = map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":  query,
+				"fields": []string{e.contentField},
+			},
+		}
+	}
+	if len(vector) > 0 {
+		body["knn"] = map[string]interface{}{
+			"field":          e.vectorField,
+			"query_vector":   vector,
+			"k":              top,
+			"num_candidates": top * 10,
+		}
+	}
+	if query != "" && len(vector) > 0 {
+		body["rank"] = map[string]interface{}{"rrf": map[string]interface{}{}}
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := esapi.SearchRequest{
+		Index: []string{e.index},
+		Body:  bytes.NewReader(bodyBytes),
+	}.Do(ctx, e.client)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, fmt.Errorf("search failed: %s", res.String())
+	}
+
+	var parsed struct {
+		Hits struct {
+			Hits []struct {
+				ID     string                 `json:"_id"`
+				Score  float32                `json:"_score"`
+				Source map[string]interface{} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&parsed); err != nil {
+		return nil, err
+	}
+
+	results := make([]AzureSearchResult, len(parsed.Hits.Hits))
+	for i, hit := range parsed.Hits.Hits {
+		content, _ := hit.Source[e.contentField].(string)
+		metadata, _ := hit.Source["metadata"].(map[string]interface{})
+		results[i] = AzureSearchResult{ID: hit.ID, Content: content, Score: hit.Score, Metadata: metadata}
+	}
+	return results, nil
+}
+
+// Upsert indexes documents via the _bulk API.
+func (e *ElasticsearchBackend) Upsert(ctx context.Context, documents []map[string]interface{}) error {
+	if len(documents) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, doc := range documents {
+		id, _ := doc["id"].(string)
+		metaLine, err := json.Marshal(map[string]interface{}{
+			"index": map[string]interface{}{"_index": e.index, "_id": id},
+		})
+		if err != nil {
+			return err
+		}
+		docLine, err := json.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		buf.Write(metaLine)
+		buf.WriteByte('\n')
+		buf.Write(docLine)
+		buf.WriteByte('\n')
+	}
+
+	res, err := esapi.BulkRequest{Body: bytes.NewReader(buf.Bytes())}.Do(ctx, e.client)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("bulk upsert failed: %s", res.String())
+	}
+	return nil
+}
+
+// Delete removes documents by ID via the _bulk API.
+func (e *ElasticsearchBackend) Delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, id := range ids {
+		metaLine, err := json.Marshal(map[string]interface{}{
+			"delete": map[string]interface{}{"_index": e.index, "_id": id},
+		})
+		if err != nil {
+			return err
+		}
+		buf.Write(metaLine)
+		buf.WriteByte('\n')
+	}
+
+	res, err := esapi.BulkRequest{Body: bytes.NewReader(buf.Bytes())}.Do(ctx, e.client)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("bulk delete failed: %s", res.String())
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter is a single OData filter expression node. Build one with
+// Eq/Ne/Gt/Ge/Lt/Le/In/SearchIn/GeoDistance/Any/All and compose larger
+// expressions with And/Or/Not, then pass it to SearchWithFilterExpr.
+// Composing Filters instead of building filter strings by hand avoids
+// injection bugs from unescaped values.
+type Filter interface {
+	String() string
+}
+
+// comparison renders "field op value", with value escaped per OData's
+// literal rules.
+type comparison struct {
+	field string
+	op    string
+	value interface{}
+}
+
+func (c comparison) String() string {
+	return fmt.Sprintf("%s %s %s", c.field, c.op, odataLiteral(c.value))
+}
+
+// Eq builds "field eq value".
+func Eq(field string, v interface{}) Filter { return comparison{field, "eq", v} }
+
+// Ne builds "field ne value".
+func Ne(field string, v interface{}) Filter { return comparison{field, "ne", v} }
+
+// Gt builds "field gt value".
+func Gt(field string, v interface{}) Filter { return comparison{field, "gt", v} }
+
+// Ge builds "field ge value".
+func Ge(field string, v interface{}) Filter { return comparison{field, "ge", v} }
+
+// Lt builds "field lt value".
+func Lt(field string, v interface{}) Filter { return comparison{field, "lt", v} }
+
+// Le builds "field le value".
+func Le(field string, v interface{}) Filter { return comparison{field, "le", v} }
+
+// odataLiteral renders v as an OData literal: strings are single-quoted
+// with embedded single quotes doubled, time.Time is RFC3339, nil is the
+// null keyword, and everything else uses fmt's default formatting.
+func odataLiteral(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "'" + strings.ReplaceAll(val, "'", "''") + "'"
+	case time.Time:
+		return val.UTC().Format(time.RFC3339)
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// boolOp renders a parenthesized "and"/"or" chain.
+type boolOp struct {
+	op      string
+	filters []Filter
+}
+
+func (b boolOp) String() string {
+	parts := make([]string, len(b.filters))
+	for i, f := range b.filters {
+		parts[i] = f.String()
+	}
+	return "(" + strings.Join(parts, " "+b.op+" ") + ")"
+}
+
+// And builds "(f1 and f2 and ...)".
+func And(filters ...Filter) Filter { return boolOp{op: "and", filters: filters} }
+
+// Or builds "(f1 or f2 or ...)".
+func Or(filters ...Filter) Filter { return boolOp{op: "or", filters: filters} }
+
+// notOp renders "not (filter)".
+type notOp struct{ filter Filter }
+
+func (n notOp) String() string { return "not (" + n.filter.String() + ")" }
+
+// Not builds "not (filter)".
+func Not(f Filter) Filter { return notOp{filter: f} }
+
+// rawFunc is a Filter whose rendering was already assembled by the
+// constructor that built it (In, SearchIn, GeoDistance, Any, All).
+type rawFunc struct{ expr string }
+
+func (r rawFunc) String() string { return r.expr }
+
+// In builds "(field eq v1 or field eq v2 or ...)" - an OR-chain of Eq
+// comparisons. For value lists too large for a readable OR-chain,
+// SearchIn's native search.in() call is the better fit.
+func In(field string, values ...interface{}) Filter {
+	filters := make([]Filter, len(values))
+	for i, v := range values {
+		filters[i] = Eq(field, v)
+	}
+	return Or(filters...)
+}
+
+// SearchIn builds Azure AI Search's native search.in(field, valueList,
+// delim) function call, which tests membership against a delimited
+// string far more compactly than an OR-chain. delim must not appear
+// inside any value. Each value has embedded single quotes doubled, the
+// same escaping odataLiteral applies to a lone string, since the joined
+// list is itself wrapped in a single-quoted OData string literal.
+func SearchIn(field string, values []string, delim string) Filter {
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = strings.ReplaceAll(v, "'", "''")
+	}
+	return rawFunc{fmt.Sprintf("search.in(%s, '%s', '%s')", field, strings.Join(escaped, delim), delim)}
+}
+
+// GeoDistance builds "geo.distance(field, geography'POINT(lon lat)') le
+// km", true for documents whose field is within meters of (lat, lon).
+func GeoDistance(field string, lat, lon, meters float64) Filter {
+	km := meters / 1000
+	return rawFunc{fmt.Sprintf("geo.distance(%s, geography'POINT(%g %g)') le %g", field, lon, lat, km)}
+}
+
+// Any builds "field/any(lambdaVar: predicate)", Azure's collection
+// lambda for "does any element of field's collection satisfy predicate".
+// predicate should reference lambdaVar rather than field, e.g.
+// Any("tags", "t", Eq("t", "invoice")).
+func Any(field, lambdaVar string, predicate Filter) Filter {
+	return rawFunc{fmt.Sprintf("%s/any(%s: %s)", field, lambdaVar, predicate.String())}
+}
+
+// All builds "field/all(lambdaVar: predicate)", Azure's collection
+// lambda for "every element of field's collection satisfies predicate".
+func All(field, lambdaVar string, predicate Filter) Filter {
+	return rawFunc{fmt.Sprintf("%s/all(%s: %s)", field, lambdaVar, predicate.String())}
+}
@@ -0,0 +1,201 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryBackend is an in-process Backend with no network dependency, for
+// unit tests and local development. Search does BM25-lite keyword
+// scoring over tokenized content (term frequency and length
+// normalization shaped like BM25, with idf derived from this backend's
+// own documents rather than a separate corpus statistic); HybridSearch
+// additionally cosine-ranks any vector a document was upserted with and
+// sums the two scores.
+type MemoryBackend struct {
+	mu   sync.RWMutex
+	docs map[string]memoryDoc
+}
+
+type memoryDoc struct {
+	id      string
+	content string
+	tokens  []string
+	vector  []float32
+	raw     map[string]interface{}
+}
+
+// NewMemoryBackend creates an empty MemoryBackend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{docs: make(map[string]memoryDoc)}
+}
+
+// EnsureIndex is a no-op: MemoryBackend has no schema to provision.
+func (m *MemoryBackend) EnsureIndex(ctx context.Context, schema IndexSchema) error {
+	return nil
+}
+
+// Upsert stores or replaces documents by their "id" field. A document
+// with a "content_vector" ([]float32) field is searchable by vector too.
+func (m *MemoryBackend) Upsert(ctx context.Context, documents []map[string]interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, d := range documents {
+		id, _ := d["id"].(string)
+		if id == "" {
+			continue
+		}
+		content, _ := d["content"].(string)
+		doc := memoryDoc{id: id, content: content, tokens: tokenize(content), raw: d}
+		if v, ok := d["content_vector"].([]float32); ok {
+			doc.vector = v
+		}
+		m.docs[id] = doc
+	}
+	return nil
+}
+
+// Delete removes documents by ID.
+func (m *MemoryBackend) Delete(ctx context.Context, ids []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range ids {
+		delete(m.docs, id)
+	}
+	return nil
+}
+
+// Search performs a keyword-only search.
+func (m *MemoryBackend) Search(ctx context.Context, query string, top int) ([]AzureSearchResult, error) {
+	return m.HybridSearch(ctx, query, nil, top)
+}
+
+// HybridSearch blends BM25-lite keyword scoring with cosine similarity
+// over stored vectors. An empty query and nil vector matches every
+// document (score 0), mirroring AzureAISearch's match-all behavior for
+// an empty search.
+func (m *MemoryBackend) HybridSearch(ctx context.Context, query string, vector []float32, top int) ([]AzureSearchResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	queryTokens := tokenize(query)
+	df := m.documentFrequencyLocked(queryTokens)
+	avgDocLen := m.averageDocLenLocked()
+
+	type scored struct {
+		doc   memoryDoc
+		score float32
+	}
+	var results []scored
+	for _, doc := range m.docs {
+		var score float32
+		if len(queryTokens) > 0 {
+			score += bm25Lite(queryTokens, doc.tokens, df, len(m.docs), avgDocLen)
+		}
+		if len(vector) > 0 && len(doc.vector) > 0 {
+			score += cosineSimilarity(vector, doc.vector)
+		}
+		if score == 0 && (len(queryTokens) > 0 || len(vector) > 0) {
+			continue
+		}
+		results = append(results, scored{doc, score})
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].score > results[j].score })
+	if top > 0 && len(results) > top {
+		results = results[:top]
+	}
+
+	out := make([]AzureSearchResult, len(results))
+	for i, r := range results {
+		metadata, _ := r.doc.raw["metadata"].(map[string]interface{})
+		out[i] = AzureSearchResult{ID: r.doc.id, Content: r.doc.content, Score: r.score, Metadata: metadata}
+	}
+	return out, nil
+}
+
+func tokenize(s string) []string {
+	return strings.Fields(strings.ToLower(s))
+}
+
+// documentFrequencyLocked counts, for each distinct query token, how
+// many documents contain it at least once.
+func (m *MemoryBackend) documentFrequencyLocked(queryTokens []string) map[string]int {
+	unique := make(map[string]bool, len(queryTokens))
+	for _, t := range queryTokens {
+		unique[t] = true
+	}
+
+	df := make(map[string]int, len(unique))
+	for _, doc := range m.docs {
+		seen := make(map[string]bool, len(unique))
+		for _, tok := range doc.tokens {
+			if unique[tok] && !seen[tok] {
+				df[tok]++
+				seen[tok] = true
+			}
+		}
+	}
+	return df
+}
+
+func (m *MemoryBackend) averageDocLenLocked() float64 {
+	if len(m.docs) == 0 {
+		return 1
+	}
+	total := 0
+	for _, doc := range m.docs {
+		total += len(doc.tokens)
+	}
+	avg := float64(total) / float64(len(m.docs))
+	if avg == 0 {
+		return 1
+	}
+	return avg
+}
+
+// bm25Lite scores query against a document's tokens with BM25's
+// term-frequency/length-normalization shape (k1=1.2, b=0.75).
+func bm25Lite(queryTokens, docTokens []string, df map[string]int, totalDocs int, avgDocLen float64) float32 {
+	const k1 = 1.2
+	const b = 0.75
+
+	tf := make(map[string]int, len(docTokens))
+	for _, t := range docTokens {
+		tf[t]++
+	}
+
+	var score float64
+	for _, qt := range queryTokens {
+		f := float64(tf[qt])
+		if f == 0 {
+			continue
+		}
+		idf := math.Log(1 + (float64(totalDocs)-float64(df[qt])+0.5)/(float64(df[qt])+0.5))
+		norm := f * (k1 + 1) / (f + k1*(1-b+b*float64(len(docTokens))/avgDocLen))
+		score += idf * norm
+	}
+	return float32(score)
+}
+
+func cosineSimilarity(a, b []float32) float32 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, magA, magB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		magA += float64(a[i]) * float64(a[i])
+		magB += float64(b[i]) * float64(b[i])
+	}
+	if magA == 0 || magB == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(magA) * math.Sqrt(magB)))
+}
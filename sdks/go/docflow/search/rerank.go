@@ -0,0 +1,110 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+)
+
+// Reranker scores passages for relevance to a query, for use as a
+// second-stage relevance pass after RerankedSearch's RRF fusion.
+// Implementations can wrap a local cross-encoder (ONNX/BGE) or a hosted
+// reranking service; Score is called once per RerankedSearch call with
+// the fused top-N passages, in order.
+type Reranker interface {
+	Score(query string, passages []string) ([]float32, error)
+}
+
+// SetReranker configures an optional reranker for RerankedSearch. A nil
+// reranker (the default) leaves RerankedSearch's RRF-fused order as-is.
+func (c *AzureAISearch) SetReranker(r Reranker) {
+	c.reranker = r
+}
+
+// RerankedSearch fuses a keyword search and a vector search with
+// reciprocal rank fusion, then, if a Reranker is configured, rescores and
+// re-sorts the fused top results with it.
+//
+// The keyword and vector queries are issued as two separate hybridSearch
+// calls and fused client-side - unlike HybridSearchFusion, which asks
+// Azure AI Search's own server-side "hybridSearch" parameter to fuse a
+// single combined request. Fusing client-side is what makes the Reranker
+// pass possible: only the caller, not the search service, ever sees both
+// ranked lists and can hand their passages to a cross-encoder.
+//
+// fusion.RRFK sets the RRF rank-offset constant k in 1/(k + rank)
+// (default 60, matching DefaultRetrievalConfig); fusion.PerRankerTopN
+// sets how many results each side recalls before fusing (default 100).
+func (c *AzureAISearch) RerankedSearch(ctx context.Context, query string, vector []float32, top int, fusion config.RetrievalConfig) ([]AzureSearchResult, error) {
+	perRankerTopN := fusion.PerRankerTopN
+	if perRankerTopN <= 0 {
+		perRankerTopN = 100
+	}
+	k := fusion.RRFK
+	if k <= 0 {
+		k = 60
+	}
+
+	keywordResults, err := c.hybridSearch(ctx, query, nil, nil, perRankerTopN, config.RetrievalConfig{})
+	if err != nil {
+		return nil, err
+	}
+	vectorResults, err := c.hybridSearch(ctx, "", vector, nil, perRankerTopN, config.RetrievalConfig{})
+	if err != nil {
+		return nil, err
+	}
+
+	fused := rrfFuse(k, keywordResults, vectorResults)
+	if top > 0 && len(fused) > top {
+		fused = fused[:top]
+	}
+
+	if c.reranker == nil || len(fused) == 0 {
+		return fused, nil
+	}
+
+	passages := make([]string, len(fused))
+	for i, r := range fused {
+		passages[i] = r.Content
+	}
+	scores, err := c.reranker.Score(query, passages)
+	if err != nil {
+		return nil, fmt.Errorf("rerank failed: %w", err)
+	}
+	for i := range fused {
+		if i < len(scores) {
+			fused[i].Score = scores[i]
+		}
+	}
+	sort.Slice(fused, func(i, j int) bool { return fused[i].Score > fused[j].Score })
+
+	return fused, nil
+}
+
+// rrfFuse combines ranked result lists into one, scoring each document by
+// Σ 1/(k + rank) summed across every list it appears in (rank is
+// 1-based), deduplicating by ID, and sorting descending by fused score.
+func rrfFuse(k int, lists ...[]AzureSearchResult) []AzureSearchResult {
+	scores := make(map[string]float32)
+	docs := make(map[string]AzureSearchResult)
+
+	for _, list := range lists {
+		for rank, r := range list {
+			scores[r.ID] += 1.0 / float32(k+rank+1)
+			if _, ok := docs[r.ID]; !ok {
+				docs[r.ID] = r
+			}
+		}
+	}
+
+	fused := make([]AzureSearchResult, 0, len(docs))
+	for id, doc := range docs {
+		doc.Score = scores[id]
+		fused = append(fused, doc)
+	}
+	sort.Slice(fused, func(i, j int) bool { return fused[i].Score > fused[j].Score })
+
+	return fused
+}
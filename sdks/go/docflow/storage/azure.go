@@ -3,20 +3,71 @@ package storage
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"path"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/service"
 )
 
+// defaultAzureBlockSize is the block size SaveReader stages when
+// AzureConfig.BlockSize is unset.
+const defaultAzureBlockSize = 4 * 1024 * 1024
+
+// defaultAzureConcurrency is how many blocks SaveReader stages at once
+// when AzureConfig.Concurrency is unset.
+const defaultAzureConcurrency = 4
+
+func init() {
+	Register("azure", func(cfg map[string]any) (Storage, error) {
+		return NewAzureStorage(AzureConfig{
+			AccountName:             cfgString(cfg, "accountName"),
+			AccountKey:              cfgString(cfg, "accountKey"),
+			ContainerName:           cfgString(cfg, "containerName"),
+			Prefix:                  cfgString(cfg, "prefix"),
+			CredentialType:          AzureCredentialType(cfgString(cfg, "credentialType")),
+			SASToken:                cfgString(cfg, "sasToken"),
+			ConnectionString:        cfgString(cfg, "connectionString"),
+			TenantID:                cfgString(cfg, "tenantId"),
+			ClientID:                cfgString(cfg, "clientId"),
+			ClientSecret:            cfgString(cfg, "clientSecret"),
+			ManagedIdentityClientID: cfgString(cfg, "managedIdentityClientId"),
+			BlockSize:               cfgInt64(cfg, "blockSize"),
+			Concurrency:             int(cfgInt64(cfg, "concurrency")),
+			DefaultURLExpiry:        cfgDuration(cfg, "defaultUrlExpiry"),
+		})
+	})
+}
+
 // AzureStorage implements Storage interface for Azure Blob Storage.
 type AzureStorage struct {
 	client        *azblob.Client
 	containerName string
 	prefix        string
 	accountName   string
+	blockSize     int64
+	concurrency   int
+
+	// defaultURLExpiry, if set, makes GetURL route through
+	// GetSignedURL instead of returning a plain unsigned URL.
+	defaultURLExpiry time.Duration
+
+	// cred is set only when NewAzureStorage was given an AccountKey;
+	// PresignedURL needs it to sign a SAS URL and errors without one,
+	// since the default-credential path has no key to sign with.
+	cred *azblob.SharedKeyCredential
 }
 
 // AzureConfig contains configuration for Azure Blob storage.
@@ -25,11 +76,97 @@ type AzureConfig struct {
 	AccountKey    string // Optional, uses DefaultAzureCredential if empty
 	ContainerName string
 	Prefix        string // Optional prefix for all blob names
+
+	// CredentialType selects how to authenticate. Defaults to
+	// AzureCredentialSharedKey if AccountKey is set, otherwise
+	// AzureCredentialDefault.
+	CredentialType AzureCredentialType
+
+	// SASToken is required for CredentialType AzureCredentialSAS - a
+	// query string (with or without its leading "?") granting scoped,
+	// time-limited access, typically generated out-of-band by whoever
+	// owns the storage account.
+	SASToken string
+
+	// ConnectionString is required for CredentialType
+	// AzureCredentialConnectionString - AccountName/AccountKey are
+	// ignored in favor of whatever account it names.
+	ConnectionString string
+
+	// TenantID, ClientID, and ClientSecret are required for
+	// CredentialType AzureCredentialClientSecret (an Azure AD app
+	// registration / service principal).
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+
+	// ManagedIdentityClientID is optional for CredentialType
+	// AzureCredentialManagedIdentity - set it to select a user-assigned
+	// identity; leave empty to use the host's system-assigned identity.
+	ManagedIdentityClientID string
+
+	// BlockSize is the size (bytes) of each block SaveReader stages
+	// before committing the block list. Defaults to 4MiB if zero.
+	BlockSize int64
+
+	// Concurrency is how many blocks SaveReader stages at once.
+	// Defaults to 4 if zero.
+	Concurrency int
+
+	// DefaultURLExpiry, if set, makes GetURL return a SAS URL valid for
+	// this long instead of a plain, unsigned (public-container-only)
+	// URL - see GetSignedURL for how it's signed.
+	DefaultURLExpiry time.Duration
 }
 
+// AzureCredentialType selects how NewAzureStorage authenticates to the
+// storage account.
+type AzureCredentialType string
+
+const (
+	// AzureCredentialDefault uses azidentity.NewDefaultAzureCredential,
+	// which tries environment variables, managed identity, then the
+	// Azure CLI's logged-in account, in that order.
+	AzureCredentialDefault AzureCredentialType = "default"
+
+	// AzureCredentialSharedKey authenticates with AccountName/AccountKey.
+	AzureCredentialSharedKey AzureCredentialType = "sharedKey"
+
+	// AzureCredentialSAS authenticates with a pre-generated SASToken
+	// appended to the service URL, with no credential object at all.
+	AzureCredentialSAS AzureCredentialType = "sas"
+
+	// AzureCredentialConnectionString authenticates via
+	// azblob.NewClientFromConnectionString(ConnectionString).
+	AzureCredentialConnectionString AzureCredentialType = "connectionString"
+
+	// AzureCredentialClientSecret authenticates as an Azure AD service
+	// principal via TenantID/ClientID/ClientSecret - the common choice
+	// for CI pipelines.
+	AzureCredentialClientSecret AzureCredentialType = "clientSecret"
+
+	// AzureCredentialManagedIdentity authenticates as the host's Azure
+	// managed identity (e.g. an AKS pod identity) via
+	// ManagedIdentityClientID.
+	AzureCredentialManagedIdentity AzureCredentialType = "managedIdentity"
+
+	// AzureCredentialAzureCLI authenticates as whichever account `az
+	// login` last signed in as on the host - convenient for local dev.
+	AzureCredentialAzureCLI AzureCredentialType = "azureCli"
+)
+
 // NewAzureStorage creates a new Azure Blob storage instance.
 func NewAzureStorage(cfg AzureConfig) (*AzureStorage, error) {
-	if cfg.AccountName == "" {
+	credType := cfg.CredentialType
+	if credType == "" {
+		if cfg.AccountKey != "" {
+			credType = AzureCredentialSharedKey
+		} else {
+			credType = AzureCredentialDefault
+		}
+	}
+
+	if credType != AzureCredentialConnectionString && cfg.AccountName == "" {
 		return nil, fmt.Errorf("account name is required")
 	}
 	if cfg.ContainerName == "" {
@@ -39,11 +176,21 @@ func NewAzureStorage(cfg AzureConfig) (*AzureStorage, error) {
 	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", cfg.AccountName)
 
 	var client *azblob.Client
+	var cred *azblob.SharedKeyCredential
 	var err error
 
-	if cfg.AccountKey != "" {
-		// Use shared key credential
-		cred, err := azblob.NewSharedKeyCredential(cfg.AccountName, cfg.AccountKey)
+	switch credType {
+	case AzureCredentialConnectionString:
+		if cfg.ConnectionString == "" {
+			return nil, fmt.Errorf("connection string is required for CredentialType AzureCredentialConnectionString")
+		}
+		client, err = azblob.NewClientFromConnectionString(cfg.ConnectionString, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client from connection string: %w", err)
+		}
+
+	case AzureCredentialSharedKey:
+		cred, err = azblob.NewSharedKeyCredential(cfg.AccountName, cfg.AccountKey)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create credential: %w", err)
 		}
@@ -51,49 +198,238 @@ func NewAzureStorage(cfg AzureConfig) (*AzureStorage, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to create client: %w", err)
 		}
-	} else {
-		// Use default credential (requires azure-identity)
-		client, err = azblob.NewClientWithNoCredential(serviceURL, nil)
+
+	case AzureCredentialSAS:
+		if cfg.SASToken == "" {
+			return nil, fmt.Errorf("SAS token is required for CredentialType AzureCredentialSAS")
+		}
+		client, err = azblob.NewClientWithNoCredential(serviceURL+"?"+strings.TrimPrefix(cfg.SASToken, "?"), nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create client: %w", err)
 		}
+
+	case AzureCredentialClientSecret:
+		if cfg.TenantID == "" || cfg.ClientID == "" || cfg.ClientSecret == "" {
+			return nil, fmt.Errorf("tenant ID, client ID, and client secret are required for CredentialType AzureCredentialClientSecret")
+		}
+		tokenCred, credErr := azidentity.NewClientSecretCredential(cfg.TenantID, cfg.ClientID, cfg.ClientSecret, nil)
+		if credErr != nil {
+			return nil, fmt.Errorf("failed to create client secret credential: %w", credErr)
+		}
+		client, err = azblob.NewClient(serviceURL, tokenCred, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client: %w", err)
+		}
+
+	case AzureCredentialManagedIdentity:
+		opts := &azidentity.ManagedIdentityCredentialOptions{}
+		if cfg.ManagedIdentityClientID != "" {
+			opts.ID = azidentity.ClientID(cfg.ManagedIdentityClientID)
+		}
+		tokenCred, credErr := azidentity.NewManagedIdentityCredential(opts)
+		if credErr != nil {
+			return nil, fmt.Errorf("failed to create managed identity credential: %w", credErr)
+		}
+		client, err = azblob.NewClient(serviceURL, tokenCred, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client: %w", err)
+		}
+
+	case AzureCredentialAzureCLI:
+		tokenCred, credErr := azidentity.NewAzureCLICredential(nil)
+		if credErr != nil {
+			return nil, fmt.Errorf("failed to create Azure CLI credential: %w", credErr)
+		}
+		client, err = azblob.NewClient(serviceURL, tokenCred, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client: %w", err)
+		}
+
+	case AzureCredentialDefault:
+		tokenCred, credErr := azidentity.NewDefaultAzureCredential(nil)
+		if credErr != nil {
+			return nil, fmt.Errorf("failed to create default Azure credential: %w", credErr)
+		}
+		client, err = azblob.NewClient(serviceURL, tokenCred, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client: %w", err)
+		}
+
+	default:
+		return nil, fmt.Errorf("azure: unknown credential type %q", credType)
+	}
+
+	blockSize := cfg.BlockSize
+	if blockSize <= 0 {
+		blockSize = defaultAzureBlockSize
+	}
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultAzureConcurrency
 	}
 
 	return &AzureStorage{
-		client:        client,
-		containerName: cfg.ContainerName,
-		prefix:        strings.TrimSuffix(cfg.Prefix, "/"),
-		accountName:   cfg.AccountName,
+		client:           client,
+		containerName:    cfg.ContainerName,
+		prefix:           strings.TrimSuffix(cfg.Prefix, "/"),
+		accountName:      cfg.AccountName,
+		blockSize:        blockSize,
+		concurrency:      concurrency,
+		defaultURLExpiry: cfg.DefaultURLExpiry,
+		cred:             cred,
 	}, nil
 }
 
 // Save stores data at the given path.
-func (s *AzureStorage) Save(filePath string, data []byte) error {
-	return s.SaveReader(filePath, bytes.NewReader(data))
+func (s *AzureStorage) Save(ctx context.Context, filePath string, data []byte) error {
+	return s.SaveReader(ctx, filePath, bytes.NewReader(data))
+}
+
+// SaveReader stages reader through the block blob API in s.blockSize
+// chunks, up to s.concurrency at once, then commits the block list -
+// so neither the whole file nor the whole upload needs to fit in memory
+// or run as one request the way UploadBuffer did. Blocks already staged
+// uncommitted against filePath (e.g. left behind by a prior call that
+// was interrupted before CommitBlockList) are reused rather than
+// re-staged, as long as they're still within the service's uncommitted
+// block retention window. Pass WithProgress(p) to observe the upload -
+// since blocks stage concurrently, OnBytes reports each block's size as
+// its StageBlock call completes rather than per-Read.
+func (s *AzureStorage) SaveReader(ctx context.Context, filePath string, reader io.Reader, opts ...TransferOption) error {
+	return s.saveReader(ctx, filePath, reader, 0, opts...)
 }
 
-// SaveReader stores data from a reader at the given path.
-func (s *AzureStorage) SaveReader(filePath string, reader io.Reader) error {
+func (s *AzureStorage) saveReader(ctx context.Context, filePath string, reader io.Reader, size int64, opts ...TransferOption) error {
+	to := CollectTransferOptions(opts...)
+	progress := to.Progress
+	if progress != nil {
+		progress.OnStart(size)
+	}
+
 	blobName := s.fullKey(filePath)
+	blockClient := s.client.ServiceClient().NewContainerClient(s.containerName).NewBlockBlobClient(blobName)
 
-	data, err := io.ReadAll(reader)
+	staged, err := s.uncommittedBlockIDs(ctx, blockClient)
 	if err != nil {
-		return fmt.Errorf("failed to read data: %w", err)
+		if progress != nil {
+			progress.OnFinish(err)
+		}
+		return fmt.Errorf("failed to list uncommitted blocks: %w", err)
 	}
 
-	_, err = s.client.UploadBuffer(context.Background(), s.containerName, blobName, data, nil)
-	if err != nil {
-		return fmt.Errorf("failed to upload to Azure: %w", err)
+	var (
+		blockIDs []string
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, s.concurrency)
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	for i := 0; ; i++ {
+		buf := make([]byte, s.blockSize)
+		n, readErr := io.ReadFull(reader, buf)
+		if n == 0 {
+			break
+		}
+		buf = buf[:n]
+
+		id := blockID(i)
+		blockIDs = append(blockIDs, id)
+
+		if !staged[id] {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(id string, block []byte) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if _, err := blockClient.StageBlock(ctx, id, streaming.NopCloser(bytes.NewReader(block)), nil); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					return
+				}
+				if progress != nil {
+					progress.OnBytes(int64(len(block)))
+				}
+			}(id, buf)
+		}
+
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			wg.Wait()
+			if progress != nil {
+				progress.OnFinish(readErr)
+			}
+			return fmt.Errorf("failed to read block %d: %w", i, readErr)
+		}
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		if progress != nil {
+			progress.OnFinish(firstErr)
+		}
+		return fmt.Errorf("failed to stage block to Azure: %w", firstErr)
 	}
 
+	if _, err := blockClient.CommitBlockList(ctx, blockIDs, nil); err != nil {
+		if progress != nil {
+			progress.OnFinish(err)
+		}
+		return fmt.Errorf("failed to commit block list to Azure: %w", err)
+	}
+
+	if progress != nil {
+		progress.OnFinish(nil)
+	}
 	return nil
 }
 
+// uncommittedBlockIDs returns the base64 block IDs blockClient's blob
+// already has staged but not committed, so SaveReader can skip
+// re-staging them on a resumed upload. A missing blob (nothing staged
+// yet) is not an error - it just means there's nothing to resume.
+func (s *AzureStorage) uncommittedBlockIDs(ctx context.Context, blockClient *blockblob.Client) (map[string]bool, error) {
+	resp, err := blockClient.GetBlockList(ctx, blockblob.BlockListTypeUncommitted, nil)
+	if err != nil {
+		return map[string]bool{}, nil
+	}
+
+	ids := make(map[string]bool, len(resp.UncommittedBlocks))
+	for _, b := range resp.UncommittedBlocks {
+		if b.Name != nil {
+			ids[*b.Name] = true
+		}
+	}
+	return ids, nil
+}
+
+// blockID returns the base64-encoded, fixed-width block ID for the i'th
+// block of an upload - fixed-width and big-endian so block IDs sort (and
+// CommitBlockList orders them) the same way the blocks themselves were
+// read off the reader.
+func blockID(i int) string {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(i))
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// SaveReaderWithSize stages fixed-size blocks regardless of the total
+// length, so size isn't needed to drive the upload itself - it's only
+// passed through to WithProgress's OnStart as the transfer's total.
+func (s *AzureStorage) SaveReaderWithSize(ctx context.Context, filePath string, reader io.Reader, size int64, opts ...TransferOption) error {
+	return s.saveReader(ctx, filePath, reader, size, opts...)
+}
+
 // Load retrieves data from the given path.
-func (s *AzureStorage) Load(filePath string) ([]byte, error) {
+func (s *AzureStorage) Load(ctx context.Context, filePath string) ([]byte, error) {
 	blobName := s.fullKey(filePath)
 
-	resp, err := s.client.DownloadStream(context.Background(), s.containerName, blobName, nil)
+	resp, err := s.client.DownloadStream(ctx, s.containerName, blobName, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download from Azure: %w", err)
 	}
@@ -103,22 +439,27 @@ func (s *AzureStorage) Load(filePath string) ([]byte, error) {
 }
 
 // LoadReader returns a reader for the data at the given path.
-func (s *AzureStorage) LoadReader(filePath string) (io.ReadCloser, error) {
+func (s *AzureStorage) LoadReader(ctx context.Context, filePath string, opts ...TransferOption) (io.ReadCloser, error) {
 	blobName := s.fullKey(filePath)
 
-	resp, err := s.client.DownloadStream(context.Background(), s.containerName, blobName, nil)
+	resp, err := s.client.DownloadStream(ctx, s.containerName, blobName, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download from Azure: %w", err)
 	}
 
-	return resp.Body, nil
+	to := CollectTransferOptions(opts...)
+	var size int64
+	if resp.ContentLength != nil {
+		size = *resp.ContentLength
+	}
+	return withProgressReadCloser(resp.Body, to.Progress, size), nil
 }
 
 // Delete removes the file at the given path.
-func (s *AzureStorage) Delete(filePath string) error {
+func (s *AzureStorage) Delete(ctx context.Context, filePath string) error {
 	blobName := s.fullKey(filePath)
 
-	_, err := s.client.DeleteBlob(context.Background(), s.containerName, blobName, nil)
+	_, err := s.client.DeleteBlob(ctx, s.containerName, blobName, nil)
 	if err != nil {
 		// Ignore if doesn't exist
 		return nil
@@ -127,20 +468,29 @@ func (s *AzureStorage) Delete(filePath string) error {
 	return nil
 }
 
-// Exists checks if a file exists at the given path.
-func (s *AzureStorage) Exists(filePath string) (bool, error) {
+// Exists checks if a file exists at the given path, via GetProperties
+// rather than pulling the blob body down just to test presence. Only a
+// BlobNotFound error is treated as "doesn't exist" - anything else
+// (auth, network) is surfaced rather than swallowed.
+func (s *AzureStorage) Exists(ctx context.Context, filePath string) (bool, error) {
 	blobName := s.fullKey(filePath)
 
-	_, err := s.client.DownloadStream(context.Background(), s.containerName, blobName, nil)
+	blobClient := s.client.ServiceClient().NewContainerClient(s.containerName).NewBlobClient(blobName)
+	_, err := blobClient.GetProperties(ctx, nil)
 	if err != nil {
-		return false, nil
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check if blob exists: %w", err)
 	}
 
 	return true, nil
 }
 
-// List returns all files in the given directory.
-func (s *AzureStorage) List(dir string) ([]string, error) {
+// List returns all files in the given directory. The blob pager already
+// fetches every page as NextPage is called, so this was never truncated
+// at one page's worth of blobs.
+func (s *AzureStorage) List(ctx context.Context, dir string) ([]string, error) {
 	prefix := s.fullKey(dir)
 	if prefix != "" && !strings.HasSuffix(prefix, "/") {
 		prefix += "/"
@@ -152,7 +502,7 @@ func (s *AzureStorage) List(dir string) ([]string, error) {
 
 	var files []string
 	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+		resp, err := pager.NextPage(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list blobs: %w", err)
 		}
@@ -168,9 +518,158 @@ func (s *AzureStorage) List(dir string) ([]string, error) {
 	return files, nil
 }
 
-// GetURL returns the Azure Blob URL for the file.
+// Stat returns lifecycle metadata for the file at path, including its
+// user-defined blob metadata, via a single GetProperties call.
+func (s *AzureStorage) Stat(ctx context.Context, filePath string) (ObjectInfo, error) {
+	blobName := s.fullKey(filePath)
+
+	blobClient := s.client.ServiceClient().NewContainerClient(s.containerName).NewBlobClient(blobName)
+	props, err := blobClient.GetProperties(ctx, nil)
+	if err != nil {
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return ObjectInfo{}, fmt.Errorf("file not found: %s", filePath)
+		}
+		return ObjectInfo{}, fmt.Errorf("failed to stat Azure blob: %w", err)
+	}
+
+	info := ObjectInfo{}
+	if props.ContentLength != nil {
+		info.Size = *props.ContentLength
+	}
+	if props.LastModified != nil {
+		info.ModTime = *props.LastModified
+	}
+	if props.ETag != nil {
+		info.ETag = string(*props.ETag)
+	}
+	if props.ContentType != nil {
+		info.ContentType = *props.ContentType
+	}
+	if len(props.ContentMD5) > 0 {
+		info.ContentMD5 = props.ContentMD5
+	}
+	if len(props.Metadata) > 0 {
+		info.Metadata = make(map[string]string, len(props.Metadata))
+		for k, v := range props.Metadata {
+			if v != nil {
+				info.Metadata[k] = *v
+			}
+		}
+	}
+	return info, nil
+}
+
+// PresignedURL returns a read-only SAS GET URL for path valid for
+// expiry, via GetSignedURL.
+func (s *AzureStorage) PresignedURL(ctx context.Context, filePath string, expiry time.Duration) (string, error) {
+	return s.GetSignedURL(ctx, filePath, SignedURLOptions{
+		Expiry:      expiry,
+		Permissions: SignedURLRead,
+	})
+}
+
+// SignedURLPermission is a bitmask of SAS permissions for GetSignedURL.
+type SignedURLPermission int
+
+const (
+	SignedURLRead SignedURLPermission = 1 << iota
+	SignedURLWrite
+	SignedURLDelete
+	SignedURLList
+)
+
+func (p SignedURLPermission) toBlobPermissions() sas.BlobPermissions {
+	return sas.BlobPermissions{
+		Read:   p&SignedURLRead != 0,
+		Write:  p&SignedURLWrite != 0,
+		Delete: p&SignedURLDelete != 0,
+		List:   p&SignedURLList != 0,
+	}
+}
+
+// SignedURLOptions configures GetSignedURL.
+type SignedURLOptions struct {
+	// Expiry is how long the URL stays valid for, from now.
+	Expiry time.Duration
+
+	// Permissions is a bitmask (SignedURLRead, SignedURLWrite, ...) of
+	// what the URL grants.
+	Permissions SignedURLPermission
+
+	// ContentDisposition and ContentType, if set, override the blob's
+	// own headers for requests made through this URL - e.g.
+	// `attachment; filename="report.pdf"` so a browser downloads
+	// rather than renders it.
+	ContentDisposition string
+	ContentType        string
+}
+
+// GetSignedURL returns a time-limited SAS URL for path. When
+// AzureConfig.AccountKey was configured it signs locally with the
+// shared key; otherwise (AAD/managed identity) it requests a user
+// delegation key via ServiceClient.GetUserDelegationCredential and
+// signs with that instead, so RAG pipelines can hand out short-lived
+// links without ever holding an account key.
+func (s *AzureStorage) GetSignedURL(ctx context.Context, filePath string, opts SignedURLOptions) (string, error) {
+	if opts.Expiry <= 0 {
+		return "", fmt.Errorf("azure: SignedURLOptions.Expiry is required")
+	}
+	blobName := s.fullKey(filePath)
+
+	values := sas.BlobSignatureValues{
+		Protocol:           sas.ProtocolHTTPS,
+		StartTime:          time.Now().Add(-5 * time.Minute).UTC(),
+		ExpiryTime:         time.Now().Add(opts.Expiry).UTC(),
+		Permissions:        opts.Permissions.toBlobPermissions().String(),
+		ContainerName:      s.containerName,
+		BlobName:           blobName,
+		ContentDisposition: opts.ContentDisposition,
+		ContentType:        opts.ContentType,
+	}
+
+	var sasQuery string
+	if s.cred != nil {
+		sasParams, err := values.SignWithSharedKeyCredential(s.cred)
+		if err != nil {
+			return "", fmt.Errorf("failed to sign SAS url: %w", err)
+		}
+		sasQuery = sasParams.Encode()
+	} else {
+		udc, err := s.client.ServiceClient().GetUserDelegationCredential(ctx, service.KeyInfo{
+			Start:  to.Ptr(values.StartTime.Format(sas.TimeFormat)),
+			Expiry: to.Ptr(values.ExpiryTime.Format(sas.TimeFormat)),
+		}, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to get user delegation credential: %w", err)
+		}
+		sasParams, err := values.SignWithUserDelegation(udc)
+		if err != nil {
+			return "", fmt.Errorf("failed to sign SAS url: %w", err)
+		}
+		sasQuery = sasParams.Encode()
+	}
+
+	return fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s?%s",
+		s.accountName, s.containerName, blobName, sasQuery), nil
+}
+
+// GetURL returns a SAS URL valid for AzureConfig.DefaultURLExpiry via
+// GetSignedURL when that's set, so RAG pipelines can hand out
+// short-lived links without exposing account keys; falls back to the
+// plain (unsigned, public-container-only) Azure Blob URL otherwise, or
+// if signing fails.
 func (s *AzureStorage) GetURL(filePath string) string {
 	blobName := s.fullKey(filePath)
+
+	if s.defaultURLExpiry > 0 {
+		if url, err := s.GetSignedURL(context.Background(), filePath, SignedURLOptions{
+			Expiry:      s.defaultURLExpiry,
+			Permissions: SignedURLRead,
+		}); err == nil {
+			return url
+		}
+	}
+
 	return fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", s.accountName, s.containerName, blobName)
 }
 
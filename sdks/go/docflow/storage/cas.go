@@ -0,0 +1,316 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// casIndexPath is where CASStorage persists its logical-path -> digest
+// index on the wrapped backend, so the index survives process restarts.
+//
+// The index is loaded once at construction and read-modify-written
+// under c.mu on every mutation, which only serializes access within a
+// single CASStorage - it is not a compare-and-swap against the backend.
+// Two processes (or two CASStorage instances) wrapping the same backend
+// concurrently can race: the second saveIndex silently overwrites the
+// first's refcount update, which can drop a still-referenced digest's
+// count to zero and delete a blob another logical path still points at.
+// CASStorage is therefore single-process-use only - run one instance
+// per backend, e.g. one per server process, not one per request.
+const casIndexPath = "_cas/index.json"
+
+// CASStorage wraps another Storage implementation and deduplicates blobs
+// by content: Save/SaveReader hash the data and store it once under a
+// sha256/aa/bb/digest path, no matter how many logical names point at
+// it. A side index maps each logical path to the digest it currently
+// resolves to, with reference counts so Delete only drops the
+// underlying blob once every logical name pointing at it is gone. This
+// lets BatchProcessor jobs that repeatedly convert the same markdown or
+// intermediate PDF reuse one blob instead of storing it once per job.
+//
+// CASStorage is safe for concurrent use by multiple goroutines within
+// one process (c.mu serializes index access), but - see casIndexPath -
+// is not safe to share across multiple processes wrapping the same
+// backend.
+type CASStorage struct {
+	backend Storage
+
+	mu    sync.Mutex
+	index casIndex
+}
+
+type casIndex struct {
+	// Paths maps a logical path to the digest it currently resolves to.
+	Paths map[string]string `json:"paths"`
+	// RefCounts maps a digest to the number of logical paths pointing at it.
+	RefCounts map[string]int `json:"refCounts"`
+}
+
+// NewCASStorage wraps backend with content-addressable dedup, loading
+// any existing index from backend so a process restart resumes with the
+// same view of stored digests. See casIndexPath for why only one
+// CASStorage should run against a given backend at a time.
+func NewCASStorage(backend Storage) (*CASStorage, error) {
+	c := &CASStorage{
+		backend: backend,
+		index: casIndex{
+			Paths:     map[string]string{},
+			RefCounts: map[string]int{},
+		},
+	}
+	if err := c.loadIndex(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *CASStorage) loadIndex() error {
+	data, err := c.backend.Load(context.Background(), casIndexPath)
+	if err != nil {
+		// No index yet - start from empty, same as a fresh backend.
+		return nil
+	}
+	var idx casIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return fmt.Errorf("cas: failed to parse index: %w", err)
+	}
+	if idx.Paths != nil {
+		c.index.Paths = idx.Paths
+	}
+	if idx.RefCounts != nil {
+		c.index.RefCounts = idx.RefCounts
+	}
+	return nil
+}
+
+// saveIndex persists the index; caller must hold c.mu.
+func (c *CASStorage) saveIndex(ctx context.Context) error {
+	data, err := json.Marshal(c.index)
+	if err != nil {
+		return fmt.Errorf("cas: failed to encode index: %w", err)
+	}
+	return c.backend.Save(ctx, casIndexPath, data)
+}
+
+func digestKey(digest string) string {
+	return fmt.Sprintf("sha256/%s/%s/%s", digest[:2], digest[2:4], digest)
+}
+
+// Save stores data at the given logical path, deduped by content digest.
+func (c *CASStorage) Save(ctx context.Context, path string, data []byte) error {
+	return c.SaveReader(ctx, path, bytes.NewReader(data))
+}
+
+// SaveReader streams reader through a sha256 hasher into a temp file
+// (via io.TeeReader, so nothing is buffered in memory) to learn the
+// content digest before touching the backend, then stores the blob
+// under that digest - skipping the write entirely if it's already
+// there - and retargets path to point at it. Pass WithProgress(p) to
+// observe this buffering step; it's not forwarded to the backend's own
+// SaveReader/SaveReaderWithSize call, since that would double-count the
+// same bytes from the caller's perspective.
+func (c *CASStorage) SaveReader(ctx context.Context, path string, reader io.Reader, opts ...TransferOption) error {
+	return c.saveReader(ctx, path, reader, -1, opts...)
+}
+
+// SaveReaderWithSize behaves like SaveReader; the digest has to be
+// computed from the full content either way, so the size hint buys
+// nothing here, but CASStorage still passes it through to the backend
+// once the digest is known, in case the wrapped backend can use it.
+func (c *CASStorage) SaveReaderWithSize(ctx context.Context, path string, reader io.Reader, size int64, opts ...TransferOption) error {
+	return c.saveReader(ctx, path, reader, size, opts...)
+}
+
+func (c *CASStorage) saveReader(ctx context.Context, path string, reader io.Reader, size int64, opts ...TransferOption) error {
+	to := CollectTransferOptions(opts...)
+	reader, finish := withProgress(reader, to.Progress, size)
+
+	tmp, err := os.CreateTemp("", "docflow-cas-*")
+	if err != nil {
+		finish(err)
+		return fmt.Errorf("cas: failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+	defer tmp.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(tmp, io.TeeReader(reader, hasher)); err != nil {
+		finish(err)
+		return fmt.Errorf("cas: failed to buffer data: %w", err)
+	}
+	digest := hex.EncodeToString(hasher.Sum(nil))
+	key := digestKey(digest)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	exists, err := c.backend.Exists(ctx, key)
+	if err != nil {
+		finish(err)
+		return fmt.Errorf("cas: failed to check existing blob: %w", err)
+	}
+	if !exists {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			finish(err)
+			return fmt.Errorf("cas: failed to rewind temp file: %w", err)
+		}
+		if size > 0 {
+			err = c.backend.SaveReaderWithSize(ctx, key, tmp, size)
+		} else {
+			err = c.backend.SaveReader(ctx, key, tmp)
+		}
+		if err != nil {
+			finish(err)
+			return fmt.Errorf("cas: failed to store blob: %w", err)
+		}
+	}
+
+	c.retarget(ctx, path, digest)
+	err = c.saveIndex(ctx)
+	finish(err)
+	return err
+}
+
+// retarget points path at digest, dropping the reference to whatever
+// digest path previously resolved to; caller must hold c.mu.
+func (c *CASStorage) retarget(ctx context.Context, path, digest string) {
+	if old, ok := c.index.Paths[path]; ok {
+		if old == digest {
+			return
+		}
+		c.decRef(ctx, old)
+	}
+	c.index.Paths[path] = digest
+	c.index.RefCounts[digest]++
+}
+
+// decRef drops one reference to digest, deleting the underlying blob
+// once the count reaches zero; caller must hold c.mu. Blob deletion
+// errors are ignored, matching the other backends' tolerance of
+// deleting something already gone.
+func (c *CASStorage) decRef(ctx context.Context, digest string) {
+	c.index.RefCounts[digest]--
+	if c.index.RefCounts[digest] <= 0 {
+		delete(c.index.RefCounts, digest)
+		_ = c.backend.Delete(ctx, digestKey(digest))
+	}
+}
+
+// Load retrieves the data that path currently resolves to.
+func (c *CASStorage) Load(ctx context.Context, path string) ([]byte, error) {
+	digest, ok := c.resolve(path)
+	if !ok {
+		return nil, fmt.Errorf("cas: file not found: %s", path)
+	}
+	return c.backend.Load(ctx, digestKey(digest))
+}
+
+// LoadReader returns a reader for the data that path currently resolves to.
+func (c *CASStorage) LoadReader(ctx context.Context, path string, opts ...TransferOption) (io.ReadCloser, error) {
+	digest, ok := c.resolve(path)
+	if !ok {
+		return nil, fmt.Errorf("cas: file not found: %s", path)
+	}
+	return c.backend.LoadReader(ctx, digestKey(digest), opts...)
+}
+
+// Delete drops path's reference to its digest, only removing the
+// underlying blob once no other logical path still references it.
+func (c *CASStorage) Delete(ctx context.Context, path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	digest, ok := c.index.Paths[path]
+	if !ok {
+		return nil
+	}
+	delete(c.index.Paths, path)
+	c.decRef(ctx, digest)
+	return c.saveIndex(ctx)
+}
+
+// Exists checks whether path is a known logical name in the index.
+func (c *CASStorage) Exists(ctx context.Context, path string) (bool, error) {
+	_, ok := c.resolve(path)
+	return ok, nil
+}
+
+// List returns the logical paths directly under dir, mirroring the
+// other backends' "direct children only" semantics.
+func (c *CASStorage) List(ctx context.Context, dir string) ([]string, error) {
+	prefix := dir
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var files []string
+	for p := range c.index.Paths {
+		rel := strings.TrimPrefix(p, prefix)
+		if prefix != "" && rel == p {
+			continue
+		}
+		if rel == "" || strings.Contains(rel, "/") {
+			continue
+		}
+		files = append(files, rel)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// GetURL returns the wrapped backend's URL for the digest that path
+// resolves to, or "" if path is unknown.
+func (c *CASStorage) GetURL(path string) string {
+	digest, ok := c.resolve(path)
+	if !ok {
+		return ""
+	}
+	return c.backend.GetURL(digestKey(digest))
+}
+
+// Stat returns lifecycle metadata for the digest that path resolves to.
+func (c *CASStorage) Stat(ctx context.Context, path string) (ObjectInfo, error) {
+	digest, ok := c.resolve(path)
+	if !ok {
+		return ObjectInfo{}, fmt.Errorf("cas: file not found: %s", path)
+	}
+	return c.backend.Stat(ctx, digestKey(digest))
+}
+
+// PresignedURL returns the wrapped backend's presigned URL for the
+// digest that path resolves to.
+func (c *CASStorage) PresignedURL(ctx context.Context, path string, expiry time.Duration) (string, error) {
+	digest, ok := c.resolve(path)
+	if !ok {
+		return "", fmt.Errorf("cas: file not found: %s", path)
+	}
+	return c.backend.PresignedURL(ctx, digestKey(digest), expiry)
+}
+
+// Digest returns the sha256 hex digest path currently resolves to, so
+// callers (e.g. BatchProcessor dedup checks) can compare content
+// identity across logical paths without loading the blob itself.
+func (c *CASStorage) Digest(path string) (string, bool) {
+	return c.resolve(path)
+}
+
+func (c *CASStorage) resolve(path string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	digest, ok := c.index.Paths[path]
+	return digest, ok
+}
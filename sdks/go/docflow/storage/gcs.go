@@ -0,0 +1,226 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+func init() {
+	Register("gcs", func(cfg map[string]any) (Storage, error) {
+		return NewGCSStorage(GCSConfig{
+			Bucket:          cfgString(cfg, "bucket"),
+			Prefix:          cfgString(cfg, "prefix"),
+			CredentialsFile: cfgString(cfg, "credentialsFile"),
+		})
+	})
+}
+
+// GCSStorage implements Storage interface for Google Cloud Storage. It
+// covers the same CRUD surface as S3Storage/AzureStorage but without
+// their encryption/presigning/multipart options - add those here as
+// users need them.
+type GCSStorage struct {
+	client *storage.Client
+	bucket string
+	prefix string
+}
+
+// GCSConfig contains configuration for Google Cloud Storage.
+type GCSConfig struct {
+	Bucket string
+	Prefix string // Optional prefix for all object names
+
+	// CredentialsFile is an optional path to a service account JSON key.
+	// Leave empty to use Application Default Credentials (GOOGLE_APPLICATION_CREDENTIALS,
+	// gcloud user credentials, or the GCE/GKE/Cloud Run metadata server).
+	CredentialsFile string
+}
+
+// NewGCSStorage creates a new Google Cloud Storage instance.
+func NewGCSStorage(cfg GCSConfig) (*GCSStorage, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("bucket is required")
+	}
+
+	ctx := context.Background()
+
+	var opts []option.ClientOption
+	if cfg.CredentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(cfg.CredentialsFile))
+	}
+
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS client: %w", err)
+	}
+
+	return &GCSStorage{
+		client: client,
+		bucket: cfg.Bucket,
+		prefix: strings.TrimSuffix(cfg.Prefix, "/"),
+	}, nil
+}
+
+// Save stores data at the given path.
+func (s *GCSStorage) Save(ctx context.Context, path string, data []byte) error {
+	return s.SaveReader(ctx, path, bytes.NewReader(data))
+}
+
+// SaveReader stores data from a reader at the given path.
+func (s *GCSStorage) SaveReader(ctx context.Context, filePath string, reader io.Reader, opts ...TransferOption) error {
+	return s.saveReader(ctx, filePath, reader, 0, opts...)
+}
+
+// SaveReaderWithSize ignores size - the GCS writer streams regardless of
+// whether the caller knows the length up front - and just calls
+// SaveReader.
+func (s *GCSStorage) SaveReaderWithSize(ctx context.Context, filePath string, reader io.Reader, size int64, opts ...TransferOption) error {
+	return s.saveReader(ctx, filePath, reader, size, opts...)
+}
+
+func (s *GCSStorage) saveReader(ctx context.Context, filePath string, reader io.Reader, size int64, opts ...TransferOption) error {
+	to := CollectTransferOptions(opts...)
+	reader, finish := withProgress(reader, to.Progress, size)
+
+	w := s.client.Bucket(s.bucket).Object(s.fullKey(filePath)).NewWriter(ctx)
+
+	if _, err := io.Copy(w, reader); err != nil {
+		w.Close()
+		finish(err)
+		return fmt.Errorf("failed to upload to GCS: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		finish(err)
+		return fmt.Errorf("failed to finalize GCS upload: %w", err)
+	}
+
+	finish(nil)
+	return nil
+}
+
+// Load retrieves data from the given path.
+func (s *GCSStorage) Load(ctx context.Context, filePath string) ([]byte, error) {
+	reader, err := s.LoadReader(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// LoadReader returns a reader for the data at the given path.
+func (s *GCSStorage) LoadReader(ctx context.Context, filePath string, opts ...TransferOption) (io.ReadCloser, error) {
+	r, err := s.client.Bucket(s.bucket).Object(s.fullKey(filePath)).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object from GCS: %w", err)
+	}
+	to := CollectTransferOptions(opts...)
+	return withProgressReadCloser(r, to.Progress, r.Attrs.Size), nil
+}
+
+// Delete removes the file at the given path.
+func (s *GCSStorage) Delete(ctx context.Context, filePath string) error {
+	if err := s.client.Bucket(s.bucket).Object(s.fullKey(filePath)).Delete(ctx); err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete from GCS: %w", err)
+	}
+	return nil
+}
+
+// Exists checks if a file exists at the given path.
+func (s *GCSStorage) Exists(ctx context.Context, filePath string) (bool, error) {
+	_, err := s.client.Bucket(s.bucket).Object(s.fullKey(filePath)).Attrs(ctx)
+	if err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// List returns all files in the given directory. GCS's ObjectIterator
+// already fetches subsequent pages internally as Next() is called, so
+// this was never truncated at one page's worth of objects.
+func (s *GCSStorage) List(ctx context.Context, dir string) ([]string, error) {
+	prefix := s.fullKey(dir)
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	it := s.client.Bucket(s.bucket).Objects(ctx, &storage.Query{
+		Prefix:    prefix,
+		Delimiter: "/",
+	})
+
+	var files []string
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		name := strings.TrimPrefix(attrs.Name, prefix)
+		if name != "" {
+			files = append(files, name)
+		}
+	}
+
+	return files, nil
+}
+
+// Stat returns lifecycle metadata for the file at path.
+func (s *GCSStorage) Stat(ctx context.Context, filePath string) (ObjectInfo, error) {
+	attrs, err := s.client.Bucket(s.bucket).Object(s.fullKey(filePath)).Attrs(ctx)
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to stat GCS object: %w", err)
+	}
+
+	return ObjectInfo{
+		Size:        attrs.Size,
+		ModTime:     attrs.Updated,
+		ETag:        attrs.Etag,
+		ContentType: attrs.ContentType,
+		ContentMD5:  attrs.MD5,
+		Metadata:    attrs.Metadata,
+	}, nil
+}
+
+// PresignedURL returns a signed GET URL for path valid for expiry. It
+// requires GCSConfig.CredentialsFile (a service account key) - signing
+// needs a private key to sign with, which Application Default
+// Credentials (the default when CredentialsFile is empty) don't expose.
+func (s *GCSStorage) PresignedURL(ctx context.Context, filePath string, expiry time.Duration) (string, error) {
+	url, err := s.client.Bucket(s.bucket).SignedURL(s.fullKey(filePath), &storage.SignedURLOptions{
+		Method:  "GET",
+		Expires: time.Now().Add(expiry),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to presign GCS url: %w", err)
+	}
+	return url, nil
+}
+
+// GetURL returns the gs:// URL for the file.
+func (s *GCSStorage) GetURL(filePath string) string {
+	return fmt.Sprintf("gs://%s/%s", s.bucket, s.fullKey(filePath))
+}
+
+func (s *GCSStorage) fullKey(filePath string) string {
+	if s.prefix == "" {
+		return filePath
+	}
+	return path.Join(s.prefix, filePath)
+}
@@ -1,15 +1,40 @@
 package storage
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"mime"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
+func init() {
+	factory := func(cfg map[string]any) (Storage, error) {
+		return NewLocalStorage(cfgString(cfg, "path"))
+	}
+	Register("file", factory)
+	Register("local", factory)
+}
+
 // LocalStorage implements Storage interface for local filesystem.
 type LocalStorage struct {
 	basePath string
+
+	// signingKey authenticates PresignedURL tokens, verified by
+	// PresignHandler. It's generated fresh per instance, so presigned
+	// URLs only verify against the LocalStorage (and process) that
+	// minted them - there's no external signing service to share a key
+	// with.
+	signingKey []byte
 }
 
 // NewLocalStorage creates a new local storage instance.
@@ -25,11 +50,16 @@ func NewLocalStorage(basePath string) (*LocalStorage, error) {
 		return nil, fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
-	return &LocalStorage{basePath: absPath}, nil
+	signingKey := make([]byte, 32)
+	if _, err := rand.Read(signingKey); err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	return &LocalStorage{basePath: absPath, signingKey: signingKey}, nil
 }
 
 // Save stores data at the given path.
-func (s *LocalStorage) Save(path string, data []byte) error {
+func (s *LocalStorage) Save(ctx context.Context, path string, data []byte) error {
 	fullPath := s.fullPath(path)
 
 	// Ensure parent directory exists
@@ -45,29 +75,46 @@ func (s *LocalStorage) Save(path string, data []byte) error {
 }
 
 // SaveReader stores data from a reader at the given path.
-func (s *LocalStorage) SaveReader(path string, reader io.Reader) error {
+func (s *LocalStorage) SaveReader(ctx context.Context, path string, reader io.Reader, opts ...TransferOption) error {
+	return s.saveReader(ctx, path, reader, 0, opts...)
+}
+
+// SaveReaderWithSize ignores size - the local filesystem has no use for
+// a size hint - and just calls SaveReader.
+func (s *LocalStorage) SaveReaderWithSize(ctx context.Context, path string, reader io.Reader, size int64, opts ...TransferOption) error {
+	return s.saveReader(ctx, path, reader, size, opts...)
+}
+
+func (s *LocalStorage) saveReader(ctx context.Context, path string, reader io.Reader, size int64, opts ...TransferOption) error {
+	to := CollectTransferOptions(opts...)
+	reader, finish := withProgress(reader, to.Progress, size)
+
 	fullPath := s.fullPath(path)
 
 	// Ensure parent directory exists
 	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		finish(err)
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
 	file, err := os.Create(fullPath)
 	if err != nil {
+		finish(err)
 		return fmt.Errorf("failed to create file: %w", err)
 	}
 	defer file.Close()
 
 	if _, err := io.Copy(file, reader); err != nil {
+		finish(err)
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
+	finish(nil)
 	return nil
 }
 
 // Load retrieves data from the given path.
-func (s *LocalStorage) Load(path string) ([]byte, error) {
+func (s *LocalStorage) Load(ctx context.Context, path string) ([]byte, error) {
 	fullPath := s.fullPath(path)
 
 	data, err := os.ReadFile(fullPath)
@@ -82,7 +129,7 @@ func (s *LocalStorage) Load(path string) ([]byte, error) {
 }
 
 // LoadReader returns a reader for the data at the given path.
-func (s *LocalStorage) LoadReader(path string) (io.ReadCloser, error) {
+func (s *LocalStorage) LoadReader(ctx context.Context, path string, opts ...TransferOption) (io.ReadCloser, error) {
 	fullPath := s.fullPath(path)
 
 	file, err := os.Open(fullPath)
@@ -93,11 +140,16 @@ func (s *LocalStorage) LoadReader(path string) (io.ReadCloser, error) {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 
-	return file, nil
+	to := CollectTransferOptions(opts...)
+	var size int64
+	if info, statErr := file.Stat(); statErr == nil {
+		size = info.Size()
+	}
+	return withProgressReadCloser(file, to.Progress, size), nil
 }
 
 // Delete removes the file at the given path.
-func (s *LocalStorage) Delete(path string) error {
+func (s *LocalStorage) Delete(ctx context.Context, path string) error {
 	fullPath := s.fullPath(path)
 
 	if err := os.Remove(fullPath); err != nil {
@@ -111,7 +163,7 @@ func (s *LocalStorage) Delete(path string) error {
 }
 
 // Exists checks if a file exists at the given path.
-func (s *LocalStorage) Exists(path string) (bool, error) {
+func (s *LocalStorage) Exists(ctx context.Context, path string) (bool, error) {
 	fullPath := s.fullPath(path)
 
 	_, err := os.Stat(fullPath)
@@ -126,7 +178,7 @@ func (s *LocalStorage) Exists(path string) (bool, error) {
 }
 
 // List returns all files in the given directory.
-func (s *LocalStorage) List(dir string) ([]string, error) {
+func (s *LocalStorage) List(ctx context.Context, dir string) ([]string, error) {
 	fullPath := s.fullPath(dir)
 
 	entries, err := os.ReadDir(fullPath)
@@ -152,6 +204,72 @@ func (s *LocalStorage) GetURL(path string) string {
 	return "file://" + s.fullPath(path)
 }
 
+// Stat returns lifecycle metadata for the file at path. ETag is left
+// empty - computing one would mean hashing the whole file, which Stat's
+// callers (checking size/mtime before deciding whether to re-fetch)
+// shouldn't have to pay for.
+func (s *LocalStorage) Stat(ctx context.Context, path string) (ObjectInfo, error) {
+	fullPath := s.fullPath(path)
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ObjectInfo{}, fmt.Errorf("file not found: %s", path)
+		}
+		return ObjectInfo{}, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	return ObjectInfo{
+		Size:        info.Size(),
+		ModTime:     info.ModTime(),
+		ContentType: mime.TypeByExtension(filepath.Ext(path)),
+	}, nil
+}
+
+// PresignedURL returns path's GetURL with a short-lived HMAC-signed
+// token appended, verifiable by PresignHandler without any external
+// signing service or shared secret beyond this LocalStorage's own
+// signingKey.
+func (s *LocalStorage) PresignedURL(ctx context.Context, path string, expiry time.Duration) (string, error) {
+	exp := time.Now().Add(expiry).Unix()
+	sig := s.sign(path, exp)
+	return fmt.Sprintf("%s?exp=%d&sig=%s", s.GetURL(path), exp, sig), nil
+}
+
+// PresignHandler returns an http.Handler that verifies a PresignedURL's
+// exp/sig query parameters before serving the requested path's bytes -
+// mount it to let PresignedURL links be fetched over HTTP instead of
+// read straight off disk. r.URL.Path (with any mount prefix already
+// stripped, e.g. via http.StripPrefix) is used as the storage path.
+func (s *LocalStorage) PresignHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/")
+
+		exp, err := strconv.ParseInt(r.URL.Query().Get("exp"), 10, 64)
+		if err != nil || time.Now().Unix() > exp {
+			http.Error(w, "expired or invalid token", http.StatusForbidden)
+			return
+		}
+		if !hmac.Equal([]byte(r.URL.Query().Get("sig")), []byte(s.sign(path, exp))) {
+			http.Error(w, "invalid signature", http.StatusForbidden)
+			return
+		}
+
+		data, err := s.Load(r.Context(), path)
+		if err != nil {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	})
+}
+
+func (s *LocalStorage) sign(path string, exp int64) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	fmt.Fprintf(mac, "%s:%d", path, exp)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 // BasePath returns the base path of the storage.
 func (s *LocalStorage) BasePath() string {
 	return s.basePath
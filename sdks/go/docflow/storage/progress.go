@@ -0,0 +1,123 @@
+package storage
+
+import "io"
+
+// Progress receives callbacks describing a SaveReader/LoadReader
+// transfer (or, for PostgresVectorStore.UpsertBatch, a batch of row
+// upserts) as it runs, so a caller can render a live bar or forward
+// updates over SSE instead of only learning the outcome once the call
+// returns. Implementations must return quickly - callbacks fire on the
+// transfer's own goroutine (or, for AzureStorage's concurrent block
+// uploads, from several goroutines at once), so a slow Progress stalls
+// the transfer itself.
+type Progress interface {
+	// OnStart is called once, before the first byte moves, with the
+	// transfer's total size in bytes if known, or 0 if not (e.g. an
+	// io.Reader of unknown length).
+	OnStart(total int64)
+
+	// OnBytes is called as bytes move, with the number transferred since
+	// the last call - a delta, not a running total.
+	OnBytes(delta int64)
+
+	// OnFile is called once per logical unit in a multi-item transfer
+	// (e.g. once per row PostgresVectorStore.UpsertBatch commits), after
+	// that item completes. done and total are 1-based/absolute counts.
+	OnFile(name string, done, total int)
+
+	// OnFinish is called exactly once, after the transfer completes
+	// (err == nil) or fails (err != nil).
+	OnFinish(err error)
+}
+
+// TransferOption configures an optional Progress for one SaveReader/
+// LoadReader/UpsertBatch call.
+type TransferOption func(*TransferOptions)
+
+// TransferOptions holds the options TransferOption functions set.
+type TransferOptions struct {
+	Progress Progress
+}
+
+// WithProgress reports transfer progress through p.
+func WithProgress(p Progress) TransferOption {
+	return func(o *TransferOptions) { o.Progress = p }
+}
+
+// CollectTransferOptions applies opts and returns the resulting
+// TransferOptions, for Storage implementations to call at the top of
+// SaveReader/SaveReaderWithSize/LoadReader.
+func CollectTransferOptions(opts ...TransferOption) TransferOptions {
+	var o TransferOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// withProgress wraps reader so every Read reports through p, and returns
+// a finish func the caller must invoke exactly once with the transfer's
+// resulting error after it completes. If p is nil, reader is returned
+// unchanged and finish is a no-op.
+func withProgress(reader io.Reader, p Progress, total int64) (io.Reader, func(error)) {
+	if p == nil {
+		return reader, func(error) {}
+	}
+	p.OnStart(total)
+	return &progressReader{r: reader, p: p}, p.OnFinish
+}
+
+// progressReader wraps an io.Reader, reporting every Read through p as
+// it happens. Used where a backend streams the caller's reader directly
+// (Local, GCS, S3, CASStorage) rather than reading it in its own
+// fixed-size chunks (AzureStorage.SaveReader reports progress per staged
+// block instead).
+type progressReader struct {
+	r io.Reader
+	p Progress
+}
+
+func (pr *progressReader) Read(b []byte) (int, error) {
+	n, err := pr.r.Read(b)
+	if n > 0 {
+		pr.p.OnBytes(int64(n))
+	}
+	return n, err
+}
+
+// withProgressReadCloser wraps rc so every Read reports through p and
+// Close reports OnFinish with whichever error (from Read or Close) the
+// transfer last saw. Returns rc unchanged if p is nil.
+func withProgressReadCloser(rc io.ReadCloser, p Progress, total int64) io.ReadCloser {
+	if p == nil {
+		return rc
+	}
+	p.OnStart(total)
+	return &progressReadCloser{rc: rc, p: p}
+}
+
+type progressReadCloser struct {
+	rc  io.ReadCloser
+	p   Progress
+	err error
+}
+
+func (pc *progressReadCloser) Read(b []byte) (int, error) {
+	n, err := pc.rc.Read(b)
+	if n > 0 {
+		pc.p.OnBytes(int64(n))
+	}
+	if err != nil && err != io.EOF {
+		pc.err = err
+	}
+	return n, err
+}
+
+func (pc *progressReadCloser) Close() error {
+	err := pc.rc.Close()
+	if err != nil && pc.err == nil {
+		pc.err = err
+	}
+	pc.p.OnFinish(pc.err)
+	return err
+}
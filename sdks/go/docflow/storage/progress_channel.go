@@ -0,0 +1,89 @@
+package storage
+
+import "sync"
+
+// TransferEventType identifies what a TransferEvent is reporting.
+type TransferEventType string
+
+const (
+	// TransferEventStart is emitted once, from OnStart.
+	TransferEventStart TransferEventType = "start"
+	// TransferEventBytes is emitted on every OnBytes callback.
+	TransferEventBytes TransferEventType = "bytes"
+	// TransferEventFile is emitted on every OnFile callback.
+	TransferEventFile TransferEventType = "file"
+	// TransferEventFinished is emitted once, from OnFinish, and is always
+	// the last event - Events is closed right after it's sent.
+	TransferEventFinished TransferEventType = "finished"
+)
+
+// TransferEvent is a single structured update ChannelProgress emits.
+// Which fields are meaningful depends on Type: Total only on
+// TransferEventStart, Delta/Transferred only on TransferEventBytes,
+// File/FileDone/FileTotal only on TransferEventFile, Err only on
+// TransferEventFinished.
+type TransferEvent struct {
+	Type        TransferEventType
+	Total       int64
+	Delta       int64
+	Transferred int64
+	File        string
+	FileDone    int
+	FileTotal   int
+	Err         error
+}
+
+// ChannelProgress implements Progress by publishing a TransferEvent onto
+// Events for every callback, so an HTTP handler can forward them as SSE
+// (or WebSocket) updates instead of rendering a local bar. Publishing
+// never blocks: a full Events channel just drops the event, the same
+// tolerance batchprogress.Job.Publish has for a slow subscriber, so a
+// transfer is never stalled by a client that stops reading.
+type ChannelProgress struct {
+	Events chan TransferEvent
+
+	mu          sync.Mutex
+	transferred int64
+}
+
+// NewChannelProgress creates a ChannelProgress whose Events channel has
+// the given buffer size. The channel is closed after the TransferEventFinished
+// event is sent.
+func NewChannelProgress(buffer int) *ChannelProgress {
+	return &ChannelProgress{Events: make(chan TransferEvent, buffer)}
+}
+
+func (c *ChannelProgress) publish(e TransferEvent) {
+	select {
+	case c.Events <- e:
+	default:
+	}
+}
+
+// OnStart implements Progress.
+func (c *ChannelProgress) OnStart(total int64) {
+	c.publish(TransferEvent{Type: TransferEventStart, Total: total})
+}
+
+// OnBytes implements Progress, tracking a running total so subscribers
+// that miss a dropped event can still see overall progress from the next
+// one - Transferred, unlike Delta, isn't something a reader can reconstruct
+// on its own.
+func (c *ChannelProgress) OnBytes(delta int64) {
+	c.mu.Lock()
+	c.transferred += delta
+	transferred := c.transferred
+	c.mu.Unlock()
+	c.publish(TransferEvent{Type: TransferEventBytes, Delta: delta, Transferred: transferred})
+}
+
+// OnFile implements Progress.
+func (c *ChannelProgress) OnFile(name string, done, total int) {
+	c.publish(TransferEvent{Type: TransferEventFile, File: name, FileDone: done, FileTotal: total})
+}
+
+// OnFinish implements Progress.
+func (c *ChannelProgress) OnFinish(err error) {
+	c.publish(TransferEvent{Type: TransferEventFinished, Err: err})
+	close(c.Events)
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+)
+
+// TerminalProgress renders a live byte-progress bar for a single
+// SaveReader/SaveReaderWithSize/LoadReader transfer (or UpsertBatch's row
+// count), for CLIs driving large uploads/downloads - the storage-transfer
+// analogue of batchprogress.TerminalReporter's per-file bar.
+type TerminalProgress struct {
+	bar *progressbar.ProgressBar
+}
+
+// NewTerminalProgress creates a TerminalProgress. Pass it to a transfer
+// call via WithProgress.
+func NewTerminalProgress() *TerminalProgress {
+	return &TerminalProgress{}
+}
+
+// OnStart implements Progress, sizing the bar to total or, if total <= 0
+// (unknown, e.g. an io.Reader of unknown length), rendering a spinner
+// instead.
+func (t *TerminalProgress) OnStart(total int64) {
+	if total <= 0 {
+		total = -1
+	}
+	t.bar = progressbar.NewOptions64(total,
+		progressbar.OptionSetDescription("transferring"),
+		progressbar.OptionShowBytes(true),
+		progressbar.OptionShowCount(),
+		progressbar.OptionThrottle(100*time.Millisecond),
+		progressbar.OptionShowElapsedTimeOnFinish(),
+	)
+}
+
+// OnBytes implements Progress.
+func (t *TerminalProgress) OnBytes(delta int64) {
+	if t.bar != nil {
+		_ = t.bar.Add64(delta)
+	}
+}
+
+// OnFile implements Progress, relabeling the bar with the row/file
+// currently completing (e.g. PostgresVectorStore.UpsertBatch's record ID).
+func (t *TerminalProgress) OnFile(name string, done, total int) {
+	if t.bar != nil {
+		t.bar.Describe(fmt.Sprintf("transferring %s (%d/%d)", name, done, total))
+	}
+}
+
+// OnFinish implements Progress.
+func (t *TerminalProgress) OnFinish(err error) {
+	if t.bar != nil {
+		_ = t.bar.Finish()
+	}
+	fmt.Println()
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Factory builds a Storage backend from a DSN's query parameters and
+// path components, as parsed by NewFromURL. Values come from a URL, so
+// they typically arrive as strings even where a backend's Config field
+// is a bool/int/duration - use the cfgString/cfgBool/... helpers to
+// convert them.
+type Factory func(cfg map[string]any) (Storage, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+// Register adds a named backend factory, so New and NewFromURL can
+// construct it by name/scheme. Backends register themselves from an
+// init() func in their own file (see local.go, s3.go, gcs.go, azure.go),
+// following the pattern rclone uses for its backend registry.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = factory
+}
+
+// New builds the backend registered under name with the given config.
+func New(name string, cfg map[string]any) (Storage, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("storage: no backend registered for %q", name)
+	}
+	return factory(cfg)
+}
+
+func cfgString(cfg map[string]any, key string) string {
+	v, _ := cfg[key].(string)
+	return v
+}
+
+func cfgBool(cfg map[string]any, key string) bool {
+	switch v := cfg[key].(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	default:
+		return false
+	}
+}
+
+func cfgInt64(cfg map[string]any, key string) int64 {
+	switch v := cfg[key].(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	default:
+		return 0
+	}
+}
+
+func cfgDuration(cfg map[string]any, key string) time.Duration {
+	switch v := cfg[key].(type) {
+	case time.Duration:
+		return v
+	case string:
+		d, _ := time.ParseDuration(v)
+		return d
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Router implements Storage by dispatching each call to a different
+// registered backend based on the longest matching path prefix - e.g.
+// "raw/" routed to S3, "cache/" to local disk, "archive/" to GCS - so
+// callers can mix hot scratch space with cold object storage behind a
+// single Storage value. Register a "" prefix as a catch-all default for
+// paths that match no mount.
+type Router struct {
+	routes []routerEntry
+}
+
+type routerEntry struct {
+	prefix  string
+	backend Storage
+}
+
+// NewRouter builds a Router from a prefix->backend map. Longer prefixes
+// take priority over shorter ones, so "raw/hot/" can be mounted
+// separately from "raw/".
+func NewRouter(routes map[string]Storage) *Router {
+	r := &Router{}
+	for prefix, backend := range routes {
+		r.routes = append(r.routes, routerEntry{prefix: prefix, backend: backend})
+	}
+	sort.Slice(r.routes, func(i, j int) bool {
+		return len(r.routes[i].prefix) > len(r.routes[j].prefix)
+	})
+	return r
+}
+
+// route finds the backend mounted for path and returns path with that
+// mount's prefix stripped, so each backend sees paths relative to its
+// own mount point rather than the Router's combined path space.
+func (r *Router) route(path string) (Storage, string, error) {
+	for _, e := range r.routes {
+		if e.prefix != "" && strings.HasPrefix(path, e.prefix) {
+			return e.backend, strings.TrimPrefix(path, e.prefix), nil
+		}
+	}
+	for _, e := range r.routes {
+		if e.prefix == "" {
+			return e.backend, path, nil
+		}
+	}
+	return nil, "", fmt.Errorf("storage: no backend mounted for path %q", path)
+}
+
+func (r *Router) Save(ctx context.Context, path string, data []byte) error {
+	backend, rel, err := r.route(path)
+	if err != nil {
+		return err
+	}
+	return backend.Save(ctx, rel, data)
+}
+
+func (r *Router) SaveReader(ctx context.Context, path string, reader io.Reader, opts ...TransferOption) error {
+	backend, rel, err := r.route(path)
+	if err != nil {
+		return err
+	}
+	return backend.SaveReader(ctx, rel, reader, opts...)
+}
+
+func (r *Router) SaveReaderWithSize(ctx context.Context, path string, reader io.Reader, size int64, opts ...TransferOption) error {
+	backend, rel, err := r.route(path)
+	if err != nil {
+		return err
+	}
+	return backend.SaveReaderWithSize(ctx, rel, reader, size, opts...)
+}
+
+func (r *Router) Load(ctx context.Context, path string) ([]byte, error) {
+	backend, rel, err := r.route(path)
+	if err != nil {
+		return nil, err
+	}
+	return backend.Load(ctx, rel)
+}
+
+func (r *Router) LoadReader(ctx context.Context, path string, opts ...TransferOption) (io.ReadCloser, error) {
+	backend, rel, err := r.route(path)
+	if err != nil {
+		return nil, err
+	}
+	return backend.LoadReader(ctx, rel, opts...)
+}
+
+func (r *Router) Delete(ctx context.Context, path string) error {
+	backend, rel, err := r.route(path)
+	if err != nil {
+		return err
+	}
+	return backend.Delete(ctx, rel)
+}
+
+func (r *Router) Exists(ctx context.Context, path string) (bool, error) {
+	backend, rel, err := r.route(path)
+	if err != nil {
+		return false, err
+	}
+	return backend.Exists(ctx, rel)
+}
+
+// List lists files under dir on whichever backend it routes to. For the
+// root directory ("" or "/") with no catch-all mount registered, it
+// instead returns the configured mount points, since there's no single
+// backend that owns the combined root.
+func (r *Router) List(ctx context.Context, dir string) ([]string, error) {
+	if dir == "" || dir == "/" {
+		if backend, rel, err := r.route(dir); err == nil {
+			return backend.List(ctx, rel)
+		}
+		var mounts []string
+		for _, e := range r.routes {
+			if e.prefix != "" {
+				mounts = append(mounts, strings.TrimSuffix(e.prefix, "/"))
+			}
+		}
+		return mounts, nil
+	}
+
+	backend, rel, err := r.route(dir)
+	if err != nil {
+		return nil, err
+	}
+	return backend.List(ctx, rel)
+}
+
+// GetURL returns "" if path matches no mounted backend, since Storage's
+// GetURL has no error return.
+func (r *Router) GetURL(path string) string {
+	backend, rel, err := r.route(path)
+	if err != nil {
+		return ""
+	}
+	return backend.GetURL(rel)
+}
+
+func (r *Router) Stat(ctx context.Context, path string) (ObjectInfo, error) {
+	backend, rel, err := r.route(path)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return backend.Stat(ctx, rel)
+}
+
+func (r *Router) PresignedURL(ctx context.Context, path string, expiry time.Duration) (string, error) {
+	backend, rel, err := r.route(path)
+	if err != nil {
+		return "", err
+	}
+	return backend.PresignedURL(ctx, rel, expiry)
+}
@@ -7,18 +7,62 @@ import (
 	"io"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
-// S3Storage implements Storage interface for AWS S3.
+func init() {
+	Register("s3", func(cfg map[string]any) (Storage, error) {
+		return NewS3Storage(S3Config{
+			Bucket:               cfgString(cfg, "bucket"),
+			Region:               cfgString(cfg, "region"),
+			Prefix:               cfgString(cfg, "prefix"),
+			Endpoint:             cfgString(cfg, "endpoint"),
+			PathStyle:            cfgBool(cfg, "pathStyle"),
+			ForceVirtualHost:     cfgBool(cfg, "forceVirtualHost"),
+			AccessKeyID:          cfgString(cfg, "accessKeyId"),
+			SecretAccessKey:      cfgString(cfg, "secretAccessKey"),
+			SessionToken:         cfgString(cfg, "sessionToken"),
+			ServerSideEncryption: cfgString(cfg, "sse"),
+			SSEKMSKeyID:          cfgString(cfg, "sseKmsKeyId"),
+			MultipartThreshold:   cfgInt64(cfg, "multipartThreshold"),
+			UsePresignedURL:      cfgBool(cfg, "presign"),
+			PresignTTL:           cfgDuration(cfg, "presignTtl"),
+		})
+	})
+}
+
+// defaultMultipartPartSize is the manager.Uploader part size used when
+// S3Config.MultipartThreshold is unset; AWS requires at least 5MiB for
+// any part but the last.
+const defaultMultipartPartSize = 8 * 1024 * 1024
+
+// defaultPresignTTL is used when S3Config.PresignTTL is unset but
+// S3Config.UsePresignedURL is true.
+const defaultPresignTTL = 15 * time.Minute
+
+// S3Storage implements Storage interface for AWS S3 and S3-compatible
+// services (MinIO, Ceph RGW, Cloudflare R2, ...) reached via a custom
+// Endpoint.
 type S3Storage struct {
-	client *s3.Client
-	bucket string
-	prefix string
-	region string
+	client        *s3.Client
+	presignClient *s3.PresignClient
+	uploader      *manager.Uploader
+	bucket        string
+	prefix        string
+	region        string
+	endpoint      string
+
+	sse        types.ServerSideEncryption
+	sseKMSKey  string
+	presignTTL time.Duration
+	presignGet bool
 }
 
 // S3Config contains configuration for S3 storage.
@@ -26,7 +70,43 @@ type S3Config struct {
 	Bucket   string
 	Region   string
 	Prefix   string // Optional prefix for all keys
-	Endpoint string // Optional custom endpoint (for MinIO, LocalStack, etc.)
+	Endpoint string // Optional custom endpoint (for MinIO, LocalStack, R2, Ceph, ...)
+
+	// PathStyle forces path-style addressing (https://host/bucket/key)
+	// instead of virtual-host style (https://bucket.host/key). Defaults
+	// to true whenever Endpoint is set, since most S3-compatible
+	// services don't support virtual-host buckets; set ForceVirtualHost
+	// to override that default.
+	PathStyle        bool
+	ForceVirtualHost bool
+
+	// Static credentials. Leave empty to fall through to the AWS SDK's
+	// default chain (environment variables, shared config/credentials
+	// files, then the EC2/ECS/EKS IAM role).
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// ServerSideEncryption is "", "AES256" (SSE-S3), or "aws:kms"
+	// (SSE-KMS). SSEKMSKeyID is required for SSE-KMS and ignored
+	// otherwise.
+	ServerSideEncryption string
+	SSEKMSKeyID          string
+
+	// MultipartThreshold is the part size (bytes) above which
+	// SaveReader switches to a multipart upload; the AWS SDK's
+	// manager.Uploader makes that decision per part, so this is really
+	// "bytes per part" rather than a single all-or-nothing cutoff.
+	// Defaults to 8MiB if zero.
+	MultipartThreshold int64
+
+	// UsePresignedURL makes GetURL return a presigned, time-limited
+	// download URL instead of a bucket/key-shaped public URL. Use this
+	// for private buckets.
+	UsePresignedURL bool
+	// PresignTTL is how long a presigned URL stays valid. Defaults to
+	// 15 minutes if zero and UsePresignedURL is true.
+	PresignTTL time.Duration
 }
 
 // NewS3Storage creates a new S3 storage instance.
@@ -37,62 +117,119 @@ func NewS3Storage(cfg S3Config) (*S3Storage, error) {
 
 	ctx := context.Background()
 
-	// Load AWS config
-	awsCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(cfg.Region))
+	var optFns []func(*config.LoadOptions) error
+	optFns = append(optFns, config.WithRegion(cfg.Region))
+	if cfg.AccessKeyID != "" {
+		optFns = append(optFns, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, cfg.SessionToken),
+		))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx, optFns...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	// Create S3 client
+	pathStyle := cfg.PathStyle
+	if cfg.Endpoint != "" && !cfg.ForceVirtualHost {
+		pathStyle = true
+	}
+
 	clientOpts := []func(*s3.Options){}
 	if cfg.Endpoint != "" {
 		clientOpts = append(clientOpts, func(o *s3.Options) {
 			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		})
+	}
+	if pathStyle {
+		clientOpts = append(clientOpts, func(o *s3.Options) {
 			o.UsePathStyle = true
 		})
 	}
 
 	client := s3.NewFromConfig(awsCfg, clientOpts...)
 
+	partSize := cfg.MultipartThreshold
+	if partSize <= 0 {
+		partSize = defaultMultipartPartSize
+	}
+	uploader := manager.NewUploader(client, func(u *manager.Uploader) {
+		u.PartSize = partSize
+	})
+
+	presignTTL := cfg.PresignTTL
+	if presignTTL <= 0 {
+		presignTTL = defaultPresignTTL
+	}
+
 	return &S3Storage{
-		client: client,
-		bucket: cfg.Bucket,
-		prefix: strings.TrimSuffix(cfg.Prefix, "/"),
-		region: cfg.Region,
+		client:        client,
+		presignClient: s3.NewPresignClient(client),
+		uploader:      uploader,
+		bucket:        cfg.Bucket,
+		prefix:        strings.TrimSuffix(cfg.Prefix, "/"),
+		region:        cfg.Region,
+		endpoint:      cfg.Endpoint,
+		sse:           types.ServerSideEncryption(cfg.ServerSideEncryption),
+		sseKMSKey:     cfg.SSEKMSKeyID,
+		presignTTL:    presignTTL,
+		presignGet:    cfg.UsePresignedURL,
 	}, nil
 }
 
 // Save stores data at the given path.
-func (s *S3Storage) Save(path string, data []byte) error {
-	return s.SaveReader(path, bytes.NewReader(data))
+func (s *S3Storage) Save(ctx context.Context, path string, data []byte) error {
+	return s.SaveReaderWithSize(ctx, path, bytes.NewReader(data), int64(len(data)))
+}
+
+// SaveReader stores data from a reader at the given path, via
+// manager.Uploader so reader contents larger than one part are uploaded
+// as an S3 multipart upload instead of buffered whole into memory.
+func (s *S3Storage) SaveReader(ctx context.Context, filePath string, reader io.Reader, opts ...TransferOption) error {
+	return s.saveReader(ctx, filePath, reader, 0, opts...)
 }
 
-// SaveReader stores data from a reader at the given path.
-func (s *S3Storage) SaveReader(filePath string, reader io.Reader) error {
+// SaveReaderWithSize is SaveReader with a known content length: the SDK
+// uses it to set PutObjectInput.ContentLength, letting it skip the
+// extra read-ahead the multipart uploader otherwise does to guess
+// whether the upload will exceed one part.
+func (s *S3Storage) SaveReaderWithSize(ctx context.Context, filePath string, reader io.Reader, size int64, opts ...TransferOption) error {
+	return s.saveReader(ctx, filePath, reader, size, opts...)
+}
+
+func (s *S3Storage) saveReader(ctx context.Context, filePath string, reader io.Reader, size int64, opts ...TransferOption) error {
+	to := CollectTransferOptions(opts...)
+	reader, finish := withProgress(reader, to.Progress, size)
+
 	key := s.fullKey(filePath)
 
-	// Read all data (S3 needs content length for some operations)
-	data, err := io.ReadAll(reader)
-	if err != nil {
-		return fmt.Errorf("failed to read data: %w", err)
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   reader,
+	}
+	if size > 0 {
+		input.ContentLength = aws.Int64(size)
+	}
+	if s.sse != "" {
+		input.ServerSideEncryption = s.sse
+		if s.sse == types.ServerSideEncryptionAwsKms && s.sseKMSKey != "" {
+			input.SSEKMSKeyId = aws.String(s.sseKMSKey)
+		}
 	}
 
-	_, err = s.client.PutObject(context.Background(), &s3.PutObjectInput{
-		Bucket:        aws.String(s.bucket),
-		Key:           aws.String(key),
-		Body:          bytes.NewReader(data),
-		ContentLength: aws.Int64(int64(len(data))),
-	})
-	if err != nil {
+	if _, err := s.uploader.Upload(ctx, input); err != nil {
+		finish(err)
 		return fmt.Errorf("failed to upload to S3: %w", err)
 	}
 
+	finish(nil)
 	return nil
 }
 
 // Load retrieves data from the given path.
-func (s *S3Storage) Load(filePath string) ([]byte, error) {
-	reader, err := s.LoadReader(filePath)
+func (s *S3Storage) Load(ctx context.Context, filePath string) ([]byte, error) {
+	reader, err := s.LoadReader(ctx, filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -102,10 +239,10 @@ func (s *S3Storage) Load(filePath string) ([]byte, error) {
 }
 
 // LoadReader returns a reader for the data at the given path.
-func (s *S3Storage) LoadReader(filePath string) (io.ReadCloser, error) {
+func (s *S3Storage) LoadReader(ctx context.Context, filePath string, opts ...TransferOption) (io.ReadCloser, error) {
 	key := s.fullKey(filePath)
 
-	result, err := s.client.GetObject(context.Background(), &s3.GetObjectInput{
+	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
 	})
@@ -113,14 +250,19 @@ func (s *S3Storage) LoadReader(filePath string) (io.ReadCloser, error) {
 		return nil, fmt.Errorf("failed to get object from S3: %w", err)
 	}
 
-	return result.Body, nil
+	to := CollectTransferOptions(opts...)
+	var size int64
+	if result.ContentLength != nil {
+		size = *result.ContentLength
+	}
+	return withProgressReadCloser(result.Body, to.Progress, size), nil
 }
 
 // Delete removes the file at the given path.
-func (s *S3Storage) Delete(filePath string) error {
+func (s *S3Storage) Delete(ctx context.Context, filePath string) error {
 	key := s.fullKey(filePath)
 
-	_, err := s.client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
 	})
@@ -132,10 +274,10 @@ func (s *S3Storage) Delete(filePath string) error {
 }
 
 // Exists checks if a file exists at the given path.
-func (s *S3Storage) Exists(filePath string) (bool, error) {
+func (s *S3Storage) Exists(ctx context.Context, filePath string) (bool, error) {
 	key := s.fullKey(filePath)
 
-	_, err := s.client.HeadObject(context.Background(), &s3.HeadObjectInput{
+	_, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
 	})
@@ -147,40 +289,115 @@ func (s *S3Storage) Exists(filePath string) (bool, error) {
 	return true, nil
 }
 
-// List returns all files in the given directory.
-func (s *S3Storage) List(dir string) ([]string, error) {
+// List returns all files in the given directory, paginating through
+// ListObjectsV2 via its Paginator so buckets with more than one page's
+// worth of objects (1000 by default) aren't silently truncated to the
+// first page.
+func (s *S3Storage) List(ctx context.Context, dir string) ([]string, error) {
 	prefix := s.fullKey(dir)
 	if prefix != "" && !strings.HasSuffix(prefix, "/") {
 		prefix += "/"
 	}
 
-	result, err := s.client.ListObjectsV2(context.Background(), &s3.ListObjectsV2Input{
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
 		Bucket: aws.String(s.bucket),
 		Prefix: aws.String(prefix),
 	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list objects: %w", err)
-	}
 
 	var files []string
-	for _, obj := range result.Contents {
-		// Remove prefix to get relative path
-		key := strings.TrimPrefix(*obj.Key, prefix)
-		if key != "" && !strings.Contains(key, "/") {
-			files = append(files, key)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, obj := range page.Contents {
+			// Remove prefix to get relative path
+			key := strings.TrimPrefix(*obj.Key, prefix)
+			if key != "" && !strings.Contains(key, "/") {
+				files = append(files, key)
+			}
 		}
 	}
 
 	return files, nil
 }
 
-// GetURL returns the S3 URL for the file.
+// Stat returns lifecycle metadata for the file at path.
+func (s *S3Storage) Stat(ctx context.Context, filePath string) (ObjectInfo, error) {
+	key := s.fullKey(filePath)
+
+	out, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to stat S3 object: %w", err)
+	}
+
+	info := ObjectInfo{}
+	if out.ContentLength != nil {
+		info.Size = *out.ContentLength
+	}
+	if out.LastModified != nil {
+		info.ModTime = *out.LastModified
+	}
+	if out.ETag != nil {
+		info.ETag = strings.Trim(*out.ETag, `"`)
+	}
+	if out.ContentType != nil {
+		info.ContentType = *out.ContentType
+	}
+	if len(out.Metadata) > 0 {
+		info.Metadata = out.Metadata
+	}
+	return info, nil
+}
+
+// PresignedURL returns a presigned GET URL for path valid for expiry,
+// regardless of S3Config.UsePresignedURL/GetURL's own TTL - useful when
+// a caller wants a link with a specific lifetime rather than the
+// bucket-wide default GetURL uses.
+func (s *S3Storage) PresignedURL(ctx context.Context, filePath string, expiry time.Duration) (string, error) {
+	key := s.fullKey(filePath)
+
+	req, err := s.presignClient.PresignGetObject(ctx,
+		&s3.GetObjectInput{Bucket: aws.String(s.bucket), Key: aws.String(key)},
+		s3.WithPresignExpires(expiry),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign S3 url: %w", err)
+	}
+	return req.URL, nil
+}
+
+// GetURL returns a presigned, time-limited URL when UsePresignedURL was
+// set, otherwise a bucket/key-shaped public URL (s3:// for a
+// default-endpoint bucket, the custom Endpoint otherwise).
 func (s *S3Storage) GetURL(filePath string) string {
 	key := s.fullKey(filePath)
+
+	if s.presignGet {
+		req, err := s.presignClient.PresignGetObject(context.Background(),
+			&s3.GetObjectInput{Bucket: aws.String(s.bucket), Key: aws.String(key)},
+			s3.WithPresignExpires(s.presignTTL),
+		)
+		if err == nil {
+			return req.URL
+		}
+		// Fall through to a public URL if presigning failed (e.g. no
+		// credentials available to sign with).
+	}
+
+	if s.endpoint != "" {
+		return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s.endpoint, "/"), s.bucket, key)
+	}
 	return fmt.Sprintf("s3://%s/%s", s.bucket, key)
 }
 
-// GetHTTPURL returns the HTTP URL for the file.
+// GetHTTPURL returns the public AWS HTTP URL for the file, regardless of
+// UsePresignedURL/Endpoint - useful when the caller specifically wants
+// the canonical AWS URL (e.g. for bucket policies that allow anonymous
+// reads).
 func (s *S3Storage) GetHTTPURL(filePath string) string {
 	key := s.fullKey(filePath)
 	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, key)
@@ -2,35 +2,82 @@
 package storage
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"time"
 )
 
-// Storage defines the interface for storing and retrieving files.
+// Storage defines the interface for storing and retrieving files. Every
+// I/O method takes a context.Context so a caller (e.g. BatchProcessor,
+// Extractor) can cancel or time out a save/load instead of it running to
+// completion regardless. GetURL is the one exception: it's pure string
+// formatting (or, for S3/Azure presigned URLs, a local signing
+// computation), not network I/O.
 type Storage interface {
 	// Save stores data at the given path.
-	Save(path string, data []byte) error
+	Save(ctx context.Context, path string, data []byte) error
 
-	// SaveReader stores data from a reader at the given path.
-	SaveReader(path string, reader io.Reader) error
+	// SaveReader stores data from a reader at the given path. Prefer
+	// SaveReaderWithSize when the size is already known - it lets
+	// backends that need a size up front (e.g. S3's multipart decision)
+	// skip buffering the reader just to find out how big it is. Pass
+	// WithProgress(p) to observe the transfer live.
+	SaveReader(ctx context.Context, path string, reader io.Reader, opts ...TransferOption) error
+
+	// SaveReaderWithSize stores data from a reader at the given path,
+	// with the caller-supplied size as a hint. size <= 0 means "unknown"
+	// and backends should fall back to their SaveReader behavior.
+	SaveReaderWithSize(ctx context.Context, path string, reader io.Reader, size int64, opts ...TransferOption) error
 
 	// Load retrieves data from the given path.
-	Load(path string) ([]byte, error)
+	Load(ctx context.Context, path string) ([]byte, error)
 
-	// LoadReader returns a reader for the data at the given path.
-	LoadReader(path string) (io.ReadCloser, error)
+	// LoadReader returns a reader for the data at the given path. Pass
+	// WithProgress(p) to observe the transfer live as the caller reads
+	// from it.
+	LoadReader(ctx context.Context, path string, opts ...TransferOption) (io.ReadCloser, error)
 
 	// Delete removes the file at the given path.
-	Delete(path string) error
+	Delete(ctx context.Context, path string) error
 
 	// Exists checks if a file exists at the given path.
-	Exists(path string) (bool, error)
+	Exists(ctx context.Context, path string) (bool, error)
 
-	// List returns all files in the given directory.
-	List(dir string) ([]string, error)
+	// List returns all files in the given directory. Implementations
+	// must paginate through their backend's list API themselves rather
+	// than returning only its first page.
+	List(ctx context.Context, dir string) ([]string, error)
 
 	// GetURL returns a URL for accessing the file (if supported).
 	// Returns empty string if not supported.
 	GetURL(path string) string
+
+	// PresignedURL returns a time-limited URL for downloading path,
+	// valid for expiry. Unlike GetURL it always errors rather than
+	// returning "" when it can't produce one - e.g. LocalStorage mints
+	// an HMAC-signed token since it has no notion of a bucket-native
+	// presigned URL, but still needs an expiry to enforce.
+	PresignedURL(ctx context.Context, path string, expiry time.Duration) (string, error)
+
+	// Stat returns lifecycle metadata for the file at path.
+	Stat(ctx context.Context, path string) (ObjectInfo, error)
+}
+
+// ObjectInfo carries lifecycle metadata for a stored object, as returned
+// by Stat. Fields a backend has no way to populate (e.g. LocalStorage's
+// ETag) are left at their zero value rather than guessed.
+type ObjectInfo struct {
+	Size        int64
+	ModTime     time.Time
+	ETag        string
+	ContentType string
+	ContentMD5  []byte
+
+	// Metadata holds the backend's user-defined key/value metadata for
+	// the object (Azure blob metadata, S3/GCS object metadata, ...).
+	// Nil for backends with no such concept (LocalStorage).
+	Metadata map[string]string
 }
 
 // StorageType represents the type of storage backend.
@@ -42,3 +89,33 @@ const (
 	StorageTypeGCS   StorageType = "gcs"
 	StorageTypeAzure StorageType = "azure"
 )
+
+// Config selects and configures one Storage backend. Only the field
+// matching Type needs to be set; NewStorage ignores the others.
+type Config struct {
+	Type StorageType
+
+	LocalBasePath string
+
+	S3    S3Config
+	GCS   GCSConfig
+	Azure AzureConfig
+}
+
+// NewStorage builds the Storage backend named by cfg.Type, so callers
+// (BatchProcessor, the examples, ...) can pick a backend from config
+// without depending on a specific backend's constructor.
+func NewStorage(cfg Config) (Storage, error) {
+	switch cfg.Type {
+	case StorageTypeLocal, "":
+		return NewLocalStorage(cfg.LocalBasePath)
+	case StorageTypeS3:
+		return NewS3Storage(cfg.S3)
+	case StorageTypeGCS:
+		return NewGCSStorage(cfg.GCS)
+	case StorageTypeAzure:
+		return NewAzureStorage(cfg.Azure)
+	default:
+		return nil, fmt.Errorf("storage: unknown backend type %q", cfg.Type)
+	}
+}
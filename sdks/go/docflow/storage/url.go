@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// NewFromURL builds a Storage backend from a DSN like
+// "s3://bucket/prefix?region=us-east-1", "file:///var/data",
+// "gcs://bucket/prefix", or "azure://account/container?accountKey=...".
+// The scheme selects the registered backend (see Register); the
+// host/path map onto that backend's bucket/container and prefix, and
+// any query parameters are passed through as its config map.
+func NewFromURL(rawURL string) (Storage, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("storage: invalid URL %q: %w", rawURL, err)
+	}
+	if u.Scheme == "" {
+		return nil, fmt.Errorf("storage: URL %q has no scheme", rawURL)
+	}
+
+	cfg := map[string]any{}
+	for k, v := range u.Query() {
+		if len(v) > 0 {
+			cfg[k] = v[0]
+		}
+	}
+
+	switch u.Scheme {
+	case "file":
+		// file:///var/data -> Host is empty, Path is "/var/data".
+		cfg["path"] = u.Path
+	case "azure":
+		// azure://account/container?... (account has no concept of a
+		// bucket/prefix split the way S3/GCS do).
+		cfg["accountName"] = u.Host
+		cfg["containerName"] = strings.TrimPrefix(u.Path, "/")
+	default:
+		// s3://bucket/prefix, gcs://bucket/prefix, and any backend
+		// registered later that follows the same bucket+prefix shape.
+		cfg["bucket"] = u.Host
+		cfg["prefix"] = strings.TrimPrefix(u.Path, "/")
+	}
+
+	return New(u.Scheme, cfg)
+}
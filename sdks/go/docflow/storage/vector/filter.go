@@ -0,0 +1,300 @@
+package vector
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// metadataKeyPattern restricts filter keys to safe identifiers so they can
+// be interpolated into jsonb path expressions (->, ->>, ?) that Postgres
+// has no parameterized form for. Predicate values are never interpolated -
+// they're always bound through $N placeholders.
+var metadataKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// Filter is a node in a metadata filter expression tree, compiled to
+// parameterized SQL against the JSONB metadata column by compileFilter.
+// Build one from Eq/Ne/In/Gt/Gte/Lt/Lte/Like/Exists and combine with
+// And/Or/Not, e.g. And(Gte("year", 2020), In("lang", []string{"en", "de"})).
+type Filter interface {
+	compile(b *filterBuilder) error
+}
+
+// filterBuilder accumulates the SQL text and bound args for one
+// compileFilter call.
+type filterBuilder struct {
+	sb   strings.Builder
+	args []interface{}
+}
+
+// bind appends v to the builder's args and returns its placeholder,
+// numbered from 1 over every value bound so far (including the caller's
+// own leading args - see compileFilter's argOffset padding).
+func (b *filterBuilder) bind(v interface{}) string {
+	b.args = append(b.args, v)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// compileFilter renders f as a SQL boolean expression and the values it
+// binds, numbered to start right after argOffset positional args the
+// caller has already bound (so $1.."$argOffset" stay the caller's).
+func compileFilter(f Filter, argOffset int) (clause string, args []interface{}, err error) {
+	b := &filterBuilder{args: make([]interface{}, argOffset, argOffset+4)}
+	if err := f.compile(b); err != nil {
+		return "", nil, err
+	}
+	return b.sb.String(), b.args[argOffset:], nil
+}
+
+// filterFromMap converts the legacy map[string]any filter shape into an
+// equivalent conjunction of Eq predicates, so SearchWithFilter and
+// HybridSearch can share one parameterized compiler with the typed DSL.
+// Returns nil (no filter) for an empty map.
+func filterFromMap(m map[string]interface{}) Filter {
+	if len(m) == 0 {
+		return nil
+	}
+	eqs := make([]Filter, 0, len(m))
+	for k, v := range m {
+		eqs = append(eqs, Eq(k, v))
+	}
+	return And(eqs...)
+}
+
+func validateKey(key string) error {
+	if !metadataKeyPattern.MatchString(key) {
+		return fmt.Errorf("vector: invalid filter key %q", key)
+	}
+	return nil
+}
+
+// metadataTextExpr returns the jsonb->>'...' chain that extracts key's
+// value as text, walking dotted paths (e.g. "a.b") through intermediate
+// -> steps. key must already be validated against metadataKeyPattern, so
+// interpolating its (quote-free) segments is safe.
+func metadataTextExpr(key string) string {
+	parts := strings.Split(key, ".")
+	expr := "metadata"
+	for i, p := range parts {
+		if i == len(parts)-1 {
+			expr += "->>'" + p + "'"
+		} else {
+			expr += "->'" + p + "'"
+		}
+	}
+	return expr
+}
+
+// isNumericValue reports whether v is a Go numeric type, so compare
+// predicates know to cast the extracted jsonb text with ::numeric rather
+// than comparing it as text.
+func isNumericValue(v interface{}) bool {
+	switch v.(type) {
+	case int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func compileCompare(b *filterBuilder, key, op string, value interface{}) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	expr := metadataTextExpr(key)
+	if isNumericValue(value) {
+		b.sb.WriteString(fmt.Sprintf("(%s)::numeric %s %s", expr, op, b.bind(value)))
+	} else {
+		b.sb.WriteString(fmt.Sprintf("%s %s %s", expr, op, b.bind(fmt.Sprint(value))))
+	}
+	return nil
+}
+
+type eqFilter struct {
+	key   string
+	value interface{}
+}
+
+// Eq matches records whose metadata[key] equals value.
+func Eq(key string, value interface{}) Filter { return eqFilter{key, value} }
+
+func (f eqFilter) compile(b *filterBuilder) error { return compileCompare(b, f.key, "=", f.value) }
+
+type neFilter struct {
+	key   string
+	value interface{}
+}
+
+// Ne matches records whose metadata[key] does not equal value.
+func Ne(key string, value interface{}) Filter { return neFilter{key, value} }
+
+func (f neFilter) compile(b *filterBuilder) error { return compileCompare(b, f.key, "!=", f.value) }
+
+type gtFilter struct {
+	key   string
+	value interface{}
+}
+
+// Gt matches records whose metadata[key] is greater than value.
+func Gt(key string, value interface{}) Filter { return gtFilter{key, value} }
+
+func (f gtFilter) compile(b *filterBuilder) error { return compileCompare(b, f.key, ">", f.value) }
+
+type gteFilter struct {
+	key   string
+	value interface{}
+}
+
+// Gte matches records whose metadata[key] is greater than or equal to value.
+func Gte(key string, value interface{}) Filter { return gteFilter{key, value} }
+
+func (f gteFilter) compile(b *filterBuilder) error { return compileCompare(b, f.key, ">=", f.value) }
+
+type ltFilter struct {
+	key   string
+	value interface{}
+}
+
+// Lt matches records whose metadata[key] is less than value.
+func Lt(key string, value interface{}) Filter { return ltFilter{key, value} }
+
+func (f ltFilter) compile(b *filterBuilder) error { return compileCompare(b, f.key, "<", f.value) }
+
+type lteFilter struct {
+	key   string
+	value interface{}
+}
+
+// Lte matches records whose metadata[key] is less than or equal to value.
+func Lte(key string, value interface{}) Filter { return lteFilter{key, value} }
+
+func (f lteFilter) compile(b *filterBuilder) error { return compileCompare(b, f.key, "<=", f.value) }
+
+type likeFilter struct {
+	key     string
+	pattern string
+}
+
+// Like matches records whose metadata[key] (as text) matches the SQL LIKE
+// pattern.
+func Like(key, pattern string) Filter { return likeFilter{key, pattern} }
+
+func (f likeFilter) compile(b *filterBuilder) error {
+	if err := validateKey(f.key); err != nil {
+		return err
+	}
+	b.sb.WriteString(fmt.Sprintf("%s LIKE %s", metadataTextExpr(f.key), b.bind(f.pattern)))
+	return nil
+}
+
+type existsFilter struct {
+	key string
+}
+
+// Exists matches records whose metadata has a top-level key named key.
+// It uses jsonb's "?" key-existence operator, which only tests top-level
+// keys, so key must not contain a dotted path.
+func Exists(key string) Filter { return existsFilter{key} }
+
+func (f existsFilter) compile(b *filterBuilder) error {
+	if err := validateKey(f.key); err != nil {
+		return err
+	}
+	if strings.Contains(f.key, ".") {
+		return fmt.Errorf("vector: Exists only supports top-level keys, got %q", f.key)
+	}
+	b.sb.WriteString(fmt.Sprintf("metadata ? %s", b.bind(f.key)))
+	return nil
+}
+
+type inFilter struct {
+	key    string
+	values interface{}
+}
+
+// In matches records whose metadata[key] equals one of values, a slice
+// (e.g. []string{"en", "de"} or []int{1, 2}).
+func In(key string, values interface{}) Filter { return inFilter{key, values} }
+
+func (f inFilter) compile(b *filterBuilder) error {
+	if err := validateKey(f.key); err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(f.values)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return fmt.Errorf("vector: In requires a slice of values, got %T", f.values)
+	}
+	if rv.Len() == 0 {
+		b.sb.WriteString("FALSE")
+		return nil
+	}
+
+	numeric := isNumericValue(rv.Index(0).Interface())
+	expr := metadataTextExpr(f.key)
+	if numeric {
+		expr = fmt.Sprintf("(%s)::numeric", expr)
+	}
+
+	placeholders := make([]string, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		v := rv.Index(i).Interface()
+		if numeric {
+			placeholders[i] = b.bind(v)
+		} else {
+			placeholders[i] = b.bind(fmt.Sprint(v))
+		}
+	}
+	b.sb.WriteString(fmt.Sprintf("%s IN (%s)", expr, strings.Join(placeholders, ", ")))
+	return nil
+}
+
+type andFilter struct{ filters []Filter }
+
+// And matches records satisfying every one of filters. And() with no
+// arguments compiles to an always-true expression.
+func And(filters ...Filter) Filter { return andFilter{filters} }
+
+func (f andFilter) compile(b *filterBuilder) error { return compileJunction(b, f.filters, "AND", "TRUE") }
+
+type orFilter struct{ filters []Filter }
+
+// Or matches records satisfying at least one of filters. Or() with no
+// arguments compiles to an always-false expression.
+func Or(filters ...Filter) Filter { return orFilter{filters} }
+
+func (f orFilter) compile(b *filterBuilder) error { return compileJunction(b, f.filters, "OR", "FALSE") }
+
+func compileJunction(b *filterBuilder, filters []Filter, op, empty string) error {
+	if len(filters) == 0 {
+		b.sb.WriteString(empty)
+		return nil
+	}
+	b.sb.WriteString("(")
+	for i, f := range filters {
+		if i > 0 {
+			b.sb.WriteString(" " + op + " ")
+		}
+		if err := f.compile(b); err != nil {
+			return err
+		}
+	}
+	b.sb.WriteString(")")
+	return nil
+}
+
+type notFilter struct{ filter Filter }
+
+// Not negates filter.
+func Not(filter Filter) Filter { return notFilter{filter} }
+
+func (f notFilter) compile(b *filterBuilder) error {
+	b.sb.WriteString("NOT (")
+	if err := f.filter.compile(b); err != nil {
+		return err
+	}
+	b.sb.WriteString(")")
+	return nil
+}
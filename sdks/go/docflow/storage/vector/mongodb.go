@@ -2,15 +2,23 @@ package vector
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	// Requires: go.mongodb.org/mongo-driver/mongo
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+	"github.com/xgaslan/docflow/sdks/go/docflow/retrieval"
+	"github.com/xgaslan/docflow/sdks/go/docflow/storage"
+	"github.com/xgaslan/docflow/sdks/go/docflow/vectorizer"
 )
 
-// MongoDBVectorStore implements vector storage using MongoDB Atlas.
+// MongoDBVectorStore implements VectorStore using MongoDB Atlas Vector
+// Search, via the official go.mongodb.org/mongo-driver client.
 type MongoDBVectorStore struct {
-	client        interface{} // *mongo.Client
-	collection    interface{} // *mongo.Collection
+	client        *mongo.Client
+	collection    *mongo.Collection
 	config        MongoDBConfig
 	initialized   bool
 	indexName     string
@@ -25,6 +33,10 @@ type MongoDBConfig struct {
 	IndexName     string
 	NumCandidates int
 	Dimensions    int
+
+	// Compression enables scalar/binary quantization of the Atlas Vector
+	// Search index. See config.VectorCompression.
+	Compression config.VectorCompression
 }
 
 // DefaultMongoDBConfig returns sensible defaults.
@@ -36,11 +48,12 @@ func DefaultMongoDBConfig() MongoDBConfig {
 		IndexName:     "vector_index",
 		NumCandidates: 100,
 		Dimensions:    1536,
+		Compression:   config.DefaultVectorCompression(),
 	}
 }
 
-// NewMongoDBVectorStore creates a new MongoDB vector store.
-// Note: Requires MongoDB driver. This is a placeholder implementation.
+// NewMongoDBVectorStore creates a new MongoDB vector store. Call
+// Initialize before using it; construction alone doesn't dial out.
 func NewMongoDBVectorStore(config MongoDBConfig) (*MongoDBVectorStore, error) {
 	return &MongoDBVectorStore{
 		config:        config,
@@ -49,85 +62,280 @@ func NewMongoDBVectorStore(config MongoDBConfig) (*MongoDBVectorStore, error) {
 	}, nil
 }
 
-// MongoVectorRecord represents a vector record for MongoDB.
-type MongoVectorRecord struct {
-	ID         string                 `bson:"_id"`
-	DocumentID string                 `bson:"document_id"`
-	ChunkIndex int                    `bson:"chunk_index"`
-	Content    string                 `bson:"content"`
-	Embedding  []float32              `bson:"embedding"`
-	Metadata   map[string]interface{} `bson:"metadata"`
-}
-
-// MongoSearchResult represents a search result from MongoDB.
-type MongoSearchResult struct {
-	ID       string
-	Content  string
-	Score    float32
-	Metadata map[string]interface{}
-}
-
-// Initialize connects to MongoDB. Placeholder implementation.
+// Initialize connects to MongoDB and pings it to fail fast on a bad URI,
+// rather than only discovering the problem on the first Upsert/Search.
+//
+// The vector field's Atlas Search index definition (created separately,
+// via the Atlas UI/API or mongosh - the driver has no "create vector
+// index" call) would carry the quantization mode Atlas Vector Search
+// supports natively:
+//
+//	{"type": "vector", "path": "embedding", "numDimensions": s.config.Dimensions,
+//	 "similarity": "cosine", "quantization": quantizationMode(s.config.Compression)}
 func (s *MongoDBVectorStore) Initialize(ctx context.Context) error {
-	// In production, use:
-	// client, err := mongo.Connect(ctx, options.Client().ApplyURI(s.config.URI))
-	// s.collection = client.Database(s.config.Database).Collection(s.config.Collection)
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(s.config.URI))
+	if err != nil {
+		return fmt.Errorf("mongodb: connect: %w", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		return fmt.Errorf("mongodb: ping: %w", err)
+	}
+
+	s.client = client
+	s.collection = client.Database(s.config.Database).Collection(s.config.Collection)
 	s.initialized = true
 	return nil
 }
 
+// quantizationMode maps our VectorCompressionKind onto Atlas Vector
+// Search's index-level "quantization" field.
+func quantizationMode(c config.VectorCompression) string {
+	switch c.Kind {
+	case config.VectorCompressionScalarQuantization:
+		return "scalar"
+	case config.VectorCompressionBinaryQuantization:
+		return "binary"
+	default:
+		return "none"
+	}
+}
+
 // Upsert inserts or updates a vector record.
-func (s *MongoDBVectorStore) Upsert(ctx context.Context, record MongoVectorRecord) error {
+func (s *MongoDBVectorStore) Upsert(ctx context.Context, record VectorRecord) error {
 	if !s.initialized {
 		return fmt.Errorf("not initialized. Call Initialize() first")
 	}
 
-	// Placeholder - in production:
-	// filter := bson.M{"_id": record.ID}
-	// update := bson.M{"$set": record}
-	// opts := options.Update().SetUpsert(true)
-	// _, err := s.collection.UpdateOne(ctx, filter, update, opts)
+	filter := bson.M{"_id": record.ID}
+	update := bson.M{"$set": record}
+	opts := options.Update().SetUpsert(true)
 
+	if _, err := s.collection.UpdateOne(ctx, filter, update, opts); err != nil {
+		return fmt.Errorf("mongodb: upsert: %w", err)
+	}
 	return nil
 }
 
-// UpsertBatch inserts multiple records.
-func (s *MongoDBVectorStore) UpsertBatch(ctx context.Context, records []MongoVectorRecord) error {
+// UpsertBatch inserts or updates multiple records in a single bulk write,
+// mirroring PostgresVectorStore.UpsertBatch's one-round-trip-per-batch
+// behavior. Since BulkWrite commits the whole batch atomically rather than
+// row by row, a WithProgress Progress only learns about individual records
+// (via OnFile) after the bulk write as a whole succeeds.
+func (s *MongoDBVectorStore) UpsertBatch(ctx context.Context, records []VectorRecord, opts ...storage.TransferOption) error {
+	to := storage.CollectTransferOptions(opts...)
+	progress := to.Progress
+	if progress != nil {
+		progress.OnStart(int64(len(records)))
+	}
+
 	if !s.initialized {
-		return fmt.Errorf("not initialized. Call Initialize() first")
+		err := fmt.Errorf("not initialized. Call Initialize() first")
+		if progress != nil {
+			progress.OnFinish(err)
+		}
+		return err
+	}
+	if len(records) == 0 {
+		if progress != nil {
+			progress.OnFinish(nil)
+		}
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, len(records))
+	for i, record := range records {
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": record.ID}).
+			SetUpdate(bson.M{"$set": record}).
+			SetUpsert(true)
 	}
 
-	for _, record := range records {
-		if err := s.Upsert(ctx, record); err != nil {
-			return err
+	if _, err := s.collection.BulkWrite(ctx, models); err != nil {
+		err = fmt.Errorf("mongodb: bulk upsert: %w", err)
+		if progress != nil {
+			progress.OnFinish(err)
 		}
+		return err
+	}
+
+	if progress != nil {
+		for i, record := range records {
+			progress.OnFile(record.ID, i+1, len(records))
+		}
+		progress.OnFinish(nil)
 	}
 	return nil
 }
 
-// Search performs vector similarity search using MongoDB Atlas Vector Search.
-func (s *MongoDBVectorStore) Search(ctx context.Context, queryVector []float32, topK int) ([]MongoSearchResult, error) {
+// Search performs vector similarity search using MongoDB Atlas Vector
+// Search's $vectorSearch aggregation stage.
+func (s *MongoDBVectorStore) Search(ctx context.Context, queryVector []float32, topK int) ([]SearchResult, error) {
+	if !s.initialized {
+		return nil, fmt.Errorf("not initialized. Call Initialize() first")
+	}
+	return s.vectorSearch(ctx, queryVector, topK, nil)
+}
+
+// vectorSearch runs the $vectorSearch pipeline described in Initialize's
+// doc comment, optionally restricted to a single document via filter.
+// When compression is enabled with Rescore set, Atlas rescores
+// automatically using the preserved full-precision vectors when the
+// index's rescore option is set, so numCandidates only needs the
+// oversampling bump.
+func (s *MongoDBVectorStore) vectorSearch(ctx context.Context, queryVector []float32, topK int, filter bson.M) ([]SearchResult, error) {
+	numCandidates := s.numCandidates
+	if s.config.Compression.Kind != config.VectorCompressionNone && s.config.Compression.Rescore {
+		numCandidates = int(float64(topK) * s.config.Compression.OversamplingFactor)
+	}
+	if numCandidates < topK {
+		numCandidates = topK
+	}
+
+	vectorSearchStage := bson.M{
+		"index":         s.indexName,
+		"path":          "embedding",
+		"queryVector":   queryVector,
+		"numCandidates": numCandidates,
+		"limit":         topK,
+	}
+	if len(filter) > 0 {
+		vectorSearchStage["filter"] = filter
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$vectorSearch", Value: vectorSearchStage}},
+		{{Key: "$project", Value: bson.M{
+			"content":  1,
+			"metadata": 1,
+			"score":    bson.M{"$meta": "vectorSearchScore"},
+		}}},
+	}
+
+	cursor, err := s.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("mongodb: vector search: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	return decodeSearchResults(ctx, cursor)
+}
+
+// decodeSearchResults drains cursor into SearchResult, matching the
+// $project shape every aggregation pipeline in this file emits.
+func decodeSearchResults(ctx context.Context, cursor *mongo.Cursor) ([]SearchResult, error) {
+	var docs []struct {
+		ID       string                 `bson:"_id"`
+		Content  string                 `bson:"content"`
+		Metadata map[string]interface{} `bson:"metadata"`
+		Score    float32                `bson:"score"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("mongodb: decode results: %w", err)
+	}
+
+	results := make([]SearchResult, len(docs))
+	for i, d := range docs {
+		results[i] = SearchResult{ID: d.ID, Content: d.Content, Score: d.Score, Metadata: d.Metadata}
+	}
+	return results, nil
+}
+
+// SearchText embeds queryText with v and searches with the resulting
+// vector. Atlas Vector Search has no server-side vectorizer of its own
+// (unlike Azure AI Search's "kind": "text" vector queries), so docflow
+// always has to compute the embedding itself here.
+func (s *MongoDBVectorStore) SearchText(ctx context.Context, v vectorizer.Vectorizer, queryText string, topK int) ([]SearchResult, error) {
+	queryVector, err := v.EmbedQuery(ctx, queryText)
+	if err != nil {
+		return nil, fmt.Errorf("vectorizer: embed query text: %w", err)
+	}
+	return s.Search(ctx, queryVector, topK)
+}
+
+// keywordSearch ranks documents by Atlas Search's BM25-based relevance
+// score, via a $search text query against a separate Atlas Search text
+// index (s.indexName + "_text" - a vector index and a search index are
+// always distinct Atlas index definitions, even over the same
+// collection).
+func (s *MongoDBVectorStore) keywordSearch(ctx context.Context, queryText string, topN int) ([]SearchResult, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$search", Value: bson.M{
+			"index": s.indexName + "_text",
+			"text":  bson.M{"query": queryText, "path": "content"},
+		}}},
+		{{Key: "$limit", Value: topN}},
+		{{Key: "$project", Value: bson.M{
+			"content":  1,
+			"metadata": 1,
+			"score":    bson.M{"$meta": "searchScore"},
+		}}},
+	}
+
+	cursor, err := s.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("mongodb: keyword search: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	return decodeSearchResults(ctx, cursor)
+}
+
+// SearchHybridRRF runs Atlas Search's keyword ranker ($search) and Atlas
+// Vector Search's dense ranker ($vectorSearch) separately, each recalling
+// retrievalCfg.PerRankerTopN candidates, then fuses the two result lists
+// with reciprocal rank fusion via fuseHybridRRF - the same approach
+// PostgresVectorStore.SearchHybridRRF uses.
+func (s *MongoDBVectorStore) SearchHybridRRF(ctx context.Context, queryText string, queryVector []float32, topK int, retrievalCfg config.RetrievalConfig) ([]SearchResult, error) {
 	if !s.initialized {
 		return nil, fmt.Errorf("not initialized. Call Initialize() first")
 	}
 
-	// In production, this would be an aggregation pipeline:
-	// pipeline := mongo.Pipeline{
-	//     {{Key: "$vectorSearch", Value: bson.M{
-	//         "index": s.indexName,
-	//         "path": "embedding",
-	//         "queryVector": queryVector,
-	//         "numCandidates": s.numCandidates,
-	//         "limit": topK,
-	//     }}},
-	//     {{Key: "$project", Value: bson.M{
-	//         "content": 1,
-	//         "metadata": 1,
-	//         "score": bson.M{"$meta": "vectorSearchScore"},
-	//     }}},
-	// }
+	perRankerTopN := retrievalCfg.PerRankerTopN
+	if perRankerTopN <= 0 {
+		perRankerTopN = 100
+	}
+
+	keywordResults, err := s.keywordSearch(ctx, queryText, perRankerTopN)
+	if err != nil {
+		return nil, fmt.Errorf("mongodb: keyword ranker: %w", err)
+	}
+
+	vectorResults, err := s.vectorSearch(ctx, queryVector, perRankerTopN, nil)
+	if err != nil {
+		return nil, fmt.Errorf("mongodb: dense ranker: %w", err)
+	}
+
+	return fuseHybridRRF(keywordResults, vectorResults, topK, retrievalCfg.RRFK), nil
+}
+
+// fuseHybridRRF combines a keyword ranker's and a dense ranker's result
+// lists with reciprocal rank fusion. It's factored out of
+// SearchHybridRRF as a pure function of two already-ranked result lists
+// so the fusion behavior (tie-breaking via the dense ranker's raw score,
+// handling a ranker that returned nothing) can be unit-tested without a
+// live MongoDB connection.
+func fuseHybridRRF(keywordResults, vectorResults []SearchResult, topK, rrfK int) []SearchResult {
+	byID := make(map[string]SearchResult, len(keywordResults)+len(vectorResults))
+	keywordIDs, keywordScores := rankedIDs(keywordResults, byID)
+	vectorIDs, vectorScores := rankedIDs(vectorResults, byID)
 
-	return []MongoSearchResult{}, nil
+	const denseRankerIndex = 1
+	fused := retrieval.RRF([][]retrieval.RankedResult{
+		retrieval.Rank(keywordIDs, keywordScores),
+		retrieval.Rank(vectorIDs, vectorScores),
+	}, rrfK, denseRankerIndex)
+
+	if topK > 0 && len(fused) > topK {
+		fused = fused[:topK]
+	}
+
+	results := make([]SearchResult, len(fused))
+	for i, f := range fused {
+		r := byID[f.ID]
+		r.Score = float32(f.Score)
+		results[i] = r
+	}
+	return results
 }
 
 // Delete removes all chunks for a document.
@@ -136,23 +344,17 @@ func (s *MongoDBVectorStore) Delete(ctx context.Context, documentID string) erro
 		return fmt.Errorf("not initialized. Call Initialize() first")
 	}
 
-	// Placeholder - in production:
-	// filter := bson.M{"document_id": documentID}
-	// _, err := s.collection.DeleteMany(ctx, filter)
-
+	filter := bson.M{"document_id": documentID}
+	if _, err := s.collection.DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("mongodb: delete: %w", err)
+	}
 	return nil
 }
 
-// Close closes the MongoDB connection.
+// Close disconnects the MongoDB client.
 func (s *MongoDBVectorStore) Close(ctx context.Context) error {
-	if s.client != nil {
-		// In production: s.client.(*mongo.Client).Disconnect(ctx)
+	if s.client == nil {
+		return nil
 	}
-	return nil
-}
-
-// Helper to convert record to JSON for debugging
-func (r MongoVectorRecord) ToJSON() (string, error) {
-	data, err := json.Marshal(r)
-	return string(data), err
+	return s.client.Disconnect(ctx)
 }
@@ -0,0 +1,89 @@
+package vector
+
+import "testing"
+
+func TestFuseHybridRRFTieBreaksOnDenseScore(t *testing.T) {
+	// "a" ranks 1st in keyword and 2nd in vector; "b" ranks 2nd in
+	// keyword and 1st in vector - their RRF scores sum to the same
+	// total, so only the dense (vector) ranker's raw score can break
+	// the tie, and "b" has the higher one.
+	keyword := []SearchResult{
+		{ID: "a", Content: "doc a", Score: 1.0},
+		{ID: "b", Content: "doc b", Score: 0.9},
+	}
+	vector := []SearchResult{
+		{ID: "b", Content: "doc b", Score: 0.9},
+		{ID: "a", Content: "doc a", Score: 0.5},
+	}
+
+	fused := fuseHybridRRF(keyword, vector, 10, 60)
+
+	if len(fused) != 2 {
+		t.Fatalf("expected 2 fused results, got %d", len(fused))
+	}
+	if fused[0].ID != "b" {
+		t.Fatalf("expected %q to win the tie via its higher dense score, got %q first", "b", fused[0].ID)
+	}
+}
+
+func TestFuseHybridRRFMissingKeywordList(t *testing.T) {
+	// The keyword ranker found nothing (e.g. no Atlas Search text index
+	// configured yet); fusion must still return the vector ranker's
+	// results instead of an empty or nil slice.
+	vector := []SearchResult{
+		{ID: "a", Score: 0.9},
+		{ID: "b", Score: 0.5},
+	}
+
+	fused := fuseHybridRRF(nil, vector, 10, 60)
+
+	if len(fused) != 2 {
+		t.Fatalf("expected 2 fused results from the vector ranker alone, got %d", len(fused))
+	}
+	if fused[0].ID != "a" {
+		t.Fatalf("expected vector ranker's top result %q first, got %q", "a", fused[0].ID)
+	}
+}
+
+func TestFuseHybridRRFMissingVectorList(t *testing.T) {
+	keyword := []SearchResult{
+		{ID: "x", Score: 2.0},
+	}
+
+	fused := fuseHybridRRF(keyword, nil, 10, 60)
+
+	if len(fused) != 1 || fused[0].ID != "x" {
+		t.Fatalf("expected the lone keyword result %q, got %+v", "x", fused)
+	}
+}
+
+func TestFuseHybridRRFRespectsTopK(t *testing.T) {
+	vector := []SearchResult{
+		{ID: "a", Score: 0.9},
+		{ID: "b", Score: 0.8},
+		{ID: "c", Score: 0.7},
+	}
+
+	fused := fuseHybridRRF(nil, vector, 2, 60)
+
+	if len(fused) != 2 {
+		t.Fatalf("expected fuseHybridRRF to cap results at topK=2, got %d", len(fused))
+	}
+}
+
+func TestFuseHybridRRFUnionsDisjointIDs(t *testing.T) {
+	// A document found by only one ranker must still appear in the fused
+	// result, scored purely from the list it did appear in.
+	keyword := []SearchResult{{ID: "only-keyword", Score: 1.0}}
+	vector := []SearchResult{{ID: "only-vector", Score: 1.0}}
+
+	fused := fuseHybridRRF(keyword, vector, 10, 60)
+
+	ids := map[string]bool{}
+	for _, f := range fused {
+		ids[f.ID] = true
+	}
+	if !ids["only-keyword"] || !ids["only-vector"] {
+		t.Fatalf("expected both disjoint IDs present, got %+v", fused)
+	}
+}
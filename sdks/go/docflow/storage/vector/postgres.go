@@ -1,12 +1,20 @@
 package vector
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
 
 	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+	"github.com/xgaslan/docflow/sdks/go/docflow/retrieval"
+	"github.com/xgaslan/docflow/sdks/go/docflow/storage"
+	"github.com/xgaslan/docflow/sdks/go/docflow/vectorizer"
 )
 
 // PostgresVectorStore implements vector storage using PostgreSQL with pgvector.
@@ -27,20 +35,31 @@ type PostgresConfig struct {
 	Schema     string
 	TableName  string
 	Dimensions int
+
+	// Compression enables scalar/binary quantization of the stored
+	// embedding so large tables fit in RAM. See config.VectorCompression.
+	Compression config.VectorCompression
+
+	// TextSearchConfig names the Postgres text search configuration (see
+	// pg_catalog.pg_ts_config) used for the generated content_tsv column
+	// and for ts_rank_cd/plainto_tsquery in HybridSearch/keywordSearch.
+	TextSearchConfig string
 }
 
 // DefaultPostgresConfig returns sensible defaults.
 func DefaultPostgresConfig() PostgresConfig {
 	return PostgresConfig{
-		Host:       "localhost",
-		Port:       5432,
-		User:       "postgres",
-		Password:   "",
-		Database:   "docflow",
-		SSLMode:    "disable",
-		Schema:     "public",
-		TableName:  "chunks",
-		Dimensions: 1536,
+		Host:             "localhost",
+		Port:             5432,
+		User:             "postgres",
+		Password:         "",
+		Database:         "docflow",
+		SSLMode:          "disable",
+		Schema:           "public",
+		TableName:        "chunks",
+		Dimensions:       1536,
+		Compression:      config.DefaultVectorCompression(),
+		TextSearchConfig: "english",
 	}
 }
 
@@ -79,7 +98,7 @@ func NewPostgresVectorStoreFromDSN(dsn, tableName string, dimensions int) (*Post
 	store := &PostgresVectorStore{
 		db:        db,
 		tableName: tableName,
-		config:    PostgresConfig{Dimensions: dimensions},
+		config:    PostgresConfig{Dimensions: dimensions, Compression: config.DefaultVectorCompression(), TextSearchConfig: "english"},
 	}
 
 	if err := store.ensureTable(); err != nil {
@@ -89,110 +108,188 @@ func NewPostgresVectorStoreFromDSN(dsn, tableName string, dimensions int) (*Post
 	return store, nil
 }
 
-// VectorRecord represents a single vector record.
-type VectorRecord struct {
-	ID         string
-	DocumentID string
-	ChunkIndex int
-	Content    string
-	Embedding  []float32
-	Metadata   map[string]interface{}
-}
-
-// SearchResult represents a search result.
-type SearchResult struct {
-	ID       string
-	Content  string
-	Score    float32
-	Metadata map[string]interface{}
-}
-
 // Upsert inserts or updates a vector record.
-func (s *PostgresVectorStore) Upsert(record VectorRecord) error {
+func (s *PostgresVectorStore) Upsert(ctx context.Context, record VectorRecord) error {
 	metadataJSON, _ := json.Marshal(record.Metadata)
-	embeddingStr := floatsToVector(record.Embedding)
+	cols, placeholders, args := s.upsertColumns(record, 5)
 
 	query := fmt.Sprintf(`
-		INSERT INTO %s (id, document_id, chunk_index, content, embedding, metadata, created_at)
-		VALUES ($1, $2, $3, $4, $5::vector, $6::jsonb, NOW())
+		INSERT INTO %s (id, document_id, chunk_index, content, metadata, created_at, %s)
+		VALUES ($1, $2, $3, $4, $5::jsonb, NOW(), %s)
 		ON CONFLICT (id) DO UPDATE SET
 			content = EXCLUDED.content,
-			embedding = EXCLUDED.embedding,
 			metadata = EXCLUDED.metadata,
-			updated_at = NOW()
-	`, s.tableName)
+			updated_at = NOW(),
+			%s
+	`, s.tableName, strings.Join(cols, ", "), strings.Join(placeholders, ", "), updateAssignments(cols))
+
+	execArgs := append([]interface{}{record.ID, record.DocumentID, record.ChunkIndex, record.Content, string(metadataJSON)}, args...)
 
-	_, err := s.db.Exec(query, record.ID, record.DocumentID, record.ChunkIndex, record.Content, embeddingStr, string(metadataJSON))
+	_, err := s.db.ExecContext(ctx, query, execArgs...)
 	return err
 }
 
 // UpsertBatch inserts multiple records in a transaction.
-func (s *PostgresVectorStore) UpsertBatch(records []VectorRecord) error {
-	tx, err := s.db.Begin()
-	if err != nil {
-		return err
+func (s *PostgresVectorStore) UpsertBatch(ctx context.Context, records []VectorRecord, opts ...storage.TransferOption) error {
+	to := storage.CollectTransferOptions(opts...)
+	progress := to.Progress
+	if progress != nil {
+		progress.OnStart(int64(len(records)))
 	}
-	defer tx.Rollback()
 
-	query := fmt.Sprintf(`
-		INSERT INTO %s (id, document_id, chunk_index, content, embedding, metadata, created_at)
-		VALUES ($1, $2, $3, $4, $5::vector, $6::jsonb, NOW())
-		ON CONFLICT (id) DO UPDATE SET
-			content = EXCLUDED.content,
-			embedding = EXCLUDED.embedding,
-			metadata = EXCLUDED.metadata,
-			updated_at = NOW()
-	`, s.tableName)
-
-	stmt, err := tx.Prepare(query)
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
+		if progress != nil {
+			progress.OnFinish(err)
+		}
 		return err
 	}
-	defer stmt.Close()
+	defer tx.Rollback()
 
-	for _, record := range records {
+	for i, record := range records {
 		metadataJSON, _ := json.Marshal(record.Metadata)
-		embeddingStr := floatsToVector(record.Embedding)
-
-		_, err := stmt.Exec(record.ID, record.DocumentID, record.ChunkIndex, record.Content, embeddingStr, string(metadataJSON))
-		if err != nil {
+		cols, placeholders, args := s.upsertColumns(record, 5)
+
+		query := fmt.Sprintf(`
+			INSERT INTO %s (id, document_id, chunk_index, content, metadata, created_at, %s)
+			VALUES ($1, $2, $3, $4, $5::jsonb, NOW(), %s)
+			ON CONFLICT (id) DO UPDATE SET
+				content = EXCLUDED.content,
+				metadata = EXCLUDED.metadata,
+				updated_at = NOW(),
+				%s
+		`, s.tableName, strings.Join(cols, ", "), strings.Join(placeholders, ", "), updateAssignments(cols))
+
+		execArgs := append([]interface{}{record.ID, record.DocumentID, record.ChunkIndex, record.Content, string(metadataJSON)}, args...)
+
+		if _, err := tx.ExecContext(ctx, query, execArgs...); err != nil {
+			if progress != nil {
+				progress.OnFinish(err)
+			}
 			return err
 		}
+		if progress != nil {
+			progress.OnFile(record.ID, i+1, len(records))
+		}
+	}
+
+	err = tx.Commit()
+	if progress != nil {
+		progress.OnFinish(err)
+	}
+	return err
+}
+
+// upsertColumns builds the embedding-related columns/placeholders/args for an
+// insert, honoring the configured VectorCompression. argOffset is the number
+// of positional args already used by the caller's query.
+func (s *PostgresVectorStore) upsertColumns(record VectorRecord, argOffset int) (cols, placeholders []string, args []interface{}) {
+	next := argOffset + 1
+
+	storeRaw := s.config.Compression.Kind == config.VectorCompressionNone ||
+		s.config.Compression.RescoreStorageMethod == config.RescoreStorageMethodPreserveOriginals
+	if storeRaw {
+		cols = append(cols, "embedding")
+		placeholders = append(placeholders, fmt.Sprintf("$%d::vector", next))
+		args = append(args, floatsToVector(record.Embedding))
+		next++
 	}
 
-	return tx.Commit()
+	switch s.config.Compression.Kind {
+	case config.VectorCompressionScalarQuantization:
+		cols = append(cols, "embedding_scalar")
+		placeholders = append(placeholders, fmt.Sprintf("$%d::vector", next))
+		args = append(args, floatsToVector(scalarQuantize(record.Embedding)))
+		next++
+	case config.VectorCompressionBinaryQuantization:
+		cols = append(cols, "embedding_bits")
+		placeholders = append(placeholders, fmt.Sprintf("$%d::bit(%d)", next, len(record.Embedding)))
+		args = append(args, binaryQuantize(record.Embedding))
+		next++
+	}
+
+	return cols, placeholders, args
+}
+
+// updateAssignments renders "col = EXCLUDED.col" for each embedding column
+// in an ON CONFLICT DO UPDATE clause.
+func updateAssignments(cols []string) string {
+	assignments := make([]string, len(cols))
+	for i, c := range cols {
+		assignments[i] = fmt.Sprintf("%s = EXCLUDED.%s", c, c)
+	}
+	return strings.Join(assignments, ", ")
 }
 
 // Search performs a similarity search.
-func (s *PostgresVectorStore) Search(queryVector []float32, topK int) ([]SearchResult, error) {
-	return s.SearchWithFilter(queryVector, topK, nil)
+func (s *PostgresVectorStore) Search(ctx context.Context, queryVector []float32, topK int) ([]SearchResult, error) {
+	return s.SearchWithFilter(ctx, queryVector, topK, nil)
 }
 
-// SearchWithFilter performs a similarity search with metadata filter.
-func (s *PostgresVectorStore) SearchWithFilter(queryVector []float32, topK int, filter map[string]interface{}) ([]SearchResult, error) {
-	vectorStr := floatsToVector(queryVector)
+// SearchText embeds queryText with v and searches with the resulting
+// vector, so callers that only have query text (no vectorizer bound to
+// the database itself, unlike Azure AI Search) can still send a
+// VectorizableTextQuery-style request.
+func (s *PostgresVectorStore) SearchText(ctx context.Context, v vectorizer.Vectorizer, queryText string, topK int) ([]SearchResult, error) {
+	queryVector, err := v.EmbedQuery(ctx, queryText)
+	if err != nil {
+		return nil, fmt.Errorf("vector: embed query text: %w", err)
+	}
+	return s.SearchWithFilter(ctx, queryVector, topK, nil)
+}
+
+// SearchWithFilter performs a similarity search with a metadata filter,
+// expressed as the legacy map[string]any shape - sugar for a conjunction
+// of Eq predicates. Use SearchWithFilterExpr for the full Filter DSL
+// (Ne/In/Gt/Gte/Lt/Lte/Like/Exists, And/Or/Not).
+func (s *PostgresVectorStore) SearchWithFilter(ctx context.Context, queryVector []float32, topK int, filter map[string]interface{}) ([]SearchResult, error) {
+	return s.searchWithFilterExpr(ctx, queryVector, topK, filterFromMap(filter))
+}
+
+// SearchWithFilterExpr performs a similarity search with a typed Filter
+// expression. When VectorCompression is enabled, it orders by the
+// compressed column and, if Rescore is set, fetches oversampled
+// candidates and reranks them against the full-precision embedding (only
+// possible when originals were preserved).
+func (s *PostgresVectorStore) SearchWithFilterExpr(ctx context.Context, queryVector []float32, topK int, filter Filter) ([]SearchResult, error) {
+	return s.searchWithFilterExpr(ctx, queryVector, topK, filter)
+}
+
+func (s *PostgresVectorStore) searchWithFilterExpr(ctx context.Context, queryVector []float32, topK int, filter Filter) ([]SearchResult, error) {
+	compression := s.config.Compression
+	rerank := compression.Kind != config.VectorCompressionNone &&
+		compression.Rescore &&
+		compression.RescoreStorageMethod == config.RescoreStorageMethodPreserveOriginals
+
+	candidateLimit := topK
+	if rerank {
+		candidateLimit = int(math.Ceil(float64(topK) * compression.OversamplingFactor))
+		if candidateLimit < topK {
+			candidateLimit = topK
+		}
+	}
+
+	orderExpr, orderArg, selectExtra := s.searchOrdering(queryVector, rerank)
 
 	query := fmt.Sprintf(`
-		SELECT id, content, metadata, 1 - (embedding <=> $1::vector) as score
+		SELECT id, content, metadata, %s
 		FROM %s
-	`, s.tableName)
+	`, selectExtra, s.tableName)
 
-	args := []interface{}{vectorStr}
+	args := []interface{}{orderArg}
 
-	if len(filter) > 0 {
-		conditions := []string{}
-		argIndex := 2
-		for key, value := range filter {
-			conditions = append(conditions, fmt.Sprintf("metadata->>'%s' = $%d", key, argIndex))
-			args = append(args, value)
-			argIndex++
+	if filter != nil {
+		clause, filterArgs, err := compileFilter(filter, len(args))
+		if err != nil {
+			return nil, fmt.Errorf("vector: compile filter: %w", err)
 		}
-		query += " WHERE " + strings.Join(conditions, " AND ")
+		query += " WHERE " + clause
+		args = append(args, filterArgs...)
 	}
 
-	query += fmt.Sprintf(" ORDER BY embedding <=> $1::vector LIMIT %d", topK)
+	query += fmt.Sprintf(" ORDER BY %s LIMIT %d", orderExpr, candidateLimit)
 
-	rows, err := s.db.Query(query, args...)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -202,28 +299,209 @@ func (s *PostgresVectorStore) SearchWithFilter(queryVector []float32, topK int,
 	for rows.Next() {
 		var r SearchResult
 		var metadataJSON string
+		var rawEmbedding sql.NullString
 
-		err := rows.Scan(&r.ID, &r.Content, &metadataJSON, &r.Score)
-		if err != nil {
+		scanArgs := []interface{}{&r.ID, &r.Content, &metadataJSON, &r.Score}
+		if rerank {
+			scanArgs = append(scanArgs, &rawEmbedding)
+		}
+
+		if err := rows.Scan(scanArgs...); err != nil {
 			continue
 		}
 
+		json.Unmarshal([]byte(metadataJSON), &r.Metadata)
+		if rerank && rawEmbedding.Valid {
+			r.Score = cosineSimilarity(queryVector, vectorToFloats(rawEmbedding.String))
+		}
+		results = append(results, r)
+	}
+
+	if rerank {
+		sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+		if len(results) > topK {
+			results = results[:topK]
+		}
+	}
+
+	return results, nil
+}
+
+// SearchHybridRRF runs the keyword and dense-vector rankers separately,
+// each recalling retrievalCfg.PerRankerTopN candidates, and fuses them in
+// Go with reciprocal rank fusion (retrievalCfg.RRFK, default 60). The
+// dense ranker's raw cosine score is used to break ties.
+func (s *PostgresVectorStore) SearchHybridRRF(ctx context.Context, queryText string, queryVector []float32, topK int, retrievalCfg config.RetrievalConfig) ([]SearchResult, error) {
+	perRankerTopN := retrievalCfg.PerRankerTopN
+	if perRankerTopN <= 0 {
+		perRankerTopN = 100
+	}
+
+	keywordResults, err := s.keywordSearch(ctx, queryText, perRankerTopN)
+	if err != nil {
+		return nil, fmt.Errorf("vector: keyword ranker: %w", err)
+	}
+
+	vectorResults, err := s.SearchWithFilter(ctx, queryVector, perRankerTopN, nil)
+	if err != nil {
+		return nil, fmt.Errorf("vector: dense ranker: %w", err)
+	}
+
+	byID := make(map[string]SearchResult, len(keywordResults)+len(vectorResults))
+	keywordIDs, keywordScores := rankedIDs(keywordResults, byID)
+	vectorIDs, vectorScores := rankedIDs(vectorResults, byID)
+
+	const denseRankerIndex = 1
+	fused := retrieval.RRF([][]retrieval.RankedResult{
+		retrieval.Rank(keywordIDs, keywordScores),
+		retrieval.Rank(vectorIDs, vectorScores),
+	}, retrievalCfg.RRFK, denseRankerIndex)
+
+	if len(fused) > topK {
+		fused = fused[:topK]
+	}
+
+	results := make([]SearchResult, len(fused))
+	for i, f := range fused {
+		r := byID[f.ID]
+		r.Score = float32(f.Score)
+		results[i] = r
+	}
+	return results, nil
+}
+
+// HybridSearch combines dense and lexical relevance into a single score,
+// alpha*cosine + (1-alpha)*ts_rank_cd, computed in one SQL query and used
+// directly for ORDER BY/LIMIT. This is a cheaper alternative to
+// SearchHybridRRF when callers want one tunable blend weight instead of
+// two independently-ranked passes.
+func (s *PostgresVectorStore) HybridSearch(ctx context.Context, query string, queryVector []float32, topK int, alpha float32, filter map[string]interface{}) ([]SearchResult, error) {
+	sqlQuery := fmt.Sprintf(`
+		SELECT id, content, metadata,
+			$3 * (1 - (embedding <=> $1::vector)) + (1 - $3) * ts_rank_cd(content_tsv, plainto_tsquery('%s', $2)) AS score
+		FROM %s
+		WHERE content_tsv @@ plainto_tsquery('%s', $2)
+	`, s.textSearchConfig(), s.tableName, s.textSearchConfig())
+
+	args := []interface{}{floatsToVector(queryVector), query, alpha}
+
+	if f := filterFromMap(filter); f != nil {
+		clause, filterArgs, err := compileFilter(f, len(args))
+		if err != nil {
+			return nil, fmt.Errorf("vector: compile filter: %w", err)
+		}
+		sqlQuery += " AND " + clause
+		args = append(args, filterArgs...)
+	}
+
+	sqlQuery += fmt.Sprintf(" ORDER BY score DESC LIMIT %d", topK)
+
+	rows, err := s.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var r SearchResult
+		var metadataJSON string
+		if err := rows.Scan(&r.ID, &r.Content, &metadataJSON, &r.Score); err != nil {
+			continue
+		}
 		json.Unmarshal([]byte(metadataJSON), &r.Metadata)
 		results = append(results, r)
 	}
+	return results, nil
+}
+
+// textSearchConfig returns the configured Postgres text search
+// configuration, defaulting to "english" when unset (e.g. stores built
+// via NewPostgresVectorStoreFromDSN before TextSearchConfig existed).
+func (s *PostgresVectorStore) textSearchConfig() string {
+	if s.config.TextSearchConfig == "" {
+		return "english"
+	}
+	return s.config.TextSearchConfig
+}
+
+// keywordSearch ranks rows by Postgres full-text search relevance
+// (ts_rank against a plain websearch-style query over content).
+func (s *PostgresVectorStore) keywordSearch(ctx context.Context, queryText string, topN int) ([]SearchResult, error) {
+	query := fmt.Sprintf(`
+		SELECT id, content, metadata, ts_rank(content_tsv, plainto_tsquery('%s', $1)) AS score
+		FROM %s
+		WHERE content_tsv @@ plainto_tsquery('%s', $1)
+		ORDER BY score DESC
+		LIMIT %d
+	`, s.textSearchConfig(), s.tableName, s.textSearchConfig(), topN)
 
+	rows, err := s.db.QueryContext(ctx, query, queryText)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var r SearchResult
+		var metadataJSON string
+		if err := rows.Scan(&r.ID, &r.Content, &metadataJSON, &r.Score); err != nil {
+			continue
+		}
+		json.Unmarshal([]byte(metadataJSON), &r.Metadata)
+		results = append(results, r)
+	}
 	return results, nil
 }
 
+// rankedIDs extracts the ID order and raw scores from a ranker's result
+// list, recording each full SearchResult in byID so SearchHybridRRF can
+// rebuild SearchResult values after fusion.
+func rankedIDs(results []SearchResult, byID map[string]SearchResult) ([]string, []float64) {
+	ids := make([]string, len(results))
+	scores := make([]float64, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+		scores[i] = float64(r.Score)
+		if _, exists := byID[r.ID]; !exists {
+			byID[r.ID] = r
+		}
+	}
+	return ids, scores
+}
+
+// searchOrdering returns the ORDER BY expression, its bound query argument,
+// and the SELECT list entry used to produce SearchResult.Score, based on the
+// configured compression kind.
+func (s *PostgresVectorStore) searchOrdering(queryVector []float32, rerank bool) (orderExpr string, orderArg interface{}, selectExtra string) {
+	scoreExpr := "1 - (embedding <=> $1::vector) as score"
+	if rerank {
+		scoreExpr += ", embedding::text"
+	}
+
+	switch s.config.Compression.Kind {
+	case config.VectorCompressionScalarQuantization:
+		return "embedding_scalar <=> $1::vector", floatsToVector(scalarQuantize(queryVector)), strings.Replace(scoreExpr, "embedding <=>", "embedding_scalar <=>", 1)
+	case config.VectorCompressionBinaryQuantization:
+		bitType := fmt.Sprintf("bit(%d)", len(queryVector))
+		return fmt.Sprintf("embedding_bits <~> $1::%s", bitType), binaryQuantize(queryVector),
+			strings.Replace(scoreExpr, "1 - (embedding <=> $1::vector)", fmt.Sprintf("1 - (embedding_bits <~> $1::%s)::float / bit_length(embedding_bits)", bitType), 1)
+	default:
+		return "embedding <=> $1::vector", floatsToVector(queryVector), scoreExpr
+	}
+}
+
 // Delete removes all chunks for a document.
-func (s *PostgresVectorStore) Delete(documentID string) error {
+func (s *PostgresVectorStore) Delete(ctx context.Context, documentID string) error {
 	query := fmt.Sprintf("DELETE FROM %s WHERE document_id = $1", s.tableName)
-	_, err := s.db.Exec(query, documentID)
+	_, err := s.db.ExecContext(ctx, query, documentID)
 	return err
 }
 
-// Close closes the database connection.
-func (s *PostgresVectorStore) Close() error {
+// Close closes the database connection. ctx is accepted to satisfy
+// VectorStore; database/sql has no context-aware Close.
+func (s *PostgresVectorStore) Close(ctx context.Context) error {
 	return s.db.Close()
 }
 
@@ -235,24 +513,44 @@ func (s *PostgresVectorStore) ensureTable() error {
 			chunk_index INTEGER NOT NULL,
 			content TEXT NOT NULL,
 			embedding vector(%d),
+			embedding_scalar vector(%d),
+			embedding_bits bit(%d),
 			metadata JSONB,
+			content_tsv tsvector GENERATED ALWAYS AS (to_tsvector('%s', content)) STORED,
 			created_at TIMESTAMP DEFAULT NOW(),
 			updated_at TIMESTAMP
 		)
-	`, s.tableName, s.config.Dimensions)
+	`, s.tableName, s.config.Dimensions, s.config.Dimensions, s.config.Dimensions, s.textSearchConfig())
 
-	_, err := s.db.Exec(query)
-	if err != nil {
+	if _, err := s.db.Exec(query); err != nil {
 		return err
 	}
 
-	// Create index
-	indexQuery := fmt.Sprintf(`
-		CREATE INDEX IF NOT EXISTS %s_embedding_idx ON %s
-		USING hnsw (embedding vector_cosine_ops)
-	`, s.config.TableName, s.tableName)
+	if _, err := s.db.Exec(fmt.Sprintf(`
+		CREATE INDEX IF NOT EXISTS %s_content_tsv_idx ON %s USING gin (content_tsv)
+	`, s.config.TableName, s.tableName)); err != nil {
+		return err
+	}
 
-	s.db.Exec(indexQuery) // Ignore error if already exists
+	// Create whichever index matches the configured compression kind; the
+	// other embedding columns stay unindexed dead weight until Kind changes.
+	switch s.config.Compression.Kind {
+	case config.VectorCompressionScalarQuantization:
+		s.db.Exec(fmt.Sprintf(`
+			CREATE INDEX IF NOT EXISTS %s_embedding_scalar_idx ON %s
+			USING hnsw (embedding_scalar vector_cosine_ops)
+		`, s.config.TableName, s.tableName))
+	case config.VectorCompressionBinaryQuantization:
+		s.db.Exec(fmt.Sprintf(`
+			CREATE INDEX IF NOT EXISTS %s_embedding_bits_idx ON %s
+			USING hnsw (embedding_bits bit_hamming_ops)
+		`, s.config.TableName, s.tableName))
+	default:
+		s.db.Exec(fmt.Sprintf(`
+			CREATE INDEX IF NOT EXISTS %s_embedding_idx ON %s
+			USING hnsw (embedding vector_cosine_ops)
+		`, s.config.TableName, s.tableName))
+	}
 
 	return nil
 }
@@ -267,3 +565,87 @@ func floatsToVector(floats []float32) string {
 	}
 	return "[" + strings.Join(parts, ",") + "]"
 }
+
+// vectorToFloats parses a pgvector textual representation, e.g. "[1.0,2.0]",
+// as produced by casting a `vector` column to text.
+func vectorToFloats(s string) []float32 {
+	s = strings.Trim(s, "[]")
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	floats := make([]float32, len(parts))
+	for i, p := range parts {
+		f, _ := strconv.ParseFloat(strings.TrimSpace(p), 32)
+		floats[i] = float32(f)
+	}
+	return floats
+}
+
+// scalarQuantizationLevels mirrors int8 resolution (256 discrete levels).
+const scalarQuantizationLevels = 256
+
+// scalarQuantize snaps each dimension to the nearest of
+// scalarQuantizationLevels values spanning that vector's own min/max, the
+// same scheme Azure AI Search and pgvector-based scalar quantization use.
+// The result is still stored as float32 so it can reuse pgvector's `vector`
+// type and HNSW index, but only carries int8-equivalent precision.
+func scalarQuantize(embedding []float32) []float32 {
+	if len(embedding) == 0 {
+		return embedding
+	}
+
+	min, max := embedding[0], embedding[0]
+	for _, v := range embedding {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	if max == min {
+		return append([]float32{}, embedding...)
+	}
+
+	step := (max - min) / (scalarQuantizationLevels - 1)
+	quantized := make([]float32, len(embedding))
+	for i, v := range embedding {
+		level := math.Round(float64((v - min) / step))
+		quantized[i] = min + float32(level)*step
+	}
+	return quantized
+}
+
+// binaryQuantize reduces each dimension to a single sign bit, rendered as a
+// Postgres bit-string literal ("1" for >= 0, "0" otherwise), matching
+// pgvector's binary_quantize() convention.
+func binaryQuantize(embedding []float32) string {
+	bits := make([]byte, len(embedding))
+	for i, v := range embedding {
+		if v >= 0 {
+			bits[i] = '1'
+		} else {
+			bits[i] = '0'
+		}
+	}
+	return string(bits)
+}
+
+// cosineSimilarity reranks oversampled candidates against their
+// full-precision embeddings.
+func cosineSimilarity(a, b []float32) float32 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB)))
+}
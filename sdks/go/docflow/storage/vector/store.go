@@ -0,0 +1,129 @@
+package vector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/config"
+	"github.com/xgaslan/docflow/sdks/go/docflow/storage"
+)
+
+// VectorStore is the common interface every vector storage provider
+// docflow can index into and query implements: PostgresVectorStore and
+// MongoDBVectorStore. Selecting a provider through config (a
+// VectorStoreConfig.Provider setting) lets callers, and the RAG
+// ingestion/search paths, swap providers without touching call sites -
+// the same role search.Backend plays for full-text/hybrid search.
+type VectorStore interface {
+	Upsert(ctx context.Context, record VectorRecord) error
+
+	// UpsertBatch upserts records in one batch. Pass storage.WithProgress(p)
+	// to observe it row by row - each record upserted fires OnFile(id,
+	// done, total) once it commits.
+	UpsertBatch(ctx context.Context, records []VectorRecord, opts ...storage.TransferOption) error
+	Search(ctx context.Context, queryVector []float32, topK int) ([]SearchResult, error)
+	Delete(ctx context.Context, documentID string) error
+	Close(ctx context.Context) error
+}
+
+var (
+	_ VectorStore = (*PostgresVectorStore)(nil)
+	_ VectorStore = (*MongoDBVectorStore)(nil)
+)
+
+// VectorRecord represents a single chunk's embedding and content, in the
+// shape every VectorStore backend persists it as.
+type VectorRecord struct {
+	ID         string                 `bson:"_id"`
+	DocumentID string                 `bson:"document_id"`
+	ChunkIndex int                    `bson:"chunk_index"`
+	Content    string                 `bson:"content"`
+	Embedding  []float32              `bson:"embedding"`
+	Metadata   map[string]interface{} `bson:"metadata"`
+}
+
+// SearchResult represents one ranked result from a VectorStore search.
+type SearchResult struct {
+	ID       string
+	Content  string
+	Score    float32
+	Metadata map[string]interface{}
+}
+
+// NewVectorStore builds the VectorStore cfg.Provider selects, translating
+// the shared VectorStoreConfig into each backend's own config type. An
+// empty Provider defaults to Postgres, matching DefaultVectorStoreConfig.
+func NewVectorStore(ctx context.Context, cfg config.VectorStoreConfig) (VectorStore, error) {
+	switch cfg.Provider {
+	case config.VectorStoreProviderMongoDB:
+		store, err := NewMongoDBVectorStore(mongoDBConfigFrom(cfg))
+		if err != nil {
+			return nil, fmt.Errorf("vector: build mongodb store: %w", err)
+		}
+		if err := store.Initialize(ctx); err != nil {
+			return nil, fmt.Errorf("vector: initialize mongodb store: %w", err)
+		}
+		return store, nil
+	case config.VectorStoreProviderPostgres, "":
+		return NewPostgresVectorStore(postgresConfigFrom(cfg))
+	default:
+		return nil, fmt.Errorf("vector: unknown provider %q", cfg.Provider)
+	}
+}
+
+func mongoDBConfigFrom(cfg config.VectorStoreConfig) MongoDBConfig {
+	mc := DefaultMongoDBConfig()
+	if cfg.ConnectionString != "" {
+		mc.URI = cfg.ConnectionString
+	}
+	if cfg.Database != "" {
+		mc.Database = cfg.Database
+	}
+	if cfg.Collection != "" {
+		mc.Collection = cfg.Collection
+	}
+	if cfg.IndexNameMongo != "" {
+		mc.IndexName = cfg.IndexNameMongo
+	}
+	if cfg.NumCandidates > 0 {
+		mc.NumCandidates = cfg.NumCandidates
+	}
+	if cfg.EmbeddingDimensions > 0 {
+		mc.Dimensions = cfg.EmbeddingDimensions
+	}
+	mc.Compression = cfg.VectorCompression
+	return mc
+}
+
+func postgresConfigFrom(cfg config.VectorStoreConfig) PostgresConfig {
+	pc := DefaultPostgresConfig()
+	if cfg.Host != "" {
+		pc.Host = cfg.Host
+	}
+	if cfg.Port > 0 {
+		pc.Port = cfg.Port
+	}
+	if cfg.User != "" {
+		pc.User = cfg.User
+	}
+	if cfg.Password != "" {
+		pc.Password = cfg.Password
+	}
+	if cfg.Database != "" {
+		pc.Database = cfg.Database
+	}
+	if cfg.SSLMode != "" {
+		pc.SSLMode = cfg.SSLMode
+	}
+	if cfg.Schema != "" {
+		pc.Schema = cfg.Schema
+	}
+	if cfg.Collection != "" {
+		pc.TableName = cfg.Collection
+	}
+	if cfg.EmbeddingDimensions > 0 {
+		pc.Dimensions = cfg.EmbeddingDimensions
+	}
+	pc.Compression = cfg.VectorCompression
+	return pc
+}
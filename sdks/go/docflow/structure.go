@@ -0,0 +1,337 @@
+package docflow
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/xgaslan/docflow/sdks/go/docflow/rag"
+)
+
+// StructuredResult is ExtractStructured's output: the same markdown
+// ExtractToMarkdown would produce, plus tables, images, and a
+// rag.DocumentMetadata describing headings, so a RAG pipeline can chunk on
+// document structure instead of flat text.
+type StructuredResult struct {
+	*ExtractResult
+	Tables   []rag.ExtractedTable
+	Images   []rag.ExtractedImage
+	Document rag.DocumentMetadata
+}
+
+// ExtractStructured extracts markdown the same way ExtractToMarkdown does,
+// then additionally detects tables and headings and, where possible,
+// extracts embedded images.
+//
+// None of this is a real layout analyzer - it's heuristic, in keeping with
+// textToMarkdown's own ALL-CAPS/bullet-glyph approach:
+//   - a table is >=3 consecutive lines whose runs of 2+ spaces (the column
+//     gaps pdftotext -layout and friends already preserve) line up with
+//     each other;
+//   - headings are ranked by distinct glyph font sizes recovered from the
+//     PDF's content streams via pdfcpu. When that fails (encrypted/odd
+//     PDFs, or no font-size variation to rank), ExtractStructured falls
+//     back to the "## " headings textToMarkdown already produced with its
+//     ALL-CAPS heuristic.
+func (e *Extractor) ExtractStructured(ctx context.Context, pdfData []byte, filename string) (*StructuredResult, error) {
+	md, err := e.ExtractToMarkdown(ctx, pdfData, filename)
+	if err != nil {
+		return nil, err
+	}
+	if !md.Success {
+		return &StructuredResult{ExtractResult: md}, nil
+	}
+
+	baseName := strings.TrimSuffix(filename, filepath.Ext(filename))
+	headings := e.documentHeadings(pdfData, md.Markdown)
+	images := extractImages(pdfData, baseName)
+	markdown, tables := renderTables(md.Markdown)
+
+	doc := rag.DocumentMetadata{
+		Title:      baseName,
+		PageCount:  md.PageCount,
+		WordCount:  len(strings.Fields(markdown)),
+		CharCount:  len(markdown),
+		TableCount: len(tables),
+		ImageCount: len(images),
+		Headings:   headings,
+	}
+
+	structured := *md
+	structured.Markdown = markdown
+
+	return &StructuredResult{
+		ExtractResult: &structured,
+		Tables:        tables,
+		Images:        images,
+		Document:      doc,
+	}, nil
+}
+
+var columnGapPattern = regexp.MustCompile(`\s{2,}`)
+
+// tableGapTolerance is how many characters a column gap may drift between
+// consecutive lines and still be considered the same column boundary.
+const tableGapTolerance = 3
+
+// renderTables groups runs of >=3 consecutive lines whose 2+-space column
+// gaps align, replaces each run in markdown with its GitHub-flavored
+// Markdown table rendering, and returns the detected tables alongside the
+// rewritten markdown.
+func renderTables(markdown string) (string, []rag.ExtractedTable) {
+	lines := strings.Split(markdown, "\n")
+
+	var tables []rag.ExtractedTable
+	var out []string
+	var block []string
+	var blockGaps [][]int
+
+	flush := func() {
+		if len(block) >= 3 {
+			table := newExtractedTable(block)
+			tables = append(tables, table)
+			out = append(out, strings.TrimRight(tableToGFM(table), "\n"))
+		} else {
+			out = append(out, block...)
+		}
+		block = nil
+		blockGaps = nil
+	}
+
+	for _, line := range lines {
+		gaps := columnGapOffsets(line)
+		if len(gaps) < 2 {
+			flush()
+			out = append(out, line)
+			continue
+		}
+		if len(blockGaps) > 0 && !gapsAlign(blockGaps[len(blockGaps)-1], gaps) {
+			flush()
+		}
+		block = append(block, line)
+		blockGaps = append(blockGaps, gaps)
+	}
+	flush()
+
+	return strings.Join(out, "\n"), tables
+}
+
+// columnGapOffsets returns the starting index of each run of 2+ spaces in
+// line - candidate column boundaries.
+func columnGapOffsets(line string) []int {
+	matches := columnGapPattern.FindAllStringIndex(line, -1)
+	offsets := make([]int, len(matches))
+	for i, m := range matches {
+		offsets[i] = m[0]
+	}
+	return offsets
+}
+
+// gapsAlign reports whether two lines' column gaps correspond 1:1 within
+// tableGapTolerance characters.
+func gapsAlign(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		diff := a[i] - b[i]
+		if diff < -tableGapTolerance || diff > tableGapTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+func newExtractedTable(lines []string) rag.ExtractedTable {
+	rows := make([][]string, len(lines))
+	for i, line := range lines {
+		rows[i] = columnGapPattern.Split(strings.TrimSpace(line), -1)
+	}
+	return rag.ExtractedTable{
+		Header: rows[0],
+		Rows:   rows[1:],
+	}
+}
+
+// tableToGFM renders an ExtractedTable as a GitHub-flavored Markdown table.
+func tableToGFM(t rag.ExtractedTable) string {
+	var sb strings.Builder
+	writeRow := func(cols []string) {
+		sb.WriteString("| ")
+		sb.WriteString(strings.Join(cols, " | "))
+		sb.WriteString(" |\n")
+	}
+	writeRow(t.Header)
+	sb.WriteString("|")
+	for range t.Header {
+		sb.WriteString(" --- |")
+	}
+	sb.WriteString("\n")
+	for _, row := range t.Rows {
+		writeRow(row)
+	}
+	return sb.String()
+}
+
+// documentHeadings ranks headings by font size via the pdfcpu content
+// stream when possible, falling back to the "## " headings already present
+// in markdown (textToMarkdown's ALL-CAPS heuristic) otherwise.
+func (e *Extractor) documentHeadings(pdfData []byte, markdown string) []rag.HeadingInfo {
+	if headings, ok := headingsByFontSize(pdfData); ok {
+		return headings
+	}
+	return headingsFromMarkdown(markdown)
+}
+
+func headingsFromMarkdown(markdown string) []rag.HeadingInfo {
+	var headings []rag.HeadingInfo
+	for _, line := range strings.Split(markdown, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			headings = append(headings, rag.HeadingInfo{
+				Text:     strings.TrimPrefix(line, "## "),
+				Level:    2,
+				StartPos: -1,
+				EndPos:   -1,
+			})
+		}
+	}
+	return headings
+}
+
+type sizedLine struct {
+	text string
+	size float64
+}
+
+// headingsByFontSize reads pdfData's content streams directly (no temp
+// file needed - pdfcpu's ExtractContent takes an io.ReadSeeker), ranks the
+// distinct font sizes bigger than the document's most common ("body")
+// size, and returns the lines set in one of those sizes as headings. The
+// bool return is false when the PDF couldn't be parsed or no heading-sized
+// text was found, signaling the caller to fall back.
+func headingsByFontSize(pdfData []byte) ([]rag.HeadingInfo, bool) {
+	var runs []sizedRun
+	digest := func(r io.Reader, pageNr int) error {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("read page %d content: %w", pageNr, err)
+		}
+		runs = append(runs, decodeContentStreamTextWithSizes(content)...)
+		return nil
+	}
+	if err := api.ExtractContent(bytes.NewReader(pdfData), nil, digest, nil); err != nil {
+		return nil, false
+	}
+
+	lines := linesBySize(runs)
+	levels := rankFontSizes(lines)
+	if len(levels) == 0 {
+		return nil, false
+	}
+
+	var headings []rag.HeadingInfo
+	for _, l := range lines {
+		if level, ok := levels[l.size]; ok {
+			headings = append(headings, rag.HeadingInfo{Text: l.text, Level: level, StartPos: -1, EndPos: -1})
+		}
+	}
+	return headings, true
+}
+
+// linesBySize reassembles decodeContentStreamTextWithSizes's runs into
+// lines (splitting on the "\n" markers it emits for Td/TD/T*), tagging
+// each line with the largest font size any of its runs used.
+func linesBySize(runs []sizedRun) []sizedLine {
+	var lines []sizedLine
+	var text strings.Builder
+	maxSize := 0.0
+
+	flush := func() {
+		if t := strings.TrimSpace(text.String()); t != "" {
+			lines = append(lines, sizedLine{text: t, size: maxSize})
+		}
+		text.Reset()
+		maxSize = 0
+	}
+
+	for _, r := range runs {
+		if r.Text == "\n" {
+			flush()
+			continue
+		}
+		text.WriteString(r.Text)
+		if r.FontSize > maxSize {
+			maxSize = r.FontSize
+		}
+	}
+	flush()
+
+	return lines
+}
+
+// rankFontSizes finds the most common ("body") font size by line count,
+// then assigns heading levels 1-6 to distinct larger sizes in descending
+// order.
+func rankFontSizes(lines []sizedLine) map[float64]int {
+	counts := map[float64]int{}
+	for _, l := range lines {
+		counts[l.size]++
+	}
+
+	bodySize, bodyCount := 0.0, 0
+	for size, count := range counts {
+		if count > bodyCount {
+			bodySize, bodyCount = size, count
+		}
+	}
+
+	var larger []float64
+	for size := range counts {
+		if size > bodySize {
+			larger = append(larger, size)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(larger)))
+
+	levels := map[float64]int{}
+	for i, size := range larger {
+		if i >= 6 {
+			break
+		}
+		levels[size] = i + 1
+	}
+	return levels
+}
+
+// extractImages pulls embedded images out of pdfData via pdfcpu (which
+// works directly off the bytes, no temp file needed) and names them after
+// baseName, the page, and their position in it.
+func extractImages(pdfData []byte, baseName string) []rag.ExtractedImage {
+	imagesByPage, err := api.ExtractImagesRaw(bytes.NewReader(pdfData), nil, nil)
+	if err != nil {
+		return nil
+	}
+
+	var images []rag.ExtractedImage
+	for _, pageImages := range imagesByPage {
+		for idx, img := range pageImages {
+			data, err := io.ReadAll(img)
+			if err != nil {
+				continue
+			}
+			images = append(images, rag.ExtractedImage{
+				Data:     data,
+				Format:   img.FileType,
+				Filename: fmt.Sprintf("%s_p%d_%d.%s", baseName, img.PageNr, idx, img.FileType),
+				Page:     img.PageNr,
+			})
+		}
+	}
+	return images
+}
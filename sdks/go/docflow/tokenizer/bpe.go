@@ -0,0 +1,50 @@
+package tokenizer
+
+import "regexp"
+
+// cl100kPattern and o200kPattern are simplified versions of the
+// pre-tokenization regexes tiktoken uses for the cl100k_base and
+// o200k_base encodings: they split text into words, runs of digits, and
+// runs of punctuation/whitespace before byte-level encoding.
+var (
+	cl100kPattern = regexp.MustCompile(`(?i)'s|'t|'re|'ve|'m|'ll|'d| ?[[:alpha:]]+| ?[[:digit:]]+| ?[^\s[:alpha:][:digit:]]+|\s+`)
+	o200kPattern  = cl100kPattern
+)
+
+// bpeTokenizer is a byte-level tokenizer in the tiktoken style: text is
+// split with a pre-tokenization regex, and each resulting piece is
+// encoded as its raw UTF-8 bytes. docflow doesn't vendor the real
+// cl100k_base/o200k_base merge-rank tables, so this omits the BPE merge
+// step; Encode/Decode still round-trip exactly, but CountTokens returns
+// the byte-level upper bound rather than the true (lower) merged count.
+type bpeTokenizer struct {
+	pattern *regexp.Regexp
+}
+
+func newBPETokenizer(pattern *regexp.Regexp) *bpeTokenizer {
+	return &bpeTokenizer{pattern: pattern}
+}
+
+func (t *bpeTokenizer) Encode(text string) []int {
+	var tokens []int
+	for _, piece := range t.pattern.FindAllString(text, -1) {
+		for _, b := range []byte(piece) {
+			tokens = append(tokens, int(b))
+		}
+	}
+	return tokens
+}
+
+func (t *bpeTokenizer) Decode(tokens []int) string {
+	bytes := make([]byte, 0, len(tokens))
+	for _, tok := range tokens {
+		if tok >= 0 && tok < 256 {
+			bytes = append(bytes, byte(tok))
+		}
+	}
+	return string(bytes)
+}
+
+func (t *bpeTokenizer) CountTokens(text string) int {
+	return len(t.Encode(text))
+}
@@ -0,0 +1,81 @@
+package tokenizer
+
+import "strings"
+
+// spaceMarker is SentencePiece's convention for marking where a decoded
+// token is preceded by whitespace, used in place of a literal space so
+// word boundaries survive tokenization.
+const spaceMarker = "▁"
+
+// SentencePieceTokenizer adapts a decoded SentencePiece vocabulary (the
+// ordered list of pieces a .model file expands to) into the Tokenizer
+// interface, for Llama/Mistral-family models. docflow doesn't parse the
+// SentencePiece protobuf model format itself - callers load the model
+// with their own tooling and pass the resulting piece list here, then
+// register it under the model's name:
+//
+//	vocab := loadSentencePieceModel("llama-3.model") // caller-supplied
+//	tokenizer.Register("llama-3", func() (tokenizer.Tokenizer, error) {
+//	    return tokenizer.NewSentencePieceTokenizer(vocab), nil
+//	})
+type SentencePieceTokenizer struct {
+	pieces    []string
+	idOfPiece map[string]int
+}
+
+// NewSentencePieceTokenizer builds a SentencePieceTokenizer from vocab,
+// an ordered list of pieces where each piece's index is its token ID.
+func NewSentencePieceTokenizer(vocab []string) *SentencePieceTokenizer {
+	idOfPiece := make(map[string]int, len(vocab))
+	for id, piece := range vocab {
+		idOfPiece[piece] = id
+	}
+	return &SentencePieceTokenizer{pieces: vocab, idOfPiece: idOfPiece}
+}
+
+// Encode greedily matches the longest known piece at each position,
+// falling back one rune at a time when nothing in the vocabulary
+// matches. This mirrors SentencePiece's unigram/BPE output shape without
+// needing its scoring model, at the cost of not always picking the same
+// split a real SentencePiece run would.
+func (t *SentencePieceTokenizer) Encode(text string) []int {
+	text = strings.ReplaceAll(text, " ", spaceMarker)
+	runes := []rune(text)
+
+	var tokens []int
+	for i := 0; i < len(runes); {
+		matched := false
+		for end := len(runes); end > i; end-- {
+			piece := string(runes[i:end])
+			if id, ok := t.idOfPiece[piece]; ok {
+				tokens = append(tokens, id)
+				i = end
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			// Unknown rune: fall back to a single-rune piece lookup, or
+			// drop it if even that isn't in the vocabulary.
+			if id, ok := t.idOfPiece[string(runes[i])]; ok {
+				tokens = append(tokens, id)
+			}
+			i++
+		}
+	}
+	return tokens
+}
+
+func (t *SentencePieceTokenizer) Decode(tokens []int) string {
+	var sb strings.Builder
+	for _, id := range tokens {
+		if id >= 0 && id < len(t.pieces) {
+			sb.WriteString(t.pieces[id])
+		}
+	}
+	return strings.ReplaceAll(sb.String(), spaceMarker, " ")
+}
+
+func (t *SentencePieceTokenizer) CountTokens(text string) int {
+	return len(t.Encode(text))
+}
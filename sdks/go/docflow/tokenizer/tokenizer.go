@@ -0,0 +1,57 @@
+// Package tokenizer provides a pluggable way to count and encode tokens
+// for ChunkingConfig.Tokenizer and RetrievalConfig.MaxContextTokens,
+// instead of the whitespace-word approximations the rag and qa packages
+// used previously.
+package tokenizer
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Tokenizer turns text into model-specific token IDs and back. Encode/
+// Decode need not round-trip byte-for-byte (BPE implementations may
+// normalize whitespace); CountTokens is the cheap path when only a count
+// is needed.
+type Tokenizer interface {
+	Encode(text string) []int
+	Decode(tokens []int) string
+	CountTokens(text string) int
+}
+
+// Factory constructs a Tokenizer, returning an error if it can't be
+// built (e.g. a model file is missing).
+type Factory func() (Tokenizer, error)
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]Factory{}
+)
+
+func init() {
+	Register("cl100k_base", func() (Tokenizer, error) { return newBPETokenizer(cl100kPattern), nil })
+	Register("o200k_base", func() (Tokenizer, error) { return newBPETokenizer(o200kPattern), nil })
+	Register("whitespace", func() (Tokenizer, error) { return newWhitespaceTokenizer(), nil })
+}
+
+// Register adds a Tokenizer under name, overwriting any existing
+// registration (including the built-ins), so callers can swap in a
+// model-specific tokenizer - e.g. tokenizer.Register("my-model", ...) -
+// without forking docflow.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	registry[name] = factory
+}
+
+// Get builds the Tokenizer registered under name. ChunkingConfig.Tokenizer
+// and similar fields are resolved through this.
+func Get(name string) (Tokenizer, error) {
+	mu.RLock()
+	factory, ok := registry[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("tokenizer: no tokenizer registered under %q", name)
+	}
+	return factory()
+}
@@ -0,0 +1,55 @@
+package tokenizer
+
+import (
+	"strings"
+	"sync"
+)
+
+// whitespaceTokenizer is the trivial fallback: one token per
+// whitespace-delimited word. It assigns IDs on first sight so Decode can
+// round-trip within a single instance's lifetime; it has no notion of a
+// fixed vocabulary.
+type whitespaceTokenizer struct {
+	mu     sync.Mutex
+	idOf   map[string]int
+	wordOf []string
+}
+
+func newWhitespaceTokenizer() *whitespaceTokenizer {
+	return &whitespaceTokenizer{idOf: map[string]int{}}
+}
+
+func (t *whitespaceTokenizer) Encode(text string) []int {
+	words := strings.Fields(text)
+	ids := make([]int, len(words))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, w := range words {
+		id, ok := t.idOf[w]
+		if !ok {
+			id = len(t.wordOf)
+			t.idOf[w] = id
+			t.wordOf = append(t.wordOf, w)
+		}
+		ids[i] = id
+	}
+	return ids
+}
+
+func (t *whitespaceTokenizer) Decode(tokens []int) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	words := make([]string, 0, len(tokens))
+	for _, id := range tokens {
+		if id >= 0 && id < len(t.wordOf) {
+			words = append(words, t.wordOf[id])
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func (t *whitespaceTokenizer) CountTokens(text string) int {
+	return len(strings.Fields(text))
+}
@@ -1,6 +1,9 @@
 package docflow
 
 import (
+	"context"
+
+	"github.com/xgaslan/docflow/sdks/go/docflow/batchprogress"
 	"github.com/xgaslan/docflow/sdks/go/docflow/config"
 	"github.com/xgaslan/docflow/sdks/go/docflow/rag"
 )
@@ -57,4 +60,12 @@ type BatchJob struct {
 	Errors         map[string]string `json:"errors,omitempty"`
 	CreatedAt      interface{}       `json:"created_at,omitempty"`   // time.Time
 	CompletedAt    interface{}       `json:"completed_at,omitempty"` // time.Time
+
+	// Progress streams structured Events for this job's files as they
+	// are processed. It is buffered so a slow or absent reader doesn't
+	// stall the worker, and closed after the JobCompleted Event is
+	// sent. Nil for jobs created before this field existed.
+	Progress chan batchprogress.Event `json:"-"`
+
+	cancel context.CancelFunc
 }
@@ -0,0 +1,322 @@
+// Package vectorizer embeds query text into a vector at query time, so
+// callers can send a VectorizableTextQuery (text + vectorizer name)
+// instead of a pre-computed embedding. This mirrors Azure AI Search's
+// vectorizer concept but also works against the Postgres/MongoDB vector
+// stores, which have no embedding step of their own.
+package vectorizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Vectorizer embeds a single piece of query text into a vector.
+type Vectorizer interface {
+	EmbedQuery(ctx context.Context, text string) ([]float32, error)
+}
+
+// BatchVectorizer is an optional capability: Vectorizers that can embed
+// many texts in one round trip (e.g. an OpenAI-compatible /embeddings
+// endpoint, which accepts an array "input") implement it so callers like
+// rag.SemanticChunker can batch-embed a document's sentences instead of
+// issuing one request per sentence.
+type BatchVectorizer interface {
+	EmbedBatch(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// httpVectorizer is the shared transport for the HTTP-based adapters
+// below; each adapter only differs in request/response shape.
+type httpVectorizer struct {
+	client *http.Client
+}
+
+func newHTTPVectorizer() httpVectorizer {
+	return httpVectorizer{client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// OpenAIConfig configures the OpenAI embeddings adapter.
+type OpenAIConfig struct {
+	APIKey string
+	Model  string
+	// BaseURL defaults to https://api.openai.com/v1.
+	BaseURL string
+}
+
+// openAIVectorizer calls OpenAI's /embeddings endpoint.
+type openAIVectorizer struct {
+	httpVectorizer
+	cfg OpenAIConfig
+}
+
+// NewOpenAIVectorizer returns a Vectorizer backed by OpenAI embeddings.
+func NewOpenAIVectorizer(cfg OpenAIConfig) Vectorizer {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.openai.com/v1"
+	}
+	if cfg.Model == "" {
+		cfg.Model = "text-embedding-3-small"
+	}
+	return &openAIVectorizer{httpVectorizer: newHTTPVectorizer(), cfg: cfg}
+}
+
+func (v *openAIVectorizer) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	payload := map[string]interface{}{
+		"model": v.cfg.Model,
+		"input": text,
+	}
+	return v.embed(ctx, v.cfg.BaseURL+"/embeddings", payload, "Bearer "+v.cfg.APIKey)
+}
+
+// EmbedBatch embeds every text in one request by passing the whole slice
+// as the OpenAI embeddings endpoint's "input" array. This also works
+// against Ollama/LM Studio/vLLM servers exposing an OpenAI-compatible
+// /v1/embeddings route via BaseURL.
+func (v *openAIVectorizer) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	payload := map[string]interface{}{
+		"model": v.cfg.Model,
+		"input": texts,
+	}
+	return v.embedBatch(ctx, v.cfg.BaseURL+"/embeddings", payload, "Bearer "+v.cfg.APIKey)
+}
+
+// AzureOpenAIConfig configures the Azure OpenAI embeddings adapter.
+type AzureOpenAIConfig struct {
+	ResourceURI  string // e.g. https://my-resource.openai.azure.com
+	DeploymentID string
+	APIKey       string
+	APIVersion   string
+}
+
+// azureOpenAIVectorizer calls an Azure OpenAI embeddings deployment.
+type azureOpenAIVectorizer struct {
+	httpVectorizer
+	cfg AzureOpenAIConfig
+}
+
+// NewAzureOpenAIVectorizer returns a Vectorizer backed by an Azure OpenAI
+// embeddings deployment.
+func NewAzureOpenAIVectorizer(cfg AzureOpenAIConfig) Vectorizer {
+	if cfg.APIVersion == "" {
+		cfg.APIVersion = "2024-07-01"
+	}
+	return &azureOpenAIVectorizer{httpVectorizer: newHTTPVectorizer(), cfg: cfg}
+}
+
+func (v *azureOpenAIVectorizer) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	url := fmt.Sprintf("%s/openai/deployments/%s/embeddings?api-version=%s",
+		v.cfg.ResourceURI, v.cfg.DeploymentID, v.cfg.APIVersion)
+	payload := map[string]interface{}{"input": text}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, mustMarshal(payload))
+	if err != nil {
+		return nil, fmt.Errorf("vectorizer: azure openai request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("api-key", v.cfg.APIKey)
+
+	return v.do(req)
+}
+
+// OllamaConfig configures the Ollama embeddings adapter.
+type OllamaConfig struct {
+	BaseURL string // defaults to http://localhost:11434
+	Model   string
+}
+
+// ollamaVectorizer calls a local Ollama server's /api/embeddings endpoint.
+type ollamaVectorizer struct {
+	httpVectorizer
+	cfg OllamaConfig
+}
+
+// NewOllamaVectorizer returns a Vectorizer backed by a local Ollama model.
+func NewOllamaVectorizer(cfg OllamaConfig) Vectorizer {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "http://localhost:11434"
+	}
+	return &ollamaVectorizer{httpVectorizer: newHTTPVectorizer(), cfg: cfg}
+}
+
+func (v *ollamaVectorizer) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	payload := map[string]interface{}{
+		"model":  v.cfg.Model,
+		"prompt": text,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", v.cfg.BaseURL+"/api/embeddings", mustMarshal(payload))
+	if err != nil {
+		return nil, fmt.Errorf("vectorizer: ollama request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vectorizer: ollama call: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("vectorizer: ollama error: %s", string(body))
+	}
+
+	var result struct {
+		Embedding []float32 `json:"embedding"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("vectorizer: ollama decode: %w", err)
+	}
+	return result.Embedding, nil
+}
+
+// WebhookConfig configures the custom-webhook adapter, matching Azure AI
+// Search's "customWebApi" vectorizer: an arbitrary HTTP endpoint that
+// accepts {"text": "..."} and returns {"vector": [...]}.
+type WebhookConfig struct {
+	URI     string
+	Headers map[string]string
+}
+
+// webhookVectorizer calls a caller-owned embedding endpoint.
+type webhookVectorizer struct {
+	httpVectorizer
+	cfg WebhookConfig
+}
+
+// NewWebhookVectorizer returns a Vectorizer that delegates embedding to a
+// custom HTTP endpoint.
+func NewWebhookVectorizer(cfg WebhookConfig) Vectorizer {
+	return &webhookVectorizer{httpVectorizer: newHTTPVectorizer(), cfg: cfg}
+}
+
+func (v *webhookVectorizer) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", v.cfg.URI, mustMarshal(map[string]interface{}{"text": text}))
+	if err != nil {
+		return nil, fmt.Errorf("vectorizer: webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, val := range v.cfg.Headers {
+		req.Header.Set(k, val)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vectorizer: webhook call: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("vectorizer: webhook error: %s", string(body))
+	}
+
+	var result struct {
+		Vector []float32 `json:"vector"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("vectorizer: webhook decode: %w", err)
+	}
+	return result.Vector, nil
+}
+
+// embed POSTs payload to url with the given Authorization header value and
+// parses an OpenAI-style {"data": [{"embedding": [...]}]} response; shared
+// by the OpenAI and Azure OpenAI adapters.
+func (h httpVectorizer) embed(ctx context.Context, url string, payload interface{}, authorization string) ([]float32, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, mustMarshal(payload))
+	if err != nil {
+		return nil, fmt.Errorf("vectorizer: request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return h.do(req)
+}
+
+// embedBatch POSTs payload (an "input" array) to url and parses an
+// OpenAI-style {"data": [{"index", "embedding"}, ...]} response back into
+// one vector per input text, in input order.
+func (h httpVectorizer) embedBatch(ctx context.Context, url string, payload interface{}, authorization string) ([][]float32, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, mustMarshal(payload))
+	if err != nil {
+		return nil, fmt.Errorf("vectorizer: request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return h.doBatch(req)
+}
+
+// doBatch is do's multi-result counterpart: it sorts the response's
+// "data" entries by "index" before returning, since providers aren't
+// required to return them in request order.
+func (h httpVectorizer) doBatch(req *http.Request) ([][]float32, error) {
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vectorizer: call: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("vectorizer: error: %s", string(body))
+	}
+
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("vectorizer: decode: %w", err)
+	}
+
+	out := make([][]float32, len(result.Data))
+	for _, d := range result.Data {
+		if d.Index < 0 || d.Index >= len(out) {
+			continue
+		}
+		out[d.Index] = d.Embedding
+	}
+	return out, nil
+}
+
+// do sends req and parses an OpenAI-style embeddings response.
+func (h httpVectorizer) do(req *http.Request) ([]float32, error) {
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vectorizer: call: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("vectorizer: error: %s", string(body))
+	}
+
+	var result struct {
+		Data []struct {
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("vectorizer: decode: %w", err)
+	}
+	if len(result.Data) == 0 {
+		return nil, fmt.Errorf("vectorizer: empty response")
+	}
+	return result.Data[0].Embedding, nil
+}
+
+func mustMarshal(v interface{}) *bytes.Reader {
+	body, _ := json.Marshal(v)
+	return bytes.NewReader(body)
+}